@@ -0,0 +1,207 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/keikoproj/kubedog"
+)
+
+const (
+	completeCommand  = "__complete"
+	featureExtension = ".feature"
+)
+
+var shells = []string{"bash", "zsh"}
+
+const bashCompletion = `# bash completion for kubedog, load it with: source <(kubedog completion bash)
+_kubedog() {
+    local IFS=$'\n' candidates
+    candidates=($(kubedog __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+    COMPREPLY=($(printf '%q\n' "${candidates[@]}"))
+    if [[ ${#COMPREPLY[@]} -eq 1 && ${COMPREPLY[0]} == */ ]]; then
+        compopt -o nospace
+    fi
+}
+complete -F _kubedog kubedog
+`
+
+const zshCompletion = `#compdef kubedog
+# zsh completion for kubedog, load it with: source <(kubedog completion zsh)
+_kubedog() {
+    local -a candidates
+    candidates=("${(@f)$(kubedog __complete "${(@)words[2,$CURRENT]}" 2>/dev/null)}")
+    candidates=(${candidates:#})
+    local -a dirs others
+    dirs=(${(M)candidates:#*/})
+    others=(${candidates:#*/})
+    (( ${#others} )) && compadd -a others
+    (( ${#dirs} )) && compadd -S '' -a dirs
+}
+compdef _kubedog kubedog
+`
+
+func printCompletion(args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected one of %v as the only argument", shells)
+	}
+	var script string
+	switch args[0] {
+	case "bash":
+		script = bashCompletion
+	case "zsh":
+		script = zshCompletion
+	default:
+		return fmt.Errorf("unsupported shell '%s', expected one of %v", args[0], shells)
+	}
+	_, err := io.WriteString(out, script)
+	return err
+}
+
+// complete prints the candidates for the last of args, the word being completed after 'kubedog'
+func complete(args []string, out io.Writer) error {
+	for _, candidate := range getCandidates(args) {
+		fmt.Fprintln(out, candidate)
+	}
+	return nil
+}
+
+func getCandidates(args []string) []string {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	current := args[len(args)-1]
+	if len(args) == 1 {
+		names := []string{}
+		for name, cmd := range commands {
+			if !cmd.hidden {
+				names = append(names, name)
+			}
+		}
+		return filterPrefix(names, current)
+	}
+
+	name := args[0]
+	cmd, ok := commands[name]
+	if !ok || cmd.hidden {
+		return nil
+	}
+	if name == "completion" {
+		if len(args) == 2 {
+			return filterPrefix(shells, current)
+		}
+		return nil
+	}
+	if cmd.flags == nil {
+		return nil
+	}
+	flags := cmd.flags(io.Discard)
+	if previous := strings.TrimLeft(args[len(args)-2], "-"); strings.HasPrefix(args[len(args)-2], "-") && !strings.Contains(previous, "=") {
+		if f := flags.Lookup(previous); f != nil && !isBoolFlag(f) {
+			return getFlagValueCandidates(name, previous, current)
+		}
+	}
+	if strings.HasPrefix(current, "-") {
+		names := []string{}
+		flags.VisitAll(func(f *flag.Flag) {
+			names = append(names, "-"+f.Name)
+		})
+		return filterPrefix(names, current)
+	}
+	if name == "run" {
+		return getPathCandidates(current, true)
+	}
+	return nil
+}
+
+func getFlagValueCandidates(command, flagName, current string) []string {
+	switch {
+	case command == "list-steps" && flagName == "category":
+		steps, err := kubedog.Syntax()
+		if err != nil {
+			return nil
+		}
+		categories := []string{}
+		for _, step := range steps {
+			categories = append(categories, step.Category)
+			categories = append(categories, step.Titles...)
+		}
+		return filterPrefix(categories, current)
+	case command == "list-steps" && flagName == "keyword":
+		steps, err := kubedog.Syntax()
+		if err != nil {
+			return nil
+		}
+		methods := []string{}
+		for _, step := range steps {
+			methods = append(methods, step.Method[strings.LastIndex(step.Method, ".")+1:])
+		}
+		return filterPrefix(methods, current)
+	case command == "run" && flagName == "format":
+		return filterPrefix(formats, current)
+	case command == "run" && (flagName == "kubeconfig" || flagName == "values" || flagName == "files"):
+		return getPathCandidates(current, false)
+	}
+	return nil
+}
+
+// getPathCandidates returns the directories and files starting with prefix, only the feature files if featuresOnly
+func getPathCandidates(prefix string, featuresOnly bool) []string {
+	matches, err := filepath.Glob(prefix + "*")
+	if err != nil {
+		return nil
+	}
+	candidates := []string{}
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			continue
+		}
+		switch {
+		case info.IsDir():
+			candidates = append(candidates, match+string(filepath.Separator))
+		case !featuresOnly || filepath.Ext(match) == featureExtension:
+			candidates = append(candidates, match)
+		}
+	}
+	return candidates
+}
+
+// filterPrefix returns the sorted unique non-empty values starting with prefix, ignoring case
+func filterPrefix(values []string, prefix string) []string {
+	seen := map[string]bool{}
+	filtered := []string{}
+	for _, value := range values {
+		if value == "" || seen[value] || !strings.HasPrefix(strings.ToLower(value), strings.ToLower(prefix)) {
+			continue
+		}
+		seen[value] = true
+		filtered = append(filtered, value)
+	}
+	sort.Strings(filtered)
+	return filtered
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
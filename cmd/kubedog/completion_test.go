@@ -0,0 +1,81 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetCandidates(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{"deploy.feature", "values.yaml", "features/scale.feature"} {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sep := string(filepath.Separator)
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "commands", args: []string{""}, want: []string{"completion", "list-steps", "run"}},
+		{name: "command prefix", args: []string{"li"}, want: []string{"list-steps"}},
+		{name: "shells", args: []string{"completion", ""}, want: []string{"bash", "zsh"}},
+		{name: "flags", args: []string{"list-steps", "-"}, want: []string{"-category", "-keyword", "-regex"}},
+		{name: "flag value", args: []string{"run", "-format", "p"}, want: []string{"pretty", "progress"}},
+		{name: "after bool flag", args: []string{"run", "-strict", dir + sep + "d"}, want: []string{dir + sep + "deploy.feature"}},
+		{name: "feature files", args: []string{"run", dir + sep}, want: []string{dir + sep + "deploy.feature", dir + sep + "features" + sep}},
+		{name: "any file as flag value", args: []string{"run", "-values", dir + sep + "v"}, want: []string{dir + sep + "values.yaml"}},
+		{name: "categories", args: []string{"list-steps", "-category", "argo"}, want: []string{"Argo CD", "Argo Rollouts", "argocd"}},
+		{name: "step names", args: []string{"list-steps", "-keyword", "RolloutShould"}, want: []string{"RolloutShouldBe"}},
+		{name: "unknown command", args: []string{"unknown", ""}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getCandidates(tt.args)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getCandidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintCompletion(t *testing.T) {
+	for _, shell := range shells {
+		out := &bytes.Buffer{}
+		if err := printCompletion([]string{shell}, out); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out.String(), "kubedog "+completeCommand) {
+			t.Errorf("printCompletion() expected the %s script to call %s, got %s", shell, completeCommand, out.String())
+		}
+	}
+	if err := printCompletion([]string{"fish"}, &bytes.Buffer{}); err == nil {
+		t.Error("printCompletion() expected error for unsupported shell")
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/keikoproj/kubedog"
+	"github.com/keikoproj/kubedog/generate/syntax/catalog"
+)
+
+const titleDelimiter = " > "
+
+type listStepsOptions struct {
+	keyword, category string
+	regex             bool
+}
+
+func newListStepsFlags(out io.Writer) (*flag.FlagSet, *listStepsOptions) {
+	o := &listStepsOptions{}
+	flags := flag.NewFlagSet("list-steps", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.StringVar(&o.keyword, "keyword", "", "only print the steps whose syntax, method, description or examples contain the keyword")
+	flags.StringVar(&o.category, "category", "", "only print the steps whose category or titles contain the category, e.g. 'pod' or 'Argo Rollouts'")
+	flags.BoolVar(&o.regex, "regex", false, "print the regexp of the steps instead of their syntax")
+	return flags, o
+}
+
+func listSteps(args []string, out io.Writer) error {
+	flags, o := newListStepsFlags(out)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %v", flags.Args())
+	}
+
+	steps, err := kubedog.Syntax()
+	if err != nil {
+		return err
+	}
+	printSteps(out, filterSteps(steps, o.keyword, o.category), o.regex)
+	return nil
+}
+
+// filterSteps returns the steps matching keyword and category, ignoring case
+func filterSteps(steps []catalog.Step, keyword, category string) []catalog.Step {
+	filtered := []catalog.Step{}
+	for _, step := range steps {
+		if keyword != "" && !containsFold(keyword, append([]string{step.Syntax, step.Regex, step.Method, step.Description}, step.Examples...)...) {
+			continue
+		}
+		if category != "" && !containsFold(category, append([]string{step.Category}, step.Titles...)...) {
+			continue
+		}
+		filtered = append(filtered, step)
+	}
+	return filtered
+}
+
+func printSteps(out io.Writer, steps []catalog.Step, regex bool) {
+	lastTitles := ""
+	for _, step := range steps {
+		if titles := strings.Join(step.Titles, titleDelimiter); titles != lastTitles {
+			if lastTitles != "" {
+				fmt.Fprintln(out)
+			}
+			fmt.Fprintln(out, titles)
+			lastTitles = titles
+		}
+		syntax := step.Syntax
+		if regex {
+			syntax = step.Regex
+		}
+		fmt.Fprintf(out, "  %s (%s)\n", syntax, step.Method)
+		if step.Deprecated != "" {
+			fmt.Fprintf(out, "      deprecated: %s\n", step.Deprecated)
+		}
+		if step.Description != "" {
+			fmt.Fprintf(out, "      %s\n", step.Description)
+		}
+		for _, example := range step.Examples {
+			fmt.Fprintf(out, "      e.g. %s\n", example)
+		}
+	}
+}
+
+func containsFold(substr string, values ...string) bool {
+	substr = strings.ToLower(substr)
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), substr) {
+			return true
+		}
+	}
+	return false
+}
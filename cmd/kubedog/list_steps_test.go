@@ -0,0 +1,80 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/keikoproj/kubedog/generate/syntax/catalog"
+)
+
+var testSteps = []catalog.Step{
+	{Syntax: "[I] wait [for] <digits> (minutes|seconds)", Method: "generic.WaitFor", Examples: []string{"And I wait for 30 seconds"}, Titles: []string{"Generic steps"}, Category: "generic"},
+	{Syntax: "[the] rollout <non-whitespace-characters> should be healthy", Method: "kdt.KubeClientSet.RolloutShouldBeHealthy", Titles: []string{"Kubernetes steps", "Argo Rollouts"}, Category: "argo"},
+	{Syntax: "[the] application <non-whitespace-characters> should be synced", Method: "kdt.KubeClientSet.ApplicationShouldBeSynced", Titles: []string{"Kubernetes steps", "Argo CD"}, Category: "argo"},
+}
+
+func TestFilterSteps(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyword  string
+		category string
+		want     int
+	}{
+		{name: "no filter", want: 3},
+		{name: "keyword in syntax", keyword: "ROLLOUT", want: 1},
+		{name: "keyword in example", keyword: "30 seconds", want: 1},
+		{name: "category", category: "argo", want: 2},
+		{name: "title", category: "argo cd", want: 1},
+		{name: "keyword and category", keyword: "synced", category: "Kubernetes", want: 1},
+		{name: "no match", keyword: "istio", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterSteps(testSteps, tt.keyword, tt.category); len(got) != tt.want {
+				t.Errorf("filterSteps() = %v, want %d steps", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintSteps(t *testing.T) {
+	out := &bytes.Buffer{}
+	printSteps(out, testSteps, false)
+	for _, want := range []string{
+		"Generic steps\n  [I] wait [for] <digits> (minutes|seconds) (generic.WaitFor)\n      e.g. And I wait for 30 seconds\n",
+		"\nKubernetes steps > Argo Rollouts\n",
+		"\nKubernetes steps > Argo CD\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("printSteps() expected output to contain %q, got %q", want, out.String())
+		}
+	}
+}
+
+func TestListSteps(t *testing.T) {
+	out := &bytes.Buffer{}
+	if err := listSteps([]string{"-category", "Generic"}, out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "kdt.KubeClientSet.WaitFor") {
+		t.Errorf("listSteps() expected output to contain kdt.KubeClientSet.WaitFor, got %q", out.String())
+	}
+	if err := listSteps([]string{"unexpected"}, out); err == nil {
+		t.Error("listSteps() expected error for unexpected arguments")
+	}
+}
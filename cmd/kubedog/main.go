@@ -0,0 +1,110 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+const usage = `kubedog is a companion tool for the kubedog godog steps.
+
+Usage:
+  kubedog <command> [flags]
+
+Commands:
+%s
+Run 'kubedog <command> -h' for the flags of a command.
+`
+
+type command struct {
+	description string
+	run         func(args []string, out io.Writer) error
+	// flags returns the flags of the command to complete them, nil if it has none
+	flags func(out io.Writer) *flag.FlagSet
+	// hidden commands are left out of the usage and the completion
+	hidden bool
+}
+
+var commands = map[string]command{}
+
+func init() {
+	commands["list-steps"] = command{
+		description: "print the syntax of the steps, filtered by keyword and category",
+		run:         listSteps,
+		flags: func(out io.Writer) *flag.FlagSet {
+			flags, _ := newListStepsFlags(out)
+			return flags
+		},
+	}
+	commands["run"] = command{
+		description: "run feature files against a cluster using the kubedog steps",
+		run:         runFeatures,
+		flags: func(out io.Writer) *flag.FlagSet {
+			flags, _ := newRunFlags(out)
+			return flags
+		},
+	}
+	commands["completion"] = command{
+		description: "print the shell completion script for " + strings.Join(shells, " or "),
+		run:         printCompletion,
+	}
+	commands[completeCommand] = command{
+		run:    complete,
+		hidden: true,
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage(os.Stderr)
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	if name == "-h" || name == "--help" || name == "help" {
+		printUsage(os.Stdout)
+		return
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command '%s'\n\n", name)
+		printUsage(os.Stderr)
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func printUsage(out io.Writer) {
+	names := []string{}
+	for name, cmd := range commands {
+		if cmd.hidden {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := &strings.Builder{}
+	for _, name := range names {
+		fmt.Fprintf(list, "  %-12s %s\n", name, commands[name].description)
+	}
+	fmt.Fprintf(out, usage, list.String())
+}
@@ -0,0 +1,144 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/keikoproj/kubedog"
+	"sigs.k8s.io/yaml"
+)
+
+var formats = []string{"pretty", "progress", "cucumber", "events", "junit"}
+
+type runOptions struct {
+	kubeconfig, awsProfile, awsEndpoint, valuesFile, filesPath, format, tags, stepUsage, namespacePrefix, ignoredFields string
+	strict, cleanup, validateSchema, auditMutations                                                                     bool
+}
+
+func newRunFlags(out io.Writer) (*flag.FlagSet, *runOptions) {
+	o := &runOptions{}
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.Usage = func() {
+		fmt.Fprintln(out, "Usage: kubedog run [flags] <feature files or directories>")
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&o.kubeconfig, "kubeconfig", "", "path to the kubeconfig, defaults to $KUBECONFIG or ~/.kube/config")
+	flags.StringVar(&o.awsProfile, "aws-profile", "", "AWS shared config profile used to discover the AWS clients, defaults to the default credential chain")
+	flags.StringVar(&o.awsEndpoint, "aws-endpoint", "", "endpoint used by all the AWS clients, e.g. http://localhost:4566 to target LocalStack")
+	flags.StringVar(&o.valuesFile, "values", "", "path to a YAML file whose values are used to render the templated resources, e.g. {{.Namespace}}")
+	flags.StringVar(&o.filesPath, "files", "", "path to the resource files referenced by the steps, defaults to templates")
+	flags.StringVar(&o.format, "format", "pretty", "godog output formatter: "+strings.Join(formats, ", "))
+	flags.StringVar(&o.tags, "tags", "", "only run the scenarios matching the tag expression, e.g. '@smoke && ~@slow'")
+	flags.BoolVar(&o.strict, "strict", true, "fail on undefined and pending steps")
+	flags.BoolVar(&o.validateSchema, "validate-schema", false, "validate the resource files against the OpenAPI schemas of the cluster before using them")
+	flags.BoolVar(&o.auditMutations, "audit-mutations", false, "write the create, update, patch and delete requests of each scenario to the artifacts directory")
+	flags.StringVar(&o.stepUsage, "step-usage", "", "directory to write step-usage.json and step-usage.md to, reporting how many times each step ran")
+	flags.StringVar(&o.namespacePrefix, "ephemeral-namespace", "", "create a namespace named after the prefix for each scenario, used as the Namespace value and deleted after the scenario")
+	flags.StringVar(&o.ignoredFields, "ignore-fields", "", "comma separated fields ignored when comparing resources to expected files, instead of status and the server populated metadata")
+	flags.BoolVar(&o.cleanup, "cleanup", false, "delete the resources in the files path before and after the run")
+	return flags, o
+}
+
+func runFeatures(args []string, out io.Writer) error {
+	flags, o := newRunFlags(out)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("expected at least one feature file or directory")
+	}
+
+	if o.kubeconfig != "" {
+		if err := os.Setenv("KUBECONFIG", o.kubeconfig); err != nil {
+			return err
+		}
+	}
+	values, err := loadValues(o.valuesFile)
+	if err != nil {
+		return err
+	}
+
+	kdt := &kubedog.Test{}
+	kdt.KubeClientSet.SetTemplateArguments(values)
+	if o.awsProfile != "" {
+		kdt.AwsClientSet.SetProfile(o.awsProfile)
+	}
+	if o.awsEndpoint != "" {
+		kdt.AwsClientSet.SetEndpoint(o.awsEndpoint)
+	}
+	if o.filesPath != "" {
+		kdt.KubeClientSet.SetFilesPath(o.filesPath)
+	}
+	kdt.KubeClientSet.SetSchemaValidation(o.validateSchema)
+	kdt.KubeClientSet.SetMutationAuditLog(o.auditMutations)
+	kdt.KubeClientSet.SetEphemeralNamespace(o.namespacePrefix)
+	if o.ignoredFields != "" {
+		kdt.KubeClientSet.SetIgnoredFields(strings.Split(o.ignoredFields, ","))
+	}
+	kdt.SetStepUsageReport(o.stepUsage)
+	status := godog.TestSuite{
+		Name: "kubedog",
+		TestSuiteInitializer: func(ctx *godog.TestSuiteContext) {
+			if o.cleanup {
+				ctx.BeforeSuite(func() { deleteAllTestResources(kdt, out) })
+				ctx.AfterSuite(func() { deleteAllTestResources(kdt, out) })
+			}
+			kdt.SetTestSuite(ctx)
+		},
+		// scenarios share the state of kdt, so they must not run concurrently
+		ScenarioInitializer: kdt.SetScenario,
+		Options: &godog.Options{
+			Format: o.format,
+			Tags:   o.tags,
+			Strict: o.strict,
+			Paths:  flags.Args(),
+			Output: out,
+		},
+	}.Run()
+	if status != 0 {
+		return fmt.Errorf("feature files failed with status %d", status)
+	}
+	return nil
+}
+
+// loadValues returns the values of a YAML file, or nil if path is empty
+func loadValues(path string) (map[string]interface{}, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed parsing values file '%s': %v", path, err)
+	}
+	return values, nil
+}
+
+func deleteAllTestResources(kdt *kubedog.Test, out io.Writer) {
+	if err := kdt.KubeClientSet.DeleteAllTestResources(); err != nil {
+		fmt.Fprintf(out, "Failed deleting the test resources: %v\n\n", err)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testFeature = `Feature: generic steps
+  Scenario: wait
+    Given the sh command is available
+    Then I wait for 0 seconds
+`
+
+const testUndefinedFeature = `Feature: undefined steps
+  Scenario: undefined
+    Given a step kubedog does not define
+`
+
+func TestRunFeatures(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		feature string
+		args    []string
+		wantErr bool
+	}{
+		{
+			name:    "passing feature",
+			feature: testFeature,
+		},
+		{
+			name:    "undefined step",
+			feature: testUndefinedFeature,
+			wantErr: true,
+		},
+		{
+			name:    "missing paths",
+			wantErr: true,
+		},
+		{
+			name:    "missing values file",
+			feature: testFeature,
+			args:    []string{"-values", filepath.Join(dir, "missing.yaml")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-format", "progress"}, tt.args...)
+			if tt.feature != "" {
+				path := filepath.Join(dir, tt.name+".feature")
+				if err := os.WriteFile(path, []byte(tt.feature), 0644); err != nil {
+					t.Fatal(err)
+				}
+				args = append(args, path)
+			}
+			out := &bytes.Buffer{}
+			if err := runFeatures(args, out); (err != nil) != tt.wantErr {
+				t.Errorf("runFeatures() error = %v, wantErr %v, output %s", err, tt.wantErr, out.String())
+			}
+		})
+	}
+}
+
+func TestLoadValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.yaml")
+	if err := os.WriteFile(path, []byte("Namespace: kubedog\nReplicas: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadValues(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"Namespace": "kubedog", "Replicas": float64(2)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadValues() = %v, want %v", got, want)
+	}
+	if got, err := loadValues(""); got != nil || err != nil {
+		t.Errorf("loadValues() = %v, %v, want nil values without a path", got, err)
+	}
+}
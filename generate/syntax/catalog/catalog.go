@@ -0,0 +1,361 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package catalog parses the steps defined in kubedog.go between the //syntax-generation:begin and //syntax-generation:end annotations.
+The comments right above a step can annotate it with:
+
+	//syntax-generation:description:<text>
+	//syntax-generation:example:<gherkin line>
+	//syntax-generation:deprecated:<replacement hint>
+*/
+package catalog
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	actionIndicator   = "//syntax-generation"
+	actionDelimiter   = ":"
+	actionBegin       = "begin"
+	actionEnd         = "end"
+	actionTitle       = "title"
+	actionDescription = "description"
+	actionExample     = "example"
+	actionDeprecated  = "deprecated"
+	actionCategory    = "category"
+	titleDelimiter    = "-"
+	stepReceiver      = "kdt.scenario"
+	stepFunction      = "Step"
+	stepPrefix        = "^"
+	stepSuffix        = "$"
+)
+
+// Title is a heading annotated with //syntax-generation:title-<rank>:<text>
+type Title struct {
+	Text string
+	Rank int
+	// Category is annotated right below the title with //syntax-generation:category:<tag>
+	Category string
+}
+
+// Step is a kdt.scenario.Step call
+type Step struct {
+	Regex       string   `json:"regex"`
+	Syntax      string   `json:"syntax,omitempty"`
+	Method      string   `json:"method"`
+	Description string   `json:"description,omitempty"`
+	Examples    []string `json:"examples,omitempty"`
+	// Deprecated is the hint to replace the step with, empty if the step is not deprecated
+	Deprecated string `json:"deprecated,omitempty"`
+	// Titles are the titles the step is under, from the highest ranked one
+	Titles []string `json:"titles,omitempty"`
+	// Category is the category tag of the closest title the step is under that has one
+	Category string `json:"category,omitempty"`
+}
+
+// Entry is either a title or a step, in the order they are found in the source
+type Entry struct {
+	Title *Title
+	Step  *Step
+}
+
+// Parse returns the titles and steps between the begin and end annotations of the Go source file
+func Parse(filename string, src interface{}) ([]Entry, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		begin, end    token.Pos
+		entries       []positionedEntry
+		comments      = map[int]*ast.Comment{}
+		lastTitleLine int
+	)
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			comments[fset.Position(comment.Pos()).Line] = comment
+			action, afterAction, ok := getAction(comment.Text)
+			if !ok {
+				continue
+			}
+			switch {
+			case action == actionDescription, action == actionExample, action == actionDeprecated:
+				if afterAction == "" {
+					return nil, errors.Errorf("expected '%s%s%s%s<text>' at %v", actionIndicator, actionDelimiter, action, actionDelimiter, fset.Position(comment.Pos()))
+				}
+			case action == actionCategory:
+				line := fset.Position(comment.Pos()).Line
+				if afterAction == "" || len(entries) == 0 || lastTitleLine != line-1 {
+					return nil, errors.Errorf("expected '%s%s%s%s<tag>' right below a title at %v", actionIndicator, actionDelimiter, action, actionDelimiter, fset.Position(comment.Pos()))
+				}
+				entries[len(entries)-1].entry.Title.Category = afterAction
+			case action == actionBegin:
+				begin = comment.Pos()
+			case action == actionEnd:
+				end = comment.Pos()
+			case strings.HasPrefix(action, actionTitle):
+				title, err := getTitle(action, afterAction)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid annotation at %v", fset.Position(comment.Pos()))
+				}
+				entries = append(entries, positionedEntry{pos: comment.Pos(), entry: Entry{Title: title}})
+				lastTitleLine = fset.Position(comment.Pos()).Line
+			default:
+				return nil, errors.Errorf("unsupported action '%s' at %v", action, fset.Position(comment.Pos()))
+			}
+		}
+	}
+	if !begin.IsValid() || !end.IsValid() || end < begin {
+		return nil, errors.Errorf("expected '%s%s%s' followed by '%s%s%s' in %v", actionIndicator, actionDelimiter, actionBegin, actionIndicator, actionDelimiter, actionEnd, filename)
+	}
+
+	var inspectErr error
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || inspectErr != nil || !isStepCall(call) {
+			return inspectErr == nil
+		}
+		step, err := getStep(call)
+		if err != nil {
+			inspectErr = errors.Wrapf(err, "invalid step at %v", fset.Position(call.Pos()))
+			return false
+		}
+		setAnnotations(step, comments, fset.Position(call.Pos()).Line)
+		entries = append(entries, positionedEntry{pos: call.Pos(), entry: Entry{Step: step}})
+		return true
+	})
+	if inspectErr != nil {
+		return nil, inspectErr
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].pos < entries[j].pos
+	})
+	result := []Entry{}
+	titles := []*Title{}
+	for _, e := range entries {
+		if e.pos <= begin || e.pos >= end {
+			continue
+		}
+		switch {
+		case e.entry.Title != nil:
+			if e.entry.Title.Rank < len(titles) {
+				titles = titles[:e.entry.Title.Rank]
+			}
+			titles = append(titles, e.entry.Title)
+		case e.entry.Step != nil:
+			for _, title := range titles {
+				e.entry.Step.Titles = append(e.entry.Step.Titles, title.Text)
+				if title.Category != "" {
+					e.entry.Step.Category = title.Category
+				}
+			}
+		}
+		result = append(result, e.entry)
+	}
+	return result, nil
+}
+
+// Steps returns the steps of the entries
+func Steps(entries []Entry) []Step {
+	steps := []Step{}
+	for _, e := range entries {
+		if e.Step != nil {
+			steps = append(steps, *e.Step)
+		}
+	}
+	return steps
+}
+
+type positionedEntry struct {
+	pos   token.Pos
+	entry Entry
+}
+
+func isStepCall(call *ast.CallExpr) bool {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && selector.Sel.Name == stepFunction && types.ExprString(selector.X) == stepReceiver
+}
+
+func getStep(call *ast.CallExpr) (*Step, error) {
+	if len(call.Args) != 2 {
+		return nil, errors.Errorf("expected '%s.%s(<regexp>, <method>)'", stepReceiver, stepFunction)
+	}
+	literal, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return nil, errors.Errorf("expected the regexp of '%s.%s' to be a string literal", stepReceiver, stepFunction)
+	}
+	regex, err := strconv.Unquote(literal.Value)
+	if err != nil {
+		return nil, err
+	}
+	return &Step{Regex: regex, Method: types.ExprString(call.Args[1])}, nil
+}
+
+// setAnnotations sets the description, examples and deprecation annotated in the consecutive comments ending on the line above line
+func setAnnotations(step *Step, comments map[int]*ast.Comment, line int) {
+	for l := line - 1; ; l-- {
+		comment, ok := comments[l]
+		if !ok {
+			return
+		}
+		action, afterAction, isAction := getAction(comment.Text)
+		if !isAction {
+			continue
+		}
+		switch action {
+		case actionDescription:
+			step.Description = afterAction
+		case actionExample:
+			step.Examples = append([]string{afterAction}, step.Examples...)
+		case actionDeprecated:
+			step.Deprecated = afterAction
+		default:
+			return
+		}
+	}
+}
+
+func getAction(comment string) (string, string, bool) {
+	if !strings.HasPrefix(comment, actionIndicator+actionDelimiter) {
+		return "", "", false
+	}
+	split := strings.SplitN(strings.TrimPrefix(comment, actionIndicator+actionDelimiter), actionDelimiter, 2)
+	if len(split) == 2 {
+		return split[0], split[1], true
+	}
+	return split[0], "", true
+}
+
+func getTitle(action, afterAction string) (*Title, error) {
+	actionSplit := strings.Split(action, titleDelimiter)
+	if len(actionSplit) != 2 || actionSplit[0] != actionTitle {
+		return nil, errors.Errorf("expected '%s' to meet format '%s%s<digit>'", action, actionTitle, titleDelimiter)
+	}
+	rank, err := strconv.Atoi(actionSplit[1])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed converting '%s' to integer", actionSplit[1])
+	}
+	if rank < 0 || rank > 9 {
+		return nil, errors.Errorf("expected '%d' to be a digit between 0 and 9", rank)
+	}
+	if afterAction == "" {
+		return nil, errors.Errorf("expected '%s%s%s%s<title>'", actionIndicator, actionDelimiter, action, actionDelimiter)
+	}
+	return &Title{Text: afterAction, Rank: rank}, nil
+}
+
+// Validate compiles the regexp of every step and returns an error for every invalid, duplicated or shadowed one.
+// godog runs the first step matching a text, so a step is shadowed when its text is also matched by a step defined before it.
+func Validate(steps []Step) []error {
+	var (
+		errs     []error
+		compiled = make([]*regexp.Regexp, len(steps))
+		seen     = map[string]string{}
+	)
+	for i, step := range steps {
+		if method, ok := seen[step.Regex]; ok {
+			errs = append(errs, errors.Errorf("step '%s' of '%s' is duplicated by '%s'", step.Regex, step.Method, method))
+			continue
+		}
+		seen[step.Regex] = step.Method
+		if !strings.HasPrefix(step.Regex, stepPrefix) || !strings.HasSuffix(step.Regex, stepSuffix) {
+			errs = append(errs, errors.Errorf("step '%s' of '%s' should begin with '%s' and end with '%s'", step.Regex, step.Method, stepPrefix, stepSuffix))
+		}
+		re, err := regexp.Compile(step.Regex)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "step '%s' of '%s' is not a valid regexp", step.Regex, step.Method))
+			continue
+		}
+		compiled[i] = re
+	}
+	for i, step := range steps {
+		if compiled[i] == nil {
+			continue
+		}
+		samples, err := getSamples(step.Regex)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed generating sample text for step '%s'", step.Regex))
+			continue
+		}
+		for _, example := range step.Examples {
+			text := trimGherkinKeyword(example)
+			if !compiled[i].MatchString(text) {
+				errs = append(errs, errors.Errorf("example '%s' of '%s' does not match '%s'", example, step.Method, step.Regex))
+				continue
+			}
+			samples = append(samples, text)
+		}
+		for j, other := range steps[:i] {
+			if compiled[j] == nil {
+				continue
+			}
+			for _, sample := range samples {
+				if compiled[j].MatchString(sample) {
+					errs = append(errs, errors.Errorf("step '%s' of '%s' is shadowed by '%s' of '%s', both match '%s'", step.Regex, step.Method, other.Regex, other.Method, sample))
+					break
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// ValidateHandler returns an error if handler can not be called by godog with the capture groups of regex
+func ValidateHandler(regex string, handler interface{}) error {
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return err
+	}
+	handlerType := reflect.TypeOf(handler)
+	if handlerType == nil || handlerType.Kind() != reflect.Func {
+		return errors.Errorf("expected the handler of '%s' to be a function but got '%T'", regex, handler)
+	}
+	params := []reflect.Type{}
+	for i := 0; i < handlerType.NumIn(); i++ {
+		params = append(params, handlerType.In(i))
+	}
+	if len(params) > 0 && params[0].Implements(contextType) {
+		params = params[1:]
+	}
+	if len(params) > 0 && isStepArgument(params[len(params)-1]) {
+		params = params[:len(params)-1]
+	}
+	if len(params) != re.NumSubexp() {
+		return errors.Errorf("expected the handler of '%s' to have %d parameters to match its capture groups but got %d", regex, re.NumSubexp(), len(params))
+	}
+	for i, param := range params {
+		if !isSupportedParameter(param) {
+			return errors.Errorf("parameter %d of the handler of '%s' has unsupported type '%s'", i, regex, param)
+		}
+	}
+	if handlerType.NumOut() > 2 {
+		return errors.Errorf("expected the handler of '%s' to return at most 2 values but got %d", regex, handlerType.NumOut())
+	}
+	return nil
+}
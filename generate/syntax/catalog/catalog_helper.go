@@ -0,0 +1,123 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+	"reflect"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+var (
+	contextType     = reflect.TypeOf((*context.Context)(nil)).Elem()
+	gherkinKeywords = []string{"Given", "When", "Then", "And", "But", "*"}
+)
+
+// trimGherkinKeyword returns the text of a step without its leading Gherkin keyword
+func trimGherkinKeyword(step string) string {
+	step = strings.TrimSpace(step)
+	for _, keyword := range gherkinKeywords {
+		if strings.HasPrefix(step, keyword+" ") {
+			return strings.TrimSpace(strings.TrimPrefix(step, keyword))
+		}
+	}
+	return step
+}
+
+// getSamples returns texts matched by regex, one skipping and one including its optional parts
+func getSamples(regex string) ([]string, error) {
+	re, err := syntax.Parse(regex, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	re = re.Simplify()
+	minimal, full := &strings.Builder{}, &strings.Builder{}
+	writeSample(minimal, re, false)
+	writeSample(full, re, true)
+	if minimal.String() == full.String() {
+		return []string{minimal.String()}, nil
+	}
+	return []string{minimal.String(), full.String()}, nil
+}
+
+func writeSample(b *strings.Builder, re *syntax.Regexp, optional bool) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		b.WriteRune(getSampleRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune('x')
+	case syntax.OpCapture, syntax.OpPlus:
+		writeSample(b, re.Sub[0], optional)
+	case syntax.OpStar, syntax.OpQuest:
+		if optional {
+			writeSample(b, re.Sub[0], optional)
+		}
+	case syntax.OpRepeat:
+		for i := 0; i < re.Min; i++ {
+			writeSample(b, re.Sub[0], optional)
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeSample(b, sub, optional)
+		}
+	case syntax.OpAlternate:
+		writeSample(b, re.Sub[0], optional)
+	}
+}
+
+// getSampleRune returns a readable rune of the character class given as ranges
+func getSampleRune(ranges []rune) rune {
+	for _, candidate := range []rune{'x', '0', 'X'} {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= candidate && candidate <= ranges[i+1] {
+				return candidate
+			}
+		}
+	}
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for r := ranges[i]; r <= ranges[i+1]; r++ {
+			if unicode.IsPrint(r) && !unicode.IsSpace(r) {
+				return r
+			}
+		}
+	}
+	return ranges[0]
+}
+
+// isStepArgument returns true for the *godog.DocString and *godog.Table parameters godog passes after the capture groups
+func isStepArgument(param reflect.Type) bool {
+	if param.Kind() != reflect.Ptr {
+		return false
+	}
+	switch param.Elem().String() {
+	case "messages.PickleDocString", "messages.PickleTable":
+		return true
+	}
+	return false
+}
+
+func isSupportedParameter(param reflect.Type) bool {
+	switch param.Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return param.Elem().Kind() == reflect.Uint8
+	}
+	return false
+}
@@ -0,0 +1,222 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+const testSource = `package kubedog
+
+func (kdt *Test) SetScenario(scenario *godog.ScenarioContext) {
+	kdt.scenario = scenario
+	kdt.scenario.Step(` + "`^ignored$`" + `, kdt.Ignored)
+	//syntax-generation:begin
+	//syntax-generation:title-0:Generic steps
+	//syntax-generation:category:generic
+	//syntax-generation:description:Pauses the scenario
+	//syntax-generation:example:And I wait for 30 seconds
+	//syntax-generation:example:And I wait 1 minutes
+	kdt.scenario.Step(` + "`^(?:I )?wait (?:for )?(\\d+) (minutes|seconds)$`" + `, common.WaitFor)
+	//syntax-generation:title-1:Kubernetes
+	//syntax-generation:deprecated:use the resource should be created step instead
+	kdt.scenario.Step("^(?:the )?resource (\\S+) should be (created|deleted)$", kdt.KubeClientSet.ResourceShouldBe)
+	//syntax-generation:end
+	kdt.scenario.Step(` + "`^also ignored$`" + `, kdt.AlsoIgnored)
+}
+`
+
+func TestParse(t *testing.T) {
+	got, err := Parse("kubedog.go", testSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{
+		{Title: &Title{Text: "Generic steps", Rank: 0, Category: "generic"}},
+		{Step: &Step{Regex: `^(?:I )?wait (?:for )?(\d+) (minutes|seconds)$`, Method: "common.WaitFor", Description: "Pauses the scenario", Examples: []string{"And I wait for 30 seconds", "And I wait 1 minutes"}, Titles: []string{"Generic steps"}, Category: "generic"}},
+		{Title: &Title{Text: "Kubernetes", Rank: 1}},
+		{Step: &Step{Regex: `^(?:the )?resource (\S+) should be (created|deleted)$`, Method: "kdt.KubeClientSet.ResourceShouldBe", Deprecated: "use the resource should be created step instead", Titles: []string{"Generic steps", "Kubernetes"}, Category: "generic"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %+v, want %+v", got, want)
+	}
+	if steps := Steps(got); len(steps) != 2 {
+		t.Errorf("Steps() expected 2 steps, got %d", len(steps))
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{
+			name: "missing annotations",
+			src:  "package kubedog\n",
+		},
+		{
+			name: "invalid title rank",
+			src:  "package kubedog\n//syntax-generation:begin\n//syntax-generation:title-a:Title\n//syntax-generation:end\n",
+		},
+		{
+			name: "missing title",
+			src:  "package kubedog\n//syntax-generation:begin\n//syntax-generation:title-1\n//syntax-generation:end\n",
+		},
+		{
+			name: "missing example",
+			src:  "package kubedog\n//syntax-generation:begin\n//syntax-generation:example\n//syntax-generation:end\n",
+		},
+		{
+			name: "category not below a title",
+			src:  "package kubedog\n//syntax-generation:begin\n//syntax-generation:category:generic\n//syntax-generation:end\n",
+		},
+		{
+			name: "unsupported action",
+			src:  "package kubedog\n//syntax-generation:begin\n//syntax-generation:other\n//syntax-generation:end\n",
+		},
+		{
+			name: "non literal regexp",
+			src:  "package kubedog\nfunc f() {\n//syntax-generation:begin\nkdt.scenario.Step(regex, kdt.Method)\n//syntax-generation:end\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse("kubedog.go", tt.src); err == nil {
+				t.Errorf("Parse() expected error")
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []Step
+		wantErr int
+	}{
+		{
+			name: "valid steps",
+			steps: []Step{
+				{Regex: `^(?:I )?get (?:the )?pods in namespace ([^"]*) with selector (\S+)$`, Method: "ListPodsWithSelector"},
+				{Regex: `^(?:I )?get (?:the )?pods in namespace ([^"]*)$`, Method: "ListPods"},
+			},
+		},
+		{
+			name: "shadowed step",
+			steps: []Step{
+				{Regex: `^(?:I )?get (?:the )?pods in namespace ([^"]*)$`, Method: "ListPods"},
+				{Regex: `^(?:I )?get (?:the )?pods in namespace ([^"]*) with selector (\S+)$`, Method: "ListPodsWithSelector"},
+			},
+			wantErr: 1,
+		},
+		{
+			name: "example not matching",
+			steps: []Step{
+				{Regex: `^(?:I )?wait (\d+) seconds$`, Method: "Wait", Examples: []string{"And I wait 1 minutes"}},
+			},
+			wantErr: 1,
+		},
+		{
+			name: "example shadowed",
+			steps: []Step{
+				{Regex: `^(?:I )?wait (\d+) minutes or more$`, Method: "WaitMinutes"},
+				{Regex: `^(?:I )?wait (\d+) (seconds|minutes) or more$`, Method: "Wait", Examples: []string{"When I wait 1 minutes or more"}},
+			},
+			wantErr: 1,
+		},
+		{
+			name: "duplicated step",
+			steps: []Step{
+				{Regex: `^(?:I )?wait (\d+) seconds$`, Method: "Wait"},
+				{Regex: `^(?:I )?wait (\d+) seconds$`, Method: "WaitAgain"},
+			},
+			wantErr: 1,
+		},
+		{
+			name: "invalid regexp",
+			steps: []Step{
+				{Regex: `^(?:I )?wait (\d+ seconds$`, Method: "Wait"},
+			},
+			wantErr: 1,
+		},
+		{
+			name: "unanchored regexp",
+			steps: []Step{
+				{Regex: `(?:I )?wait (\d+) seconds`, Method: "Wait"},
+			},
+			wantErr: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if errs := Validate(tt.steps); len(errs) != tt.wantErr {
+				t.Errorf("Validate() errors = %v, wantErr %d", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		regex   string
+		handler interface{}
+		wantErr bool
+	}{
+		{
+			name:    "matching parameters",
+			regex:   `^(?:I )?wait (\d+) (minutes|seconds)$`,
+			handler: func(duration int, unit string) error { return nil },
+		},
+		{
+			name:    "context parameter",
+			regex:   `^(?:I )?wait (\d+) (minutes|seconds)$`,
+			handler: func(ctx context.Context, duration int, unit string) error { return nil },
+		},
+		{
+			name:    "missing parameter",
+			regex:   `^(?:I )?wait (\d+) (minutes|seconds)$`,
+			handler: func(duration int) error { return nil },
+			wantErr: true,
+		},
+		{
+			name:    "unused capture group",
+			regex:   `^((?:a )?Kubernetes cluster)$`,
+			handler: func() error { return nil },
+			wantErr: true,
+		},
+		{
+			name:    "unsupported parameter",
+			regex:   `^(?:I )?wait (\d+) seconds$`,
+			handler: func(duration bool) error { return nil },
+			wantErr: true,
+		},
+		{
+			name:    "not a function",
+			regex:   `^(?:I )?wait (\d+) seconds$`,
+			handler: "wait",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateHandler(tt.regex, tt.handler); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
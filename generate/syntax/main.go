@@ -15,44 +15,55 @@ limitations under the License.
 package main
 
 import (
-	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
-	"strconv"
+	"path/filepath"
 	"strings"
+	"unicode"
 
+	"github.com/keikoproj/kubedog/generate/syntax/catalog"
 	"github.com/keikoproj/kubedog/generate/syntax/replace"
+	"github.com/keikoproj/kubedog/generate/syntax/snippets"
 	log "github.com/sirupsen/logrus"
 )
 
 const (
 	sourceFilePath           = "kubedog.go"
 	destinationFilePath      = "docs/syntax.md"
+	vscodeFilePath           = "docs/snippets/kubedog.code-snippets"
+	intellijFilePath         = "docs/snippets/kubedog.xml"
+	jsonFilePath             = "docs/syntax.json"
+	modeDocs                 = "docs"
+	modeVSCode               = "vscode"
+	modeIntelliJ             = "intellij"
+	modeJSON                 = "json"
+	modeValidate             = "validate"
 	newLine                  = "\n"
-	actionIndicator          = "//syntax-generation"
-	actionDelimiter          = ":"
-	actionBegin              = "begin"
-	actionEnd                = "end"
-	actionTitle              = "title"
-	titleDelimiter           = "-"
 	titleRankStep            = "#"
 	processedTitleBeginning  = "## "
 	processedStepBeginning   = "- "
-	stepIndicator            = "kdt.scenario.Step"
-	stepDelimiter            = "`"
+	processedDetailBeginning = "  - "
+	processedExampleLabel    = "Example: "
+	processedDeprecatedLabel = "Deprecated: "
+	markdownStrikethrough    = "~~"
+	contentsTitle            = "Contents"
+	contentsIndentation      = "  "
+	anchorFormat             = `<a name="%s"></a>`
 	stepPrefix               = "^"
 	stepSuffix               = "$"
-	methodPrefix             = ","
-	methodSuffix             = ")"
 	markdownCodeDelimiter    = "`"
 	gherkinKeyword           = "<GK>"
-	destinationFileBeginning = "# Syntax" + newLine + "Below you will find the step syntax next to the name of the method it utilizes. Here GK stands for [Gherkin](https://cucumber.io/docs/gherkin/reference/#keywords) Keyword and words in brackets ([]) are optional:" + newLine
+	destinationFileBeginning = "# Syntax" + newLine + "Below you will find the step syntax next to the name of the method it utilizes. Here GK stands for [Gherkin](https://cucumber.io/docs/gherkin/reference/#keywords) Keyword and words in brackets ([]) are optional. Sections are tagged with the category of their steps, e.g. `pod`, which `kubedog list-steps -category` filters on:" + newLine
 )
 
 var replacements = replace.Replacements{
 	{Replacee: `(\d+)`, Replacer: `<digits>`},
 	{Replacee: `(\S+)`, Replacer: `<non-whitespace-characters>`},
 	{Replacee: `([^"]*)`, Replacer: `<any-characters-except-(")>`},
+	{Replacee: `([^']*)`, Replacer: `<any-characters-except-(')>`},
 }
 
 var bracketsReplacements = replace.BracketsReplacements{
@@ -83,33 +94,77 @@ var bracketsReplacements = replace.BracketsReplacements{
 }
 
 func main() {
-	sourceFile, err := os.Open(sourceFilePath)
+	mode := flag.String("mode", modeDocs, fmt.Sprintf("what to generate from the steps: '%s', a '%s' catalog, '%s' snippets or '%s' live templates, or '%s' to only validate them", modeDocs, modeJSON, modeVSCode, modeIntelliJ, modeValidate))
+	flag.Parse()
+
+	entries, err := catalog.Parse(sourceFilePath, nil)
 	if err != nil {
-		log.Error(err)
+		log.Fatal(err)
 	}
-	defer sourceFile.Close()
-	fileScanner := bufio.NewScanner(sourceFile)
-	fileScanner.Split(bufio.ScanLines)
-	rawSyntax := []string{}
-	for fileScanner.Scan() {
-		line := fileScanner.Text()
-		if isBeginAction(line) {
-			log.Debugf("found begin action as '%s'", line)
-			for fileScanner.Scan() {
-				line := fileScanner.Text()
-				if isEndAction(line) {
-					log.Debugf("found end action as '%s'", line)
-					break
-				}
-				rawSyntax = append(rawSyntax, line)
-			}
-			break
+	log.Infof("found %d entries to process in '%s'", len(entries), sourceFilePath)
+	switch *mode {
+	case modeDocs:
+		processedSyntax := processSyntax(entries)
+		createSyntaxDocumentation(processedSyntax)
+	case modeJSON:
+		steps := catalog.Steps(entries)
+		for i := range steps {
+			steps[i].Syntax = getSyntax(steps[i])
+		}
+		out := &bytes.Buffer{}
+		encoder := json.NewEncoder(out)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(steps); err != nil {
+			log.Fatal(err)
+		}
+		writeFile(jsonFilePath, out.Bytes())
+	case modeVSCode:
+		out, err := snippets.VSCode(getSteps(entries))
+		if err != nil {
+			log.Fatal(err)
+		}
+		writeFile(vscodeFilePath, out)
+	case modeIntelliJ:
+		out, err := snippets.IntelliJ(getSteps(entries))
+		if err != nil {
+			log.Fatal(err)
 		}
+		writeFile(intellijFilePath, out)
+	case modeValidate:
+		errs := catalog.Validate(catalog.Steps(entries))
+		for _, err := range errs {
+			log.Error(err)
+		}
+		if len(errs) > 0 {
+			log.Fatalf("found %d invalid steps in '%s'", len(errs), sourceFilePath)
+		}
+		log.Infof("all steps in '%s' are valid", sourceFilePath)
+	default:
+		log.Fatalf("unsupported mode '%s'", *mode)
+	}
+}
+
+// getSteps returns the steps to generate snippets for, leaving out the deprecated ones
+func getSteps(entries []catalog.Entry) []snippets.Step {
+	steps := []snippets.Step{}
+	for _, step := range catalog.Steps(entries) {
+		if step.Deprecated != "" {
+			continue
+		}
+		steps = append(steps, snippets.Step{Regex: step.Regex, Method: step.Method})
+	}
+	return steps
+}
+
+func writeFile(path string, content []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Fatal(err)
+	}
+	log.Infof("writing to '%s'", path)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		log.Fatal(err)
 	}
-	log.Infof("found raw syntax to process as:")
-	printStringSlice(rawSyntax)
-	processedSyntax := processSyntax(rawSyntax)
-	createSyntaxDocumentation(processedSyntax)
 }
 
 func createSyntaxDocumentation(processedSyntax []string) {
@@ -133,111 +188,82 @@ func createSyntaxDocumentation(processedSyntax []string) {
 	printFile(destinationFilePath)
 }
 
-func processSyntax(rawSyntax []string) []string {
+func processSyntax(entries []catalog.Entry) []string {
+	processedContents := []string{newLine + processedTitleBeginning + contentsTitle + newLine}
 	processedSyntax := []string{}
-	for _, rawLine := range rawSyntax {
+	anchors := map[string]int{}
+	for _, entry := range entries {
 		switch {
-		case strings.Contains(rawLine, actionIndicator):
-			title, titleRank := mustGetTitle(rawLine)
-			titleBeginning := getTitleProcessedRank(titleRank)
-			processedTitle := newLine + titleBeginning + title + newLine
-			log.Debugf("processed '%s' as: '%s'", rawLine, processedTitle)
+		case entry.Title != nil:
+			anchor := getAnchor(entry.Title.Text, anchors)
+			processedContents = append(processedContents, processContentsEntry(*entry.Title, anchor))
+			titleBeginning := getTitleProcessedRank(entry.Title.Rank)
+			processedTitle := newLine + titleBeginning + fmt.Sprintf(anchorFormat, anchor) + entry.Title.Text + newLine
+			log.Debugf("processed title '%s' as: '%s'", entry.Title.Text, processedTitle)
 			processedSyntax = append(processedSyntax, processedTitle)
-		case strings.Contains(rawLine, stepIndicator):
-			processedStep := processedStepBeginning + processStep(rawLine) + newLine
-			log.Debugf("processed '%s' as: '%s'", rawLine, processedStep)
+		case entry.Step != nil:
+			processedStep := processedStepBeginning + processStep(*entry.Step) + newLine
+			if entry.Step.Deprecated != "" {
+				processedStep += processedDetailBeginning + processedDeprecatedLabel + entry.Step.Deprecated + newLine
+			}
+			if entry.Step.Description != "" {
+				processedStep += processedDetailBeginning + entry.Step.Description + newLine
+			}
+			for _, example := range entry.Step.Examples {
+				processedStep += processedDetailBeginning + processedExampleLabel + markdownCodeDelimiter + example + markdownCodeDelimiter + newLine
+			}
+			log.Debugf("processed step '%s' as: '%s'", entry.Step.Regex, processedStep)
 			processedSyntax = append(processedSyntax, processedStep)
 		}
 	}
-	return processedSyntax
+	return append(processedContents, processedSyntax...)
 }
 
-func processStep(rawStep string) string {
-	if !strings.Contains(rawStep, stepIndicator) {
-		log.Fatalf("expected '%s' to contain '%s'", rawStep, stepIndicator)
-	}
-	rawStepSplit := strings.Split(rawStep, stepDelimiter)
-	if len(rawStepSplit) != 3 {
-		log.Fatalf("expected '%s' to meet format '%s(%s<regexp>%s, <method>)'", rawStep, stepIndicator, stepDelimiter, stepDelimiter)
+// processContentsEntry returns the line linking to a title in the table of contents
+func processContentsEntry(title catalog.Title, anchor string) string {
+	processedEntry := strings.Repeat(contentsIndentation, title.Rank) + processedStepBeginning + "[" + title.Text + "](#" + anchor + ")"
+	if title.Category != "" {
+		processedEntry += " " + markdownCodeDelimiter + title.Category + markdownCodeDelimiter
 	}
-	processedStep := rawStepSplit[1]
-	processedStep = strings.TrimPrefix(processedStep, stepPrefix)
-	processedStep = strings.TrimSuffix(processedStep, stepSuffix)
-	processedStep = replacements.Replace(processedStep)
-	processedStep = bracketsReplacements.Replace(processedStep)
-	method := rawStepSplit[2]
-	method = strings.TrimPrefix(method, methodPrefix)
-	method = strings.TrimSuffix(method, methodSuffix)
-	return markdownCodeDelimiter + gherkinKeyword + " " + processedStep + markdownCodeDelimiter + method
+	return processedEntry + newLine
 }
 
-func mustGetTitle(line string) (string, int) {
-	action, afterAction := getAction(line)
-	actionSplit := strings.Split(action, titleDelimiter)
-	if len(actionSplit) != 2 {
-		log.Fatalf("expected '%s' to meet format '%s%s<digit>'", action, actionTitle, titleDelimiter)
-	}
-	action, titleRankString := actionSplit[0], actionSplit[1]
-	if action != actionTitle {
-		log.Fatalf("expected '%s' to contain '%s%s%s'", line, actionIndicator, actionDelimiter, actionTitle)
-	}
-	titleRank, err := strconv.Atoi(titleRankString)
-	if err != nil {
-		log.Fatalf("failed converting '%s' to integer: '%v'", titleRankString, err)
-	}
-	if afterAction == "" {
-		log.Fatalf("expected '%s' to contain '%s%s%s%s<title>'", line, actionIndicator, actionDelimiter, actionTitle, actionDelimiter)
-	}
-	return afterAction, titleRank
-}
-
-func getTitleProcessedRank(rank int) string {
-	if rank < 0 || rank > 9 {
-		log.Fatalf("expected '%d' to be a digit between 1 and 9)", rank)
+// getAnchor returns a unique anchor for text, made of its lower case letters, digits and dashes
+func getAnchor(text string, anchors map[string]int) string {
+	anchor := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-':
+			return unicode.ToLower(r)
+		case unicode.IsSpace(r):
+			return '-'
+		}
+		return -1
+	}, text)
+	anchors[anchor]++
+	if count := anchors[anchor]; count > 1 {
+		return fmt.Sprintf("%s-%d", anchor, count-1)
 	}
-	totalRankString := strings.Repeat(titleRankStep, rank)
-	return totalRankString + processedTitleBeginning
-}
-
-func isEndAction(line string) bool {
-	return isAction(actionEnd, line)
+	return anchor
 }
 
-func isBeginAction(line string) bool {
-	return isAction(actionBegin, line)
-}
-
-func isAction(expectedAction, line string) bool {
-	if expectedAction == "" {
-		log.Fatal("expectedAction cant be empty")
+func processStep(step catalog.Step) string {
+	processedStep := markdownCodeDelimiter + gherkinKeyword + " " + getSyntax(step) + markdownCodeDelimiter
+	if step.Deprecated != "" {
+		processedStep = markdownStrikethrough + processedStep + markdownStrikethrough
 	}
-	action, _ := getAction(line)
-	return action == expectedAction
+	return processedStep + " " + step.Method
 }
 
-func getAction(line string) (string, string) {
-	if strings.Contains(line, actionIndicator) {
-		lineSplit := strings.Split(line, actionDelimiter)
-		if len(lineSplit) < 2 {
-			log.Fatalf("expected '%s' to contain at least 2 elements, the actionIndicator '%s' and an action separated by '%s' but got '%v'", line, actionIndicator, actionDelimiter, lineSplit)
-		}
-		action := lineSplit[1]
-		if len(lineSplit) > 2 {
-			afterAction := lineSplit[2]
-			if len(lineSplit) > 3 {
-				log.Warnf("'%s' had more than 3 elements delimited by '%s'. took action '%s' and afterAction '%s' and ignored the rest", line, actionDelimiter, action, afterAction)
-			}
-			return action, afterAction
-		}
-		return action, ""
-	}
-	return "", ""
+// getSyntax returns the human readable syntax of the regexp of a step
+func getSyntax(step catalog.Step) string {
+	syntax := strings.TrimPrefix(step.Regex, stepPrefix)
+	syntax = strings.TrimSuffix(syntax, stepSuffix)
+	syntax = replacements.Replace(syntax)
+	return bracketsReplacements.Replace(syntax)
 }
 
-func printStringSlice(slice []string) {
-	for _, s := range slice {
-		fmt.Println(s)
-	}
+func getTitleProcessedRank(rank int) string {
+	return strings.Repeat(titleRankStep, rank) + processedTitleBeginning
 }
 
 func printFile(path string) {
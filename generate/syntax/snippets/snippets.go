@@ -0,0 +1,205 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snippets
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+const (
+	abbreviationPrefix = "kd-"
+	templateGroup      = "kubedog"
+)
+
+type Step struct {
+	Regex  string
+	Method string
+}
+
+type part struct {
+	text    string
+	isArg   bool
+	name    string
+	choices []string
+}
+
+type vscodeSnippet struct {
+	Prefix      string `json:"prefix"`
+	Body        string `json:"body"`
+	Description string `json:"description"`
+}
+
+type intellijTemplateSet struct {
+	XMLName   xml.Name           `xml:"templateSet"`
+	Group     string             `xml:"group,attr"`
+	Templates []intellijTemplate `xml:"template"`
+}
+
+type intellijTemplate struct {
+	Name             string             `xml:"name,attr"`
+	Value            string             `xml:"value,attr"`
+	Description      string             `xml:"description,attr"`
+	ToReformat       bool               `xml:"toReformat,attr"`
+	ToShortenFQNames bool               `xml:"toShortenFQNames,attr"`
+	Variables        []intellijVariable `xml:"variable"`
+	Context          intellijContext    `xml:"context"`
+}
+
+type intellijVariable struct {
+	Name         string `xml:"name,attr"`
+	Expression   string `xml:"expression,attr"`
+	DefaultValue string `xml:"defaultValue,attr"`
+	AlwaysStopAt bool   `xml:"alwaysStopAt,attr"`
+}
+
+type intellijContext struct {
+	Options []intellijOption `xml:"option"`
+}
+
+type intellijOption struct {
+	Name  string `xml:"name,attr"`
+	Value bool   `xml:"value,attr"`
+}
+
+// VSCode returns a VSCode snippets file with one snippet per step, triggered by 'kd-<method>'
+func VSCode(steps []Step) ([]byte, error) {
+	snippets := map[string]vscodeSnippet{}
+	for _, step := range steps {
+		parts := parse(step.Regex)
+		snippets[preview(parts)] = vscodeSnippet{
+			Prefix:      abbreviation(step.Method),
+			Body:        vscodeBody(parts),
+			Description: step.Method,
+		}
+	}
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snippets); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// IntelliJ returns an IntelliJ live templates file with one template per step, abbreviated as 'kd-<method>'
+func IntelliJ(steps []Step) ([]byte, error) {
+	templateSet := intellijTemplateSet{Group: templateGroup}
+	abbreviations := map[string]int{}
+	for _, step := range steps {
+		parts := parse(step.Regex)
+		name := abbreviation(step.Method)
+		// live template abbreviations must be unique, steps sharing a method are numbered
+		abbreviations[name]++
+		if count := abbreviations[name]; count > 1 {
+			name = fmt.Sprintf("%s-%d", name, count)
+		}
+		value, variables := intellijBody(parts)
+		templateSet.Templates = append(templateSet.Templates, intellijTemplate{
+			Name:             name,
+			Value:            value,
+			Description:      step.Method,
+			ToShortenFQNames: true,
+			Variables:        variables,
+			Context: intellijContext{
+				Options: []intellijOption{{Name: "OTHER", Value: true}},
+			},
+		})
+	}
+	out, err := xml.MarshalIndent(templateSet, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+func abbreviation(method string) string {
+	return abbreviationPrefix + method[strings.LastIndex(method, ".")+1:]
+}
+
+func preview(parts []part) string {
+	var builder strings.Builder
+	for _, p := range parts {
+		if !p.isArg {
+			builder.WriteString(p.text)
+			continue
+		}
+		if len(p.choices) != 0 {
+			builder.WriteString("(" + strings.Join(p.choices, "|") + ")")
+			continue
+		}
+		builder.WriteString("<" + p.name + ">")
+	}
+	return builder.String()
+}
+
+func vscodeBody(parts []part) string {
+	var (
+		builder strings.Builder
+		index   int
+	)
+	escaper := strings.NewReplacer(`\`, `\\`, `$`, `\$`, `}`, `\}`)
+	choiceEscaper := strings.NewReplacer(`\`, `\\`, `,`, `\,`, `|`, `\|`)
+	for _, p := range parts {
+		if !p.isArg {
+			builder.WriteString(escaper.Replace(p.text))
+			continue
+		}
+		index++
+		if len(p.choices) != 0 {
+			choices := make([]string, 0, len(p.choices))
+			for _, c := range p.choices {
+				choices = append(choices, choiceEscaper.Replace(c))
+			}
+			builder.WriteString(fmt.Sprintf("${%d|%s|}", index, strings.Join(choices, ",")))
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("${%d:%s}", index, p.name))
+	}
+	return builder.String()
+}
+
+func intellijBody(parts []part) (string, []intellijVariable) {
+	var (
+		builder   strings.Builder
+		variables []intellijVariable
+	)
+	for _, p := range parts {
+		if !p.isArg {
+			builder.WriteString(strings.ReplaceAll(p.text, "$", "$$"))
+			continue
+		}
+		variable := intellijVariable{
+			Name:         fmt.Sprintf("ARG%d", len(variables)+1),
+			AlwaysStopAt: true,
+		}
+		if len(p.choices) != 0 {
+			quoted := make([]string, 0, len(p.choices))
+			for _, c := range p.choices {
+				quoted = append(quoted, fmt.Sprintf("%q", c))
+			}
+			variable.Expression = fmt.Sprintf("enum(%s)", strings.Join(quoted, ","))
+		} else {
+			variable.DefaultValue = fmt.Sprintf("%q", p.name)
+		}
+		variables = append(variables, variable)
+		builder.WriteString("$" + variable.Name + "$")
+	}
+	return builder.String(), variables
+}
@@ -0,0 +1,146 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snippets
+
+import "strings"
+
+const regexpMetaCharacters = `\()[]{}*+?.^$|`
+
+var placeholderNames = map[string]string{
+	`\d+`:   "number",
+	`\S+`:   "value",
+	`[^"]*`: "text",
+	`[^']*`: "text",
+}
+
+// parse splits a step regexp into literal text and arguments, optional groups are dropped
+// and only the first alternative of a required non-capturing group is kept.
+func parse(regex string) []part {
+	regex = strings.TrimSuffix(strings.TrimPrefix(regex, "^"), "$")
+	var (
+		parts   []part
+		literal strings.Builder
+	)
+	flush := func() {
+		if literal.Len() != 0 {
+			parts = append(parts, part{text: literal.String()})
+			literal.Reset()
+		}
+	}
+	for i := 0; i < len(regex); i++ {
+		switch c := regex[i]; c {
+		case '\\':
+			if i+1 < len(regex) {
+				i++
+				literal.WriteByte(regex[i])
+			}
+		case '(':
+			end := closingParenthesis(regex, i)
+			content := regex[i+1 : end]
+			optional := end+1 < len(regex) && regex[end+1] == '?'
+			if optional {
+				end++
+			}
+			if strings.HasPrefix(content, "?:") {
+				if !optional {
+					flush()
+					parts = append(parts, parse(alternatives(content[2:])[0])...)
+				}
+			} else {
+				flush()
+				parts = append(parts, newArgument(content))
+			}
+			i = end
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	flush()
+	return mergeLiterals(parts)
+}
+
+func newArgument(content string) part {
+	choices := alternatives(content)
+	isChoice := true
+	for _, choice := range choices {
+		if strings.ContainsAny(choice, regexpMetaCharacters) {
+			isChoice = false
+			break
+		}
+	}
+	if isChoice {
+		return part{isArg: true, choices: choices}
+	}
+	if name, ok := placeholderNames[content]; ok {
+		return part{isArg: true, name: name}
+	}
+	return part{isArg: true, name: "value"}
+}
+
+// closingParenthesis returns the index of the parenthesis closing the one at start, or the last index if unbalanced
+func closingParenthesis(regex string, start int) int {
+	depth := 0
+	for i := start; i < len(regex); i++ {
+		switch regex[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(regex) - 1
+}
+
+// alternatives splits the top level alternatives of a group
+func alternatives(content string) []string {
+	var (
+		result []string
+		depth  int
+		last   int
+	)
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '|':
+			if depth == 0 {
+				result = append(result, content[last:i])
+				last = i + 1
+			}
+		}
+	}
+	return append(result, content[last:])
+}
+
+func mergeLiterals(parts []part) []part {
+	var merged []part
+	for _, p := range parts {
+		if n := len(merged); n != 0 && !p.isArg && !merged[n-1].isArg {
+			merged[n-1].text += p.text
+			continue
+		}
+		merged = append(merged, p)
+	}
+	return merged
+}
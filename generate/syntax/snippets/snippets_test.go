@@ -0,0 +1,101 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snippets
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestVSCodeBody(t *testing.T) {
+	tests := []struct {
+		name  string
+		regex string
+		want  string
+	}{
+		{
+			name:  "optional groups are dropped",
+			regex: `^(?:I )?wait (?:for )?(\d+) (minutes|seconds)$`,
+			want:  `wait ${1:number} ${2|minutes,seconds|}`,
+		},
+		{
+			name:  "first alternative of required group",
+			regex: `^(?:the )?rollout (\S+) in (?:the )?namespace (\S+) (?:should be|is) (Healthy|Paused)$`,
+			want:  `rollout ${1:value} in namespace ${2:value} should be ${3|Healthy,Paused|}`,
+		},
+		{
+			name:  "escaped characters and quoted text",
+			regex: `^(?:the )?current Auto Scaling Group (?:is )?scaled to \(min, max\) = \((\d+), (\d+)\)$`,
+			want:  `current Auto Scaling Group scaled to (min, max) = (${1:number}, ${2:number})`,
+		},
+		{
+			name:  "nested groups",
+			regex: `^(?:the )?Prometheus query "([^"]*)" should return a value (<|<=) (-?\d+(?:\.\d+)?) within (\S+)$`,
+			want:  `Prometheus query "${1:text}" should return a value ${2|<,<=|} ${3:value} within ${4:value}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := vscodeBody(parse(tt.regex)); got != tt.want {
+				t.Errorf("vscodeBody() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVSCode(t *testing.T) {
+	steps := []Step{
+		{Regex: `^(?:I )?wait (?:for )?(\d+) (minutes|seconds)$`, Method: "kdt.KubeClientSet.Wait"},
+		{Regex: `^(?:the )?resource (\S+) should be (created|deleted)$`, Method: "kdt.KubeClientSet.ResourceShouldBe"},
+	}
+	out, err := VSCode(steps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snippets := map[string]vscodeSnippet{}
+	if err := json.Unmarshal(out, &snippets); err != nil {
+		t.Fatal(err)
+	}
+	snippet, ok := snippets["resource <value> should be (created|deleted)"]
+	if !ok {
+		t.Fatalf("VSCode() missing snippet, got %v", snippets)
+	}
+	if snippet.Prefix != "kd-ResourceShouldBe" || snippet.Body != "resource ${1:value} should be ${2|created,deleted|}" {
+		t.Errorf("VSCode() unexpected snippet %+v", snippet)
+	}
+}
+
+func TestIntelliJ(t *testing.T) {
+	steps := []Step{
+		{Regex: `^(?:I )?wait (?:for )?(\d+) (minutes|seconds)$`, Method: "kdt.KubeClientSet.Wait"},
+		{Regex: `^(?:I )?wait (\d+) (minutes|seconds)$`, Method: "kdt.KubeClientSet.Wait"},
+	}
+	out, err := IntelliJ(steps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`name="kd-Wait"`,
+		`name="kd-Wait-2"`,
+		`value="wait $ARG1$ $ARG2$"`,
+		`expression="enum(&#34;minutes&#34;,&#34;seconds&#34;)"`,
+		`defaultValue="&#34;number&#34;"`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("IntelliJ() expected output to contain %v, got %s", want, out)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testutil holds the helpers shared by the tests of the kube packages
+package testutil
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+)
+
+// NewFakeDynamicClient returns a fake dynamic client tracking the objects, the resource of each object is looked up by
+// its kind in resources. The GVR is set explicitly because the fake client does not pluralize every kind correctly
+func NewFakeDynamicClient(t *testing.T, resources map[string]schema.GroupVersionResource, objects ...*unstructured.Unstructured) *fakeDynamic.FakeDynamicClient {
+	t.Helper()
+	listKinds := make(map[schema.GroupVersionResource]string, len(resources))
+	for kind, gvr := range resources {
+		// must end in 'List': https://github.com/kubernetes/client-go/blob/1309f64d6648411b4a36a2f7fa84dd8df31884b6/dynamic/fake/simple.go#L92
+		listKinds[gvr] = kind + "List"
+	}
+	client := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	for _, object := range objects {
+		gvr, ok := resources[object.GetKind()]
+		if !ok {
+			t.Fatalf("no resource for kind '%s'", object.GetKind())
+		}
+		if err := client.Tracker().Create(gvr, object, object.GetNamespace()); err != nil {
+			t.Fatalf("failed to track %s '%s/%s': %v", object.GetKind(), object.GetNamespace(), object.GetName(), err)
+		}
+	}
+	return client
+}
+
+// GetHostAndPort returns the host and the port of the URL of a test server
+func GetHostAndPort(t *testing.T, rawURL string) (string, int) {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Hostname(), port
+}
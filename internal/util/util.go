@@ -20,6 +20,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
@@ -50,6 +51,7 @@ var (
 		"the object has been modified",
 		"an error on the server",
 	}
+	unsafeFileNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
 )
 
 type FuncToRetryWithReturn func() (interface{}, error)
@@ -167,3 +169,8 @@ func ExtractField(data any, path []string) (any, error) {
 
 	return nil, errors.New("field not found")
 }
+
+// SanitizeFileName replaces the characters that are not safe in a file name, like the slashes of a scenario name, with dashes
+func SanitizeFileName(name string) string {
+	return strings.Trim(unsafeFileNameCharacters.ReplaceAllString(name, "-"), "-")
+}
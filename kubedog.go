@@ -15,18 +15,34 @@ limitations under the License.
 package kubedog
 
 //go:generate go run generate/syntax/main.go
+//go:generate go run generate/syntax/main.go -mode json
+//go:generate go run generate/syntax/main.go -mode vscode
+//go:generate go run generate/syntax/main.go -mode intellij
 import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/cucumber/godog"
 	aws "github.com/keikoproj/kubedog/pkg/aws"
 	"github.com/keikoproj/kubedog/pkg/generic"
 	"github.com/keikoproj/kubedog/pkg/kube"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/structured"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 )
 
 type Test struct {
 	suite         *godog.TestSuiteContext
-	scenario      *godog.ScenarioContext
+	scenario      stepDefiner
 	KubeClientSet kube.ClientSet
 	AwsClientSet  aws.ClientSet
+	stepUsage     stepUsage
+	stepUsageDir  string
+	scenarioStart time.Time
 }
 
 /*
@@ -34,65 +50,507 @@ SetScenario sets the ScenarioContext and contains the steps definition, should b
 Check https://github.com/keikoproj/kubedog/blob/master/docs/syntax.md for steps syntax details.
 */
 func (kdt *Test) SetScenario(scenario *godog.ScenarioContext) {
+	scenario.StepContext().Before(warnDeprecatedStep)
+	scenario.StepContext().Before(kdt.recordStepUsage)
+	scenario.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		kdt.scenarioStart = time.Now()
+		kdt.KubeClientSet.SetContext(ctx)
+		kdt.KubeClientSet.ResetScenarioState()
+		kdt.AwsClientSet.SetContext(ctx)
+		if err := kdt.KubeClientSet.CreateEphemeralNamespace(); err != nil {
+			return ctx, err
+		}
+		return ctx, nil
+	})
+	scenario.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		kdt.KubeClientSet.ExportMutationAuditLog(sc.Name)
+		if err != nil {
+			kdt.KubeClientSet.ExportEvents(sc.Name, kdt.scenarioStart)
+		}
+		if err := kdt.KubeClientSet.DeleteEphemeralNamespace(); err != nil {
+			log.Warnf("failed deleting the ephemeral namespace: %v", err)
+		}
+		// godog cancels the context of the scenario once it ends
+		kdt.KubeClientSet.SetContext(nil)
+		kdt.AwsClientSet.SetContext(nil)
+		return ctx, nil
+	})
+	kdt.setScenario(scenario)
+}
+
+// KubeContext returns the Kubernetes clients the steps use, set it to the result of kube.NewClientSet to inject clients
+func (kdt *Test) KubeContext() *kube.ClientSet {
+	return &kdt.KubeClientSet
+}
+
+// AwsContext returns the AWS clients the steps use
+func (kdt *Test) AwsContext() *aws.ClientSet {
+	return &kdt.AwsClientSet
+}
+
+// stepDefiner is the part of godog.ScenarioContext used to define the steps
+type stepDefiner interface {
+	Step(expr, stepFunc interface{})
+}
+
+func (kdt *Test) setScenario(scenario stepDefiner) {
 	kdt.scenario = scenario
 	//syntax-generation:begin
 	//syntax-generation:title-0:Generic steps
-	kdt.scenario.Step(`^(?:I )?wait (?:for )?(\d+) (minutes|seconds)$`, generic.WaitFor)
+	//syntax-generation:category:generic
+	//syntax-generation:description:Pauses the scenario for the given amount of time, logging the progress every 30 seconds or every minute
+	//syntax-generation:example:And I wait for 30 seconds
+	kdt.scenario.Step(`^(?:I )?wait (?:for )?(\d+) (minutes|seconds)$`, kdt.KubeClientSet.WaitFor)
+	//syntax-generation:description:Validates the command can be found in the PATH
+	//syntax-generation:example:Given the kubectl command is available
 	kdt.scenario.Step(`^the (\S+) command is available$`, generic.CommandExists)
+	//syntax-generation:description:Runs the command with the space separated args and validates its exit status
+	//syntax-generation:example:Then I run the kubectl command with the get nodes args and the command succeeds
 	kdt.scenario.Step(`^I run the (\S+) command with the ([^"]*) args and the command (fails|succeeds)$`, generic.RunCommand)
 	//syntax-generation:title-0:Kubernetes steps
-	kdt.scenario.Step(`^((?:a )?Kubernetes cluster|(?:there are )?(?:valid )?Kubernetes Credentials)$`, kdt.KubeClientSet.DiscoverClients)
+	//syntax-generation:category:kube
+	//syntax-generation:description:Creates the Kubernetes clients from the KUBECONFIG environment variable or the default kubeconfig
+	//syntax-generation:example:Given a Kubernetes cluster
+	kdt.scenario.Step(`^(?:(?:a )?Kubernetes cluster|(?:there are )?(?:valid )?Kubernetes Credentials)$`, kdt.KubeClientSet.DiscoverClients)
 	kdt.scenario.Step(`^(?:the )?Kubernetes cluster should be (created|deleted|upgraded)$`, kdt.KubeClientSet.KubernetesClusterShouldBe)
+	//syntax-generation:example:When I store the current time as upgrade-start
 	kdt.scenario.Step(`^(?:I )?store (?:the )?current time as ([^"]*)$`, kdt.KubeClientSet.SetTimestamp)
+	//syntax-generation:description:Validates the time passed since the stored time, to assert SLOs like a rollout completing within a few minutes
+	//syntax-generation:example:Then less than 5 minutes should have passed since "upgrade-start"
+	kdt.scenario.Step(`^(less|more) than (\d+) (minutes|seconds) should have passed since "([^"]*)"$`, kdt.KubeClientSet.TimeSinceTimestampShouldBe)
 	//syntax-generation:title-1:Unstructured Resources
+	//syntax-generation:category:unstructured
+	//syntax-generation:description:Runs the operation on the resource defined in the file under the files path, templates by default
+	//syntax-generation:example:When I create the resource deployment.yaml
+	//syntax-generation:example:And I delete resource deployment.yaml
 	kdt.scenario.Step(`^(?:I )?(create|submit|delete|update|upsert) (?:the )?resource (\S+)$`, kdt.KubeClientSet.ResourceOperation)
+	//syntax-generation:example:When I create the resource deployment.yaml in the default namespace
 	kdt.scenario.Step(`^(?:I )?(create|submit|delete|update|upsert) (?:the )?resource (\S+) in (?:the )?([^"]*) namespace$`, kdt.KubeClientSet.ResourceOperationInNamespace)
 	kdt.scenario.Step(`^(?:I )?(create|submit|delete|update|upsert) (?:the )?resources in (\S+)$`, kdt.KubeClientSet.ResourcesOperation)
+	//syntax-generation:description:Renders the kustomization directory under the files path in-process like 'kustomize build' and applies the operation to every rendered resource
+	//syntax-generation:example:When I create the resources from the kustomize overlay overlays/staging
+	kdt.scenario.Step(`^(?:I )?(create|submit|delete|update|upsert) (?:the )?resources from (?:the )?kustomize overlay (\S+)$`, kdt.KubeClientSet.KustomizeResourcesOperation)
 	kdt.scenario.Step(`^(?:I )?(create|submit|delete|update|upsert) (?:the )?resources in (\S+) in (?:the )?([^"]*) namespace$`, kdt.KubeClientSet.ResourcesOperationInNamespace)
+	//syntax-generation:description:Runs the operation on every resource in the file, then waits until each one is current like the resource should be current step
+	//syntax-generation:example:When I create the resources in app.yaml and wait for them to be ready
+	kdt.scenario.Step(`^(?:I )?(create|submit|update|upsert) (?:the )?resources in (\S+) and wait for (?:them to be )?ready$`, kdt.KubeClientSet.ResourcesOperationAndWaitReady)
+	//syntax-generation:example:When I create the resource invalid.yaml, the operation should fail
 	kdt.scenario.Step(`^(?:I )?(create|submit|delete|update|upsert) (?:the )?resource (\S+), the operation should (succeed|fail)$`, kdt.KubeClientSet.ResourceOperationWithResult)
 	kdt.scenario.Step(`^(?:I )?(create|submit|delete|update|upsert) (?:the )?resource (\S+) in (?:the )?([^"]*) namespace, the operation should (succeed|fail)$`, kdt.KubeClientSet.ResourceOperationWithResultInNamespace)
+	kdt.scenario.Step(`^(?:I )?(create|submit|update|upsert) (?:the )?resource (\S+), the operation should be denied with (?:the )?message '([^']*)'$`, kdt.KubeClientSet.ResourceOperationShouldBeDenied)
+	kdt.scenario.Step(`^(?:I )?(create|submit|update|upsert) (?:the )?resource (\S+) in (?:the )?(\S+) namespace, the operation should be denied with (?:the )?message '([^']*)'$`, kdt.KubeClientSet.ResourceOperationInNamespaceShouldBeDenied)
+	kdt.scenario.Step(`^(?:the )?dry run of (?:the )?resource (\S+) should (?:be mutated to )?have (?:the )?field (\S+)$`, kdt.KubeClientSet.ResourceDryRunShouldHaveField)
+	kdt.scenario.Step(`^(?:the )?dry run of (?:the )?resource (\S+) should be denied with (?:the )?message '([^']*)'$`, kdt.KubeClientSet.ResourceDryRunShouldBeDenied)
 	kdt.scenario.Step(`^(?:the )?resource ([^"]*) should be (created|deleted)$`, kdt.KubeClientSet.ResourceShouldBe)
+	//syntax-generation:description:Waits until the field at the path of the selector, separated by dots and with dots in field names escaped as '\.', has the value in '<key>=<value>', exists in '<key>' or is absent in '!<key>'
+	//syntax-generation:example:Then the resource deployment.yaml should converge to selector .metadata.labels.app=web
+	//syntax-generation:example:Then the resource widget.yaml should converge to selector .metadata.annotations.example\.com/ready
+	//syntax-generation:example:Then the resource widget.yaml should converge to selector !.metadata.finalizers
 	kdt.scenario.Step(`^(?:the )?resource (\S+) (?:should )?converge to selector (\S+)$`, kdt.KubeClientSet.ResourceShouldConvergeToSelector)
 	kdt.scenario.Step(`^(?:the )?resource (\S+) (?:should )?converge to field (\S+)$`, kdt.KubeClientSet.ResourceShouldConvergeToField)
+	//syntax-generation:description:Waits for every document of a multi-document YAML at the same time and reports each resource that did not get there
+	//syntax-generation:example:Then the resources in manifests.yaml should be created
+	kdt.scenario.Step(`^(?:the )?resources in (\S+) should be (created|deleted)$`, kdt.KubeClientSet.ResourcesShouldBe)
+	//syntax-generation:example:Then the resources in manifests.yaml should converge to selector .metadata.labels.app=web
+	kdt.scenario.Step(`^(?:the )?resources in (\S+) (?:should )?converge to selector (\S+)$`, kdt.KubeClientSet.ResourcesShouldConvergeToSelector)
+	//syntax-generation:description:Waits until the field is at least or at most the value, compared as quantities like 3 or 500Mi when both parse as one and as durations like 30s otherwise
+	//syntax-generation:example:Then the resource deployment.yaml field .status.readyReplicas should be at least 3
+	//syntax-generation:example:Then the resource widget.yaml field .status.lastSyncDuration should be at most 30s
+	kdt.scenario.Step(`^(?:the )?resource (\S+) field (\S+) should be (at least|at most) (\S+)$`, kdt.KubeClientSet.ResourceFieldShouldBe)
+	//syntax-generation:description:Waits until a value at the JSONPath of the resource satisfies the operator, one of ==, !=, >, >=, <, <=, contains or matches, where != needs every value to differ and matches takes a regexp
+	//syntax-generation:example:Then the resource deployment.yaml jsonpath .status.conditions[?(@.type=="Available")].status should == True
+	//syntax-generation:example:Then the resource deployment.yaml field .spec.template.spec.containers[*].image should match ^registry.internal/.*$
+	kdt.scenario.Step(`^(?:the )?resource (\S+) (?:field|jsonpath) (\S+) should (==|!=|>=|<=|>|<|contains|contain|matches|match) (\S+)$`, kdt.KubeClientSet.ResourceFieldShouldMatchJSONPath)
+	//syntax-generation:description:Waits until the resource has every field of the expected file, lists having the same items in order, ignoring status, managedFields, uid, resourceVersion, creationTimestamp and generation, or the fields set with -ignore-fields, and the optional comma separated fields
+	//syntax-generation:example:Then the resource deployment.yaml should match the expected state in deployment-expected.yaml
+	//syntax-generation:example:Then the resource deployment.yaml should match the expected state in deployment-expected.yaml ignoring metadata.annotations,spec.replicas
+	kdt.scenario.Step(`^(?:the )?resource (\S+) should match (?:the )?expected state (?:in|of) (\S+)(?: ignoring (\S+))?$`, kdt.KubeClientSet.ResourceShouldMatchExpected)
 	kdt.scenario.Step(`^(?:the )?resource ([^"]*) condition ([^"]*) should be ([^"]*)$`, kdt.KubeClientSet.ResourceConditionShouldBe)
+	//syntax-generation:description:Waits until the resource is current following the kstatus conventions: workloads are rolled out, Jobs are complete and any other kind has observed its generation without a false Ready or true Reconciling condition, failing early on a Stalled condition or a failed rollout
+	//syntax-generation:example:Then the resource widget.yaml should be current
+	kdt.scenario.Step(`^(?:the )?resource (\S+) (?:should be|is) current$`, kdt.KubeClientSet.ResourceShouldBeCurrent)
+	//syntax-generation:description:Waits until the resource exists and is current in the cluster of each comma separated kubeconfig context, reporting the status in every cluster that is not; the resource file is read with the discovery of the current cluster
+	//syntax-generation:example:Then the resource configmap.yaml should be current in the clusters us-east-1,us-west-2
+	kdt.scenario.Step(`^(?:the )?resource (\S+) (?:should be|is) current in (?:the )?clusters? (\S+)$`, kdt.KubeClientSet.ResourceShouldBeCurrentInClusters)
 	kdt.scenario.Step(`^(?:I )?update (?:the )?resource ([^"]*) with ([^"]*) set to ([^"]*)$`, kdt.KubeClientSet.UpdateResourceWithField)
+	//syntax-generation:description:Validates the field manager owns the field of the live resource according to its managedFields, a field that changes owners between checks points to a controller and a human fighting over it
+	//syntax-generation:example:Then the resource deployment.yaml field spec.replicas should be owned by the field manager my-operator
+	kdt.scenario.Step(`^(?:the )?resource (\S+) field (\S+) should be (?:owned|managed) by (?:the )?field manager (\S+)$`, kdt.KubeClientSet.ResourceFieldShouldBeOwnedBy)
+	//syntax-generation:description:Sets the field through the status subresource to simulate the status a controller writes, which updates of the resource ignore when its CRD enables the status subresource
+	//syntax-generation:example:When I update the status of the resource widget.yaml with .status.phase set to Failed
+	kdt.scenario.Step(`^(?:I )?update (?:the )?status of (?:the )?resource (\S+) with (\S+) set to (\S+)$`, kdt.KubeClientSet.UpdateResourceStatusWithField)
+	//syntax-generation:description:Fails with a report of the resources under the files path, and optionally of the cluster objects last applied, whose API is removed in the Kubernetes version
+	//syntax-generation:example:Then the resources should not use APIs removed in Kubernetes 1.25
+	//syntax-generation:example:Then the resources and cluster objects should not use any APIs removed in Kubernetes v1.29
+	kdt.scenario.Step(`^(?:the )?(resources|resources and cluster objects) should not use (?:any )?APIs removed in Kubernetes (\S+)$`, kdt.KubeClientSet.ResourcesShouldNotUseRemovedAPIs)
+	//syntax-generation:description:Waits until the field of the resource matches the same field of the source resource of the same type, ignoring the optional comma separated fields under it; useful for resources copied by replication controllers
+	//syntax-generation:example:Then the secret db-credentials in the namespace team-b should match db-credentials in the namespace team-a on the field data
+	//syntax-generation:example:Then the deployment web in the namespace blue should match web in the namespace green on the field spec ignoring replicas,template.metadata.annotations
+	kdt.scenario.Step(`^(?:the )?(\S+) (\S+) in (?:the )?namespace (\S+) should match (\S+) in (?:the )?namespace (\S+) on (?:the )?field (\S+)(?: ignoring (\S+))?$`, kdt.KubeClientSet.ResourceFieldShouldMatch)
+	//syntax-generation:description:Deletes the resource and waits until the resources of the comma separated kinds, in the apiVersion/Kind format, with an ownerReference to it are garbage collected
+	//syntax-generation:example:When I delete the resource deployment.yaml, its children of kinds apps/v1/ReplicaSet,v1/Pod should be garbage collected
+	kdt.scenario.Step(`^(?:I )?delete (?:the )?resource (\S+), its children of kinds? (\S+) should be garbage collected$`, kdt.KubeClientSet.DeleteResourceChildrenShouldBeGarbageCollected)
+	//syntax-generation:description:Waits until the CustomResourceDefinitions, webhook configurations, ClusterRoles and Namespaces in the file, or in the .yaml files of the directory, under the files path are deleted; any finalizers still blocking them are reported
+	//syntax-generation:example:Then the operator resources in my-operator should be uninstalled
+	kdt.scenario.Step(`^(?:the )?(?:operator )?resources in (\S+) should be (?:fully )?uninstalled$`, kdt.KubeClientSet.ResourcesShouldBeUninstalled)
 	kdt.scenario.Step(`^(?:I )?verify InstanceGroups (?:are )?in "ready" state$`, kdt.KubeClientSet.VerifyInstanceGroups)
+	kdt.scenario.Step(`^(?:I )?scale (?:the )?instancegroup (\S+) in (?:the )?namespace (\S+) to min (\d+) max (\d+)$`, kdt.KubeClientSet.ScaleInstanceGroup)
+	kdt.scenario.Step(`^(?:the )?instancegroup (\S+) in (?:the )?namespace (\S+) (?:should be|is) (\S+)$`, kdt.KubeClientSet.InstanceGroupShouldBe)
+	kdt.scenario.Step(`^(?:the )?instancegroup (\S+) in (?:the )?namespace (\S+) should have (?:the )?number of nodes matching (?:its )?min size$`, kdt.KubeClientSet.InstanceGroupNodesShouldMatchMinSize)
 	//syntax-generation:title-1:Structured Resources
+	//syntax-generation:category:structured
 	//syntax-generation:title-2:Pods
-	kdt.scenario.Step(`^(?:I )?get (?:the )?pods in namespace ([^"]*)$`, kdt.KubeClientSet.ListPods)
+	//syntax-generation:category:pod
 	kdt.scenario.Step(`^(?:I )?get (?:the )?pods in namespace ([^"]*) with selector (\S+)$`, kdt.KubeClientSet.ListPodsWithSelector)
+	kdt.scenario.Step(`^(?:I )?get (?:the )?pods in namespace ([^"]*)$`, kdt.KubeClientSet.ListPods)
 	kdt.scenario.Step(`^(?:the )?pods in namespace ([^"]*) with selector (\S+) have restart count less than (\d+)$`, kdt.KubeClientSet.PodsWithSelectorHaveRestartCountLessThan)
+	//syntax-generation:description:Stores the restart counts of the containers of the pods with the selector, and the current time, under the name
+	//syntax-generation:example:Given I store the restart counts of pods in namespace my-team with selector app=my-app as before-upgrade
+	kdt.scenario.Step(`^(?:I )?store (?:the )?restart counts of (?:the )?pods in namespace (\S+) with selector (\S+) as (\S+)$`, kdt.KubeClientSet.StoreRestartCounts)
+	//syntax-generation:description:Compares the restart counts stored under two names, or stored under a name with the current ones; the containers of new pods count all their restarts
+	//syntax-generation:example:Then the restart counts of pods should not increase since before-upgrade
+	//syntax-generation:example:Then the restart counts of pods should increase by at most 1 between before-upgrade and after-upgrade
+	kdt.scenario.Step(`^(?:the )?restart counts of (?:the )?pods should (not increase|increase by at most \d+) (?:between (\S+) and (\S+)|since (\S+))$`, kdt.KubeClientSet.RestartCountsShouldIncrease)
 	kdt.scenario.Step(`^(some|all) pods in namespace (\S+) with selector (\S+) have "([^"]*)" in logs since ([^"]*) time$`, kdt.KubeClientSet.SomeOrAllPodsInNamespaceWithSelectorHaveStringInLogsSinceTime)
 	kdt.scenario.Step(`^some pods in namespace (\S+) with selector (\S+) don't have "([^"]*)" in logs since ([^"]*) time$`, kdt.KubeClientSet.SomePodsInNamespaceWithSelectorDontHaveStringInLogsSinceTime)
 	kdt.scenario.Step(`^(?:the )?pods in namespace (\S+) with selector (\S+) have no errors in logs since ([^"]*) time$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime)
 	kdt.scenario.Step(`^(?:the )?pods in namespace (\S+) with selector (\S+) have some errors in logs since ([^"]*) time$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorHaveSomeErrorsInLogsSinceTime)
 	kdt.scenario.Step(`^(?:all )?(?:the )?(?:pod|pods) in (?:the )?namespace (\S+) with (?:the )?label selector (\S+) (?:should )?(?:converge to|have) (?:the )?field selector (\S+)$`, kdt.KubeClientSet.PodsInNamespaceWithLabelSelectorConvergeToFieldSelector)
 	kdt.scenario.Step(`^(?:the )?pods in namespace (\S+) with selector (\S+) should have labels (\S+)$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldHaveLabels)
+	kdt.scenario.Step(`^(?:the )?pods in namespace (\S+) with selector (\S+) should have (?:the )?(\S+) container(?: injected)?$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldHaveContainer)
+	kdt.scenario.Step(`^(?:the )?pods in namespace (\S+) with selector (\S+) should (?:be )?(?:scheduled|run) on Fargate$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldRunOnFargate)
+	//syntax-generation:description:Validates the pods are running with a limit of the extended resource, on nodes advertising enough allocatable capacity of it for their pods
+	//syntax-generation:example:Then the pods in namespace ml-training with selector app=trainer should be allocated the extended resource nvidia.com/gpu
+	kdt.scenario.Step(`^(?:the )?pods in namespace (\S+) with selector (\S+) should be allocated (?:the )?(?:extended )?resource (\S+)$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldBeAllocatedResource)
+	//syntax-generation:description:Deletes random pods matching the selector and stores how many of them were ready, to check the recovery with the step below
+	//syntax-generation:example:When I delete 2 random pods with selector app=my-app in namespace my-namespace
+	kdt.scenario.Step(`^(?:I )?delete (\d+) random pods? with selector (\S+) in (?:the )?namespace (\S+)$`, kdt.KubeClientSet.DeleteRandomPods)
+	//syntax-generation:example:Then the pods with selector app=my-app in namespace my-namespace should recover their ready count
+	kdt.scenario.Step(`^(?:the )?pods with selector (\S+) in (?:the )?namespace (\S+) should recover (?:their|the) ready count$`, kdt.KubeClientSet.PodsShouldRecoverReadyCount)
+	//syntax-generation:description:Deletes the namespace and waits for it to be gone, removing its finalizers if it is still terminating when the waiter times out
+	//syntax-generation:example:When I delete the namespace my-team
+	kdt.scenario.Step(`^(?:I )?delete (?:the )?namespace (\S+)$`, kdt.KubeClientSet.DeleteNamespace)
+	//syntax-generation:example:Given I create the namespace my-team
+	kdt.scenario.Step(`^(?:I )?create (?:the )?namespace (\S+)$`, kdt.KubeClientSet.CreateNamespace)
 	kdt.scenario.Step(`^(?:the )?pod (\S+) in namespace (\S+) should have labels (\S+)$`, kdt.KubeClientSet.PodInNamespaceShouldHaveLabels)
+	//syntax-generation:description:Waits for an event with the reason to be reported for the object, the events seen since a failed scenario started are also exported to the artifacts path
+	//syntax-generation:example:Then the events for deployment/my-app in namespace my-namespace should contain reason ScalingReplicaSet
+	kdt.scenario.Step(`^(?:the )?events for (\S+)/(\S+) in (?:the )?namespace (\S+) should contain reason (\S+)$`, kdt.KubeClientSet.EventsShouldContainReason)
+	//syntax-generation:example:Then events for pod/my-pod in the namespace my-namespace should not contain reason BackOff
+	kdt.scenario.Step(`^(?:the )?events for (\S+)/(\S+) in (?:the )?namespace (\S+) should not contain reason (\S+)$`, kdt.KubeClientSet.EventsShouldNotContainReason)
+	//syntax-generation:description:Runs the command in the container like kubectl exec and fails if it exits with a non-zero code, quote arguments with spaces using single quotes
+	//syntax-generation:example:When I exec "cat /etc/resolv.conf" in pod my-pod container app in namespace my-namespace
+	kdt.scenario.Step(`^(?:I )?exec "([^"]*)" in (?:the )?pod (\S+) container (\S+) in (?:the )?namespace (\S+)$`, kdt.KubeClientSet.ExecInPod)
+	//syntax-generation:description:Validates the stdout or stderr of the command contains the string, useful to check in-cluster DNS, mounted files and sidecars from inside a pod
+	//syntax-generation:example:Then I exec "nslookup kubernetes.default" in pod my-pod container app in namespace my-namespace and the output should contain "Address"
+	//syntax-generation:example:Then I exec "sh -c 'ls /etc/config'" in pod my-pod container app in namespace my-namespace and the output should contain "settings.yaml"
+	kdt.scenario.Step(`^(?:I )?exec "([^"]*)" in (?:the )?pod (\S+) container (\S+) in (?:the )?namespace (\S+) and the output should contain "([^"]*)"$`, kdt.KubeClientSet.ExecInPodOutputShouldContain)
 	//syntax-generation:title-2:Others
+	//syntax-generation:description:Runs the operation on the secret with a key per environment variable, from a comma separated list of names or globs like PREFIX_*; update adds the keys to the existing secret in a single update
+	//syntax-generation:example:When I create the secret db-credentials in namespace my-team from environment variable DB_USERNAME,DB_PASSWORD
+	//syntax-generation:example:When I update the secret app-config in namespace my-team from APP_*
 	kdt.scenario.Step(`^(?:I )?(create|submit|update) (?:the )?secret (\S+) in namespace (\S+) from (?:environment variable )?(\S+)$`, kdt.KubeClientSet.SecretOperationFromEnvironmentVariable)
+	//syntax-generation:description:Runs the operation on the secret with the content of the file under the files path as the value of the key, binary files like keystores are supported; update adds the key to the existing secret
+	//syntax-generation:example:When I create the secret webhook-tls in namespace my-team with the key tls.crt from the file certs/tls.crt
+	kdt.scenario.Step(`^(?:I )?(create|submit|update) (?:the )?secret (\S+) in namespace (\S+) with (?:the )?key (\S+) from (?:the )?file (\S+)$`, kdt.KubeClientSet.SecretOperationFromFile)
 	kdt.scenario.Step(`^(?:I )?delete (?:the )?secret (\S+) in namespace (\S+)$`, kdt.KubeClientSet.SecretDelete)
+	//syntax-generation:description:Runs the operation on the secret with a key per literal, from a comma separated list of key=value pairs like kubectl --from-literal; update adds the keys to the existing secret
+	//syntax-generation:example:When I create the secret db-credentials in namespace my-team from the literal username=admin,password=s3cr3t
+	kdt.scenario.Step(`^(?:I )?(create|submit|update) (?:the )?secret (\S+) in namespace (\S+) from (?:the )?(?:literal|literals) (\S+)$`, kdt.KubeClientSet.SecretOperationFromLiterals)
+	//syntax-generation:description:Runs the operation on the secret with a key per file under the files path, named after the file, from a comma separated list of files or directories like kubectl --from-file; update adds the keys to the existing secret
+	//syntax-generation:example:When I create the secret webhook-tls in namespace my-team from the files certs/tls.crt,certs/tls.key
+	//syntax-generation:example:When I update the secret app-config in namespace my-team from the directory config
+	kdt.scenario.Step(`^(?:I )?(create|submit|update) (?:the )?secret (\S+) in namespace (\S+) from (?:the )?(?:file|files|directory) (\S+)$`, kdt.KubeClientSet.SecretOperationFromFiles)
+	//syntax-generation:description:Validates the key of the secret has the value, the base64 value is decoded before comparing; values are not logged
+	//syntax-generation:example:Then the secret db-credentials in the namespace my-team should have the key username with the value "admin"
+	//syntax-generation:example:And the secret db-credentials in the namespace my-team should have the key password with the base64 value "czNjcjN0"
+	kdt.scenario.Step(`^(?:the )?secret (\S+) in (?:the )?namespace (\S+) should have (?:the )?key (\S+) with (?:the )?(value|base64 value) "([^"]*)"$`, kdt.KubeClientSet.SecretKeyShouldHaveValue)
 	kdt.scenario.Step(`^(\d+) node(?:s)? with selector (\S+) should be (found|ready)$`, kdt.KubeClientSet.NodesWithSelectorShouldBe)
+	//syntax-generation:description:Cordons, uncordons or drains the nodes with the selector; a drain cordons the node and evicts its pods other than DaemonSet and mirror pods, retrying evictions refused by a PodDisruptionBudget until the pods are deleted
+	//syntax-generation:example:When I cordon the nodes with selector node.kubernetes.io/instance-type=m5.large
+	//syntax-generation:example:And I drain the nodes with selector node.kubernetes.io/instance-type=m5.large
+	kdt.scenario.Step(`^(?:I )?(cordon|uncordon|drain) (?:the )?(?:node|nodes) with selector (\S+)$`, kdt.KubeClientSet.NodeOperationWithSelector)
+	//syntax-generation:description:Drains the nodes with the selector, evicting their pods with the grace period instead of the one of each pod
+	//syntax-generation:example:When I drain the nodes with selector instancegroup=upgrade-test with a grace period of 30 seconds
+	kdt.scenario.Step(`^(?:I )?drain (?:the )?(?:node|nodes) with selector (\S+) with (?:a )?grace period of (\d+) seconds$`, kdt.KubeClientSet.NodeDrainWithSelector)
+	//syntax-generation:description:Adds or removes the taint, formatted as key=value:effect or key:effect, on the nodes with the selector; a removed taint matches the key and effect
+	//syntax-generation:example:When I taint the nodes with selector instancegroup=gpu with dedicated=gpu:NoSchedule
+	//syntax-generation:example:And I untaint the nodes with selector instancegroup=gpu with dedicated:NoSchedule
+	kdt.scenario.Step(`^(?:I )?(taint|untaint) (?:the )?(?:node|nodes) with selector (\S+) with (\S+)$`, kdt.KubeClientSet.NodeTaintWithSelector)
+	//syntax-generation:description:Adds the label, formatted as key=value, or removes the label key from the nodes with the selector
+	//syntax-generation:example:When I label the nodes with selector instancegroup=gpu with accelerator=nvidia
+	//syntax-generation:example:And I unlabel the nodes with selector instancegroup=gpu with accelerator
+	kdt.scenario.Step(`^(?:I )?(label|unlabel) (?:the )?(?:node|nodes) with selector (\S+) with (\S+)$`, kdt.KubeClientSet.NodeLabelWithSelector)
+	//syntax-generation:description:Waits until every node with the selector has, or does not have, the taint formatted as key=value:effect or key:effect; the value is only compared when given
+	//syntax-generation:example:Then the nodes with selector instancegroup=gpu should have the taint dedicated=gpu:NoSchedule
+	//syntax-generation:example:And the nodes with selector instancegroup=gpu should not have the taint dedicated:NoExecute
+	kdt.scenario.Step(`^(?:the )?(?:node|nodes) with selector (\S+) should (have|not have) (?:the )?taint (\S+)$`, kdt.KubeClientSet.NodeHasTaint)
+	//syntax-generation:description:Waits until every node with the selector has, or does not have, the label formatted as key=value or key; the value is only compared when given
+	//syntax-generation:example:Then the nodes with selector instancegroup=gpu should have the label accelerator=nvidia
+	//syntax-generation:example:And the nodes with selector instancegroup=gpu should not have the label accelerator
+	kdt.scenario.Step(`^(?:the )?(?:node|nodes) with selector (\S+) should (have|not have) (?:the )?label (\S+)$`, kdt.KubeClientSet.NodeHasLabel)
 	kdt.scenario.Step(`^(?:the )?(deployment|hpa|horizontalpodautoscaler|service|pdb|poddisruptionbudget|sa|serviceaccount|configmap) ([^"]*) (is|is not) in namespace ([^"]*)$`, kdt.KubeClientSet.ResourceInNamespace)
+	//syntax-generation:description:Validates the used amount of the resource in the resource quota compares with a quantity or a percentage of its hard limit
+	//syntax-generation:example:Then the used requests.cpu of the resource quota compute in the namespace my-team should be < 80% of hard
+	//syntax-generation:example:And the used pods of the resource quota compute in the namespace my-team should be == 3
+	kdt.scenario.Step(`^(?:the )?used (\S+) of (?:the )?(?:resource quota|resourcequota) (\S+) in (?:the )?namespace (\S+) should be (<|<=|>|>=|==|!=) (\d+(?:\.\d+)?% of hard|\S+)$`, kdt.KubeClientSet.ResourceQuotaUsageShouldBe)
+	//syntax-generation:description:Creates a pod without resources with a server side dry run and validates the container defaults of the limit ranges in the namespace were applied to it
+	//syntax-generation:example:Then the limit range defaults in the namespace my-team should be applied to new pods
+	kdt.scenario.Step(`^(?:the )?(?:limit range|limitrange) defaults in (?:the )?namespace (\S+) should be applied to (?:new )?pods$`, kdt.KubeClientSet.LimitRangeDefaultsShouldBeApplied)
+	//syntax-generation:description:Creates a pod with the image from a private registry and the image pull secret, then validates the image is pulled and the container runs, or that the pull fails with ErrImagePull or ImagePullBackOff; the pod is deleted afterwards
+	//syntax-generation:example:Then a pod with the image registry.example.com/my-team/my-app:v1 and the image pull secret registry-creds in the namespace my-team should run
+	//syntax-generation:example:And a pod with the image registry.example.com/my-team/my-app:v1 and the image pull secret expired-creds in the namespace my-team should fail to pull
+	kdt.scenario.Step(`^(?:a )?pod with (?:the )?image (\S+) and (?:the )?image pull secret (\S+) in (?:the )?namespace (\S+) should (run|fail to pull)$`, kdt.KubeClientSet.ImagePullShouldBe)
 	kdt.scenario.Step(`^(?:I )?scale (?:the )?deployment ([^"]*) in namespace ([^"]*) to (\d+)$`, kdt.KubeClientSet.ScaleDeployment)
 	kdt.scenario.Step(`^(?:I )?validate Prometheus Statefulset ([^"]*) in namespace ([^"]*) has volumeClaimTemplates name ([^"]*)$`, kdt.KubeClientSet.ValidatePrometheusVolumeClaimTemplatesName)
 	kdt.scenario.Step(`^(?:I )?get (?:the )?nodes list$`, kdt.KubeClientSet.ListNodes)
+	//syntax-generation:description:Sets the partition of the rolling update strategy of the statefulset, only the pods with an ordinal greater than or equal to it are updated to a new revision
+	//syntax-generation:example:When I set the rolling update partition of the statefulset db in the namespace my-team to 2
+	kdt.scenario.Step(`^(?:I )?set (?:the )?rolling update partition of (?:the )?statefulset (\S+) in (?:the )?namespace (\S+) to (\d+)$`, kdt.KubeClientSet.SetStatefulSetPartition)
+	//syntax-generation:description:Waits until the pods of the statefulset with an ordinal greater than or equal to its partition are ready with the update revision, failing as soon as a pod below the partition is updated
+	//syntax-generation:example:Then only the pods of the statefulset db in the namespace my-team from its partition should be updated
+	kdt.scenario.Step(`^(?:only )?(?:the )?pods of (?:the )?statefulset (\S+) in (?:the )?namespace (\S+) from (?:its|the) partition should be updated$`, kdt.KubeClientSet.StatefulSetPartitionShouldBeUpdated)
+	//syntax-generation:description:Waits until the daemonset is rolled out: its latest generation is observed and every desired pod is scheduled, updated and available; on timeout the pod of each node is reported
+	//syntax-generation:example:Then the daemonset node-exporter is running in namespace monitoring
 	kdt.scenario.Step(`^(?:the )?daemonset ([^"]*) is running in namespace ([^"]*)$`, kdt.KubeClientSet.DaemonSetIsRunning)
 	kdt.scenario.Step(`^(?:the )?deployment ([^"]*) is running in namespace ([^"]*)$`, kdt.KubeClientSet.DeploymentIsRunning)
+	//syntax-generation:description:Validates the statefulset observed its latest generation, every pod is ready and the pods are updated to the update revision, only the pods from the partition of a partitioned rolling update
+	//syntax-generation:example:Then the statefulset kafka is running in namespace streaming
+	kdt.scenario.Step(`^(?:the )?statefulset ([^"]*) is running in namespace ([^"]*)$`, kdt.KubeClientSet.StatefulSetIsRunning)
+	//syntax-generation:description:Waits until the statefulset is rolled out like 'kubectl rollout status'
+	//syntax-generation:example:Then the statefulset kafka in the namespace streaming rollout should be complete
+	kdt.scenario.Step(`^(?:the )?statefulset (\S+) in (?:the )?namespace (\S+) (?:rollout )?should be (?:complete|rolled out)$`, kdt.KubeClientSet.StatefulSetRolloutComplete)
+	//syntax-generation:description:Waits until the container of the workload and of every pod that is not being deleted has the image, so an upgrade is verified to be rolled out rather than only ready
+	//syntax-generation:example:Then the deployment my-app in the namespace my-team should have the container app with the image registry.example.com/my-team/my-app:v1.2.3
+	kdt.scenario.Step(`^(?:the )?(deployment|daemonset|statefulset) (\S+) in (?:the )?namespace (\S+) should (?:have|run) (?:the )?container (\S+) with (?:the )?image (\S+)$`, kdt.KubeClientSet.WorkloadHasContainerImage)
+	//syntax-generation:description:Sets spec.paused of the deployment, pausing also records the replicasets it owns
+	//syntax-generation:example:When I pause the deployment my-app in the namespace my-team
+	kdt.scenario.Step(`^(?:I )?(pause|resume) (?:the )?deployment (\S+) in (?:the )?namespace (\S+)$`, kdt.KubeClientSet.DeploymentPauseOperation)
+	//syntax-generation:description:Validates the deployment or rollout owns no replicaset besides the ones recorded when it was paused
+	//syntax-generation:example:Then the deployment my-app in the namespace my-team should not create new replicasets while paused
+	kdt.scenario.Step(`^(?:the )?(deployment|rollout) (\S+) in (?:the )?namespace (\S+) should not (?:have )?create(?:d)? (?:any )?new replicasets while paused$`, kdt.KubeClientSet.ReplicaSetsShouldNotBeCreatedWhilePaused)
 	kdt.scenario.Step(`^(?:the )?data in (?:the )?ConfigMap "([^"]*)" in namespace "([^"]*)" has key "([^"]*)" with value "([^"]*)"$`, kdt.KubeClientSet.ConfigMapDataHasKeyAndValue)
+	//syntax-generation:description:Runs the operation on the configmap with a key per literal, from a comma separated list of key=value pairs like kubectl --from-literal; update adds the keys to the existing configmap
+	//syntax-generation:example:When I create the configmap app-config in namespace my-team from the literal log-level=debug,replicas=3
+	kdt.scenario.Step(`^(?:I )?(create|submit|update) (?:the )?configmap (\S+) in namespace (\S+) from (?:the )?(?:literal|literals) (\S+)$`, kdt.KubeClientSet.ConfigMapOperationFromLiterals)
+	//syntax-generation:description:Runs the operation on the configmap with a key per file under the files path, named after the file, from a comma separated list of files or directories like kubectl --from-file; files that are not valid UTF-8 are set as binary data
+	//syntax-generation:example:When I create the configmap app-config in namespace my-team from the file config/application.properties
+	//syntax-generation:example:When I update the configmap dashboards in namespace monitoring from the directory dashboards
+	kdt.scenario.Step(`^(?:I )?(create|submit|update) (?:the )?configmap (\S+) in namespace (\S+) from (?:the )?(?:file|files|directory) (\S+)$`, kdt.KubeClientSet.ConfigMapOperationFromFiles)
+	//syntax-generation:description:Deletes the configmap, a configmap that is not found is ignored
+	//syntax-generation:example:When I delete the configmap app-config in namespace my-team
+	kdt.scenario.Step(`^(?:I )?delete (?:the )?configmap (\S+) in namespace (\S+)$`, kdt.KubeClientSet.ConfigMapDelete)
+	//syntax-generation:description:Waits until the key of the configmap has the value, for operators that write their status into configmaps
+	//syntax-generation:example:Then the configmap cluster-autoscaler-status in the namespace kube-system should have the key health with the value "Healthy"
+	kdt.scenario.Step(`^(?:the )?configmap (\S+) in (?:the )?namespace (\S+) should have (?:the )?key (\S+) with (?:the )?value "([^"]*)"$`, kdt.KubeClientSet.ConfigMapKeyConvergesTo)
 	kdt.scenario.Step(`^(?:the )?persistentvolume ([^"]*) exists with status (Available|Bound|Released|Failed|Pending)$`, kdt.KubeClientSet.PersistentVolExists)
 	kdt.scenario.Step(`^(?:the )?persistentvolumeclaim ([^"]*) exists with status (Available|Bound|Released|Failed|Pending) in namespace ([^"]*)$`, kdt.KubeClientSet.PersistentVolClaimExists)
+	kdt.scenario.Step(`^(?:the )?(validating|mutating) webhook configuration (\S+) should be ready$`, kdt.KubeClientSet.WebhookConfigurationShouldBeReady)
+	kdt.scenario.Step(`^(?:the )?storageclass (\S+) should (?:dynamically )?provision a (?:writable )?volume in namespace (\S+)$`, kdt.KubeClientSet.StorageClassShouldProvisionVolume)
+	//syntax-generation:description:Reviews the access of the service account with a SubjectAccessReview, the service account is namespace/name or a name in the namespace, and the resource can have a group and subresource like deployments.apps or pods/log; without a namespace the access is cluster wide
+	//syntax-generation:example:Then the service account my-operator in namespace operators should be able to list deployments.apps in namespace default
+	//syntax-generation:example:And the service account operators/my-operator should not be able to delete nodes
+	kdt.scenario.Step(`^(?:the )?service ?account (\S+) (?:in (?:the )?namespace (\S+) )?(should|should not) be able to (\S+) (\S+)(?: in (?:the )?namespace (\S+))?$`, kdt.KubeClientSet.ServiceAccountShouldBeAbleTo)
 	kdt.scenario.Step(`^(?:the )?(clusterrole|clusterrolebinding) with name ([^"]*) should be found$`, kdt.KubeClientSet.ClusterRbacIsFound)
+	kdt.scenario.Step(`^(?:the )?ingress (\S+) in (?:the )?namespace (\S+) (?:is )?(?:available )?on port (\d+) and path (\S+) with method (\S+), headers "([^"]*)" and body '([^']*)' (?:should )?(?:return|returns) status (\d+)$`, kdt.KubeClientSet.IngressAvailableWithRequest)
+	kdt.scenario.Step(`^(?:the )?ingress (\S+) in (?:the )?namespace (\S+) on port (\d+) and path (\S+) (?:should )?(?:return|returns) (?:a )?(?:response )?body that (contains|matches) '([^']*)'$`, kdt.KubeClientSet.IngressResponseBodyShould)
+	kdt.scenario.Step(`^(?:the )?ingress (\S+) in (?:the )?namespace (\S+) on port (\d+) and path (\S+) (?:should )?(?:return|returns) (?:a )?(?:response )?body with json path (\S+) (?:set to|equal to) '([^']*)'$`, kdt.KubeClientSet.IngressResponseBodyJSONPathShouldBe)
 	kdt.scenario.Step(`^(?:the )?ingress (\S+) in (?:the )?namespace (\S+) (?:is )?(?:available )?on port (\d+) and path ([^"]*)$`, kdt.KubeClientSet.IngressAvailable)
 	kdt.scenario.Step(`^(?:I )?send (\d+) tps to ingress (\S+) in (?:the )?namespace (\S+) (?:available )?on port (\d+) and path ([^"]*) for (\d+) (minutes|seconds) expecting up to (\d+) error(?:s)?$`, kdt.KubeClientSet.SendTrafficToIngress)
+	//syntax-generation:description:Runs the command with sh in a Job with the image, exports its output to the artifacts directory and deletes the Job; the image must contain a shell
+	//syntax-generation:example:Then I run image busybox:1.36 with command "nslookup kubernetes.default" in namespace my-team and it should exit with code 0
+	kdt.scenario.Step(`^(?:I )?run (?:the )?image (\S+) with command "([^"]*)" in (?:the )?namespace (\S+) and it should exit with code (\d+)$`, kdt.KubeClientSet.RunJobShouldExitWithCode)
+	//syntax-generation:description:Waits for the Job to complete, the logs of its pods are exported to the artifacts directory when it fails or the waiter times out
+	//syntax-generation:example:Then the job db-migration in the namespace my-team should be completed
+	kdt.scenario.Step(`^(?:the )?job (\S+) in (?:the )?namespace (\S+) should (?:be completed|complete)$`, kdt.KubeClientSet.JobIsCompleted)
+	//syntax-generation:description:Waits for the Job to complete and validates the time between its start and completion
+	//syntax-generation:example:Then the job db-migration in the namespace my-team should succeed within 5 minutes
+	kdt.scenario.Step(`^(?:the )?job (\S+) in (?:the )?namespace (\S+) should succeed within (\d+) (minutes|seconds)$`, kdt.KubeClientSet.JobSucceededWithinDuration)
+	//syntax-generation:description:Waits for the CronJob to schedule a Job
+	//syntax-generation:example:Then the cronjob nightly-backup in the namespace my-team should have a last schedule time
+	kdt.scenario.Step(`^(?:the )?cronjob (\S+) in (?:the )?namespace (\S+) should have (?:a )?last schedule time$`, kdt.KubeClientSet.CronJobHasLastScheduleTime)
+	//syntax-generation:description:Creates a short lived pod in the source namespace that opens a TCP connection to the port of the service, then deletes it; useful to validate NetworkPolicies and meshes from within the cluster
+	//syntax-generation:example:Then the service my-app in the namespace my-team should be reachable on port 8080 from the namespace my-team
+	//syntax-generation:example:And the service my-app in the namespace my-team should not be reachable on port 8080 from the namespace other-team
+	kdt.scenario.Step(`^(?:the )?service (\S+) in (?:the )?namespace (\S+) (should|should not) be reachable on port (\d+) from (?:the )?namespace (\S+)$`, kdt.KubeClientSet.ServiceShouldBeReachable)
+	kdt.scenario.Step(`^(?:the )?service (\S+) in (?:the )?namespace (\S+) should resolve (?:to its ClusterIP )?from within the cluster$`, kdt.KubeClientSet.ServiceShouldResolveInCluster)
+	//syntax-generation:description:Waits until the EndpointSlices of the service, or its Endpoints when it has none, have at least the number of ready endpoints, so the service is known to route to pods
+	//syntax-generation:example:Then the service my-app in the namespace my-team should have at least 2 ready endpoints
+	kdt.scenario.Step(`^(?:the )?service (\S+) in (?:the )?namespace (\S+) should have (?:at least )?(\d+) ready (?:endpoint|endpoints)$`, kdt.KubeClientSet.ServiceHasEndpoints)
+	kdt.scenario.Step(`^(?:the )?service (\S+) in (?:the )?namespace (\S+) (?:is )?(?:available )?on port (\d+) and path (\S+)$`, kdt.KubeClientSet.ServiceAvailable)
+	kdt.scenario.Step(`^(?:the )?service (\S+) in (?:the )?namespace (\S+) (?:is )?(?:available )?on port (\d+) and path (\S+) with method (\S+), headers "([^"]*)" and body '([^']*)' (?:should )?(?:return|returns) status (\d+)$`, kdt.KubeClientSet.ServiceAvailableWithRequest)
+	//syntax-generation:description:Adds the header to the next HTTP request or traffic target, the header, body, timeout and TLS settings are cleared once the request is sent or the traffic target is added
+	//syntax-generation:example:Given I set the HTTP request header Authorization to "Bearer my-token"
+	kdt.scenario.Step(`^(?:I )?set (?:the )?HTTP (?:request )?header (\S+) to "([^"]*)"$`, kdt.KubeClientSet.SetHTTPRequestHeader)
+	//syntax-generation:description:Sets the body of the next HTTP request
+	//syntax-generation:example:Given I set the HTTP request body to '{"name": "my-app"}'
+	kdt.scenario.Step(`^(?:I )?set (?:the )?HTTP (?:request )?body to '([^']*)'$`, kdt.KubeClientSet.SetHTTPRequestBody)
+	//syntax-generation:description:Sets the timeout of each attempt of the next HTTP request, 10 seconds by default
+	//syntax-generation:example:Given I set the HTTP request timeout to 30 seconds
+	kdt.scenario.Step(`^(?:I )?set (?:the )?HTTP (?:request )?timeout to (\d+) (minutes|seconds)$`, kdt.KubeClientSet.SetHTTPRequestTimeout)
+	//syntax-generation:description:Skips verifying the TLS certificate of the endpoint of the next HTTP request, for self-signed certificates
+	//syntax-generation:example:Given I skip the TLS verification of the HTTP request
+	kdt.scenario.Step(`^(?:I )?skip (?:the )?TLS verification (?:of|for) (?:the )?HTTP request$`, kdt.KubeClientSet.SkipHTTPRequestTLSVerification)
+	//syntax-generation:description:Sends the HTTP request to the URL until it responds with any status code, the response is validated by the HTTP response steps
+	//syntax-generation:example:When I send a GET request to the URL https://my-app.example.com/healthz
+	kdt.scenario.Step(`^(?:I )?send (?:a |an )?(GET|HEAD|POST|PUT|PATCH|DELETE|OPTIONS) request to (?:the )?(?:URL|url) (\S+)$`, kdt.KubeClientSet.SendHTTPRequestToURL)
+	//syntax-generation:description:Sends the HTTP request to the load balancer of the ingress until it responds with any status code
+	//syntax-generation:example:When I send a POST request to the ingress my-app in the namespace my-team on port 443 and path /api/orders
+	kdt.scenario.Step(`^(?:I )?send (?:a |an )?(GET|HEAD|POST|PUT|PATCH|DELETE|OPTIONS) request to (?:the )?ingress (\S+) in (?:the )?namespace (\S+) on port (\d+) and path (\S+)$`, kdt.KubeClientSet.SendHTTPRequestToIngress)
+	//syntax-generation:description:Sends the HTTP request to the service through a port-forward to one of its ready pods until it responds with any status code
+	//syntax-generation:example:When I send a GET request to the service my-app in the namespace my-team on port 8080 and path /healthz
+	kdt.scenario.Step(`^(?:I )?send (?:a |an )?(GET|HEAD|POST|PUT|PATCH|DELETE|OPTIONS) request to (?:the )?service (\S+) in (?:the )?namespace (\S+) on port (\d+) and path (\S+)$`, kdt.KubeClientSet.SendHTTPRequestToService)
+	//syntax-generation:description:Validates the status code of the last HTTP response is the status code, the class or in the inclusive range; otherwise the request is sent again until it is
+	//syntax-generation:example:Then the HTTP response should have status 200
+	//syntax-generation:example:And the HTTP response should have status 2xx
+	//syntax-generation:example:And the HTTP response should have status 200-204
+	kdt.scenario.Step(`^(?:the )?HTTP response (?:should )?(?:have|has) (?:the )?status (?:code )?(\S+)$`, kdt.KubeClientSet.HTTPResponseStatusCodeShouldBe)
+	//syntax-generation:description:Validates the body of the last HTTP response contains the text or matches the regular expression; otherwise the request is sent again until it does
+	//syntax-generation:example:Then the HTTP response should have a body that contains 'ok'
+	kdt.scenario.Step(`^(?:the )?HTTP response (?:should )?(?:have|has) (?:a )?body that (contains|matches) '([^']*)'$`, kdt.KubeClientSet.HTTPResponseBodyShould)
+	//syntax-generation:description:Validates the json path of the body of the last HTTP response has the value; otherwise the request is sent again until it does
+	//syntax-generation:example:Then the HTTP response should have a body with json path .status set to 'UP'
+	kdt.scenario.Step(`^(?:the )?HTTP response (?:should )?(?:have|has) (?:a )?body with json path (\S+) (?:set to|equal to) '([^']*)'$`, kdt.KubeClientSet.HTTPResponseBodyJSONPathShouldBe)
+	kdt.scenario.Step(`^(?:I )?send (\d+) tps to service (\S+) in (?:the )?namespace (\S+) (?:available )?on port (\d+) and path (\S+) for (\d+) (minutes|seconds) expecting up to (\d+) error(?:s)?$`, kdt.KubeClientSet.SendTrafficToService)
+	kdt.scenario.Step(`^(?:the )?(p50|p90|p95|p99|mean|max) latency of (?:the )?traffic (?:test )?should be less than (\S+)$`, kdt.KubeClientSet.TrafficLatencyShouldBeLessThan)
+	kdt.scenario.Step(`^(?:the )?success ratio of (?:the )?traffic (?:test )?should be at least (\S+)$`, kdt.KubeClientSet.TrafficSuccessRatioShouldBeAtLeast)
+	kdt.scenario.Step(`^(?:at least )?(\d+)% of (?:the )?traffic (?:test )?(?:responses )?should have status code (\d+)$`, kdt.KubeClientSet.TrafficStatusCodePercentageShouldBeAtLeast)
+	//syntax-generation:description:Validates the rate of successful requests per second of the last traffic test, the json and html reports of each traffic test are exported to the artifacts path
+	//syntax-generation:example:Then the throughput of the traffic test should be at least 95 requests per second
+	kdt.scenario.Step(`^(?:the )?throughput of (?:the )?traffic (?:test )?should be at least (\S+) (?:requests per second|rps)$`, kdt.KubeClientSet.TrafficThroughputShouldBeAtLeast)
+	kdt.scenario.Step(`^(?:I )?add (?:the )?ingress (\S+) in (?:the )?namespace (\S+) on port (\d+) and path (\S+) as traffic target (\S+) with (\d+) tps$`, kdt.KubeClientSet.AddIngressTrafficTarget)
+	kdt.scenario.Step(`^(?:I )?add (?:the )?ingress (\S+) in (?:the )?namespace (\S+) on port (\d+) and path (\S+) as traffic target (\S+) with (\d+) tps and headers "([^"]*)"$`, kdt.KubeClientSet.AddIngressTrafficTargetWithHeaders)
+	//syntax-generation:description:Adds the ingress, or the service through a port-forward, as a traffic target sent requests with the method to the comma separated paths in proportion to their weights, the weight follows a colon and defaults to 1; the header, body, timeout and TLS settings of the HTTP request steps are used
+	//syntax-generation:example:When I add the ingress my-app in the namespace my-team on port 443 and paths /api/orders:3,/healthz:1 as traffic target orders with 20 tps of POST https requests
+	//syntax-generation:example:And I add the service my-app in the namespace my-team on port 8080 and path /api/orders as traffic target direct with 5 tps of GET http requests
+	kdt.scenario.Step(`^(?:I )?add (?:the )?(ingress|service) (\S+) in (?:the )?namespace (\S+) on port (\d+) and (?:path|paths) (\S+) as traffic target (\S+) with (\d+) tps of (GET|POST|PUT|PATCH|DELETE) (http|https) requests$`, kdt.KubeClientSet.AddTrafficTarget)
+	kdt.scenario.Step(`^(?:I )?send traffic to (?:all )?(?:the )?traffic targets for (\d+) (minutes|seconds) expecting up to (\d+) error(?:s)? per target$`, kdt.KubeClientSet.SendTrafficToTargets)
+	kdt.scenario.Step(`^(?:the )?(p50|p90|p95|p99|mean|max) latency of traffic target (\S+) should be less than (\S+)$`, kdt.KubeClientSet.TrafficTargetLatencyShouldBeLessThan)
+	kdt.scenario.Step(`^(?:the )?success ratio of traffic target (\S+) should be at least (\S+)$`, kdt.KubeClientSet.TrafficTargetSuccessRatioShouldBeAtLeast)
+	kdt.scenario.Step(`^(?:at least )?(\d+)% of traffic target (\S+) (?:responses )?should have status code (\d+)$`, kdt.KubeClientSet.TrafficTargetStatusCodePercentageShouldBeAtLeast)
+	//syntax-generation:description:Validates the rate of successful requests per second of the traffic target in the last traffic test sent to the targets
+	//syntax-generation:example:Then the throughput of traffic target canary should be at least 9.5 requests per second
+	kdt.scenario.Step(`^(?:the )?throughput of traffic target (\S+) should be at least (\S+) (?:requests per second|rps)$`, kdt.KubeClientSet.TrafficTargetThroughputShouldBeAtLeast)
+	//syntax-generation:title-1:Gateway API
+	//syntax-generation:category:gateway
+	kdt.scenario.Step(`^(?:the )?gateway (\S+) in (?:the )?namespace (\S+) (?:should be|is) programmed$`, kdt.KubeClientSet.GatewayShouldBeProgrammed)
+	kdt.scenario.Step(`^(?:the )?httproute (\S+) in (?:the )?namespace (\S+) (?:should be|is) accepted$`, kdt.KubeClientSet.HTTPRouteShouldBeAccepted)
+	kdt.scenario.Step(`^(?:the )?httproute (\S+) in (?:the )?namespace (\S+) (?:is )?(?:available )?on port (\d+) and path (\S+)$`, kdt.KubeClientSet.HTTPRouteAvailable)
+	kdt.scenario.Step(`^(?:I )?send (\d+) tps to httproute (\S+) in (?:the )?namespace (\S+) (?:available )?on port (\d+) and path (\S+) for (\d+) (minutes|seconds) expecting up to (\d+) error(?:s)?$`, kdt.KubeClientSet.SendTrafficToHTTPRoute)
+	//syntax-generation:title-1:Istio
+	//syntax-generation:category:istio
+	kdt.scenario.Step(`^(?:the )?virtualservice (\S+) in (?:the )?namespace (\S+) (?:is )?(?:available )?(?:through|via) (?:the )?ingress gateway (\S+) in (?:the )?namespace (\S+) on port (\d+) and path (\S+)$`, kdt.KubeClientSet.VirtualServiceAvailable)
+	kdt.scenario.Step(`^(?:I )?send (\d+) tps to virtualservice (\S+) in (?:the )?namespace (\S+) (?:through|via) (?:the )?ingress gateway (\S+) in (?:the )?namespace (\S+) on port (\d+) and path (\S+) for (\d+) (minutes|seconds) expecting up to (\d+) error(?:s)?$`, kdt.KubeClientSet.SendTrafficToVirtualService)
+	//syntax-generation:title-1:Argo Rollouts
+	//syntax-generation:category:rollout
+	kdt.scenario.Step(`^(?:the )?rollout (\S+) in (?:the )?namespace (\S+) (?:should be|is) (Healthy|Progressing|Paused|Degraded)$`, kdt.KubeClientSet.RolloutShouldBe)
+	//syntax-generation:description:Promotes, aborts, pauses or resumes the rollout, pausing also records the replicasets it owns
+	//syntax-generation:example:When I pause the rollout my-app in the namespace my-team
+	kdt.scenario.Step(`^(?:I )?(promote|abort|pause|resume) (?:the )?rollout (\S+) in (?:the )?namespace (\S+)$`, kdt.KubeClientSet.RolloutOperation)
+	//syntax-generation:title-1:Argo CD
+	//syntax-generation:category:argocd
+	kdt.scenario.Step(`^(?:the )?(?:argocd )?application (\S+) in (?:the )?namespace (\S+) (?:should be|is) (Synced|OutOfSync|Healthy|Progressing|Degraded|Suspended|Missing)$`, kdt.KubeClientSet.ApplicationShouldBe)
+	kdt.scenario.Step(`^(?:I )?sync (?:the )?(?:argocd )?application (\S+) in (?:the )?namespace (\S+)$`, kdt.KubeClientSet.SyncApplication)
+	//syntax-generation:title-1:Flux
+	//syntax-generation:category:flux
+	kdt.scenario.Step(`^(?:the )?flux (kustomization|helmrelease) (\S+) in (?:the )?namespace (\S+) (?:should be|is) ready(?: at revision (\S+))?$`, kdt.KubeClientSet.FluxResourceShouldBeReady)
+	kdt.scenario.Step(`^(?:I )?reconcile (?:the )?flux (kustomization|helmrelease) (\S+) in (?:the )?namespace (\S+)$`, kdt.KubeClientSet.FluxReconcile)
+	//syntax-generation:title-1:Helm
+	//syntax-generation:category:helm
+	kdt.scenario.Step(`^(?:the )?helm release (\S+) in (?:the )?namespace (\S+) (?:should be|is) deployed$`, kdt.KubeClientSet.HelmReleaseShouldBeDeployed)
+	kdt.scenario.Step(`^(?:the )?helm release (\S+) in (?:the )?namespace (\S+) (?:should be|is) deployed (?:with|at) chart version (\S+)$`, kdt.KubeClientSet.HelmReleaseShouldBeDeployedWithChartVersion)
+	kdt.scenario.Step(`^(?:the )?helm release (\S+) in (?:the )?namespace (\S+) (?:should be|is) deployed (?:with|at) revision (\d+)$`, kdt.KubeClientSet.HelmReleaseShouldBeDeployedWithRevision)
+	//syntax-generation:title-1:cert-manager
+	//syntax-generation:category:certmanager
+	kdt.scenario.Step(`^(?:the )?certificate (\S+) in (?:the )?namespace (\S+) (?:should be|is) ready$`, kdt.KubeClientSet.CertificateShouldBeReady)
+	kdt.scenario.Step(`^(?:the )?certificate (\S+) in (?:the )?namespace (\S+) (?:should )?(?:cover|covers) (?:the )?dns names (\S+)$`, kdt.KubeClientSet.CertificateSecretShouldCoverDNSNames)
+	kdt.scenario.Step(`^(?:the )?certificate (\S+) in (?:the )?namespace (\S+) (?:should be|is) valid for at least (\S+)$`, kdt.KubeClientSet.CertificateSecretShouldBeValidFor)
+	//syntax-generation:title-1:keikoproj upgrade-manager
+	//syntax-generation:category:upgrademanager
+	kdt.scenario.Step(`^(?:the )?rollingupgrade (\S+) in (?:the )?namespace (\S+) (?:should be|is) (init|running|completed|error)$`, kdt.KubeClientSet.RollingUpgradeShouldBe)
+	//syntax-generation:title-1:KEDA
+	//syntax-generation:category:keda
+	kdt.scenario.Step(`^(?:the )?scaledobject (\S+) in (?:the )?namespace (\S+) (?:should be|is) ready$`, kdt.KubeClientSet.ScaledObjectShouldBeReady)
+	kdt.scenario.Step(`^(?:the )?scaledobject (\S+) in (?:the )?namespace (\S+) should scale its target (from zero|to zero)$`, kdt.KubeClientSet.ScaledObjectTargetShouldScale)
+	//syntax-generation:title-1:Vertical Pod Autoscaler
+	//syntax-generation:category:vpa
+	kdt.scenario.Step(`^(?:the )?verticalpodautoscaler (\S+) in (?:the )?namespace (\S+) should provide (?:a )?recommendation(?: for (?:the )?container (\S+))?$`, kdt.KubeClientSet.VerticalPodAutoscalerShouldProvideRecommendation)
+	//syntax-generation:description:Validates the target the verticalpodautoscaler recommends for the container is within the bounds, as resource quantities
+	//syntax-generation:example:Then the memory recommendation for the container app of the verticalpodautoscaler my-app in the namespace my-team should be between 128Mi and 512Mi
+	kdt.scenario.Step(`^(?:the )?(cpu|memory) recommendation (?:of|for) (?:the )?container (\S+) of (?:the )?verticalpodautoscaler (\S+) in (?:the )?namespace (\S+) should be between (\S+) and (\S+)$`, kdt.KubeClientSet.VerticalPodAutoscalerRecommendationShouldBeBetween)
+	//syntax-generation:title-1:Metrics APIs
+	//syntax-generation:category:metrics
+	kdt.scenario.Step(`^(?:the )?custom metric (\S+) (?:of|for) (?:the )?(\S+) (\S+) in (?:the )?namespace (\S+) should be between (\S+) and (\S+)$`, kdt.KubeClientSet.CustomMetricShouldBeBetween)
+	kdt.scenario.Step(`^(?:the )?external metric (\S+) in (?:the )?namespace (\S+) should be between (\S+) and (\S+)$`, kdt.KubeClientSet.ExternalMetricShouldBeBetween)
+	//syntax-generation:description:Scrapes /metrics of the service through a port-forward, on the port named metrics unless given, and validates every sample of the metric with labels matching the comma separated name=value or name!=value selector
+	//syntax-generation:example:Then the metric controller_runtime_reconcile_total with labels controller=rollout,result=error from the service my-operator in the namespace my-team should be less than 1
+	kdt.scenario.Step(`^(?:the )?metric (\S+)(?: with labels (\S+))? (?:of|from) (?:the )?service (\S+) in (?:the )?namespace (\S+)(?: (?:on )?port (\d+))? should be (greater|less) than (-?\d+(?:\.\d+)?)$`, kdt.KubeClientSet.ServiceMetricShouldBe)
+	//syntax-generation:title-1:Prometheus and Alertmanager
+	//syntax-generation:category:prometheus
+	kdt.scenario.Step(`^(?:the )?Prometheus query "([^"]*)" should return a value (<|<=|>|>=|==|!=) (-?\d+(?:\.\d+)?) within (\S+)$`, kdt.KubeClientSet.PrometheusQueryShouldReturnValue)
+	kdt.scenario.Step(`^(?:the )?alert (\S+) (should|should not) be firing since ([^"]*) time$`, kdt.KubeClientSet.AlertFiringSinceTime)
+	//syntax-generation:title-1:Policy engines
+	//syntax-generation:category:policy
+	kdt.scenario.Step(`^(?:the )?policy reports in (?:the )?namespace (\S+) should have no violations$`, kdt.KubeClientSet.PolicyReportsShouldHaveNoViolations)
+	kdt.scenario.Step(`^(?:the )?(\S+) constraint (\S+) should have no violations in (?:the )?namespace (\S+)$`, kdt.KubeClientSet.ConstraintShouldHaveNoViolations)
 	//syntax-generation:title-0:AWS steps
+	//syntax-generation:category:aws
 	kdt.scenario.Step(`^(?:there are )?(?:valid )?AWS Credentials$`, kdt.AwsClientSet.DiscoverClients)
+	//syntax-generation:description:Discovers the AWS clients using the named profile of the shared config and credentials files
+	//syntax-generation:example:Given valid AWS Credentials from the profile staging
+	kdt.scenario.Step(`^(?:there are )?(?:valid )?AWS Credentials (?:for|from) (?:the )?profile (\S+)$`, kdt.AwsClientSet.DiscoverClientsWithProfile)
 	kdt.scenario.Step(`^an Auto Scaling Group named ([^"]*)$`, kdt.AwsClientSet.AnASGNamed)
 	kdt.scenario.Step(`^(?:I )?update (?:the )?current Auto Scaling Group with ([^"]*) set to ([^"]*)$`, kdt.AwsClientSet.UpdateFieldOfCurrentASG)
 	kdt.scenario.Step(`^(?:the )?current Auto Scaling Group (?:is )?scaled to \(min, max\) = \((\d+), (\d+)\)$`, kdt.AwsClientSet.ScaleCurrentASG)
+	//syntax-generation:example:Then the current Auto Scaling Group should have 3 instances InService
+	kdt.scenario.Step(`^(?:the )?current Auto Scaling Group should have (\d+) instance(?:s)? (?:in )?(InService|Pending|Terminating|Terminated|Standby)$`, kdt.AwsClientSet.CurrentASGInstancesShouldBe)
+	//syntax-generation:example:Then the current Auto Scaling Group should use launch template version $Latest
+	kdt.scenario.Step(`^(?:the )?current Auto Scaling Group should use (?:the )?launch template version (\S+)$`, kdt.AwsClientSet.CurrentASGLaunchTemplateVersionShouldBe)
+	//syntax-generation:example:Then the AMI of the current Auto Scaling Group launch template should match the SSM parameter /aws/service/eks/optimized-ami/1.28/amazon-linux-2/recommended/image_id
+	kdt.scenario.Step(`^(?:the )?AMI of (?:the )?current Auto Scaling Group launch template should match (?:the )?SSM parameter (\S+)$`, kdt.AwsClientSet.CurrentASGLaunchTemplateAMIShouldMatchSSMParameter)
+	kdt.scenario.Step(`^(?:the )?current Auto Scaling Group should have (?:the )?lifecycle hook (\S+)(?: for (launching|terminating))?$`, kdt.AwsClientSet.CurrentASGShouldHaveLifecycleHook)
+	//syntax-generation:example:When I complete the lifecycle action of hook node-drainer for instance i-0123456789abcdef0
+	kdt.scenario.Step(`^(?:I )?(complete|abandon) (?:the )?lifecycle action of (?:the )?(?:lifecycle )?hook (\S+) for (?:the )?instance (\S+)$`, kdt.AwsClientSet.LifecycleActionOperation)
+	//syntax-generation:description:Validates the Fargate profile of the cluster in the CLUSTER_NAME environment variable is active, optionally with a selector for the namespace and labels
+	//syntax-generation:example:Then the Fargate profile default-profile should exist with a selector for namespace kube-system and labels k8s-app=kube-dns
+	kdt.scenario.Step(`^(?:the )?Fargate profile (\S+) should exist(?: with (?:a )?selector for (?:the )?namespace (\S+)(?: and labels (\S+))?)?$`, kdt.AwsClientSet.FargateProfileShouldExist)
+	//syntax-generation:example:Then the image tag v1.2.3 should exist in the ECR repository my-team/my-app
+	kdt.scenario.Step(`^(?:the )?image tag (\S+) should exist in (?:the )?ECR repository (\S+)$`, kdt.AwsClientSet.ECRImageTagShouldExist)
+	//syntax-generation:description:Synchronously invokes the Lambda function, optionally with a JSON payload, and keeps its response for the following steps
+	//syntax-generation:example:When I invoke the Lambda function node-lifecycle-handler with payload '{"detail-type":"test"}'
+	kdt.scenario.Step(`^(?:I )?invoke (?:the )?Lambda function (\S+)(?: with (?:the )?payload '([^']*)')?$`, kdt.AwsClientSet.InvokeLambdaFunction)
+	//syntax-generation:example:Then the Lambda function should return status code 200
+	kdt.scenario.Step(`^(?:the )?Lambda function (?:should )?(?:return|returns) (?:the )?status code (\d+)$`, kdt.AwsClientSet.LambdaResponseStatusCodeShouldBe)
+	//syntax-generation:example:Then the Lambda response should have json path .statusCode set to '200'
+	kdt.scenario.Step(`^(?:the )?Lambda (?:function )?response (?:should have|has) (?:the )?(?:json path|field) (\S+) (?:set to|equal to) '([^']*)'$`, kdt.AwsClientSet.LambdaResponseFieldShouldBe)
+	kdt.scenario.Step(`^(?:the )?EventBridge rule (\S+)(?: on (?:the )?event bus (\S+))? should (?:exist and )?be enabled$`, kdt.AwsClientSet.EventBridgeRuleShouldBeEnabled)
+	//syntax-generation:example:Then the EventBridge rule node-termination on the event bus default should target arn:aws:sqs:us-west-2:123456789012:node-termination
+	kdt.scenario.Step(`^(?:the )?EventBridge rule (\S+)(?: on (?:the )?event bus (\S+))? should target (\S+)$`, kdt.AwsClientSet.EventBridgeRuleShouldTarget)
+	//syntax-generation:description:Puts an event with the source and detail type on the event bus, the detail defaults to an empty JSON object
+	//syntax-generation:example:When I put a test event with source kubedog.test and detail type "EC2 Spot Instance Interruption Warning" on the event bus default
+	kdt.scenario.Step(`^(?:I )?put (?:a )?(?:test )?event with source (\S+) and detail type "([^"]*)" on (?:the )?event bus (\S+)(?: with (?:the )?detail '([^']*)')?$`, kdt.AwsClientSet.PutEventBridgeEvent)
 	kdt.scenario.Step(`^(?:the )?DNS name (\S+) (should|should not) be created in hostedZoneID (\S+)$`, kdt.AwsClientSet.DnsNameShouldOrNotInHostedZoneID)
 	kdt.scenario.Step(`^(?:I )?(add|remove) (?:the )?(\S+) role as trusted entity to iam role ([^"]*)$`, kdt.AwsClientSet.IamRoleTrust)
+	//syntax-generation:description:Adds or removes the shared iam role and managed policy of the cluster, adding waits until IAM propagates them
+	//syntax-generation:example:Given I add cluster shared iam role
 	kdt.scenario.Step(`^(?:I )?(add|remove) cluster shared iam role$`, kdt.AwsClientSet.ClusterSharedIamOperation)
+	//syntax-generation:title-0:Kubernetes and AWS steps
+	//syntax-generation:category:kube-aws
+	kdt.scenario.Step(`^(?:the )?external-dns records of (?:the )?(ingress|service) (\S+) in (?:the )?namespace (\S+) should be created in hostedZoneID (\S+) and resolve$`, kdt.ExternalDNSRecordsShouldResolve)
+	kdt.scenario.Step(`^(?:I )?create (?:a )?rollingupgrade (\S+) in (?:the )?namespace (\S+) for (?:the )?current Auto Scaling Group$`, kdt.CreateRollingUpgradeForCurrentASG)
+	kdt.scenario.Step(`^(?:I )?(?:create|submit) (?:the )?resource (\S+), (\d+) node(?:s)? with selector (\S+) should (?:be )?scale(?:d)? up by (?:the )?cluster autoscaler in (?:the )?current Auto Scaling Group$`, kdt.ClusterAutoscalerShouldScaleUp)
+	//syntax-generation:example:Then the pods matching the Fargate profile default-profile should be scheduled on Fargate
+	kdt.scenario.Step(`^(?:the )?pods matching (?:the )?Fargate profile (\S+) should (?:be )?(?:scheduled|run) on Fargate$`, kdt.PodsOfFargateProfileShouldRunOnFargate)
+	//syntax-generation:description:Validates the pods of the deployment run the digest of the image tag in the ECR repository
+	//syntax-generation:example:Then the deployment my-app in the namespace my-team should run the image tag v1.2.3 of the ECR repository my-team/my-app
+	kdt.scenario.Step(`^(?:the )?deployment (\S+) in (?:the )?namespace (\S+) should run (?:the )?image tag (\S+) of (?:the )?ECR repository (\S+)$`, kdt.DeploymentShouldRunECRImageTag)
+	//syntax-generation:description:Validates the load balancer of the service is an NLB with the scheme and, when given, the target type
+	//syntax-generation:example:Then the NLB of the service my-app in the namespace my-team should be internal with ip targets
+	kdt.scenario.Step(`^(?:the )?NLB of (?:the )?service (\S+) in (?:the )?namespace (\S+) should be (internal|internet-facing)(?: with (instance|ip) targets)?$`, kdt.ServiceNLBShouldBe)
+	kdt.scenario.Step(`^(?:all )?(?:the )?targets of (?:the )?NLB of (?:the )?service (\S+) in (?:the )?namespace (\S+) should be healthy$`, kdt.ServiceNLBTargetsShouldBeHealthy)
+	//syntax-generation:description:Terminates the EC2 instance of a random ready node with the selector, then waits for the node to be replaced and for the pods of the controllers that ran on it, other than DaemonSets, to be ready again on other nodes
+	//syntax-generation:example:When I terminate the EC2 instance of a node with selector node.kubernetes.io/instancegroup=workers, the node should be replaced and its pods rescheduled
+	kdt.scenario.Step(`^(?:I )?terminate (?:the )?EC2 instance of a (?:random )?node with selector (\S+), the node should be replaced and its pods rescheduled$`, kdt.TerminateNodeInstanceShouldBeHealed)
 	//syntax-generation:end
 }
 
@@ -101,4 +559,134 @@ SetTestSuite sets the TestSuiteContext, should be use in the InitializeTestSuite
 */
 func (kdt *Test) SetTestSuite(testSuite *godog.TestSuiteContext) {
 	kdt.suite = testSuite
+	testSuite.AfterSuite(func() {
+		if kdt.stepUsageDir == "" {
+			return
+		}
+		if err := kdt.ExportStepUsage(kdt.stepUsageDir); err != nil {
+			log.Warnf("failed exporting the step usage: %v", err)
+		}
+	})
+}
+
+/*
+ExternalDNSRecordsShouldResolve validates external-dns created a record in the hosted zone for every hostname of the ingress or service,
+and that each hostname resolves.
+*/
+func (kdt *Test) ExternalDNSRecordsShouldResolve(resourceType, name, namespace, hostedZoneID string) error {
+	hostnames, err := structured.GetExternalDNSHostnames(kdt.KubeClientSet.KubeInterface, resourceType, name, namespace)
+	if err != nil {
+		return err
+	}
+	for _, hostname := range hostnames {
+		if err := kdt.AwsClientSet.DnsNameShouldResolve(hostname, hostedZoneID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+CreateRollingUpgradeForCurrentASG creates an upgrade-manager RollingUpgrade targeting the current Auto Scaling Group.
+*/
+func (kdt *Test) CreateRollingUpgradeForCurrentASG(name, namespace string) error {
+	asgName, err := kdt.AwsClientSet.GetCurrentASGName()
+	if err != nil {
+		return err
+	}
+	return kdt.KubeClientSet.CreateRollingUpgrade(name, namespace, asgName)
+}
+
+/*
+ClusterAutoscalerShouldScaleUp creates a workload that does not fit in the cluster, then validates the expected nodes joined
+and the desired capacity of the current Auto Scaling Group increased.
+*/
+func (kdt *Test) ClusterAutoscalerShouldScaleUp(resourceFileName string, expectedNodes int, selector string) error {
+	desiredCapacity, err := kdt.AwsClientSet.GetCurrentASGDesiredCapacity()
+	if err != nil {
+		return err
+	}
+	if err := kdt.KubeClientSet.ResourceOperation(common.OperationCreate, resourceFileName); err != nil {
+		return err
+	}
+	if err := kdt.KubeClientSet.NodesWithSelectorShouldBe(expectedNodes, selector, common.StateReady); err != nil {
+		return err
+	}
+	scaledCapacity, err := kdt.AwsClientSet.GetCurrentASGDesiredCapacity()
+	if err != nil {
+		return err
+	}
+	if scaledCapacity <= desiredCapacity {
+		return errors.Errorf("expected desired capacity of the current Auto Scaling Group to increase from %d, but it is %d", desiredCapacity, scaledCapacity)
+	}
+	return nil
+}
+
+/*
+PodsOfFargateProfileShouldRunOnFargate validates the pods matching every selector of the Fargate profile are scheduled on Fargate nodes.
+*/
+func (kdt *Test) PodsOfFargateProfileShouldRunOnFargate(profileName string) error {
+	selectors, err := kdt.AwsClientSet.GetFargateProfileSelectors(profileName)
+	if err != nil {
+		return err
+	}
+	for _, selector := range selectors {
+		var labels []string
+		for k, v := range selector.Labels {
+			labels = append(labels, k+"="+awssdk.StringValue(v))
+		}
+		sort.Strings(labels)
+		if err := kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldRunOnFargate(awssdk.StringValue(selector.Namespace), strings.Join(labels, ",")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+DeploymentShouldRunECRImageTag validates the pods of the deployment run the same digest the image tag has in the ECR repository.
+*/
+func (kdt *Test) DeploymentShouldRunECRImageTag(name, namespace, tag, repository string) error {
+	digest, err := kdt.AwsClientSet.GetECRImageDigest(repository, tag)
+	if err != nil {
+		return err
+	}
+	return kdt.KubeClientSet.DeploymentShouldRunImageDigest(name, namespace, repository, digest)
+}
+
+/*
+ServiceNLBShouldBe validates the NLB created for the service of type LoadBalancer has the scheme and, when given, the target type.
+*/
+func (kdt *Test) ServiceNLBShouldBe(name, namespace, scheme, targetType string) error {
+	hostname, err := kdt.KubeClientSet.GetServiceLoadBalancerHostname(name, namespace)
+	if err != nil {
+		return err
+	}
+	return kdt.AwsClientSet.NLBShouldBe(hostname, scheme, targetType)
+}
+
+/*
+ServiceNLBTargetsShouldBeHealthy waits for the targets of the NLB created for the service of type LoadBalancer to be healthy.
+*/
+func (kdt *Test) ServiceNLBTargetsShouldBeHealthy(name, namespace string) error {
+	hostname, err := kdt.KubeClientSet.GetServiceLoadBalancerHostname(name, namespace)
+	if err != nil {
+		return err
+	}
+	return kdt.AwsClientSet.NLBTargetsShouldBeHealthy(hostname)
+}
+
+/*
+TerminateNodeInstanceShouldBeHealed terminates the EC2 instance of a random ready node with the selector, then validates the node
+is replaced and the pods that ran on it are rescheduled.
+*/
+func (kdt *Test) TerminateNodeInstanceShouldBeHealed(selector string) error {
+	disruption, err := kdt.KubeClientSet.NewNodeDisruption(selector)
+	if err != nil {
+		return err
+	}
+	if err := kdt.AwsClientSet.TerminateInstance(disruption.InstanceID); err != nil {
+		return err
+	}
+	return kdt.KubeClientSet.NodeDisruptionShouldBeHealed(disruption)
 }
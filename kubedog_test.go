@@ -0,0 +1,305 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubedog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/keikoproj/kubedog/generate/syntax/catalog"
+	"github.com/keikoproj/kubedog/pkg/kube"
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type stepRecorder struct {
+	steps    []catalog.Step
+	handlers []interface{}
+}
+
+func (r *stepRecorder) Step(expr, stepFunc interface{}) {
+	regex, _ := expr.(string)
+	r.steps = append(r.steps, catalog.Step{Regex: regex})
+	r.handlers = append(r.handlers, stepFunc)
+}
+
+func TestSetScenario(t *testing.T) {
+	recorder := &stepRecorder{}
+	kdt := Test{}
+	kdt.setScenario(recorder)
+	if len(recorder.steps) == 0 {
+		t.Fatal("expected steps to be defined")
+	}
+	for i, step := range recorder.steps {
+		if err := catalog.ValidateHandler(step.Regex, recorder.handlers[i]); err != nil {
+			t.Error(err)
+		}
+	}
+	for _, err := range catalog.Validate(recorder.steps) {
+		t.Error(err)
+	}
+}
+
+func TestKubeContext(t *testing.T) {
+	client := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+	})
+	kdt := Test{}
+	*kdt.KubeContext() = *kube.NewClientSet(client, nil, kube.WithWaiter(1, time.Millisecond))
+	if err := kdt.KubeClientSet.DiscoverClients(); err != nil {
+		t.Fatalf("DiscoverClients() error = %v, expected the injected clients to be kept", err)
+	}
+	if kdt.KubeContext().KubeInterface != client {
+		t.Fatal("expected the injected client to be used")
+	}
+	if err := kdt.KubeClientSet.DeploymentPauseOperation("pause", "web", "default"); err != nil {
+		t.Fatalf("DeploymentPauseOperation() error = %v", err)
+	}
+	deployment, err := client.AppsV1().Deployments("default").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deployment.Spec.Paused {
+		t.Error("expected the injected client to pause the deployment")
+	}
+}
+
+func TestEphemeralNamespace(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	kc := kube.NewClientSet(client, nil,
+		kube.WithEphemeralNamespace("e2e"),
+		kube.WithTemplateArguments(map[string]interface{}{"Region": "us-west-2"}),
+		kube.WithWaiter(1, time.Millisecond),
+	)
+	if err := kc.CreateEphemeralNamespace(); err != nil {
+		t.Fatalf("CreateEphemeralNamespace() error = %v", err)
+	}
+	name := kc.EphemeralNamespace()
+	if !strings.HasPrefix(name, "e2e-") {
+		t.Fatalf("EphemeralNamespace() = %v, expected it to start with the prefix", name)
+	}
+	if _, err := client.CoreV1().Namespaces().Get(context.Background(), name, metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected namespace %v to be created: %v", name, err)
+	}
+	if err := kc.DeleteEphemeralNamespace(); err != nil {
+		t.Fatalf("DeleteEphemeralNamespace() error = %v", err)
+	}
+	if _, err := client.CoreV1().Namespaces().Get(context.Background(), name, metav1.GetOptions{}); !kerrors.IsNotFound(err) {
+		t.Errorf("expected namespace %v to be deleted, got %v", name, err)
+	}
+	if kc.EphemeralNamespace() != "" {
+		t.Errorf("EphemeralNamespace() = %v, expected no namespace after the scenario", kc.EphemeralNamespace())
+	}
+
+	kc = kube.NewClientSet(client, nil, kube.WithEphemeralNamespace("e2e"), kube.WithTemplateArguments(struct{ Namespace string }{}))
+	if err := kc.CreateEphemeralNamespace(); err == nil {
+		t.Error("CreateEphemeralNamespace() expected an error for template arguments that are not a map")
+	}
+}
+
+func TestSyntax(t *testing.T) {
+	recorder := &stepRecorder{}
+	kdt := Test{}
+	kdt.setScenario(recorder)
+	steps, err := Syntax()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != len(recorder.steps) {
+		t.Fatalf("expected docs/syntax.json to have %d steps but got %d, try running 'make generate'", len(recorder.steps), len(steps))
+	}
+	for i, step := range steps {
+		if step.Regex != recorder.steps[i].Regex {
+			t.Errorf("expected step %d of docs/syntax.json to be '%s' but got '%s', try running 'make generate'", i, recorder.steps[i].Regex, step.Regex)
+		}
+	}
+}
+
+func TestGetDeprecation(t *testing.T) {
+	steps := compileSteps([]catalog.Step{
+		{Regex: `^(?:I )?get (?:the )?pods in namespace (\S+) with selector (\S+)$`},
+		{Regex: `^(?:I )?get (?:the )?pods in namespace (\S+)$`, Deprecated: "use 'get pods in namespace <namespace> with selector <selector>' instead"},
+	})
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "deprecated step", text: "I get the pods in namespace default", want: "use 'get pods in namespace <namespace> with selector <selector>' instead"},
+		{name: "first matching step is not deprecated", text: "I get the pods in namespace default with selector app=nginx"},
+		{name: "undefined step", text: "a step kubedog does not define"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getDeprecation(steps, tt.text); got != tt.want {
+				t.Errorf("getDeprecation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStepUsage(t *testing.T) {
+	kdt := Test{}
+	for _, text := range []string{"a Kubernetes cluster", "Kubernetes Credentials", "I wait for 30 seconds", "an undefined step"} {
+		if _, err := kdt.recordStepUsage(context.Background(), &godog.Step{Text: text}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	usage, err := kdt.StepUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int{}
+	for _, step := range usage {
+		counts[step.Method] += step.Count
+	}
+	if counts["kdt.KubeClientSet.DiscoverClients"] != 2 || counts["kdt.KubeClientSet.WaitFor"] != 1 {
+		t.Errorf("expected DiscoverClients to be used twice and WaitFor once, got %v and %v", counts["kdt.KubeClientSet.DiscoverClients"], counts["kdt.KubeClientSet.WaitFor"])
+	}
+
+	dir := t.TempDir()
+	if err := kdt.ExportStepUsage(dir); err != nil {
+		t.Fatal(err)
+	}
+	report, err := os.ReadFile(filepath.Join(dir, stepUsageMarkdownFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(report), fmt.Sprintf("2 of %d steps used", len(usage))) || !strings.Contains(string(report), "| kdt.KubeClientSet.DiscoverClients | 2 |") {
+		t.Errorf("unexpected step usage report:\n%s", report)
+	}
+	if _, err := os.Stat(filepath.Join(dir, stepUsageJSONFile)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHTTPRequest(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer some-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"method":%q,"received":%q}`, r.Method, body)
+	}))
+	defer server.Close()
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+
+	kc := kube.NewClientSet(fake.NewSimpleClientset(), nil, kube.WithWaiter(1, time.Millisecond))
+	if err := kc.HTTPResponseStatusCodeShouldBe("2xx"); err == nil {
+		t.Error("HTTPResponseStatusCodeShouldBe() expected an error before a request is sent")
+	}
+	for _, step := range []func() error{
+		func() error { return kc.SetHTTPRequestHeader("Authorization", "Bearer some-token") },
+		func() error { return kc.SetHTTPRequestBody("some-body") },
+		func() error { return kc.SetHTTPRequestTimeout(5, "seconds") },
+		kc.SkipHTTPRequestTLSVerification,
+		func() error { return kc.SendHTTPRequestToURL("POST", server.URL) },
+		func() error { return kc.HTTPResponseStatusCodeShouldBe("200-299") },
+		func() error { return kc.HTTPResponseBodyShould("contains", "some-body") },
+		func() error { return kc.HTTPResponseBodyJSONPathShouldBe(".method", "POST") },
+	} {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := kc.HTTPResponseStatusCodeShouldBe("5xx"); err == nil {
+		t.Error("HTTPResponseStatusCodeShouldBe() expected an error for another status")
+	}
+	if err := kc.SetHTTPRequestTimeout(5, "hours"); err == nil {
+		t.Error("SetHTTPRequestTimeout() expected an error for unsupported units")
+	}
+
+	// the settings are cleared once the request is sent
+	if err := kc.SendHTTPRequestToURL("GET", server.URL); err == nil {
+		t.Error("SendHTTPRequestToURL() expected the TLS verification to fail")
+	}
+}
+
+func TestAddTrafficTarget(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths = map[string]int{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		paths[r.URL.Path]++
+		mu.Unlock()
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer some-token" || string(body) != "some-body" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Status: networkingv1.IngressStatus{LoadBalancer: networkingv1.IngressLoadBalancerStatus{
+			Ingress: []networkingv1.IngressLoadBalancerIngress{{Hostname: serverURL.Hostname()}},
+		}},
+	}
+
+	kc := kube.NewClientSet(fake.NewSimpleClientset(ingress), nil, kube.WithWaiter(1, time.Millisecond), kube.WithArtifactsPath(t.TempDir()))
+	if err := kc.AddTrafficTarget("deployment", "web", "default", port, "/", "web", 10, "GET", "http"); err == nil {
+		t.Error("AddTrafficTarget() expected an error for an unsupported kind")
+	}
+	for _, step := range []func() error{
+		func() error { return kc.SetHTTPRequestHeader("Authorization", "Bearer some-token") },
+		func() error { return kc.SetHTTPRequestBody("some-body") },
+		func() error {
+			return kc.AddTrafficTarget("ingress", "web", "default", port, "/orders:3,/healthz", "web", 8, "POST", "http")
+		},
+		func() error { return kc.SendTrafficToTargets(1, "seconds", 0) },
+	} {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	metrics := kc.TrafficTargetMetrics("web")
+	if metrics == nil || metrics.StatusCodes["200"] != int(metrics.Requests) {
+		t.Fatalf("TrafficTargetMetrics() = %+v, want only successful requests", metrics)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if paths["/healthz"] == 0 || paths["/orders"] < 2*paths["/healthz"] {
+		t.Errorf("requests per path = %v, want about 3 requests to /orders per request to /healthz", paths)
+	}
+}
@@ -15,24 +15,40 @@ limitations under the License.
 package aws
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/aws/aws-sdk-go/service/eks/eksiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/keikoproj/kubedog/internal/util"
 	kIam "github.com/keikoproj/kubedog/pkg/aws/iam"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -44,18 +60,64 @@ type ClientSet struct {
 	Route53Client    route53iface.Route53API
 	IAMClient        iamiface.IAMAPI
 	STSClient        stsiface.STSAPI
+	EC2Client        ec2iface.EC2API
+	SSMClient        ssmiface.SSMAPI
+	ECRClient        ecriface.ECRAPI
+	LambdaClient     lambdaiface.LambdaAPI
+	EventsClient     eventbridgeiface.EventBridgeAPI
+	ELBv2Client      elbv2iface.ELBV2API
 	asgName          string
 	launchConfigName string
+	profile          string
+	endpoint         string
+	lambdaName       string
+	lambdaOutput     *lambda.InvokeOutput
+	waiterInterval   time.Duration
+	waiterTries      int
+	ctx              context.Context
+}
+
+func (c *ClientSet) SetWaiterInterval(duration time.Duration) {
+	c.waiterInterval = duration
+}
+
+func (c *ClientSet) SetWaiterTries(tries int) {
+	c.waiterTries = tries
+}
+
+// SetProfile sets the shared config profile used by DiscoverClients instead of the default credential chain.
+func (c *ClientSet) SetProfile(profile string) {
+	c.profile = profile
+}
+
+// SetEndpoint sets the endpoint used by all the clients DiscoverClients creates, e.g. http://localhost:4566 to target LocalStack.
+func (c *ClientSet) SetEndpoint(endpoint string) {
+	c.endpoint = endpoint
+}
+
+// SetContext sets the context the waiters return early on when it is done, SetScenario sets the context of each scenario
+func (c *ClientSet) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// DiscoverClientsWithProfile creates the clients with the given shared config profile, the profile set with SetProfile is
+// kept for the next DiscoverClients
+func (c *ClientSet) DiscoverClientsWithProfile(profile string) error {
+	return c.discoverClients(profile)
 }
 
 func (c *ClientSet) DiscoverClients() error {
+	return c.discoverClients(c.profile)
+}
+
+func (c *ClientSet) discoverClients(profile string) error {
 	var (
 		sess     *session.Session
 		identity *sts.GetCallerIdentityOutput
 		err      error
 	)
 
-	if sess, err = session.NewSession(); err != nil {
+	if sess, err = c.newSession(profile); err != nil {
 		return err
 	}
 
@@ -71,6 +133,12 @@ func (c *ClientSet) DiscoverClients() error {
 	c.Route53Client = route53.New(sess)
 	c.IAMClient = iam.New(sess)
 	c.STSClient = sts.New(sess)
+	c.EC2Client = ec2.New(sess)
+	c.SSMClient = ssm.New(sess)
+	c.ECRClient = ecr.New(sess)
+	c.LambdaClient = lambda.New(sess)
+	c.EventsClient = eventbridge.New(sess)
+	c.ELBv2Client = elbv2.New(sess)
 
 	return nil
 }
@@ -116,6 +184,391 @@ func (c *ClientSet) ScaleCurrentASG(desiredMin, desiredMax int64) error {
 	return nil
 }
 
+func (c *ClientSet) CurrentASGInstancesShouldBe(count int64, lifecycleState string) error {
+	var (
+		counter int
+		w       = c.getWaiterConfig()
+	)
+	for {
+		if counter >= w.GetTries() {
+			return errors.Errorf("waiter timed out waiting for %d instances of ASG %v to be %v", count, c.asgName, lifecycleState)
+		}
+		asg, err := c.getCurrentASG()
+		if err != nil {
+			return err
+		}
+		var found int64
+		for _, instance := range asg.Instances {
+			if strings.EqualFold(aws.StringValue(instance.LifecycleState), lifecycleState) {
+				found++
+			}
+		}
+		if found == count {
+			log.Infof("%d instances of ASG %v are %v", count, c.asgName, lifecycleState)
+			return nil
+		}
+		log.Infof("waiting for %d instances of ASG %v to be %v, found %d", count, c.asgName, lifecycleState, found)
+		counter++
+		if err := w.Sleep(c.getContext()); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *ClientSet) GetCurrentASGDesiredCapacity() (int64, error) {
+	if c.ASClient == nil {
+		return 0, errors.Errorf("Unable to get current ASG desired capacity: The AS client was not found, use the method GetAWSCredsAndClients")
+	}
+
+	out, err := c.ASClient.DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{aws.String(c.asgName)},
+	})
+	if err != nil {
+		return 0, errors.Errorf("Failed describing the ASG %v: %v", c.asgName, err)
+	} else if len(out.AutoScalingGroups) == 0 {
+		return 0, errors.Errorf("No ASG found by the name: '%s'", c.asgName)
+	}
+
+	return aws.Int64Value(out.AutoScalingGroups[0].DesiredCapacity), nil
+}
+
+func (c *ClientSet) CurrentASGLaunchTemplateVersionShouldBe(expectedVersion string) error {
+	launchTemplate, err := c.getCurrentASGLaunchTemplate()
+	if err != nil {
+		return err
+	}
+	version := aws.StringValue(launchTemplate.Version)
+	if version == expectedVersion {
+		log.Infof("ASG %v uses launch template %v version %v", c.asgName, getLaunchTemplateIdentifier(launchTemplate), version)
+		return nil
+	}
+
+	resolvedVersion, err := c.resolveLaunchTemplateVersion(launchTemplate, version)
+	if err != nil {
+		return err
+	}
+	resolvedExpectedVersion, err := c.resolveLaunchTemplateVersion(launchTemplate, expectedVersion)
+	if err != nil {
+		return err
+	}
+	if resolvedVersion != resolvedExpectedVersion {
+		return errors.Errorf("expected ASG %v to use launch template %v version %v (%v) but got %v (%v)", c.asgName, getLaunchTemplateIdentifier(launchTemplate), expectedVersion, resolvedExpectedVersion, version, resolvedVersion)
+	}
+	log.Infof("ASG %v uses launch template %v version %v (%v)", c.asgName, getLaunchTemplateIdentifier(launchTemplate), version, resolvedVersion)
+	return nil
+}
+
+func (c *ClientSet) CurrentASGLaunchTemplateAMIShouldMatchSSMParameter(parameterName string) error {
+	launchTemplate, err := c.getCurrentASGLaunchTemplate()
+	if err != nil {
+		return err
+	}
+	version, err := c.resolveLaunchTemplateVersion(launchTemplate, aws.StringValue(launchTemplate.Version))
+	if err != nil {
+		return err
+	}
+	imageID, err := c.getLaunchTemplateImageID(launchTemplate, version)
+	if err != nil {
+		return err
+	}
+	expectedImageID, err := c.getSSMParameterValue(parameterName)
+	if err != nil {
+		return err
+	}
+	if imageID != expectedImageID {
+		return errors.Errorf("expected launch template %v version %v of ASG %v to use AMI %v from SSM parameter %v but got %v", getLaunchTemplateIdentifier(launchTemplate), version, c.asgName, expectedImageID, parameterName, imageID)
+	}
+	log.Infof("launch template %v version %v of ASG %v uses AMI %v from SSM parameter %v", getLaunchTemplateIdentifier(launchTemplate), version, c.asgName, imageID, parameterName)
+	return nil
+}
+
+func (c *ClientSet) CurrentASGShouldHaveLifecycleHook(hookName, transition string) error {
+	hook, err := c.getCurrentASGLifecycleHook(hookName)
+	if err != nil {
+		return err
+	}
+	if transition != "" {
+		expectedTransition, ok := lifecycleTransitions[transition]
+		if !ok {
+			return errors.Errorf("unsupported lifecycle transition '%s', expected one of 'launching' or 'terminating'", transition)
+		}
+		if aws.StringValue(hook.LifecycleTransition) != expectedTransition {
+			return errors.Errorf("expected lifecycle hook %v of ASG %v to be for %v but got %v", hookName, c.asgName, expectedTransition, aws.StringValue(hook.LifecycleTransition))
+		}
+	}
+	log.Infof("ASG %v has lifecycle hook %v for %v", c.asgName, hookName, aws.StringValue(hook.LifecycleTransition))
+	return nil
+}
+
+func (c *ClientSet) LifecycleActionOperation(operation, hookName, instanceID string) error {
+	result, ok := lifecycleActionResults[operation]
+	if !ok {
+		return errors.Errorf("unsupported lifecycle action operation '%s', expected one of 'complete' or 'abandon'", operation)
+	}
+	if _, err := c.getCurrentASGLifecycleHook(hookName); err != nil {
+		return err
+	}
+	_, err := c.ASClient.CompleteLifecycleAction(&autoscaling.CompleteLifecycleActionInput{
+		AutoScalingGroupName:  aws.String(c.asgName),
+		LifecycleHookName:     aws.String(hookName),
+		InstanceId:            aws.String(instanceID),
+		LifecycleActionResult: aws.String(result),
+	})
+	if err != nil {
+		return errors.Errorf("Failed to %v the lifecycle action of hook %v for instance %v in ASG %v: %v", operation, hookName, instanceID, c.asgName, err)
+	}
+	log.Infof("%v lifecycle action of hook %v for instance %v in ASG %v with result %v", operation, hookName, instanceID, c.asgName, result)
+	return nil
+}
+
+// FargateProfileShouldExist validates the Fargate profile of the cluster is active and, when given, has a selector for the namespace and the comma separated key=value labels.
+func (c *ClientSet) FargateProfileShouldExist(profileName, namespace, labels string) error {
+	profile, err := c.getFargateProfile(profileName)
+	if err != nil {
+		return err
+	}
+	if status := aws.StringValue(profile.Status); status != eks.FargateProfileStatusActive {
+		return errors.Errorf("Fargate profile %v is %v, expected %v", profileName, status, eks.FargateProfileStatusActive)
+	}
+	if namespace == "" {
+		log.Infof("Fargate profile %v is %v", profileName, eks.FargateProfileStatusActive)
+		return nil
+	}
+	expectedLabels := map[string]string{}
+	for _, item := range strings.Split(labels, ",") {
+		vals := strings.Split(item, "=")
+		if len(vals) != 2 {
+			continue
+		}
+		expectedLabels[vals[0]] = vals[1]
+	}
+	for _, selector := range profile.Selectors {
+		if aws.StringValue(selector.Namespace) != namespace || len(selector.Labels) != len(expectedLabels) {
+			continue
+		}
+		matched := true
+		for k, v := range expectedLabels {
+			if aws.StringValue(selector.Labels[k]) != v {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			log.Infof("Fargate profile %v has a selector for namespace %v with labels '%v'", profileName, namespace, labels)
+			return nil
+		}
+	}
+	return errors.Errorf("Fargate profile %v has no selector for namespace %v with labels '%v'", profileName, namespace, labels)
+}
+
+func (c *ClientSet) ECRImageTagShouldExist(tag, repository string) error {
+	digest, err := c.GetECRImageDigest(repository, tag)
+	if err != nil {
+		return err
+	}
+	log.Infof("image tag %v exists in ECR repository %v with digest %v", tag, repository, digest)
+	return nil
+}
+
+// InvokeLambdaFunction synchronously invokes the function with the optional JSON payload and keeps its response for the following assertions.
+func (c *ClientSet) InvokeLambdaFunction(functionName, payload string) error {
+	if c.LambdaClient == nil {
+		return errors.Errorf("Unable to invoke Lambda function %v: The Lambda client was not found, use the method GetAWSCredsAndClients", functionName)
+	}
+	input := &lambda.InvokeInput{
+		FunctionName:   aws.String(functionName),
+		InvocationType: aws.String(lambda.InvocationTypeRequestResponse),
+	}
+	if payload != "" {
+		input.Payload = []byte(payload)
+	}
+	c.lambdaName, c.lambdaOutput = functionName, nil
+	out, err := c.LambdaClient.Invoke(input)
+	if err != nil {
+		return errors.Errorf("Failed invoking Lambda function %v: %v", functionName, err)
+	}
+	c.lambdaOutput = out
+	log.Infof("Lambda function %v returned status code %d", functionName, aws.Int64Value(out.StatusCode))
+	return nil
+}
+
+func (c *ClientSet) LambdaResponseStatusCodeShouldBe(expectedStatusCode int64) error {
+	out, err := c.getLambdaOutput()
+	if err != nil {
+		return err
+	}
+	if functionError := aws.StringValue(out.FunctionError); functionError != "" {
+		return errors.Errorf("Lambda function %v failed with %v error: %s", c.lambdaName, functionError, out.Payload)
+	}
+	if statusCode := aws.Int64Value(out.StatusCode); statusCode != expectedStatusCode {
+		return errors.Errorf("Lambda function %v returned status code %d, expected %d", c.lambdaName, statusCode, expectedStatusCode)
+	}
+	return nil
+}
+
+// LambdaResponseFieldShouldBe accepts kubectl style json path expressions with or without braces, e.g. '.status' or '{.items[0].name}'.
+func (c *ClientSet) LambdaResponseFieldShouldBe(jsonPath, expectedValue string) error {
+	out, err := c.getLambdaOutput()
+	if err != nil {
+		return err
+	}
+	value, err := getJSONPathValue(out.Payload, jsonPath)
+	if err != nil {
+		return errors.Errorf("Failed reading json path '%v' of Lambda function %v response: %v", jsonPath, c.lambdaName, err)
+	}
+	if value != expectedValue {
+		return errors.Errorf("json path '%v' of Lambda function %v response has value '%v' but expected '%v'", jsonPath, c.lambdaName, value, expectedValue)
+	}
+	return nil
+}
+
+// EventBridgeRuleShouldBeEnabled validates the rule exists and is enabled, on the default event bus if none is given.
+func (c *ClientSet) EventBridgeRuleShouldBeEnabled(ruleName, eventBusName string) error {
+	rule, err := c.getEventBridgeRule(ruleName, eventBusName)
+	if err != nil {
+		return err
+	}
+	if state := aws.StringValue(rule.State); !strings.HasPrefix(state, eventbridge.RuleStateEnabled) {
+		return errors.Errorf("EventBridge rule %v is %v, expected %v", ruleName, state, eventbridge.RuleStateEnabled)
+	}
+	log.Infof("EventBridge rule %v is %v", ruleName, aws.StringValue(rule.State))
+	return nil
+}
+
+// EventBridgeRuleShouldTarget validates the rule, on the default event bus if none is given, has a target with the ARN.
+func (c *ClientSet) EventBridgeRuleShouldTarget(ruleName, eventBusName, targetARN string) error {
+	if _, err := c.getEventBridgeRule(ruleName, eventBusName); err != nil {
+		return err
+	}
+	input := &eventbridge.ListTargetsByRuleInput{Rule: aws.String(ruleName)}
+	if eventBusName != "" {
+		input.EventBusName = aws.String(eventBusName)
+	}
+	for {
+		out, err := c.EventsClient.ListTargetsByRule(input)
+		if err != nil {
+			return errors.Errorf("Failed listing the targets of EventBridge rule %v: %v", ruleName, err)
+		}
+		if hasEventBridgeTarget(out.Targets, targetARN) {
+			break
+		}
+		if out.NextToken == nil {
+			return errors.Errorf("EventBridge rule %v has no target with ARN %v", ruleName, targetARN)
+		}
+		input.NextToken = out.NextToken
+	}
+	log.Infof("EventBridge rule %v targets %v", ruleName, targetARN)
+	return nil
+}
+
+// PutEventBridgeEvent puts an event with the JSON detail, an empty object if none is given, on the event bus.
+func (c *ClientSet) PutEventBridgeEvent(source, detailType, eventBusName, detail string) error {
+	if c.EventsClient == nil {
+		return errors.Errorf("Unable to put event on EventBridge event bus %v: The EventBridge client was not found, use the method GetAWSCredsAndClients", eventBusName)
+	}
+	if detail == "" {
+		detail = "{}"
+	}
+	out, err := c.EventsClient.PutEvents(&eventbridge.PutEventsInput{
+		Entries: []*eventbridge.PutEventsRequestEntry{
+			{
+				EventBusName: aws.String(eventBusName),
+				Source:       aws.String(source),
+				DetailType:   aws.String(detailType),
+				Detail:       aws.String(detail),
+			},
+		},
+	})
+	if err != nil {
+		return errors.Errorf("Failed putting event on EventBridge event bus %v: %v", eventBusName, err)
+	}
+	if aws.Int64Value(out.FailedEntryCount) > 0 {
+		entry := out.Entries[0]
+		return errors.Errorf("Failed putting event on EventBridge event bus %v: %v: %v", eventBusName, aws.StringValue(entry.ErrorCode), aws.StringValue(entry.ErrorMessage))
+	}
+	log.Infof("put event %v on EventBridge event bus %v", aws.StringValue(out.Entries[0].EventId), eventBusName)
+	return nil
+}
+
+// NLBShouldBe validates the load balancer with the DNS name is a network load balancer with the scheme and, when given, the target type for all its target groups.
+func (c *ClientSet) NLBShouldBe(dnsName, scheme, targetType string) error {
+	lb, err := c.getLoadBalancer(dnsName)
+	if err != nil {
+		return err
+	}
+	if lbType := aws.StringValue(lb.Type); lbType != elbv2.LoadBalancerTypeEnumNetwork {
+		return errors.Errorf("load balancer %v is of type %v, expected %v", dnsName, lbType, elbv2.LoadBalancerTypeEnumNetwork)
+	}
+	if lbScheme := aws.StringValue(lb.Scheme); lbScheme != scheme {
+		return errors.Errorf("NLB %v has scheme %v, expected %v", dnsName, lbScheme, scheme)
+	}
+	if targetType == "" {
+		return nil
+	}
+	targetGroups, err := c.getLoadBalancerTargetGroups(lb)
+	if err != nil {
+		return err
+	}
+	for _, targetGroup := range targetGroups {
+		if groupTargetType := aws.StringValue(targetGroup.TargetType); groupTargetType != targetType {
+			return errors.Errorf("target group %v of NLB %v has target type %v, expected %v", aws.StringValue(targetGroup.TargetGroupName), dnsName, groupTargetType, targetType)
+		}
+	}
+	log.Infof("NLB %v is %v with %v targets", dnsName, scheme, targetType)
+	return nil
+}
+
+// NLBTargetsShouldBeHealthy waits for all the registered targets of all the target groups of the load balancer with the DNS name to be healthy.
+func (c *ClientSet) NLBTargetsShouldBeHealthy(dnsName string) error {
+	var (
+		counter int
+		w       = c.getWaiterConfig()
+	)
+	lb, err := c.getLoadBalancer(dnsName)
+	if err != nil {
+		return err
+	}
+	targetGroups, err := c.getLoadBalancerTargetGroups(lb)
+	if err != nil {
+		return err
+	}
+	for {
+		if counter >= w.GetTries() {
+			return errors.Errorf("waiter timed out waiting for the targets of NLB %v to be healthy", dnsName)
+		}
+		unhealthy, err := c.getUnhealthyTargets(targetGroups)
+		if err != nil {
+			return err
+		}
+		if len(unhealthy) == 0 {
+			log.Infof("targets of NLB %v are healthy", dnsName)
+			return nil
+		}
+		log.Infof("waiting for the targets of NLB %v to be healthy, unhealthy: %v", dnsName, strings.Join(unhealthy, ", "))
+		counter++
+		if err := w.Sleep(c.getContext()); err != nil {
+			return err
+		}
+	}
+}
+
+// TerminateInstance terminates the EC2 instance, its Auto Scaling Group keeps the desired capacity and replaces it.
+func (c *ClientSet) TerminateInstance(instanceID string) error {
+	if c.EC2Client == nil {
+		return errors.Errorf("Unable to terminate EC2 instance %v: The EC2 client was not found, use the method GetAWSCredsAndClients", instanceID)
+	}
+	out, err := c.EC2Client.TerminateInstances(&ec2.TerminateInstancesInput{
+		InstanceIds: aws.StringSlice([]string{instanceID}),
+	})
+	if err != nil {
+		return errors.Errorf("Failed terminating EC2 instance %v: %v", instanceID, err)
+	}
+	for _, instance := range out.TerminatingInstances {
+		log.Infof("EC2 instance %v is %v", aws.StringValue(instance.InstanceId), aws.StringValue(instance.CurrentState.Name))
+	}
+	return nil
+}
+
 func (c *ClientSet) UpdateFieldOfCurrentASG(field, value string) error {
 	var (
 		err        error
@@ -285,6 +738,13 @@ func (c *ClientSet) ClusterSharedIamOperation(operation string) error {
 			return errors.Wrap(err, "failed to create shared cluster managed policy")
 		}
 		log.Infof("BDD >> created shared iam policy: %s", aws.StringValue(policy.Arn))
+
+		if _, err := kIam.WaitForIamRoleUsable(roleName, c.IAMClient); err != nil {
+			return errors.Wrap(err, "failed waiting for shared cluster role to propagate")
+		}
+		if _, err := kIam.WaitForManagedPolicyUsable(clusterSharedPolicy, c.IAMClient); err != nil {
+			return errors.Wrap(err, "failed waiting for shared cluster managed policy to propagate")
+		}
 	case "remove":
 		err := kIam.DeleteManagedPolicy(clusterSharedPolicy, c.IAMClient)
 		if err != nil {
@@ -314,3 +774,22 @@ func (c *ClientSet) DnsNameShouldOrNotInHostedZoneID(dnsName, shouldOrNot, hoste
 		return fmt.Errorf("invalid option '%s'. expected 'should' or 'should not'", shouldOrNot)
 	}
 }
+
+// DnsNameShouldResolve waits for the record of dnsName to be created in the hosted zone and to resolve
+func (c *ClientSet) DnsNameShouldResolve(dnsName, hostedZoneID string) error {
+	if c.Route53Client == nil {
+		return errors.Errorf("Unable to get records of %v: The Route53 client was not found, use the method DiscoverClients", dnsName)
+	}
+	backoff := util.GetExpBackoff(dnsPropagationSteps)
+	return util.RetryOnAnyError(&backoff, func() error {
+		if err := c.dnsNameInHostedZoneID(dnsName, hostedZoneID); err != nil {
+			return err
+		}
+		addresses, err := net.LookupHost(dnsName)
+		if err != nil {
+			return errors.Wrapf(err, "dnsName %s does not resolve", dnsName)
+		}
+		log.Infof("dnsName %s resolves to %v", dnsName, addresses)
+		return nil
+	})
+}
@@ -15,20 +15,40 @@ limitations under the License.
 package aws
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/util/jsonpath"
 )
 
 const (
+	launchTemplateVersionLatest    = "$Latest"
+	launchTemplateVersionDefault   = "$Default"
+	ssmImageIDPrefix               = "resolve:ssm:"
 	clusterNameEnvironmentVariable = "CLUSTER_NAME"
+	// external-dns syncs every minute by default, 8 exponential steps wait up to ~8 minutes
+	dnsPropagationSteps = 8
 )
 
 func (c *ClientSet) GetEksVpc() (string, error) {
@@ -46,6 +66,340 @@ func (c *ClientSet) GetEksVpc() (string, error) {
 	return aws.StringValue(result.Cluster.ResourcesVpcConfig.VpcId), nil
 }
 
+// GetFargateProfileSelectors returns the pod selectors of the Fargate profile of the cluster.
+func (c *ClientSet) GetFargateProfileSelectors(profileName string) ([]*eks.FargateProfileSelector, error) {
+	profile, err := c.getFargateProfile(profileName)
+	if err != nil {
+		return nil, err
+	}
+	return profile.Selectors, nil
+}
+
+func (c *ClientSet) getFargateProfile(profileName string) (*eks.FargateProfile, error) {
+	if c.EKSClient == nil {
+		return nil, errors.Errorf("Unable to get Fargate profile %v: The EKS client was not found, use the method GetAWSCredsAndClients", profileName)
+	}
+	clusterName, err := getClusterName()
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.EKSClient.DescribeFargateProfile(&eks.DescribeFargateProfileInput{
+		ClusterName:        aws.String(clusterName),
+		FargateProfileName: aws.String(profileName),
+	})
+	if err != nil {
+		return nil, errors.Errorf("Failed describing the Fargate profile %v of cluster %v: %v", profileName, clusterName, err)
+	} else if out.FargateProfile == nil {
+		return nil, errors.Errorf("No Fargate profile found by the name '%s' in cluster %v", profileName, clusterName)
+	}
+	return out.FargateProfile, nil
+}
+
+// GetECRImageDigest returns the digest of the image tag in the ECR repository.
+func (c *ClientSet) GetECRImageDigest(repository, tag string) (string, error) {
+	if c.ECRClient == nil {
+		return "", errors.Errorf("Unable to get image tag %v of ECR repository %v: The ECR client was not found, use the method GetAWSCredsAndClients", tag, repository)
+	}
+	out, err := c.ECRClient.DescribeImages(&ecr.DescribeImagesInput{
+		RepositoryName: aws.String(repository),
+		ImageIds:       []*ecr.ImageIdentifier{{ImageTag: aws.String(tag)}},
+	})
+	if err != nil {
+		return "", errors.Errorf("Failed describing the image tag %v of ECR repository %v: %v", tag, repository, err)
+	} else if len(out.ImageDetails) == 0 {
+		return "", errors.Errorf("No image found by the tag '%s' in ECR repository %v", tag, repository)
+	}
+	return aws.StringValue(out.ImageDetails[0].ImageDigest), nil
+}
+
+func (c *ClientSet) getEventBridgeRule(ruleName, eventBusName string) (*eventbridge.DescribeRuleOutput, error) {
+	if c.EventsClient == nil {
+		return nil, errors.Errorf("Unable to get EventBridge rule %v: The EventBridge client was not found, use the method GetAWSCredsAndClients", ruleName)
+	}
+	input := &eventbridge.DescribeRuleInput{Name: aws.String(ruleName)}
+	if eventBusName != "" {
+		input.EventBusName = aws.String(eventBusName)
+	}
+	out, err := c.EventsClient.DescribeRule(input)
+	if err != nil {
+		return nil, errors.Errorf("Failed describing the EventBridge rule %v: %v", ruleName, err)
+	}
+	return out, nil
+}
+
+func hasEventBridgeTarget(targets []*eventbridge.Target, targetARN string) bool {
+	for _, target := range targets {
+		if aws.StringValue(target.Arn) == targetARN {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ClientSet) getLoadBalancer(dnsName string) (*elbv2.LoadBalancer, error) {
+	if c.ELBv2Client == nil {
+		return nil, errors.Errorf("Unable to get load balancer %v: The ELBv2 client was not found, use the method GetAWSCredsAndClients", dnsName)
+	}
+	var lb *elbv2.LoadBalancer
+	err := c.ELBv2Client.DescribeLoadBalancersPages(&elbv2.DescribeLoadBalancersInput{}, func(page *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+		for _, l := range page.LoadBalancers {
+			if strings.EqualFold(aws.StringValue(l.DNSName), dnsName) {
+				lb = l
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Errorf("Failed describing the load balancers: %v", err)
+	} else if lb == nil {
+		return nil, errors.Errorf("No load balancer found by the DNS name '%s'", dnsName)
+	}
+	return lb, nil
+}
+
+func (c *ClientSet) getLoadBalancerTargetGroups(lb *elbv2.LoadBalancer) ([]*elbv2.TargetGroup, error) {
+	out, err := c.ELBv2Client.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+		LoadBalancerArn: lb.LoadBalancerArn,
+	})
+	if err != nil {
+		return nil, errors.Errorf("Failed describing the target groups of load balancer %v: %v", aws.StringValue(lb.LoadBalancerName), err)
+	} else if len(out.TargetGroups) == 0 {
+		return nil, errors.Errorf("No target group found for load balancer %v", aws.StringValue(lb.LoadBalancerName))
+	}
+	return out.TargetGroups, nil
+}
+
+// getUnhealthyTargets returns '<target group>/<target id>: <state>' for every target not healthy, or the target group if it has no targets
+func (c *ClientSet) getUnhealthyTargets(targetGroups []*elbv2.TargetGroup) ([]string, error) {
+	var unhealthy []string
+	for _, targetGroup := range targetGroups {
+		groupName := aws.StringValue(targetGroup.TargetGroupName)
+		out, err := c.ELBv2Client.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
+			TargetGroupArn: targetGroup.TargetGroupArn,
+		})
+		if err != nil {
+			return nil, errors.Errorf("Failed describing the target health of target group %v: %v", groupName, err)
+		}
+		if len(out.TargetHealthDescriptions) == 0 {
+			unhealthy = append(unhealthy, fmt.Sprintf("%v: no targets", groupName))
+		}
+		for _, target := range out.TargetHealthDescriptions {
+			if state := aws.StringValue(target.TargetHealth.State); state != elbv2.TargetHealthStateEnumHealthy {
+				unhealthy = append(unhealthy, fmt.Sprintf("%v/%v: %v", groupName, aws.StringValue(target.Target.Id), state))
+			}
+		}
+	}
+	return unhealthy, nil
+}
+
+func (c *ClientSet) getLambdaOutput() (*lambda.InvokeOutput, error) {
+	if c.lambdaOutput == nil {
+		return nil, errors.Errorf("No Lambda function response found, use the method InvokeLambdaFunction")
+	}
+	return c.lambdaOutput, nil
+}
+
+func getJSONPathValue(data []byte, jsonPath string) (string, error) {
+	expression := jsonPath
+	if !strings.HasPrefix(expression, "{") {
+		expression = fmt.Sprintf("{%s}", expression)
+	}
+	parser := jsonpath.New("response")
+	if err := parser.Parse(expression); err != nil {
+		return "", err
+	}
+	var object interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return "", errors.Wrapf(err, "'%s' is not valid json", data)
+	}
+	var value bytes.Buffer
+	if err := parser.Execute(&value, object); err != nil {
+		return "", err
+	}
+	return value.String(), nil
+}
+
+func (c *ClientSet) newSession(profile string) (*session.Session, error) {
+	options := session.Options{}
+	if profile != "" {
+		log.Infof("using AWS profile %v", profile)
+		options.Profile = profile
+		options.SharedConfigState = session.SharedConfigEnable
+	}
+	if c.endpoint != "" {
+		log.Infof("using AWS endpoint %v", c.endpoint)
+		options.Config.Endpoint = aws.String(c.endpoint)
+	}
+	return session.NewSessionWithOptions(options)
+}
+
+func (c *ClientSet) GetCurrentASGName() (string, error) {
+	if c.asgName == "" {
+		return "", errors.Errorf("No current Auto Scaling Group, use the step 'an Auto Scaling Group named'")
+	}
+	return c.asgName, nil
+}
+
+func (c *ClientSet) getContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+func (c *ClientSet) getWaiterConfig() common.WaiterConfig {
+	defaultWaiterInterval := time.Second * 30
+	defaultWaiterTries := 40
+	interval, tries := c.waiterInterval, c.waiterTries
+	if interval <= 0 {
+		interval = defaultWaiterInterval
+	}
+	if tries <= 0 {
+		tries = defaultWaiterTries
+	}
+	return common.NewWaiterConfig(tries, interval)
+}
+
+func (c *ClientSet) getCurrentASG() (*autoscaling.Group, error) {
+	if c.ASClient == nil {
+		return nil, errors.Errorf("Unable to get current ASG: The AS client was not found, use the method GetAWSCredsAndClients")
+	}
+	asgName, err := c.GetCurrentASGName()
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.ASClient.DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{aws.String(asgName)},
+	})
+	if err != nil {
+		return nil, errors.Errorf("Failed describing the ASG %v: %v", asgName, err)
+	} else if len(out.AutoScalingGroups) == 0 {
+		return nil, errors.Errorf("No ASG found by the name: '%s'", asgName)
+	}
+	return out.AutoScalingGroups[0], nil
+}
+
+var (
+	lifecycleTransitions = map[string]string{
+		"launching":   "autoscaling:EC2_INSTANCE_LAUNCHING",
+		"terminating": "autoscaling:EC2_INSTANCE_TERMINATING",
+	}
+	lifecycleActionResults = map[string]string{
+		"complete": "CONTINUE",
+		"abandon":  "ABANDON",
+	}
+)
+
+func (c *ClientSet) getCurrentASGLifecycleHook(hookName string) (*autoscaling.LifecycleHook, error) {
+	if c.ASClient == nil {
+		return nil, errors.Errorf("Unable to get lifecycle hook %v: The AS client was not found, use the method GetAWSCredsAndClients", hookName)
+	}
+	asgName, err := c.GetCurrentASGName()
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.ASClient.DescribeLifecycleHooks(&autoscaling.DescribeLifecycleHooksInput{
+		AutoScalingGroupName: aws.String(asgName),
+		LifecycleHookNames:   []*string{aws.String(hookName)},
+	})
+	if err != nil {
+		return nil, errors.Errorf("Failed describing the lifecycle hooks of ASG %v: %v", asgName, err)
+	} else if len(out.LifecycleHooks) == 0 {
+		return nil, errors.Errorf("No lifecycle hook found by the name '%s' in ASG %v", hookName, asgName)
+	}
+	return out.LifecycleHooks[0], nil
+}
+
+func (c *ClientSet) getCurrentASGLaunchTemplate() (*autoscaling.LaunchTemplateSpecification, error) {
+	asg, err := c.getCurrentASG()
+	if err != nil {
+		return nil, err
+	}
+	if asg.LaunchTemplate != nil {
+		return asg.LaunchTemplate, nil
+	}
+	if asg.MixedInstancesPolicy != nil && asg.MixedInstancesPolicy.LaunchTemplate != nil && asg.MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification != nil {
+		return asg.MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification, nil
+	}
+	return nil, errors.Errorf("ASG %v does not use a launch template", c.asgName)
+}
+
+// resolveLaunchTemplateVersion returns the version number $Latest and $Default point to, or version as is
+func (c *ClientSet) resolveLaunchTemplateVersion(launchTemplate *autoscaling.LaunchTemplateSpecification, version string) (string, error) {
+	if version != launchTemplateVersionLatest && version != launchTemplateVersionDefault && version != "" {
+		return version, nil
+	}
+	if c.EC2Client == nil {
+		return "", errors.Errorf("Unable to describe launch template %v: The EC2 client was not found, use the method GetAWSCredsAndClients", getLaunchTemplateIdentifier(launchTemplate))
+	}
+	input := &ec2.DescribeLaunchTemplatesInput{}
+	if launchTemplate.LaunchTemplateId != nil {
+		input.LaunchTemplateIds = []*string{launchTemplate.LaunchTemplateId}
+	} else {
+		input.LaunchTemplateNames = []*string{launchTemplate.LaunchTemplateName}
+	}
+	out, err := c.EC2Client.DescribeLaunchTemplates(input)
+	if err != nil {
+		return "", errors.Errorf("Failed describing the launch template %v: %v", getLaunchTemplateIdentifier(launchTemplate), err)
+	} else if len(out.LaunchTemplates) == 0 {
+		return "", errors.Errorf("No launch template found by: '%s'", getLaunchTemplateIdentifier(launchTemplate))
+	}
+	if version == launchTemplateVersionLatest {
+		return fmt.Sprint(aws.Int64Value(out.LaunchTemplates[0].LatestVersionNumber)), nil
+	}
+	// the ASG uses the default version when none is set
+	return fmt.Sprint(aws.Int64Value(out.LaunchTemplates[0].DefaultVersionNumber)), nil
+}
+
+func (c *ClientSet) getLaunchTemplateImageID(launchTemplate *autoscaling.LaunchTemplateSpecification, version string) (string, error) {
+	if c.EC2Client == nil {
+		return "", errors.Errorf("Unable to describe launch template %v: The EC2 client was not found, use the method GetAWSCredsAndClients", getLaunchTemplateIdentifier(launchTemplate))
+	}
+	input := &ec2.DescribeLaunchTemplateVersionsInput{
+		Versions: []*string{aws.String(version)},
+	}
+	if launchTemplate.LaunchTemplateId != nil {
+		input.LaunchTemplateId = launchTemplate.LaunchTemplateId
+	} else {
+		input.LaunchTemplateName = launchTemplate.LaunchTemplateName
+	}
+	out, err := c.EC2Client.DescribeLaunchTemplateVersions(input)
+	if err != nil {
+		return "", errors.Errorf("Failed describing version %v of the launch template %v: %v", version, getLaunchTemplateIdentifier(launchTemplate), err)
+	} else if len(out.LaunchTemplateVersions) == 0 || out.LaunchTemplateVersions[0].LaunchTemplateData == nil {
+		return "", errors.Errorf("No version %v found for the launch template %v", version, getLaunchTemplateIdentifier(launchTemplate))
+	}
+	imageID := aws.StringValue(out.LaunchTemplateVersions[0].LaunchTemplateData.ImageId)
+	// the image can be resolved from an SSM parameter when instances launch
+	if strings.HasPrefix(imageID, ssmImageIDPrefix) {
+		return c.getSSMParameterValue(strings.TrimPrefix(imageID, ssmImageIDPrefix))
+	}
+	return imageID, nil
+}
+
+func (c *ClientSet) getSSMParameterValue(name string) (string, error) {
+	if c.SSMClient == nil {
+		return "", errors.Errorf("Unable to get SSM parameter %v: The SSM client was not found, use the method GetAWSCredsAndClients", name)
+	}
+	out, err := c.SSMClient.GetParameter(&ssm.GetParameterInput{
+		Name: aws.String(name),
+	})
+	if err != nil {
+		return "", errors.Errorf("Failed getting the SSM parameter %v: %v", name, err)
+	} else if out.Parameter == nil {
+		return "", errors.Errorf("No SSM parameter found by the name: '%s'", name)
+	}
+	return aws.StringValue(out.Parameter.Value), nil
+}
+
+func getLaunchTemplateIdentifier(launchTemplate *autoscaling.LaunchTemplateSpecification) string {
+	if launchTemplate.LaunchTemplateName != nil {
+		return aws.StringValue(launchTemplate.LaunchTemplateName)
+	}
+	return aws.StringValue(launchTemplate.LaunchTemplateId)
+}
+
 func getAccountNumber(svc stsiface.STSAPI) string {
 	// Region is defaulted to "us-west-2"
 	input := &sts.GetCallerIdentityInput{}
@@ -15,12 +15,36 @@ limitations under the License.
 package aws
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/eks/eksiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/onsi/gomega"
@@ -32,8 +56,10 @@ const (
 
 type mockAutoScalingClient struct {
 	autoscalingiface.AutoScalingAPI
-	ASGs []*autoscaling.Group
-	Err  error
+	ASGs             []*autoscaling.Group
+	LifecycleHooks   []*autoscaling.LifecycleHook
+	CompletedActions []*autoscaling.CompleteLifecycleActionInput
+	Err              error
 }
 
 type STSMocker struct {
@@ -48,6 +74,312 @@ func (s *STSMocker) GetCallerIdentity(*sts.GetCallerIdentityInput) (*sts.GetCall
 	return output, nil
 }
 
+type mockRoute53Client struct {
+	route53iface.Route53API
+	RecordSets []*route53.ResourceRecordSet
+}
+
+func (m *mockRoute53Client) ListResourceRecordSets(*route53.ListResourceRecordSetsInput) (*route53.ListResourceRecordSetsOutput, error) {
+	return &route53.ListResourceRecordSetsOutput{ResourceRecordSets: m.RecordSets}, nil
+}
+
+type mockEC2Client struct {
+	ec2iface.EC2API
+	LaunchTemplates []*ec2.LaunchTemplate
+	ImageIDs        map[string]string
+	Instances       map[string]string
+}
+
+func (m *mockEC2Client) TerminateInstances(input *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
+	out := &ec2.TerminateInstancesOutput{}
+	for _, instanceID := range aws.StringValueSlice(input.InstanceIds) {
+		if _, ok := m.Instances[instanceID]; !ok {
+			return nil, errors.New("InvalidInstanceID.NotFound")
+		}
+		m.Instances[instanceID] = ec2.InstanceStateNameShuttingDown
+		out.TerminatingInstances = append(out.TerminatingInstances, &ec2.InstanceStateChange{
+			InstanceId:   aws.String(instanceID),
+			CurrentState: &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameShuttingDown)},
+		})
+	}
+	return out, nil
+}
+
+func (m *mockEC2Client) DescribeLaunchTemplates(*ec2.DescribeLaunchTemplatesInput) (*ec2.DescribeLaunchTemplatesOutput, error) {
+	return &ec2.DescribeLaunchTemplatesOutput{LaunchTemplates: m.LaunchTemplates}, nil
+}
+
+func (m *mockEC2Client) DescribeLaunchTemplateVersions(input *ec2.DescribeLaunchTemplateVersionsInput) (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+	out := &ec2.DescribeLaunchTemplateVersionsOutput{}
+	if imageID, ok := m.ImageIDs[aws.StringValue(input.Versions[0])]; ok {
+		out.LaunchTemplateVersions = []*ec2.LaunchTemplateVersion{
+			{LaunchTemplateData: &ec2.ResponseLaunchTemplateData{ImageId: aws.String(imageID)}},
+		}
+	}
+	return out, nil
+}
+
+type mockEKSClient struct {
+	eksiface.EKSAPI
+	FargateProfiles []*eks.FargateProfile
+}
+
+func (m *mockEKSClient) DescribeFargateProfile(input *eks.DescribeFargateProfileInput) (*eks.DescribeFargateProfileOutput, error) {
+	for _, profile := range m.FargateProfiles {
+		if aws.StringValue(profile.FargateProfileName) == aws.StringValue(input.FargateProfileName) {
+			return &eks.DescribeFargateProfileOutput{FargateProfile: profile}, nil
+		}
+	}
+	return nil, errors.New("ResourceNotFoundException")
+}
+
+type mockECRClient struct {
+	ecriface.ECRAPI
+	ImageDigests map[string]string
+}
+
+func (m *mockECRClient) DescribeImages(input *ecr.DescribeImagesInput) (*ecr.DescribeImagesOutput, error) {
+	digest, ok := m.ImageDigests[aws.StringValue(input.RepositoryName)+":"+aws.StringValue(input.ImageIds[0].ImageTag)]
+	if !ok {
+		return nil, errors.New("ImageNotFoundException")
+	}
+	return &ecr.DescribeImagesOutput{ImageDetails: []*ecr.ImageDetail{{ImageDigest: aws.String(digest)}}}, nil
+}
+
+type mockLambdaClient struct {
+	lambdaiface.LambdaAPI
+	Output *lambda.InvokeOutput
+	Input  *lambda.InvokeInput
+	Err    error
+}
+
+func (m *mockLambdaClient) Invoke(input *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+	m.Input = input
+	return m.Output, m.Err
+}
+
+type mockEventBridgeClient struct {
+	eventbridgeiface.EventBridgeAPI
+	Rules   []*eventbridge.Rule
+	Targets map[string][]*eventbridge.Target
+	Events  []*eventbridge.PutEventsRequestEntry
+	PutErr  string
+}
+
+func (m *mockEventBridgeClient) DescribeRule(input *eventbridge.DescribeRuleInput) (*eventbridge.DescribeRuleOutput, error) {
+	for _, rule := range m.Rules {
+		if aws.StringValue(rule.Name) == aws.StringValue(input.Name) && aws.StringValue(rule.EventBusName) == aws.StringValue(input.EventBusName) {
+			return &eventbridge.DescribeRuleOutput{Name: rule.Name, EventBusName: rule.EventBusName, State: rule.State}, nil
+		}
+	}
+	return nil, errors.New("ResourceNotFoundException")
+}
+
+func (m *mockEventBridgeClient) ListTargetsByRule(input *eventbridge.ListTargetsByRuleInput) (*eventbridge.ListTargetsByRuleOutput, error) {
+	// one target per page
+	targets := m.Targets[aws.StringValue(input.Rule)]
+	var i int
+	if input.NextToken != nil {
+		i = int(aws.StringValue(input.NextToken)[0] - '0')
+	}
+	out := &eventbridge.ListTargetsByRuleOutput{}
+	if i < len(targets) {
+		out.Targets = targets[i : i+1]
+	}
+	if i+1 < len(targets) {
+		out.NextToken = aws.String(string(rune('0' + i + 1)))
+	}
+	return out, nil
+}
+
+func (m *mockEventBridgeClient) PutEvents(input *eventbridge.PutEventsInput) (*eventbridge.PutEventsOutput, error) {
+	m.Events = append(m.Events, input.Entries...)
+	if m.PutErr != "" {
+		return &eventbridge.PutEventsOutput{
+			FailedEntryCount: aws.Int64(1),
+			Entries:          []*eventbridge.PutEventsResultEntry{{ErrorCode: aws.String(m.PutErr)}},
+		}, nil
+	}
+	return &eventbridge.PutEventsOutput{
+		FailedEntryCount: aws.Int64(0),
+		Entries:          []*eventbridge.PutEventsResultEntry{{EventId: aws.String("event-1")}},
+	}, nil
+}
+
+type mockELBv2Client struct {
+	elbv2iface.ELBV2API
+	LoadBalancers []*elbv2.LoadBalancer
+	TargetGroups  map[string][]*elbv2.TargetGroup
+	TargetHealth  map[string][]*elbv2.TargetHealthDescription
+}
+
+func (m *mockELBv2Client) DescribeLoadBalancersPages(input *elbv2.DescribeLoadBalancersInput, fn func(*elbv2.DescribeLoadBalancersOutput, bool) bool) error {
+	for i, lb := range m.LoadBalancers {
+		if !fn(&elbv2.DescribeLoadBalancersOutput{LoadBalancers: []*elbv2.LoadBalancer{lb}}, i == len(m.LoadBalancers)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func (m *mockELBv2Client) DescribeTargetGroups(input *elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error) {
+	return &elbv2.DescribeTargetGroupsOutput{TargetGroups: m.TargetGroups[aws.StringValue(input.LoadBalancerArn)]}, nil
+}
+
+func (m *mockELBv2Client) DescribeTargetHealth(input *elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error) {
+	return &elbv2.DescribeTargetHealthOutput{TargetHealthDescriptions: m.TargetHealth[aws.StringValue(input.TargetGroupArn)]}, nil
+}
+
+type mockSSMClient struct {
+	ssmiface.SSMAPI
+	Parameters map[string]string
+}
+
+func (m *mockSSMClient) GetParameter(input *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
+	value, ok := m.Parameters[aws.StringValue(input.Name)]
+	if !ok {
+		return nil, errors.New("ParameterNotFound")
+	}
+	return &ssm.GetParameterOutput{Parameter: &ssm.Parameter{Value: aws.String(value)}}, nil
+}
+
+func newLaunchTemplateClientSet(version string) ClientSet {
+	return ClientSet{
+		ASClient: &mockAutoScalingClient{
+			ASGs: []*autoscaling.Group{
+				{
+					AutoScalingGroupName: aws.String("ASG-name-1"),
+					LaunchTemplate: &autoscaling.LaunchTemplateSpecification{
+						LaunchTemplateName: aws.String("launch-template-1"),
+						Version:            aws.String(version),
+					},
+				},
+			},
+		},
+		EC2Client: &mockEC2Client{
+			LaunchTemplates: []*ec2.LaunchTemplate{
+				{
+					LaunchTemplateName:   aws.String("launch-template-1"),
+					LatestVersionNumber:  aws.Int64(3),
+					DefaultVersionNumber: aws.Int64(2),
+				},
+			},
+			ImageIDs: map[string]string{
+				"2": "ami-00000000000000002",
+				"3": "resolve:ssm:/aws/service/eks/optimized-ami/1.28/amazon-linux-2/recommended/image_id",
+			},
+		},
+		SSMClient: &mockSSMClient{
+			Parameters: map[string]string{
+				"/aws/service/eks/optimized-ami/1.28/amazon-linux-2/recommended/image_id": "ami-00000000000000003",
+				"/kubedog/previous-ami": "ami-00000000000000002",
+			},
+		},
+		asgName: "ASG-name-1",
+	}
+}
+
+func TestCurrentASGLaunchTemplateVersionShouldBe(t *testing.T) {
+	tests := []struct {
+		name            string
+		version         string
+		expectedVersion string
+		wantErr         bool
+	}{
+		{name: "same version", version: "2", expectedVersion: "2"},
+		{name: "same alias", version: "$Latest", expectedVersion: "$Latest"},
+		{name: "latest resolved", version: "3", expectedVersion: "$Latest"},
+		{name: "expected resolved", version: "$Default", expectedVersion: "2"},
+		{name: "not latest", version: "2", expectedVersion: "$Latest", wantErr: true},
+		{name: "different version", version: "1", expectedVersion: "2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newLaunchTemplateClientSet(tt.version)
+			if err := client.CurrentASGLaunchTemplateVersionShouldBe(tt.expectedVersion); (err != nil) != tt.wantErr {
+				t.Errorf("CurrentASGLaunchTemplateVersionShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// No launch template
+	client := newLaunchTemplateClientSet("1")
+	client.ASClient.(*mockAutoScalingClient).ASGs[0].LaunchTemplate = nil
+	if err := client.CurrentASGLaunchTemplateVersionShouldBe("1"); err == nil {
+		t.Errorf("CurrentASGLaunchTemplateVersionShouldBe() expected error without a launch template")
+	}
+}
+
+func TestCurrentASGLaunchTemplateAMIShouldMatchSSMParameter(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   string
+		parameter string
+		wantErr   bool
+	}{
+		{name: "image id", version: "2", parameter: "/kubedog/previous-ami"},
+		{name: "image resolved from SSM", version: "$Latest", parameter: "/aws/service/eks/optimized-ami/1.28/amazon-linux-2/recommended/image_id"},
+		{name: "different image", version: "$Default", parameter: "/aws/service/eks/optimized-ami/1.28/amazon-linux-2/recommended/image_id", wantErr: true},
+		{name: "missing parameter", version: "2", parameter: "/kubedog/missing", wantErr: true},
+		{name: "missing version", version: "1", parameter: "/kubedog/previous-ami", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newLaunchTemplateClientSet(tt.version)
+			if err := client.CurrentASGLaunchTemplateAMIShouldMatchSSMParameter(tt.parameter); (err != nil) != tt.wantErr {
+				t.Errorf("CurrentASGLaunchTemplateAMIShouldMatchSSMParameter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDnsNameShouldResolve(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	// Not Route53Client
+	err := (&ClientSet{}).DnsNameShouldResolve("localhost", "some-hosted-zone-id")
+	g.Expect(err).Should(gomega.HaveOccurred())
+
+	client := ClientSet{Route53Client: &mockRoute53Client{
+		RecordSets: []*route53.ResourceRecordSet{
+			{
+				Name:            aws.String("localhost"),
+				ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("127.0.0.1")}},
+			},
+		},
+	}}
+	err = client.DnsNameShouldResolve("localhost", "some-hosted-zone-id")
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+}
+
+func TestGetCurrentASGDesiredCapacity(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	// Empty client
+	_, err := (&ClientSet{}).GetCurrentASGDesiredCapacity()
+	g.Expect(err).Should(gomega.HaveOccurred())
+
+	client := ClientSet{
+		ASClient: &mockAutoScalingClient{
+			ASGs: []*autoscaling.Group{
+				{
+					AutoScalingGroupName: aws.String("ASG-name-1"),
+					DesiredCapacity:      aws.Int64(3),
+				},
+			},
+		},
+		asgName: "ASG-name-1",
+	}
+	capacity, err := client.GetCurrentASGDesiredCapacity()
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(capacity).To(gomega.Equal(int64(3)))
+
+	// ASG not found
+	client.asgName = "ASG-name-2"
+	_, err = client.GetCurrentASGDesiredCapacity()
+	g.Expect(err).Should(gomega.HaveOccurred())
+}
+
 func TestAnASGNamed(t *testing.T) {
 	var (
 		g     = gomega.NewWithT(t)
@@ -256,3 +588,511 @@ func (asc *mockAutoScalingClient) DescribeAutoScalingGroups(input *autoscaling.D
 	}
 	return out, asc.Err
 }
+
+func (asc *mockAutoScalingClient) DescribeLifecycleHooks(input *autoscaling.DescribeLifecycleHooksInput) (*autoscaling.DescribeLifecycleHooksOutput, error) {
+	hooks := []*autoscaling.LifecycleHook{}
+	for _, hook := range asc.LifecycleHooks {
+		for _, name := range aws.StringValueSlice(input.LifecycleHookNames) {
+			if aws.StringValue(hook.LifecycleHookName) == name && aws.StringValue(hook.AutoScalingGroupName) == aws.StringValue(input.AutoScalingGroupName) {
+				hooks = append(hooks, hook)
+			}
+		}
+	}
+	return &autoscaling.DescribeLifecycleHooksOutput{LifecycleHooks: hooks}, asc.Err
+}
+
+func (asc *mockAutoScalingClient) CompleteLifecycleAction(input *autoscaling.CompleteLifecycleActionInput) (*autoscaling.CompleteLifecycleActionOutput, error) {
+	asc.CompletedActions = append(asc.CompletedActions, input)
+	return &autoscaling.CompleteLifecycleActionOutput{}, asc.Err
+}
+
+func newLifecycleHookClientSet() (ClientSet, *mockAutoScalingClient) {
+	asClient := &mockAutoScalingClient{
+		LifecycleHooks: []*autoscaling.LifecycleHook{
+			{
+				AutoScalingGroupName: aws.String("ASG-name-1"),
+				LifecycleHookName:    aws.String("node-drainer"),
+				LifecycleTransition:  aws.String("autoscaling:EC2_INSTANCE_TERMINATING"),
+			},
+		},
+	}
+	return ClientSet{ASClient: asClient, asgName: "ASG-name-1"}, asClient
+}
+
+func TestCurrentASGShouldHaveLifecycleHook(t *testing.T) {
+	tests := []struct {
+		name       string
+		hookName   string
+		transition string
+		wantErr    bool
+	}{
+		{name: "hook exists", hookName: "node-drainer"},
+		{name: "hook exists for transition", hookName: "node-drainer", transition: "terminating"},
+		{name: "hook for other transition", hookName: "node-drainer", transition: "launching", wantErr: true},
+		{name: "unsupported transition", hookName: "node-drainer", transition: "rebooting", wantErr: true},
+		{name: "hook missing", hookName: "node-bootstrapper", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newLifecycleHookClientSet()
+			if err := client.CurrentASGShouldHaveLifecycleHook(tt.hookName, tt.transition); (err != nil) != tt.wantErr {
+				t.Errorf("CurrentASGShouldHaveLifecycleHook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLifecycleActionOperation(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	client, asClient := newLifecycleHookClientSet()
+	err := client.LifecycleActionOperation("abandon", "node-drainer", "i-0123456789")
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(asClient.CompletedActions).To(gomega.HaveLen(1))
+	g.Expect(aws.StringValue(asClient.CompletedActions[0].LifecycleActionResult)).To(gomega.Equal("ABANDON"))
+	g.Expect(aws.StringValue(asClient.CompletedActions[0].InstanceId)).To(gomega.Equal("i-0123456789"))
+
+	// Unsupported operation
+	err = client.LifecycleActionOperation("retry", "node-drainer", "i-0123456789")
+	g.Expect(err).Should(gomega.HaveOccurred())
+
+	// Missing hook
+	err = client.LifecycleActionOperation("complete", "node-bootstrapper", "i-0123456789")
+	g.Expect(err).Should(gomega.HaveOccurred())
+
+	// Error completing the action
+	asClient.Err = errors.New("ValidationError")
+	err = client.LifecycleActionOperation("complete", "node-drainer", "i-0123456789")
+	g.Expect(err).Should(gomega.HaveOccurred())
+}
+
+func TestCurrentASGInstancesShouldBe(t *testing.T) {
+	client := ClientSet{
+		ASClient: &mockAutoScalingClient{
+			ASGs: []*autoscaling.Group{
+				{
+					AutoScalingGroupName: aws.String("ASG-name-1"),
+					Instances: []*autoscaling.Instance{
+						{InstanceId: aws.String("i-1"), LifecycleState: aws.String("InService")},
+						{InstanceId: aws.String("i-2"), LifecycleState: aws.String("InService")},
+						{InstanceId: aws.String("i-3"), LifecycleState: aws.String("Pending")},
+					},
+				},
+			},
+		},
+		asgName: "ASG-name-1",
+	}
+	client.SetWaiterTries(2)
+	client.SetWaiterInterval(time.Millisecond)
+
+	tests := []struct {
+		name           string
+		count          int64
+		lifecycleState string
+		wantErr        bool
+	}{
+		{name: "instances in service", count: 2, lifecycleState: "InService"},
+		{name: "case insensitive state", count: 1, lifecycleState: "pending"},
+		{name: "no instances terminated", count: 0, lifecycleState: "Terminated"},
+		{name: "timed out", count: 3, lifecycleState: "InService", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := client.CurrentASGInstancesShouldBe(tt.count, tt.lifecycleState); (err != nil) != tt.wantErr {
+				t.Errorf("CurrentASGInstancesShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// No current ASG
+	if err := (&ClientSet{ASClient: &mockAutoScalingClient{}}).CurrentASGInstancesShouldBe(1, "InService"); err == nil {
+		t.Errorf("CurrentASGInstancesShouldBe() expected error without a current ASG")
+	}
+
+	// The context of the scenario is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.SetContext(ctx)
+	client.SetWaiterTries(100)
+	client.SetWaiterInterval(time.Minute)
+	if err := client.CurrentASGInstancesShouldBe(3, "InService"); !errors.Is(err, context.Canceled) {
+		t.Errorf("CurrentASGInstancesShouldBe() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestFargateProfileShouldExist(t *testing.T) {
+	t.Setenv(clusterNameEnvironmentVariable, "cluster-1")
+	client := ClientSet{
+		EKSClient: &mockEKSClient{
+			FargateProfiles: []*eks.FargateProfile{
+				{
+					FargateProfileName: aws.String("default-profile"),
+					Status:             aws.String(eks.FargateProfileStatusActive),
+					Selectors: []*eks.FargateProfileSelector{
+						{Namespace: aws.String("default")},
+						{Namespace: aws.String("kube-system"), Labels: map[string]*string{"k8s-app": aws.String("kube-dns")}},
+					},
+				},
+				{
+					FargateProfileName: aws.String("new-profile"),
+					Status:             aws.String(eks.FargateProfileStatusCreating),
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		profileName string
+		namespace   string
+		labels      string
+		wantErr     bool
+	}{
+		{name: "profile active", profileName: "default-profile"},
+		{name: "selector for namespace", profileName: "default-profile", namespace: "default"},
+		{name: "selector for namespace and labels", profileName: "default-profile", namespace: "kube-system", labels: "k8s-app=kube-dns"},
+		{name: "selector with different labels", profileName: "default-profile", namespace: "kube-system", labels: "k8s-app=coredns", wantErr: true},
+		{name: "selector without labels", profileName: "default-profile", namespace: "kube-system", wantErr: true},
+		{name: "no selector for namespace", profileName: "default-profile", namespace: "monitoring", wantErr: true},
+		{name: "profile not active", profileName: "new-profile", wantErr: true},
+		{name: "profile missing", profileName: "missing-profile", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := client.FargateProfileShouldExist(tt.profileName, tt.namespace, tt.labels); (err != nil) != tt.wantErr {
+				t.Errorf("FargateProfileShouldExist() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// No EKS client
+	if err := (&ClientSet{}).FargateProfileShouldExist("default-profile", "", ""); err == nil {
+		t.Errorf("FargateProfileShouldExist() expected error without an EKS client")
+	}
+}
+
+func TestECRImageTagShouldExist(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	// No ECR client
+	err := (&ClientSet{}).ECRImageTagShouldExist("v1.2.3", "my-team/my-app")
+	g.Expect(err).Should(gomega.HaveOccurred())
+
+	client := ClientSet{ECRClient: &mockECRClient{
+		ImageDigests: map[string]string{"my-team/my-app:v1.2.3": "sha256:0123456789abcdef"},
+	}}
+	err = client.ECRImageTagShouldExist("v1.2.3", "my-team/my-app")
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+	digest, err := client.GetECRImageDigest("my-team/my-app", "v1.2.3")
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(digest).To(gomega.Equal("sha256:0123456789abcdef"))
+
+	// Missing tag
+	err = client.ECRImageTagShouldExist("v1.2.4", "my-team/my-app")
+	g.Expect(err).Should(gomega.HaveOccurred())
+}
+
+func TestLambdaFunction(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	// No Lambda client
+	err := (&ClientSet{}).InvokeLambdaFunction("node-lifecycle-handler", "")
+	g.Expect(err).Should(gomega.HaveOccurred())
+
+	// No response
+	lambdaClient := &mockLambdaClient{}
+	client := ClientSet{LambdaClient: lambdaClient}
+	err = client.LambdaResponseStatusCodeShouldBe(200)
+	g.Expect(err).Should(gomega.HaveOccurred())
+
+	lambdaClient.Output = &lambda.InvokeOutput{
+		StatusCode: aws.Int64(200),
+		Payload:    []byte(`{"statusCode":200,"body":{"drained":true,"nodes":["node-1"]}}`),
+	}
+	err = client.InvokeLambdaFunction("node-lifecycle-handler", `{"detail-type":"test"}`)
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(string(lambdaClient.Input.Payload)).To(gomega.Equal(`{"detail-type":"test"}`))
+	g.Expect(client.LambdaResponseStatusCodeShouldBe(200)).ShouldNot(gomega.HaveOccurred())
+	g.Expect(client.LambdaResponseStatusCodeShouldBe(202)).Should(gomega.HaveOccurred())
+	g.Expect(client.LambdaResponseFieldShouldBe(".statusCode", "200")).ShouldNot(gomega.HaveOccurred())
+	g.Expect(client.LambdaResponseFieldShouldBe("{.body.nodes[0]}", "node-1")).ShouldNot(gomega.HaveOccurred())
+	g.Expect(client.LambdaResponseFieldShouldBe(".body.drained", "false")).Should(gomega.HaveOccurred())
+	g.Expect(client.LambdaResponseFieldShouldBe(".body.missing", "")).Should(gomega.HaveOccurred())
+
+	// Function error
+	lambdaClient.Output = &lambda.InvokeOutput{
+		StatusCode:    aws.Int64(200),
+		FunctionError: aws.String("Unhandled"),
+		Payload:       []byte(`{"errorMessage":"timeout"}`),
+	}
+	err = client.InvokeLambdaFunction("node-lifecycle-handler", "")
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(lambdaClient.Input.Payload).To(gomega.BeNil())
+	g.Expect(client.LambdaResponseStatusCodeShouldBe(200)).Should(gomega.HaveOccurred())
+
+	// Error invoking the function
+	lambdaClient.Err = errors.New("ResourceNotFoundException")
+	err = client.InvokeLambdaFunction("node-lifecycle-handler", "")
+	g.Expect(err).Should(gomega.HaveOccurred())
+	g.Expect(client.LambdaResponseFieldShouldBe(".errorMessage", "timeout")).Should(gomega.HaveOccurred())
+}
+
+func newEventBridgeClientSet() (ClientSet, *mockEventBridgeClient) {
+	eventsClient := &mockEventBridgeClient{
+		Rules: []*eventbridge.Rule{
+			{Name: aws.String("node-termination"), State: aws.String(eventbridge.RuleStateEnabled)},
+			{Name: aws.String("node-termination"), EventBusName: aws.String("platform"), State: aws.String(eventbridge.RuleStateDisabled)},
+		},
+		Targets: map[string][]*eventbridge.Target{
+			"node-termination": {
+				{Arn: aws.String("arn:aws:sqs:us-west-2:123456789012:node-termination")},
+				{Arn: aws.String("arn:aws:lambda:us-west-2:123456789012:function:node-lifecycle-handler")},
+			},
+		},
+	}
+	return ClientSet{EventsClient: eventsClient}, eventsClient
+}
+
+func TestEventBridgeRuleShouldBeEnabled(t *testing.T) {
+	tests := []struct {
+		name         string
+		ruleName     string
+		eventBusName string
+		wantErr      bool
+	}{
+		{name: "enabled on default bus", ruleName: "node-termination"},
+		{name: "disabled on custom bus", ruleName: "node-termination", eventBusName: "platform", wantErr: true},
+		{name: "rule missing", ruleName: "node-launch", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newEventBridgeClientSet()
+			if err := client.EventBridgeRuleShouldBeEnabled(tt.ruleName, tt.eventBusName); (err != nil) != tt.wantErr {
+				t.Errorf("EventBridgeRuleShouldBeEnabled() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// No EventBridge client
+	if err := (&ClientSet{}).EventBridgeRuleShouldBeEnabled("node-termination", ""); err == nil {
+		t.Errorf("EventBridgeRuleShouldBeEnabled() expected error without an EventBridge client")
+	}
+}
+
+func TestEventBridgeRuleShouldTarget(t *testing.T) {
+	tests := []struct {
+		name      string
+		ruleName  string
+		targetARN string
+		wantErr   bool
+	}{
+		{name: "first target", ruleName: "node-termination", targetARN: "arn:aws:sqs:us-west-2:123456789012:node-termination"},
+		{name: "target on next page", ruleName: "node-termination", targetARN: "arn:aws:lambda:us-west-2:123456789012:function:node-lifecycle-handler"},
+		{name: "target missing", ruleName: "node-termination", targetARN: "arn:aws:sns:us-west-2:123456789012:alerts", wantErr: true},
+		{name: "rule missing", ruleName: "node-launch", targetARN: "arn:aws:sqs:us-west-2:123456789012:node-termination", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newEventBridgeClientSet()
+			if err := client.EventBridgeRuleShouldTarget(tt.ruleName, "", tt.targetARN); (err != nil) != tt.wantErr {
+				t.Errorf("EventBridgeRuleShouldTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPutEventBridgeEvent(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	// No EventBridge client
+	err := (&ClientSet{}).PutEventBridgeEvent("kubedog.test", "Test Event", "default", "")
+	g.Expect(err).Should(gomega.HaveOccurred())
+
+	client, eventsClient := newEventBridgeClientSet()
+	err = client.PutEventBridgeEvent("kubedog.test", "Test Event", "default", "")
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	err = client.PutEventBridgeEvent("kubedog.test", "Test Event", "platform", `{"instance-id":"i-0123456789"}`)
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(eventsClient.Events).To(gomega.HaveLen(2))
+	g.Expect(aws.StringValue(eventsClient.Events[0].Detail)).To(gomega.Equal("{}"))
+	g.Expect(aws.StringValue(eventsClient.Events[1].Detail)).To(gomega.Equal(`{"instance-id":"i-0123456789"}`))
+	g.Expect(aws.StringValue(eventsClient.Events[1].EventBusName)).To(gomega.Equal("platform"))
+
+	// Failed entry
+	eventsClient.PutErr = "MalformedDetail"
+	err = client.PutEventBridgeEvent("kubedog.test", "Test Event", "default", "not-json")
+	g.Expect(err).Should(gomega.HaveOccurred())
+}
+
+func TestTerminateInstance(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	// No EC2 client
+	err := (&ClientSet{}).TerminateInstance("i-0123456789")
+	g.Expect(err).Should(gomega.HaveOccurred())
+
+	ec2Client := &mockEC2Client{Instances: map[string]string{"i-0123456789": ec2.InstanceStateNameRunning}}
+	client := &ClientSet{EC2Client: ec2Client}
+	err = client.TerminateInstance("i-0123456789")
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(ec2Client.Instances["i-0123456789"]).To(gomega.Equal(ec2.InstanceStateNameShuttingDown))
+
+	// Instance not found
+	err = client.TerminateInstance("i-9876543210")
+	g.Expect(err).Should(gomega.HaveOccurred())
+}
+
+func TestNewSession(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	config := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(config, []byte("[profile staging]\nregion = eu-west-1\n"), 0644)
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	t.Setenv("AWS_CONFIG_FILE", config)
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AWS_SDK_LOAD_CONFIG", "")
+
+	client := ClientSet{}
+	client.SetProfile("staging")
+	sess, err := client.newSession(client.profile)
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(aws.StringValue(sess.Config.Region)).To(gomega.Equal("eu-west-1"))
+	g.Expect(sess.Config.Endpoint).To(gomega.BeNil())
+
+	// Endpoint override
+	client.SetEndpoint("http://localhost:4566")
+	sess, err = client.newSession(client.profile)
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(aws.StringValue(sess.Config.Endpoint)).To(gomega.Equal("http://localhost:4566"))
+	g.Expect(aws.StringValue(sess.Config.Region)).To(gomega.Equal("eu-west-1"))
+	client.SetEndpoint("")
+
+	// Default credential chain
+	client.SetProfile("")
+	sess, err = client.newSession(client.profile)
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(aws.StringValue(sess.Config.Region)).To(gomega.BeEmpty())
+}
+
+func TestDiscoverClientsWithProfile(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	var accessKeyID string
+	stsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the credential of the signature starts with the access key id
+		credential := strings.SplitN(r.Header.Get("Authorization"), "Credential=", 2)
+		g.Expect(credential).To(gomega.HaveLen(2))
+		accessKeyID = strings.SplitN(credential[1], "/", 2)[0]
+		fmt.Fprint(w, `<GetCallerIdentityResponse><GetCallerIdentityResult><Arn>arn:aws:iam::123456789012:user/kubedog</Arn></GetCallerIdentityResult></GetCallerIdentityResponse>`)
+	}))
+	defer stsServer.Close()
+
+	config := filepath.Join(t.TempDir(), "config")
+	profiles := "[profile staging]\nregion = eu-west-1\naws_access_key_id = STAGING\naws_secret_access_key = secret\n" +
+		"[profile production]\nregion = eu-west-1\naws_access_key_id = PRODUCTION\naws_secret_access_key = secret\n"
+	err := os.WriteFile(config, []byte(profiles), 0644)
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	t.Setenv("AWS_CONFIG_FILE", config)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+
+	client := ClientSet{}
+	client.SetProfile("staging")
+	client.SetEndpoint(stsServer.URL)
+
+	err = client.DiscoverClientsWithProfile("production")
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(accessKeyID).To(gomega.Equal("PRODUCTION"))
+
+	// The profile of the step does not replace the configured one
+	err = client.DiscoverClients()
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(accessKeyID).To(gomega.Equal("STAGING"))
+}
+
+func newNLBClientSet() ClientSet {
+	newTargetHealth := func(id, state string) *elbv2.TargetHealthDescription {
+		return &elbv2.TargetHealthDescription{
+			Target:       &elbv2.TargetDescription{Id: aws.String(id)},
+			TargetHealth: &elbv2.TargetHealth{State: aws.String(state)},
+		}
+	}
+	client := ClientSet{
+		ELBv2Client: &mockELBv2Client{
+			LoadBalancers: []*elbv2.LoadBalancer{
+				{LoadBalancerArn: aws.String("alb-1"), DNSName: aws.String("alb-1.elb.amazonaws.com"), Type: aws.String(elbv2.LoadBalancerTypeEnumApplication), Scheme: aws.String("internal")},
+				{LoadBalancerArn: aws.String("nlb-1"), DNSName: aws.String("nlb-1.elb.amazonaws.com"), Type: aws.String(elbv2.LoadBalancerTypeEnumNetwork), Scheme: aws.String("internal")},
+				{LoadBalancerArn: aws.String("nlb-2"), DNSName: aws.String("nlb-2.elb.amazonaws.com"), Type: aws.String(elbv2.LoadBalancerTypeEnumNetwork), Scheme: aws.String("internet-facing")},
+			},
+			TargetGroups: map[string][]*elbv2.TargetGroup{
+				"nlb-1": {{TargetGroupArn: aws.String("tg-1"), TargetGroupName: aws.String("tg-1"), TargetType: aws.String("ip")}},
+				"nlb-2": {
+					{TargetGroupArn: aws.String("tg-2"), TargetGroupName: aws.String("tg-2"), TargetType: aws.String("instance")},
+					{TargetGroupArn: aws.String("tg-3"), TargetGroupName: aws.String("tg-3"), TargetType: aws.String("instance")},
+				},
+			},
+			TargetHealth: map[string][]*elbv2.TargetHealthDescription{
+				"tg-1": {newTargetHealth("10.0.0.1", "healthy"), newTargetHealth("10.0.0.2", "healthy")},
+				"tg-2": {newTargetHealth("i-1", "healthy")},
+				"tg-3": {newTargetHealth("i-1", "unhealthy")},
+			},
+		},
+	}
+	client.SetWaiterTries(2)
+	client.SetWaiterInterval(time.Millisecond)
+	return client
+}
+
+func TestNLBShouldBe(t *testing.T) {
+	tests := []struct {
+		name       string
+		dnsName    string
+		scheme     string
+		targetType string
+		wantErr    bool
+	}{
+		{name: "internal", dnsName: "nlb-1.elb.amazonaws.com", scheme: "internal"},
+		{name: "internal with ip targets", dnsName: "NLB-1.elb.amazonaws.com", scheme: "internal", targetType: "ip"},
+		{name: "internet-facing with instance targets", dnsName: "nlb-2.elb.amazonaws.com", scheme: "internet-facing", targetType: "instance"},
+		{name: "different scheme", dnsName: "nlb-2.elb.amazonaws.com", scheme: "internal", wantErr: true},
+		{name: "different target type", dnsName: "nlb-1.elb.amazonaws.com", scheme: "internal", targetType: "instance", wantErr: true},
+		{name: "not an NLB", dnsName: "alb-1.elb.amazonaws.com", scheme: "internal", wantErr: true},
+		{name: "load balancer missing", dnsName: "nlb-3.elb.amazonaws.com", scheme: "internal", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newNLBClientSet()
+			if err := client.NLBShouldBe(tt.dnsName, tt.scheme, tt.targetType); (err != nil) != tt.wantErr {
+				t.Errorf("NLBShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// No ELBv2 client
+	if err := (&ClientSet{}).NLBShouldBe("nlb-1.elb.amazonaws.com", "internal", ""); err == nil {
+		t.Errorf("NLBShouldBe() expected error without an ELBv2 client")
+	}
+}
+
+func TestNLBTargetsShouldBeHealthy(t *testing.T) {
+	tests := []struct {
+		name    string
+		dnsName string
+		wantErr bool
+	}{
+		{name: "healthy targets", dnsName: "nlb-1.elb.amazonaws.com"},
+		{name: "unhealthy target", dnsName: "nlb-2.elb.amazonaws.com", wantErr: true},
+		{name: "no target groups", dnsName: "alb-1.elb.amazonaws.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newNLBClientSet()
+			if err := client.NLBTargetsShouldBeHealthy(tt.dnsName); (err != nil) != tt.wantErr {
+				t.Errorf("NLBTargetsShouldBeHealthy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"net/url"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -26,8 +27,18 @@ import (
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/keikoproj/kubedog/internal/util"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
+// propagationRetry waits up to about two minutes, new roles and policies usually propagate in ten seconds
+var propagationRetry = wait.Backoff{
+	Steps:    10,
+	Duration: 2 * time.Second,
+	Factor:   1.5,
+	Jitter:   0.1,
+	Cap:      30 * time.Second,
+}
+
 func GetIamRole(roleName string, iamClient iamiface.IAMAPI) (*iam.Role, error) {
 	params := &iam.GetRoleInput{
 		RoleName: aws.String(roleName),
@@ -49,6 +60,10 @@ func PutIAMRole(name, description string, policyJSON []byte, iamClient iamiface.
 		if err != nil {
 			return nil, err
 		}
+		// a new role can be missing from reads for a few seconds
+		if _, err := WaitForIamRoleUsable(name, iamClient); err != nil {
+			return out, err
+		}
 		return out, nil
 	}
 	// If role already exits just update assume role policy
@@ -59,6 +74,30 @@ func PutIAMRole(name, description string, policyJSON []byte, iamClient iamiface.
 	return out, nil
 }
 
+// WaitForIamRoleUsable retries getting the role until IAM propagates it. Only the propagation to GetRole is awaited, the
+// trust policy of a new role can take a few more seconds before the role can be assumed
+func WaitForIamRoleUsable(roleName string, iamClient iamiface.IAMAPI) (*iam.Role, error) {
+	out, err := util.RetryOnError(&propagationRetry, isPropagating, func() (interface{}, error) {
+		return iamClient.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("iam role %q did not propagate. %v", roleName, err)
+	}
+	log.Infof("iam role %q is usable", roleName)
+	return out.(*iam.GetRoleOutput).Role, nil
+}
+
+// WaitForManagedPolicyUsable retries getting the managed policy until IAM propagates it
+func WaitForManagedPolicyUsable(arn string, iamClient iamiface.IAMAPI) (*iam.Policy, error) {
+	out, err := util.RetryOnError(&propagationRetry, isPropagating, func() (interface{}, error) {
+		return iamClient.GetPolicy(&iam.GetPolicyInput{PolicyArn: aws.String(arn)})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("managed policy %q did not propagate. %v", arn, err)
+	}
+	return out.(*iam.GetPolicyOutput).Policy, nil
+}
+
 func UpdateIAMAssumeRole(roleName string, policyJSON []byte, iamClient iamiface.IAMAPI) (*iam.UpdateAssumeRolePolicyOutput, error) {
 	json := string(policyJSON)
 	params := &iam.UpdateAssumeRolePolicyInput{
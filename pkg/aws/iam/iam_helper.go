@@ -151,6 +151,14 @@ func isThrottling(err error) bool {
 	return false
 }
 
+// isPropagating returns true for the errors of roles and policies IAM has not propagated yet
+func isPropagating(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == iam.ErrCodeNoSuchEntityException {
+		return true
+	}
+	return isThrottling(err)
+}
+
 func createIAMRole(name, description string, policyJSON []byte, iamClient iamiface.IAMAPI, tags ...*iam.Tag) (*iam.Role, error) {
 	json := string(policyJSON)
 	role := &iam.CreateRoleInput{
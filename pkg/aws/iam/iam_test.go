@@ -19,9 +19,11 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/yaml"
 )
 
@@ -162,6 +164,62 @@ func TestPutIAMRole(t *testing.T) {
 	g.Expect(output).ToNot(gomega.BeNil())
 }
 
+// propagatingIAMClient fails to get the role until it was called the number of propagation calls
+type propagatingIAMClient struct {
+	FakeIAMClient
+	propagationCalls int
+	calls            int
+}
+
+func (fiam *propagatingIAMClient) GetRole(roleInput *iam.GetRoleInput) (*iam.GetRoleOutput, error) {
+	fiam.calls++
+	if fiam.calls <= fiam.propagationCalls {
+		return nil, awserr.New(iam.ErrCodeNoSuchEntityException, "role not found", nil)
+	}
+	return fiam.FakeIAMClient.GetRole(roleInput)
+}
+
+func TestWaitForIamRoleUsable(t *testing.T) {
+	defaultRetry := propagationRetry
+	propagationRetry = wait.Backoff{Steps: 3, Duration: time.Millisecond}
+	defer func() { propagationRetry = defaultRetry }()
+
+	tests := []struct {
+		name      string
+		iamClient *propagatingIAMClient
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "Positive Test: role exists",
+			iamClient: &propagatingIAMClient{},
+			wantCalls: 1,
+		},
+		{
+			name:      "Positive Test: role propagates",
+			iamClient: &propagatingIAMClient{propagationCalls: 2},
+			wantCalls: 3,
+		},
+		{
+			name:      "Negative Test: role does not propagate",
+			iamClient: &propagatingIAMClient{propagationCalls: 3},
+			wantCalls: 3,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := WaitForIamRoleUsable("test-role", tt.iamClient)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WaitForIamRoleUsable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.iamClient.calls != tt.wantCalls {
+				t.Errorf("WaitForIamRoleUsable() got the role %d times, want %d", tt.iamClient.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
 func TestPutManagedPolicy(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
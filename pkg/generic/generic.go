@@ -16,44 +16,62 @@ package generic
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
 	log "github.com/sirupsen/logrus"
 )
 
+// WaitFor pauses for the duration, logging the progress every minute or every 30 seconds
 func WaitFor(duration int, durationUnits string) error {
+	return WaitForCtx(context.Background(), duration, durationUnits)
+}
+
+// WaitForCtx is WaitFor, returning early when the context is done
+func WaitForCtx(ctx context.Context, duration int, durationUnits string) error {
+	unit, increments, err := getWaitIncrements(duration, durationUnits)
+	if err != nil {
+		return err
+	}
+	var waited time.Duration
+	for _, increment := range increments {
+		if err := common.NewWaiterConfig(1, increment).Sleep(ctx); err != nil {
+			return err
+		}
+		waited += increment
+		log.Infof("waited '%d' out of '%d' '%s'", waited/unit, duration, durationUnits)
+	}
+	return nil
+}
+
+// getWaitIncrements returns the unit of the duration and the increments to wait for it, of a minute or of 30 seconds
+func getWaitIncrements(duration int, durationUnits string) (time.Duration, []time.Duration, error) {
+	var unit, tick time.Duration
 	switch durationUnits {
 	case util.DurationMinutes:
-		increment := 1
-		d := increment
-		for d <= duration {
-			time.Sleep(time.Duration(increment) * time.Minute)
-			log.Infof("waited '%d' out of '%d' '%s'", d, duration, durationUnits)
-			d += increment
-		}
-		return nil
+		unit, tick = time.Minute, time.Minute
 	case util.DurationSeconds:
-		increment := 30
-		d := increment
-		for d <= duration {
-			time.Sleep(time.Duration(increment) * time.Second)
-			log.Infof("waited '%d' out of '%d' '%s'", d, duration, durationUnits)
-			d += increment
-		}
-		lastIncrement := duration - d + increment
-		if lastIncrement > 0 {
-			time.Sleep(time.Duration(lastIncrement) * time.Second)
-			d += lastIncrement - increment
-			log.Infof("waited '%d' out of '%d' '%s'", d, duration, durationUnits)
-		}
-		return nil
+		unit, tick = time.Second, 30*time.Second
 	default:
-		return fmt.Errorf("unsupported duration units: '%s'", durationUnits)
+		return 0, nil, fmt.Errorf("unsupported duration units: '%s'", durationUnits)
+	}
+
+	var increments []time.Duration
+	total := time.Duration(duration) * unit
+	for waited := time.Duration(0); waited < total; {
+		increment := tick
+		if remaining := total - waited; remaining < increment {
+			increment = remaining
+		}
+		increments = append(increments, increment)
+		waited += increment
 	}
+	return unit, increments, nil
 }
 
 func CommandExists(command string) error {
@@ -15,9 +15,74 @@ limitations under the License.
 package generic
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/internal/util"
 )
 
+func TestGetWaitIncrements(t *testing.T) {
+	tests := []struct {
+		name           string
+		duration       int
+		durationUnits  string
+		wantIncrements []time.Duration
+		wantErr        bool
+	}{
+		{
+			name:           "Positive Test: minutes",
+			duration:       2,
+			durationUnits:  util.DurationMinutes,
+			wantIncrements: []time.Duration{time.Minute, time.Minute},
+		},
+		{
+			name:           "Positive Test: seconds with a remainder",
+			duration:       70,
+			durationUnits:  util.DurationSeconds,
+			wantIncrements: []time.Duration{30 * time.Second, 30 * time.Second, 10 * time.Second},
+		},
+		{
+			name:          "Positive Test: zero",
+			duration:      0,
+			durationUnits: util.DurationSeconds,
+		},
+		{
+			name:          "Negative Test: unsupported units",
+			duration:      1,
+			durationUnits: "hours",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, increments, err := getWaitIncrements(tt.duration, tt.durationUnits)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getWaitIncrements() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(increments, tt.wantIncrements) {
+				t.Errorf("getWaitIncrements() = %v, want %v", increments, tt.wantIncrements)
+			}
+		})
+	}
+}
+
+func TestWaitForCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := WaitForCtx(ctx, 1, util.DurationMinutes); !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitForCtx() error = %v, expected the canceled context to stop the wait", err)
+	}
+	if err := WaitForCtx(context.Background(), 0, util.DurationSeconds); err != nil {
+		t.Errorf("WaitForCtx() error = %v", err)
+	}
+	if err := WaitForCtx(context.Background(), 1, "hours"); err == nil {
+		t.Errorf("WaitForCtx() expected an error for unsupported units")
+	}
+}
+
 func TestCommandExists(t *testing.T) {
 	type args struct {
 		command string
@@ -0,0 +1,81 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"context"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/dynamic"
+)
+
+// ApplicationShouldBe waits for the sync status or the health status of the application, depending on the expected status.
+func ApplicationShouldBe(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, name, namespace, status string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+	statusPath, err := getStatusPath(status)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for application %v/%v to be %v", namespace, name, status)
+		}
+		log.Infof("waiting for application %v/%v to be %v", namespace, name, status)
+		application, err := getApplication(dynamicClient, name, namespace)
+		if err != nil {
+			return err
+		}
+		currentStatus := getStatus(application, statusPath)
+		if currentStatus == status {
+			log.Infof("application %v/%v is %v", namespace, name, status)
+			return nil
+		}
+		log.Infof("application %v/%v is %v", namespace, name, currentStatus)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// SyncApplication triggers a sync of the application to its current sync revision by setting its operation field.
+func SyncApplication(dynamicClient dynamic.Interface, name, namespace string) error {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+	application, err := getApplication(dynamicClient, name, namespace)
+	if err != nil {
+		return err
+	}
+	if operation, ok := application.Object["operation"]; ok && operation != nil {
+		return errors.Errorf("application %v/%v already has an operation in progress", namespace, name)
+	}
+	patch, err := getSyncOperationPatch(application)
+	if err != nil {
+		return err
+	}
+	if err := patchApplication(dynamicClient, name, namespace, patch); err != nil {
+		return errors.Wrapf(err, "failed to sync application %v/%v", namespace, name)
+	}
+	log.Infof("triggered sync of application %v/%v", namespace, name)
+	return nil
+}
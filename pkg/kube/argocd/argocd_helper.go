@@ -0,0 +1,91 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	SyncStatusSynced    = "Synced"
+	SyncStatusOutOfSync = "OutOfSync"
+
+	HealthStatusHealthy     = "Healthy"
+	HealthStatusProgressing = "Progressing"
+	HealthStatusDegraded    = "Degraded"
+	HealthStatusSuspended   = "Suspended"
+	HealthStatusMissing     = "Missing"
+
+	syncInitiator = "kubedog"
+)
+
+var ApplicationResource = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}
+
+func getApplication(dynamicClient dynamic.Interface, name, namespace string) (*unstructured.Unstructured, error) {
+	application, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return dynamicClient.Resource(ApplicationResource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get application '%v'", name)
+	}
+	return application.(*unstructured.Unstructured), nil
+}
+
+func patchApplication(dynamicClient dynamic.Interface, name, namespace string, patch []byte) error {
+	_, err := dynamicClient.Resource(ApplicationResource).Namespace(namespace).Patch(context.Background(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func getStatusPath(status string) ([]string, error) {
+	switch status {
+	case SyncStatusSynced, SyncStatusOutOfSync:
+		return []string{"status", "sync", "status"}, nil
+	case HealthStatusHealthy, HealthStatusProgressing, HealthStatusDegraded, HealthStatusSuspended, HealthStatusMissing:
+		return []string{"status", "health", "status"}, nil
+	default:
+		return nil, common.Errorf(common.ErrUnsupportedOperation, "unsupported application status: '%s'", status)
+	}
+}
+
+func getStatus(application *unstructured.Unstructured, statusPath []string) string {
+	status, _, _ := unstructured.NestedString(application.Object, statusPath...)
+	return status
+}
+
+// getSyncOperationPatch builds the same operation 'argocd app sync' sets, syncing to the revision the application last compared against
+func getSyncOperationPatch(application *unstructured.Unstructured) ([]byte, error) {
+	sync := map[string]interface{}{}
+	if revision, _, _ := unstructured.NestedString(application.Object, "status", "sync", "revision"); revision != "" {
+		sync["revision"] = revision
+	}
+	return json.Marshal(map[string]interface{}{
+		"operation": map[string]interface{}{
+			"initiatedBy": map[string]interface{}{
+				"username": syncInitiator,
+			},
+			"sync": sync,
+		},
+	})
+}
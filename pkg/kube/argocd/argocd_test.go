@@ -0,0 +1,129 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/internal/testutil"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	applicationName = "application1"
+	namespace       = "argocd"
+	revision        = "0123456789abcdef"
+)
+
+func TestApplicationShouldBe(t *testing.T) {
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		status        string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: application synced",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newApplication(SyncStatusSynced, HealthStatusProgressing)),
+			status:        SyncStatusSynced,
+		},
+		{
+			name:          "Positive Test: application healthy",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newApplication(SyncStatusOutOfSync, HealthStatusHealthy)),
+			status:        HealthStatusHealthy,
+		},
+		{
+			name:          "Negative Test: application out of sync",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newApplication(SyncStatusOutOfSync, HealthStatusHealthy)),
+			status:        SyncStatusSynced,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: unsupported status",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newApplication(SyncStatusSynced, HealthStatusHealthy)),
+			status:        "Running",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: application not found",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources),
+			status:        SyncStatusSynced,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid client",
+			dynamicClient: nil,
+			status:        SyncStatusSynced,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ApplicationShouldBe(context.Background(), tt.dynamicClient, common.NewWaiterConfig(1, time.Millisecond), applicationName, namespace, tt.status); (err != nil) != tt.wantErr {
+				t.Errorf("ApplicationShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSyncApplication(t *testing.T) {
+	dynamicClient := testutil.NewFakeDynamicClient(t, fakeResources, newApplication(SyncStatusOutOfSync, HealthStatusHealthy))
+	if err := SyncApplication(dynamicClient, applicationName, namespace); err != nil {
+		t.Fatalf("SyncApplication() error = %v", err)
+	}
+	application, err := getApplication(dynamicClient, applicationName, namespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _, _ := unstructured.NestedString(application.Object, "operation", "sync", "revision"); got != revision {
+		t.Errorf("SyncApplication() sync revision = '%v', want '%v'", got, revision)
+	}
+	if got, _, _ := unstructured.NestedString(application.Object, "operation", "initiatedBy", "username"); got != syncInitiator {
+		t.Errorf("SyncApplication() initiated by = '%v', want '%v'", got, syncInitiator)
+	}
+	if err := SyncApplication(dynamicClient, applicationName, namespace); err == nil {
+		t.Errorf("SyncApplication() expected error with an operation in progress")
+	}
+}
+
+// fakeResources are the resources of the kinds tracked by the fake dynamic client
+var fakeResources = map[string]schema.GroupVersionResource{
+	"Application": ApplicationResource,
+}
+
+func newApplication(syncStatus, healthStatus string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata": map[string]interface{}{
+			"name":      applicationName,
+			"namespace": namespace,
+		},
+		"status": map[string]interface{}{
+			"sync": map[string]interface{}{
+				"status":   syncStatus,
+				"revision": revision,
+			},
+			"health": map[string]interface{}{
+				"status": healthStatus,
+			},
+		},
+	}}
+}
@@ -0,0 +1,186 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/pkg/errors"
+)
+
+// Entry is a mutating request sent to the Kubernetes API server
+type Entry struct {
+	Time        time.Time `json:"time"`
+	Operation   string    `json:"operation"`
+	APIVersion  string    `json:"apiVersion"`
+	Kind        string    `json:"kind"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Subresource string    `json:"subresource,omitempty"`
+	DryRun      bool      `json:"dryRun,omitempty"`
+	StatusCode  int       `json:"statusCode,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	// Diff is the list of fields changed by an update or patch
+	Diff []string `json:"diff,omitempty"`
+}
+
+// Log records the create, update, patch and delete requests sent through the transports it wraps
+type Log struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// Wrap returns a transport recording the mutating requests sent through rt, it can be used with rest.Config.Wrap
+func (l *Log) Wrap(rt http.RoundTripper) http.RoundTripper {
+	return &transport{log: l, next: rt}
+}
+
+// Entries returns a copy of the recorded entries
+func (l *Log) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Entry(nil), l.entries...)
+}
+
+// Export writes the recorded entries as JSON to a file named after name in artifactsPath and resets the log,
+// it returns an empty path when there are no entries
+func (l *Log) Export(artifactsPath, name string) (string, error) {
+	l.mu.Lock()
+	entries := l.entries
+	l.entries = nil
+	l.mu.Unlock()
+
+	if len(entries) == 0 {
+		return "", nil
+	}
+	if err := os.MkdirAll(artifactsPath, 0755); err != nil {
+		return "", errors.Wrapf(err, "failed to create artifacts directory '%s'", artifactsPath)
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	filePath := filepath.Join(artifactsPath, fmt.Sprintf("mutations-%s-%d.json", util.SanitizeFileName(name), time.Now().Unix()))
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return "", errors.Wrapf(err, "failed to write mutations to '%s'", filePath)
+	}
+	return filePath, nil
+}
+
+func (l *Log) record(entry Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+}
+
+type transport struct {
+	log  *Log
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	operation, ok := operations[req.Method]
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+	info, ok := parseRequestPath(req.URL.Path)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	entry := Entry{
+		Time:        time.Now(),
+		Operation:   operation,
+		APIVersion:  info.apiVersion(),
+		Kind:        info.resource,
+		Namespace:   info.namespace,
+		Name:        info.name,
+		Subresource: info.subresource,
+		DryRun:      req.URL.Query().Has("dryRun"),
+	}
+	requestObject := decodeObject(body)
+	switch operation {
+	case operationUpdate:
+		if info.subresource == "" {
+			entry.Diff = t.getUpdateDiff(req, requestObject)
+		}
+	case operationPatch:
+		entry.Diff = getPatchedFields(req.Header.Get("Content-Type"), body)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		entry.Error = err.Error()
+		t.log.record(entry)
+		return resp, err
+	}
+	entry.StatusCode = resp.StatusCode
+	responseObject, err := readResponseObject(resp)
+	if err != nil {
+		return nil, err
+	}
+	for _, object := range []map[string]interface{}{requestObject, responseObject} {
+		if kind, _ := object["kind"].(string); kind != "" && kind != "Status" && kind != "DeleteOptions" {
+			entry.Kind = kind
+			if name := getObjectName(object); entry.Name == "" && name != "" {
+				entry.Name = name
+			}
+			break
+		}
+	}
+	t.log.record(entry)
+	return resp, nil
+}
+
+// getUpdateDiff returns the fields changed by an update compared to the current object
+func (t *transport) getUpdateDiff(req *http.Request, object map[string]interface{}) []string {
+	if object == nil {
+		return nil
+	}
+	getReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, req.URL.String(), nil)
+	if err != nil {
+		return nil
+	}
+	getReq.Header = req.Header.Clone()
+	getReq.URL.RawQuery = ""
+	resp, err := t.next.RoundTrip(getReq)
+	if err != nil {
+		return nil
+	}
+	current, err := readResponseObject(resp)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	return getChangedFields("", pruneObject(current), pruneObject(object))
+}
@@ -0,0 +1,206 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	operationCreate = "create"
+	operationUpdate = "update"
+	operationPatch  = "patch"
+	operationDelete = "delete"
+)
+
+var operations = map[string]string{
+	http.MethodPost:   operationCreate,
+	http.MethodPut:    operationUpdate,
+	http.MethodPatch:  operationPatch,
+	http.MethodDelete: operationDelete,
+}
+
+// requestInfo is the resource of a request path, e.g. /apis/apps/v1/namespaces/default/deployments/web/scale
+type requestInfo struct {
+	group, version, namespace, resource, name, subresource string
+}
+
+func (i requestInfo) apiVersion() string {
+	if i.group == "" {
+		return i.version
+	}
+	return i.group + "/" + i.version
+}
+
+// parseRequestPath returns the resource of a request path, it returns false for paths that are not resources, e.g. /version
+func parseRequestPath(path string) (requestInfo, bool) {
+	var info requestInfo
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) >= 3 && parts[0] == "api":
+		info.version, parts = parts[1], parts[2:]
+	case len(parts) >= 4 && parts[0] == "apis":
+		info.group, info.version, parts = parts[1], parts[2], parts[3:]
+	default:
+		return info, false
+	}
+	// namespaces are resources themselves unless followed by a resource
+	if len(parts) >= 3 && parts[0] == "namespaces" {
+		info.namespace, parts = parts[1], parts[2:]
+	}
+	info.resource = parts[0]
+	if len(parts) > 1 {
+		info.name = parts[1]
+	}
+	if len(parts) > 2 {
+		info.subresource = strings.Join(parts[2:], "/")
+	}
+	return info, true
+}
+
+// readResponseObject decodes the body of the response as an object and restores it for the caller
+func readResponseObject(resp *http.Response) (map[string]interface{}, error) {
+	if resp.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return decodeObject(body), nil
+}
+
+// decodeObject returns the JSON object in data, or nil if it is not one
+func decodeObject(data []byte) map[string]interface{} {
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil
+	}
+	return object
+}
+
+func getObjectName(object map[string]interface{}) string {
+	metadata, _ := object["metadata"].(map[string]interface{})
+	if name, _ := metadata["name"].(string); name != "" {
+		return name
+	}
+	generateName, _ := metadata["generateName"].(string)
+	return generateName
+}
+
+// pruneObject removes the fields set by the API server that change on every update
+func pruneObject(object map[string]interface{}) map[string]interface{} {
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"resourceVersion", "managedFields", "generation", "creationTimestamp", "uid"} {
+			delete(metadata, field)
+		}
+	}
+	delete(object, "status")
+	return object
+}
+
+// getPatchedFields returns the fields set by a JSON merge, strategic merge, apply or JSON patch
+func getPatchedFields(contentType string, patch []byte) []string {
+	if contentType == string(types.JSONPatchType) {
+		var operations []struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}
+		if err := json.Unmarshal(patch, &operations); err != nil {
+			return nil
+		}
+		fields := make([]string, 0, len(operations))
+		for _, operation := range operations {
+			fields = append(fields, strings.ReplaceAll(strings.TrimPrefix(operation.Path, "/"), "/", "."))
+		}
+		return fields
+	}
+	object := decodeObject(patch)
+	if object == nil {
+		return nil
+	}
+	return getFieldPaths("", object)
+}
+
+// getFieldPaths returns the paths of the leaf fields of an object, including the null fields removed by a merge patch
+func getFieldPaths(path string, value interface{}) []string {
+	object, ok := value.(map[string]interface{})
+	if !ok || len(object) == 0 {
+		if path == "" {
+			return nil
+		}
+		return []string{path}
+	}
+	fields := make([]string, 0)
+	for key, nested := range object {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+		fields = append(fields, getFieldPaths(fieldPath, nested)...)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// getChangedFields returns the paths of the fields that differ between two objects, recursing into nested objects
+func getChangedFields(path string, current, desired interface{}) []string {
+	currentObject, currentIsObject := current.(map[string]interface{})
+	desiredObject, desiredIsObject := desired.(map[string]interface{})
+	if !currentIsObject || !desiredIsObject {
+		if equal(current, desired) {
+			return nil
+		}
+		return []string{path}
+	}
+	keys := make(map[string]bool)
+	for key := range currentObject {
+		keys[key] = true
+	}
+	for key := range desiredObject {
+		keys[key] = true
+	}
+	fields := make([]string, 0)
+	for key := range keys {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+		fields = append(fields, getChangedFields(fieldPath, currentObject[key], desiredObject[key])...)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func equal(a, b interface{}) bool {
+	aData, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bData, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(aData, bData)
+}
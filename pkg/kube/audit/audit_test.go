@@ -0,0 +1,138 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLog(t *testing.T) {
+	current := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"default","resourceVersion":"1"},"spec":{"replicas":1,"paused":false}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodDelete:
+			io.WriteString(w, `{"kind":"Status","status":"Success"}`)
+		default:
+			io.WriteString(w, current)
+		}
+	}))
+	defer server.Close()
+
+	l := &Log{}
+	client := &http.Client{Transport: l.Wrap(http.DefaultTransport)}
+	send := func(method, path, contentType, body string) {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", contentType)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if data, _ := io.ReadAll(resp.Body); len(data) == 0 {
+			t.Errorf("expected the response body to be readable after being recorded")
+		}
+	}
+	send(http.MethodGet, "/apis/apps/v1/namespaces/default/deployments/web", "", "")
+	send(http.MethodPost, "/api/v1/namespaces", "application/json", `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"team"}}`)
+	send(http.MethodPut, "/apis/apps/v1/namespaces/default/deployments/web", "application/json", `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"default","resourceVersion":"1"},"spec":{"replicas":3,"paused":false}}`)
+	send(http.MethodPatch, "/apis/apps/v1/namespaces/default/deployments/web/scale?dryRun=All", "application/merge-patch+json", `{"spec":{"replicas":2},"metadata":{"labels":{"app":null}}}`)
+	send(http.MethodPatch, "/apis/apps/v1/namespaces/default/deployments/web", "application/json-patch+json", `[{"op":"replace","path":"/spec/paused","value":true}]`)
+	send(http.MethodDelete, "/api/v1/namespaces/default/pods/web-1", "application/json", `{"kind":"DeleteOptions","apiVersion":"v1"}`)
+	send(http.MethodPost, "/version", "application/json", `{}`)
+
+	want := []Entry{
+		{Operation: operationCreate, APIVersion: "v1", Kind: "Namespace", Name: "team", StatusCode: http.StatusOK},
+		{Operation: operationUpdate, APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "web", StatusCode: http.StatusOK, Diff: []string{"spec.replicas"}},
+		{Operation: operationPatch, APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "web", Subresource: "scale", DryRun: true, StatusCode: http.StatusOK, Diff: []string{"metadata.labels.app", "spec.replicas"}},
+		{Operation: operationPatch, APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "web", StatusCode: http.StatusOK, Diff: []string{"spec.paused"}},
+		{Operation: operationDelete, APIVersion: "v1", Kind: "pods", Namespace: "default", Name: "web-1", StatusCode: http.StatusOK},
+	}
+	got := l.Entries()
+	for i := range got {
+		got[i].Time = want[0].Time
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %+v, want %+v", got, want)
+	}
+
+	artifactsPath := t.TempDir()
+	filePath, err := l.Export(artifactsPath, "my scenario")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(filePath, "mutations-my-scenario-") {
+		t.Errorf("unexpected file name %v", filePath)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exported []Entry
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatal(err)
+	}
+	if len(exported) != len(want) {
+		t.Errorf("expected %d exported entries, got %d", len(want), len(exported))
+	}
+	if filePath, err := l.Export(artifactsPath, "my scenario"); err != nil || filePath != "" {
+		t.Errorf("expected the log to be reset after export, got '%v', %v", filePath, err)
+	}
+}
+
+func TestParseRequestPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   requestInfo
+		wantOk bool
+	}{
+		{
+			path:   "/api/v1/namespaces/default/pods/web-1/eviction",
+			want:   requestInfo{version: "v1", namespace: "default", resource: "pods", name: "web-1", subresource: "eviction"},
+			wantOk: true,
+		},
+		{
+			path:   "/api/v1/namespaces/default",
+			want:   requestInfo{version: "v1", resource: "namespaces", name: "default"},
+			wantOk: true,
+		},
+		{
+			path:   "/apis/rbac.authorization.k8s.io/v1/clusterroles",
+			want:   requestInfo{group: "rbac.authorization.k8s.io", version: "v1", resource: "clusterroles"},
+			wantOk: true,
+		},
+		{
+			path: "/healthz",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := parseRequestPath(tt.path)
+			if ok != tt.wantOk || (ok && got != tt.want) {
+				t.Errorf("parseRequestPath() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certmanager
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+func CertificateShouldBeReady(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, name, namespace string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for certificate %v/%v to be ready", namespace, name)
+		}
+		log.Infof("waiting for certificate %v/%v to be ready", namespace, name)
+		certificate, err := getCertificate(dynamicClient, name, namespace)
+		if err != nil {
+			return err
+		}
+		ready, message := common.GetReadyCondition(certificate)
+		if ready {
+			log.Infof("certificate %v/%v is ready", namespace, name)
+			return nil
+		}
+		log.Infof("certificate %v/%v is not ready: '%v'", namespace, name, message)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// CertificateSecretShouldCoverDNSNames validates the issued certificate covers each of the comma separated dnsNames.
+func CertificateSecretShouldCoverDNSNames(dynamicClient dynamic.Interface, kubeClientset kubernetes.Interface, name, namespace, dnsNames string) error {
+	cert, err := getIssuedCertificate(dynamicClient, kubeClientset, name, namespace)
+	if err != nil {
+		return err
+	}
+	for _, dnsName := range strings.Split(dnsNames, ",") {
+		dnsName = strings.TrimSpace(dnsName)
+		if dnsName == "" {
+			continue
+		}
+		if err := cert.VerifyHostname(dnsName); err != nil {
+			return errors.Errorf("certificate %v/%v does not cover '%v', it covers '%v'", namespace, name, dnsName, cert.DNSNames)
+		}
+	}
+	log.Infof("certificate %v/%v covers '%v'", namespace, name, dnsNames)
+	return nil
+}
+
+// CertificateSecretShouldBeValidFor validates the issued certificate is valid now and does not expire within minValidity, e.g. '720h'.
+func CertificateSecretShouldBeValidFor(dynamicClient dynamic.Interface, kubeClientset kubernetes.Interface, name, namespace, minValidity string) error {
+	validity, err := time.ParseDuration(minValidity)
+	if err != nil {
+		return err
+	}
+	cert, err := getIssuedCertificate(dynamicClient, kubeClientset, name, namespace)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if now.Before(cert.NotBefore) {
+		return errors.Errorf("certificate %v/%v is not valid before '%v'", namespace, name, cert.NotBefore)
+	}
+	if remaining := cert.NotAfter.Sub(now); remaining < validity {
+		return errors.Errorf("certificate %v/%v expires at '%v', in '%v' but expected at least '%v'", namespace, name, cert.NotAfter, remaining.Round(time.Second), validity)
+	}
+	log.Infof("certificate %v/%v is valid until '%v'", namespace, name, cert.NotAfter)
+	return nil
+}
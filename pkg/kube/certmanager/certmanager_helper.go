@@ -0,0 +1,76 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certmanager
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+var CertificateResource = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+func getCertificate(dynamicClient dynamic.Interface, name, namespace string) (*unstructured.Unstructured, error) {
+	certificate, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return dynamicClient.Resource(CertificateResource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get certificate '%v'", name)
+	}
+	return certificate.(*unstructured.Unstructured), nil
+}
+
+// getIssuedCertificate parses the leaf certificate from the tls secret of the certificate
+func getIssuedCertificate(dynamicClient dynamic.Interface, kubeClientset kubernetes.Interface, name, namespace string) (*x509.Certificate, error) {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return nil, err
+	}
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+	certificate, err := getCertificate(dynamicClient, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+	if secretName == "" {
+		return nil, errors.Errorf("certificate %v/%v has no spec.secretName", namespace, name)
+	}
+	secret, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.CoreV1().Secrets(namespace).Get(context.Background(), secretName, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get secret '%v' of certificate '%v'", secretName, name)
+	}
+	block, _ := pem.Decode(secret.(*corev1.Secret).Data[corev1.TLSCertKey])
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.Errorf("secret %v/%v has no pem encoded certificate in '%v'", namespace, secretName, corev1.TLSCertKey)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse certificate in secret %v/%v", namespace, secretName)
+	}
+	return cert, nil
+}
@@ -0,0 +1,188 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certmanager
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/internal/testutil"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	certificateName = "certificate1"
+	secretName      = "certificate1-tls"
+	namespace       = "namespace1"
+)
+
+func TestCertificateShouldBeReady(t *testing.T) {
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: certificate ready",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newCertificate("True")),
+		},
+		{
+			name:          "Negative Test: certificate not ready",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newCertificate("False")),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: certificate not found",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid client",
+			dynamicClient: nil,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CertificateShouldBeReady(context.Background(), tt.dynamicClient, common.NewWaiterConfig(1, time.Millisecond), certificateName, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("CertificateShouldBeReady() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCertificateSecret(t *testing.T) {
+	dynamicClient := testutil.NewFakeDynamicClient(t, fakeResources, newCertificate("True"))
+	kubeClientset := fake.NewSimpleClientset(newTLSSecret(t, []string{"app.example.com", "*.api.example.com"}, 30*24*time.Hour))
+	tests := []struct {
+		name    string
+		fn      func() error
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: covers dns names",
+			fn: func() error {
+				return CertificateSecretShouldCoverDNSNames(dynamicClient, kubeClientset, certificateName, namespace, "app.example.com,v1.api.example.com")
+			},
+		},
+		{
+			name: "Negative Test: does not cover dns name",
+			fn: func() error {
+				return CertificateSecretShouldCoverDNSNames(dynamicClient, kubeClientset, certificateName, namespace, "app.example.com,other.example.com")
+			},
+			wantErr: true,
+		},
+		{
+			name: "Positive Test: valid for at least",
+			fn: func() error {
+				return CertificateSecretShouldBeValidFor(dynamicClient, kubeClientset, certificateName, namespace, "168h")
+			},
+		},
+		{
+			name: "Negative Test: near expiry",
+			fn: func() error {
+				return CertificateSecretShouldBeValidFor(dynamicClient, kubeClientset, certificateName, namespace, "1000h")
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid duration",
+			fn: func() error {
+				return CertificateSecretShouldBeValidFor(dynamicClient, kubeClientset, certificateName, namespace, "7d")
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: secret not found",
+			fn: func() error {
+				return CertificateSecretShouldBeValidFor(dynamicClient, fake.NewSimpleClientset(), certificateName, namespace, "168h")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fakeResources are the resources of the kinds tracked by the fake dynamic client
+var fakeResources = map[string]schema.GroupVersionResource{
+	"Certificate": CertificateResource,
+}
+
+func newCertificate(ready string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata": map[string]interface{}{
+			"name":      certificateName,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"secretName": secretName,
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": common.ConditionTypeReady, "status": ready, "message": "Certificate is up to date"},
+			},
+		},
+	}}
+}
+
+func newTLSSecret(t *testing.T, dnsNames []string, validity time.Duration) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validity),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		},
+	}
+}
@@ -15,9 +15,13 @@ limitations under the License.
 package common
 
 import (
+	"context"
 	"time"
 
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -33,6 +37,8 @@ const (
 	StateUpgraded = "upgraded"
 	StateReady    = "ready"
 	StateFound    = "found"
+
+	ConditionTypeReady = "Ready"
 )
 
 type WaiterConfig struct {
@@ -60,9 +66,49 @@ func (w WaiterConfig) GetTries() int {
 	return defaultWaiterTries
 }
 
+// Sleep waits for the interval, returning the error of the context early when it is done
+func (w WaiterConfig) Sleep(ctx context.Context) error {
+	timer := time.NewTimer(w.GetInterval())
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "waiter canceled")
+	case <-timer.C:
+		return nil
+	}
+}
+
 func ValidateClientset(kubeClientset kubernetes.Interface) error {
 	if kubeClientset == nil {
-		return errors.Errorf("'k8s.io/client-go/kubernetes.Interface' is nil.")
+		return Errorf(ErrClientNotInitialized, "'k8s.io/client-go/kubernetes.Interface' is nil.")
+	}
+	return nil
+}
+
+func ValidateDynamicClient(dynamicClient dynamic.Interface) error {
+	if dynamicClient == nil {
+		return Errorf(ErrClientNotInitialized, "'k8s.io/client-go/dynamic.Interface' is nil.")
 	}
 	return nil
 }
+
+// GetReadyCondition returns whether the Ready condition of the resource is True and its message, a condition observed for
+// an older generation of the resource is not ready
+func GetReadyCondition(resource *unstructured.Unstructured) (bool, string) {
+	conditions, _, err := unstructured.NestedSlice(resource.Object, "status", "conditions")
+	if err != nil {
+		return false, err.Error()
+	}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != ConditionTypeReady {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		if observedGeneration, ok := condition["observedGeneration"].(int64); ok && observedGeneration != resource.GetGeneration() {
+			return false, "waiting for spec update to be observed"
+		}
+		return condition["status"] == string(metav1.ConditionTrue), message
+	}
+	return false, "no Ready condition"
+}
@@ -0,0 +1,78 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestGetReadyCondition(t *testing.T) {
+	tests := []struct {
+		name        string
+		conditions  []interface{}
+		wantReady   bool
+		wantMessage string
+	}{
+		{
+			name: "Positive Test: ready",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Reconciling", "status": "False"},
+				map[string]interface{}{"type": ConditionTypeReady, "status": "True", "message": "up to date"},
+			},
+			wantReady:   true,
+			wantMessage: "up to date",
+		},
+		{
+			name: "Positive Test: ready for the current generation",
+			conditions: []interface{}{
+				map[string]interface{}{"type": ConditionTypeReady, "status": "True", "observedGeneration": int64(2)},
+			},
+			wantReady: true,
+		},
+		{
+			name: "Negative Test: not ready",
+			conditions: []interface{}{
+				map[string]interface{}{"type": ConditionTypeReady, "status": "False", "message": "reconciling"},
+			},
+			wantMessage: "reconciling",
+		},
+		{
+			name: "Negative Test: ready for an older generation",
+			conditions: []interface{}{
+				map[string]interface{}{"type": ConditionTypeReady, "status": "True", "observedGeneration": int64(1)},
+			},
+			wantMessage: "waiting for spec update to be observed",
+		},
+		{
+			name:        "Negative Test: no Ready condition",
+			wantMessage: "no Ready condition",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			resource.SetGeneration(2)
+			if tt.conditions != nil {
+				_ = unstructured.SetNestedSlice(resource.Object, tt.conditions, "status", "conditions")
+			}
+			ready, message := GetReadyCondition(resource)
+			if ready != tt.wantReady || message != tt.wantMessage {
+				t.Errorf("GetReadyCondition() = (%v, %q), want (%v, %q)", ready, message, tt.wantReady, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// The causes of the errors of kubedog, use errors.Is to branch on them
+var (
+	ErrWaiterTimeout        = errors.New("waiter timed out")
+	ErrResourceNotFound     = errors.New("resource not found")
+	ErrUnsupportedOperation = errors.New("unsupported operation")
+	ErrClientNotInitialized = errors.New("client not initialized")
+)
+
+// Error is an error of kubedog with one of the causes above, use errors.As to get it from a wrapped error
+type Error struct {
+	Cause   error
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is matches ErrResourceNotFound when the cause is a NotFound error of the API server
+func (e *Error) Is(target error) bool {
+	return target == ErrResourceNotFound && kerrors.IsNotFound(e.Cause)
+}
+
+// Errorf formats the message of an error with the cause
+func Errorf(cause error, format string, args ...interface{}) error {
+	return &Error{Cause: cause, Message: fmt.Sprintf(format, args...)}
+}
+
+// WrapNotFound wraps a NotFound error of the API server so errors.Is matches it with ErrResourceNotFound, kerrors.IsNotFound
+// still matches it. Other errors are returned as they are
+func WrapNotFound(err error) error {
+	if !kerrors.IsNotFound(err) {
+		return err
+	}
+	return &Error{Cause: err, Message: err.Error()}
+}
@@ -0,0 +1,102 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestErrorf(t *testing.T) {
+	causes := []error{ErrWaiterTimeout, ErrResourceNotFound, ErrUnsupportedOperation, ErrClientNotInitialized}
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "Positive Test: error",
+			err:  Errorf(ErrWaiterTimeout, "waiter timed out waiting for %v", "resource"),
+		},
+		{
+			name: "Positive Test: wrapped error",
+			err:  errors.Wrap(Errorf(ErrWaiterTimeout, "waiter timed out waiting for %v", "resource"), "step failed"),
+		},
+		{
+			name: "Positive Test: error wrapped with %w",
+			err:  fmt.Errorf("step failed: %w", Errorf(ErrWaiterTimeout, "waiter timed out waiting for %v", "resource")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, cause := range causes {
+				if got, want := errors.Is(tt.err, cause), cause == ErrWaiterTimeout; got != want {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", tt.err, cause, got, want)
+				}
+			}
+			var e *Error
+			if !errors.As(tt.err, &e) {
+				t.Fatalf("errors.As(%v) = false, expected a *Error", tt.err)
+			}
+			if e.Cause != ErrWaiterTimeout || e.Message != "waiter timed out waiting for resource" {
+				t.Errorf("errors.As(%v) = %+v, want the cause and the message", tt.err, *e)
+			}
+		})
+	}
+}
+
+func TestWrapNotFound(t *testing.T) {
+	notFound := kerrors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, "deployment1")
+	tests := []struct {
+		name         string
+		err          error
+		wantNotFound bool
+	}{
+		{
+			name:         "Positive Test: not found",
+			err:          WrapNotFound(notFound),
+			wantNotFound: true,
+		},
+		{
+			name:         "Positive Test: wrapped not found",
+			err:          errors.Wrap(WrapNotFound(notFound), "failed to get deployment"),
+			wantNotFound: true,
+		},
+		{
+			name: "Negative Test: other error of the API server",
+			err:  WrapNotFound(kerrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "deployment1", errors.New("forbidden"))),
+		},
+		{
+			name: "Negative Test: nil",
+			err:  WrapNotFound(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, ErrResourceNotFound); got != tt.wantNotFound {
+				t.Errorf("errors.Is(%v, ErrResourceNotFound) = %v, want %v", tt.err, got, tt.wantNotFound)
+			}
+			if got := kerrors.IsNotFound(tt.err); got != tt.wantNotFound {
+				t.Errorf("kerrors.IsNotFound(%v) = %v, want %v", tt.err, got, tt.wantNotFound)
+			}
+			if errors.Is(tt.err, ErrWaiterTimeout) {
+				t.Errorf("errors.Is(%v, ErrWaiterTimeout) = true, want false", tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	unstruct "github.com/keikoproj/kubedog/pkg/kube/unstructured"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// ManifestsShouldNotUseRemovedAPIs fails with a report of the documents in the yaml files under resourcesPath whose API is removed in targetVersion.
+func ManifestsShouldNotUseRemovedAPIs(TemplateArguments interface{}, resourcesPath, targetVersion string) error {
+	target, err := parseVersion(targetVersion)
+	if err != nil {
+		return err
+	}
+
+	var findings []string
+	err = filepath.Walk(resourcesPath, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if info.IsDir() || filepath.Ext(path) != ".yaml" {
+			return nil
+		}
+
+		resources, err := unstruct.GetManifests(TemplateArguments, path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode %v", path)
+		}
+		for i, resource := range resources {
+			if api, ok := getRemovedAPI(resource.GroupVersionKind(), target); ok {
+				findings = append(findings, fmt.Sprintf("%v: document %v: %v/%v: %v", path, i, resource.GetKind(), resource.GetName(), api))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return reportFindings(findings, resourcesPath, targetVersion)
+}
+
+// ClusterShouldNotUseRemovedAPIs fails with a report of the objects in the cluster that were last applied with an API removed in targetVersion.
+func ClusterShouldNotUseRemovedAPIs(dynamicClient dynamic.Interface, dc discovery.DiscoveryInterface, targetVersion string) error {
+	if err := validateClients(dynamicClient, dc); err != nil {
+		return err
+	}
+	target, err := parseVersion(targetVersion)
+	if err != nil {
+		return err
+	}
+
+	var (
+		findings []string
+		listed   = map[schema.GroupVersionResource][]unstructured.Unstructured{}
+	)
+	for _, api := range removedAPIs {
+		if !target.AtLeast(api.removedIn) {
+			continue
+		}
+		gvr, ok := getServedResource(dc, api.Kind, api.GroupVersion(), api.replacement)
+		if !ok {
+			continue
+		}
+		if _, ok := listed[gvr]; !ok {
+			objects, err := dynamicClient.Resource(gvr).Namespace(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				return errors.Wrapf(err, "failed to list %v", gvr)
+			}
+			listed[gvr] = objects.Items
+		}
+		for _, object := range listed[gvr] {
+			if getLastAppliedAPIVersion(&object) == api.GroupVersion().String() {
+				findings = append(findings, fmt.Sprintf("cluster: %v %v: %v", object.GetKind(), objectName(&object), api))
+			}
+		}
+	}
+	return reportFindings(findings, "the cluster", targetVersion)
+}
@@ -0,0 +1,180 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+type removedAPI struct {
+	schema.GroupVersionKind
+	removedIn   *version.Version
+	replacement schema.GroupVersion
+}
+
+func (api removedAPI) String() string {
+	replacement := "there is no replacement"
+	if !api.replacement.Empty() {
+		replacement = fmt.Sprintf("use %v", api.replacement)
+	}
+	return fmt.Sprintf("%v %v is removed in %v, %v", api.GroupVersion(), api.Kind, api.removedIn, replacement)
+}
+
+func newRemovedAPI(groupVersion, kind, removedIn, replacement string) removedAPI {
+	gv := schema.GroupVersion{}
+	if replacement != "" {
+		gv, _ = schema.ParseGroupVersion(replacement)
+	}
+	return removedAPI{
+		GroupVersionKind: schema.FromAPIVersionAndKind(groupVersion, kind),
+		removedIn:        version.MustParseGeneric(removedIn),
+		replacement:      gv,
+	}
+}
+
+// removedAPIs are the APIs removed from Kubernetes, https://kubernetes.io/docs/reference/using-api/deprecation-guide
+var removedAPIs = []removedAPI{
+	newRemovedAPI("extensions/v1beta1", "DaemonSet", "1.16", "apps/v1"),
+	newRemovedAPI("extensions/v1beta1", "Deployment", "1.16", "apps/v1"),
+	newRemovedAPI("extensions/v1beta1", "ReplicaSet", "1.16", "apps/v1"),
+	newRemovedAPI("extensions/v1beta1", "NetworkPolicy", "1.16", "networking.k8s.io/v1"),
+	newRemovedAPI("extensions/v1beta1", "PodSecurityPolicy", "1.16", "policy/v1beta1"),
+	newRemovedAPI("apps/v1beta1", "Deployment", "1.16", "apps/v1"),
+	newRemovedAPI("apps/v1beta1", "StatefulSet", "1.16", "apps/v1"),
+	newRemovedAPI("apps/v1beta2", "DaemonSet", "1.16", "apps/v1"),
+	newRemovedAPI("apps/v1beta2", "Deployment", "1.16", "apps/v1"),
+	newRemovedAPI("apps/v1beta2", "ReplicaSet", "1.16", "apps/v1"),
+	newRemovedAPI("apps/v1beta2", "StatefulSet", "1.16", "apps/v1"),
+	newRemovedAPI("extensions/v1beta1", "Ingress", "1.22", "networking.k8s.io/v1"),
+	newRemovedAPI("networking.k8s.io/v1beta1", "Ingress", "1.22", "networking.k8s.io/v1"),
+	newRemovedAPI("networking.k8s.io/v1beta1", "IngressClass", "1.22", "networking.k8s.io/v1"),
+	newRemovedAPI("admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", "1.22", "admissionregistration.k8s.io/v1"),
+	newRemovedAPI("admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration", "1.22", "admissionregistration.k8s.io/v1"),
+	newRemovedAPI("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "1.22", "apiextensions.k8s.io/v1"),
+	newRemovedAPI("apiregistration.k8s.io/v1beta1", "APIService", "1.22", "apiregistration.k8s.io/v1"),
+	newRemovedAPI("certificates.k8s.io/v1beta1", "CertificateSigningRequest", "1.22", "certificates.k8s.io/v1"),
+	newRemovedAPI("coordination.k8s.io/v1beta1", "Lease", "1.22", "coordination.k8s.io/v1"),
+	newRemovedAPI("rbac.authorization.k8s.io/v1beta1", "ClusterRole", "1.22", "rbac.authorization.k8s.io/v1"),
+	newRemovedAPI("rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding", "1.22", "rbac.authorization.k8s.io/v1"),
+	newRemovedAPI("rbac.authorization.k8s.io/v1beta1", "Role", "1.22", "rbac.authorization.k8s.io/v1"),
+	newRemovedAPI("rbac.authorization.k8s.io/v1beta1", "RoleBinding", "1.22", "rbac.authorization.k8s.io/v1"),
+	newRemovedAPI("scheduling.k8s.io/v1beta1", "PriorityClass", "1.22", "scheduling.k8s.io/v1"),
+	newRemovedAPI("storage.k8s.io/v1beta1", "CSIDriver", "1.22", "storage.k8s.io/v1"),
+	newRemovedAPI("storage.k8s.io/v1beta1", "CSINode", "1.22", "storage.k8s.io/v1"),
+	newRemovedAPI("storage.k8s.io/v1beta1", "StorageClass", "1.22", "storage.k8s.io/v1"),
+	newRemovedAPI("storage.k8s.io/v1beta1", "VolumeAttachment", "1.22", "storage.k8s.io/v1"),
+	newRemovedAPI("batch/v1beta1", "CronJob", "1.25", "batch/v1"),
+	newRemovedAPI("discovery.k8s.io/v1beta1", "EndpointSlice", "1.25", "discovery.k8s.io/v1"),
+	newRemovedAPI("events.k8s.io/v1beta1", "Event", "1.25", "events.k8s.io/v1"),
+	newRemovedAPI("autoscaling/v2beta1", "HorizontalPodAutoscaler", "1.25", "autoscaling/v2"),
+	newRemovedAPI("policy/v1beta1", "PodDisruptionBudget", "1.25", "policy/v1"),
+	newRemovedAPI("policy/v1beta1", "PodSecurityPolicy", "1.25", ""),
+	newRemovedAPI("node.k8s.io/v1beta1", "RuntimeClass", "1.25", "node.k8s.io/v1"),
+	newRemovedAPI("autoscaling/v2beta2", "HorizontalPodAutoscaler", "1.26", "autoscaling/v2"),
+	newRemovedAPI("flowcontrol.apiserver.k8s.io/v1beta1", "FlowSchema", "1.26", "flowcontrol.apiserver.k8s.io/v1"),
+	newRemovedAPI("flowcontrol.apiserver.k8s.io/v1beta1", "PriorityLevelConfiguration", "1.26", "flowcontrol.apiserver.k8s.io/v1"),
+	newRemovedAPI("storage.k8s.io/v1beta1", "CSIStorageCapacity", "1.27", "storage.k8s.io/v1"),
+	newRemovedAPI("flowcontrol.apiserver.k8s.io/v1beta2", "FlowSchema", "1.29", "flowcontrol.apiserver.k8s.io/v1"),
+	newRemovedAPI("flowcontrol.apiserver.k8s.io/v1beta2", "PriorityLevelConfiguration", "1.29", "flowcontrol.apiserver.k8s.io/v1"),
+	newRemovedAPI("flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", "1.32", "flowcontrol.apiserver.k8s.io/v1"),
+	newRemovedAPI("flowcontrol.apiserver.k8s.io/v1beta3", "PriorityLevelConfiguration", "1.32", "flowcontrol.apiserver.k8s.io/v1"),
+}
+
+func getRemovedAPI(gvk schema.GroupVersionKind, target *version.Version) (removedAPI, bool) {
+	for _, api := range removedAPIs {
+		if api.GroupVersionKind == gvk && target.AtLeast(api.removedIn) {
+			return api, true
+		}
+	}
+	return removedAPI{}, false
+}
+
+func parseVersion(targetVersion string) (*version.Version, error) {
+	target, err := version.ParseGeneric(targetVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse Kubernetes version '%v'", targetVersion)
+	}
+	return target, nil
+}
+
+// getServedResource returns the resource of the kind in the first group version served by the cluster
+func getServedResource(dc discovery.DiscoveryInterface, kind string, groupVersions ...schema.GroupVersion) (schema.GroupVersionResource, bool) {
+	for _, gv := range groupVersions {
+		if gv.Empty() {
+			continue
+		}
+		resources, err := dc.ServerResourcesForGroupVersion(gv.String())
+		if err != nil {
+			continue
+		}
+		for _, resource := range resources.APIResources {
+			if resource.Kind == kind && !strings.Contains(resource.Name, "/") {
+				return gv.WithResource(resource.Name), true
+			}
+		}
+	}
+	return schema.GroupVersionResource{}, false
+}
+
+func getLastAppliedAPIVersion(object *unstructured.Unstructured) string {
+	lastApplied, ok := object.GetAnnotations()[corev1.LastAppliedConfigAnnotation]
+	if !ok {
+		return ""
+	}
+	var typeMeta struct {
+		APIVersion string `json:"apiVersion"`
+	}
+	if err := json.Unmarshal([]byte(lastApplied), &typeMeta); err != nil {
+		return ""
+	}
+	return typeMeta.APIVersion
+}
+
+func objectName(object *unstructured.Unstructured) string {
+	if object.GetNamespace() == "" {
+		return object.GetName()
+	}
+	return fmt.Sprintf("%v/%v", object.GetNamespace(), object.GetName())
+}
+
+func reportFindings(findings []string, scanned, targetVersion string) error {
+	if len(findings) == 0 {
+		log.Infof("%v does not use APIs removed in Kubernetes %v", scanned, targetVersion)
+		return nil
+	}
+	return errors.Errorf("%v uses APIs removed in Kubernetes %v:\n%v", scanned, targetVersion, strings.Join(findings, "\n"))
+}
+
+func validateClients(dynamicClient dynamic.Interface, dc discovery.DiscoveryInterface) error {
+	if dynamicClient == nil {
+		return common.Errorf(common.ErrClientNotInitialized, "'k8s.io/client-go/dynamic.Interface' is nil.")
+	}
+	if dc == nil {
+		return common.Errorf(common.ErrClientNotInitialized, "'k8s.io/client-go/discovery.DiscoveryInterface' is nil.")
+	}
+	return nil
+}
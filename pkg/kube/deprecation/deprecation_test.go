@@ -0,0 +1,169 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	fakeDiscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	kTesting "k8s.io/client-go/testing"
+)
+
+const manifests = `apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: {{.Name}}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{.Name}}
+---
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: {{.Name}}
+`
+
+func TestManifestsShouldNotUseRemovedAPIs(t *testing.T) {
+	resourcesPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(resourcesPath, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(resourcesPath, "app", "app.yaml"), []byte(manifests), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(resourcesPath, "README.md"), []byte("not a manifest"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	args := map[string]string{"Name": "app1"}
+	tests := []struct {
+		name          string
+		targetVersion string
+		findings      []string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: no removed APIs",
+			targetVersion: "1.21",
+		},
+		{
+			name:          "Negative Test: removed API",
+			targetVersion: "v1.22.5",
+			findings:      []string{"app.yaml: document 2: Ingress/app1: networking.k8s.io/v1beta1 Ingress is removed in 1.22, use networking.k8s.io/v1"},
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: removed APIs",
+			targetVersion: "1.25",
+			findings: []string{
+				"app.yaml: document 0: PodDisruptionBudget/app1: policy/v1beta1 PodDisruptionBudget is removed in 1.25, use policy/v1",
+				"app.yaml: document 2: Ingress/app1",
+			},
+			wantErr: true,
+		},
+		{
+			name:          "Negative Test: invalid version",
+			targetVersion: "latest",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ManifestsShouldNotUseRemovedAPIs(args, resourcesPath, tt.targetVersion)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ManifestsShouldNotUseRemovedAPIs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, finding := range tt.findings {
+				if !strings.Contains(err.Error(), finding) {
+					t.Errorf("ManifestsShouldNotUseRemovedAPIs() error = %v, want finding %v", err, finding)
+				}
+			}
+		})
+	}
+}
+
+func TestClusterShouldNotUseRemovedAPIs(t *testing.T) {
+	newObject := func(name, lastAppliedAPIVersion string) runtime.Object {
+		object := &unstructured.Unstructured{}
+		object.SetAPIVersion("batch/v1")
+		object.SetKind("CronJob")
+		object.SetName(name)
+		object.SetNamespace("namespace1")
+		object.SetAnnotations(map[string]string{
+			"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"` + lastAppliedAPIVersion + `","kind":"CronJob"}`,
+		})
+		return object
+	}
+	newDynamicClient := func(objects ...runtime.Object) dynamic.Interface {
+		return fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{{Group: "batch", Version: "v1", Resource: "cronjobs"}: "CronJobList"},
+			objects...)
+	}
+	dc := &fakeDiscovery.FakeDiscovery{Fake: &kTesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: "batch/v1",
+		APIResources: []metav1.APIResource{{Name: "cronjobs", Kind: "CronJob", Namespaced: true}, {Name: "cronjobs/status", Kind: "CronJob"}},
+	}}}}
+	tests := []struct {
+		name            string
+		dynamicClient   dynamic.Interface
+		discoveryClient discovery.DiscoveryInterface
+		targetVersion   string
+		wantErr         bool
+	}{
+		{
+			name:            "Positive Test: applied with the replacement API",
+			dynamicClient:   newDynamicClient(newObject("cronjob1", "batch/v1")),
+			discoveryClient: dc,
+			targetVersion:   "1.25",
+		},
+		{
+			name:            "Positive Test: API not removed yet",
+			dynamicClient:   newDynamicClient(newObject("cronjob1", "batch/v1beta1")),
+			discoveryClient: dc,
+			targetVersion:   "1.24",
+		},
+		{
+			name:            "Negative Test: applied with removed API",
+			dynamicClient:   newDynamicClient(newObject("cronjob1", "batch/v1"), newObject("cronjob2", "batch/v1beta1")),
+			discoveryClient: dc,
+			targetVersion:   "1.25",
+			wantErr:         true,
+		},
+		{
+			name:            "Negative Test: invalid client",
+			discoveryClient: dc,
+			targetVersion:   "1.25",
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ClusterShouldNotUseRemovedAPIs(tt.dynamicClient, tt.discoveryClient, tt.targetVersion); (err != nil) != tt.wantErr {
+				t.Errorf("ClusterShouldNotUseRemovedAPIs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
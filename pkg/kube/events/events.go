@@ -0,0 +1,123 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// GetEvents returns the events of the object in the namespace sorted by the time they were last seen, the kind is case-insensitive
+func GetEvents(kubeClientset kubernetes.Interface, kind, name, namespace string) ([]corev1.Event, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	events, err := listEvents(kubeClientset, namespace, fmt.Sprintf("involvedObject.name=%s", name))
+	if err != nil {
+		return nil, err
+	}
+	var objectEvents []corev1.Event
+	for _, event := range events {
+		if event.InvolvedObject.Name == name && strings.EqualFold(event.InvolvedObject.Kind, kind) {
+			objectEvents = append(objectEvents, event)
+		}
+	}
+	return objectEvents, nil
+}
+
+// EventsShouldContainReason waits for an event with the reason to be reported for the object
+func EventsShouldContainReason(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, kind, name, namespace, reason string) error {
+	var counter int
+
+	for {
+		events, err := GetEvents(kubeClientset, kind, name, namespace)
+		if err != nil {
+			return err
+		}
+		if event, ok := findEventWithReason(events, reason); ok {
+			log.Infof("found event %v for %v %v/%v: %v", reason, kind, namespace, name, event.Message)
+			return nil
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for an event with reason %v for %v %v/%v, found reasons %v", reason, kind, namespace, name, getReasons(events))
+		}
+		log.Infof("waiting for an event with reason %v for %v %v/%v", reason, kind, namespace, name)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// EventsShouldNotContainReason validates no event with the reason was reported for the object
+func EventsShouldNotContainReason(kubeClientset kubernetes.Interface, kind, name, namespace, reason string) error {
+	events, err := GetEvents(kubeClientset, kind, name, namespace)
+	if err != nil {
+		return err
+	}
+	if event, ok := findEventWithReason(events, reason); ok {
+		return errors.Errorf("found event %v for %v %v/%v: %v", reason, kind, namespace, name, event.Message)
+	}
+	return nil
+}
+
+// ExportEvents writes the events of every namespace last seen since the time to a file named after name in artifactsPath
+// and logs the warnings among them, it returns an empty path when there are no events
+func ExportEvents(kubeClientset kubernetes.Interface, artifactsPath, name string, since time.Time) (string, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return "", err
+	}
+
+	events, err := listEvents(kubeClientset, metav1.NamespaceAll, "")
+	if err != nil {
+		return "", err
+	}
+	var recent []corev1.Event
+	for _, event := range events {
+		if !getEventTime(event).Before(since) {
+			recent = append(recent, event)
+		}
+	}
+	if len(recent) == 0 {
+		return "", nil
+	}
+
+	for _, event := range recent {
+		if event.Type == corev1.EventTypeWarning {
+			log.Warnf("event %v for %v %v/%v: %v", event.Reason, event.InvolvedObject.Kind, event.InvolvedObject.Namespace, event.InvolvedObject.Name, event.Message)
+		}
+	}
+	if err := os.MkdirAll(artifactsPath, 0755); err != nil {
+		return "", errors.Wrapf(err, "failed to create artifacts directory '%s'", artifactsPath)
+	}
+	filePath := filepath.Join(artifactsPath, fmt.Sprintf("events-%s-%d.txt", util.SanitizeFileName(name), time.Now().Unix()))
+	if err := os.WriteFile(filePath, []byte(formatEvents(recent)), 0644); err != nil {
+		return "", errors.Wrapf(err, "failed to write events to '%s'", filePath)
+	}
+	return filePath, nil
+}
@@ -0,0 +1,84 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func listEvents(kubeClientset kubernetes.Interface, namespace, fieldSelector string) ([]corev1.Event, error) {
+	eventList, err := kubeClientset.CoreV1().Events(namespace).List(context.Background(), metav1.ListOptions{FieldSelector: fieldSelector})
+	if err != nil {
+		return nil, err
+	}
+	events := eventList.Items
+	sort.SliceStable(events, func(i, j int) bool {
+		return getEventTime(events[i]).Before(getEventTime(events[j]))
+	})
+	return events, nil
+}
+
+// getEventTime returns when the event was last seen, events.k8s.io events only set the event time
+func getEventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+func findEventWithReason(events []corev1.Event, reason string) (corev1.Event, bool) {
+	for _, event := range events {
+		if event.Reason == reason {
+			return event, true
+		}
+	}
+	return corev1.Event{}, false
+}
+
+func getReasons(events []corev1.Event) []string {
+	reasons := []string{}
+	seen := map[string]bool{}
+	for _, event := range events {
+		if !seen[event.Reason] {
+			seen[event.Reason] = true
+			reasons = append(reasons, event.Reason)
+		}
+	}
+	return reasons
+}
+
+// formatEvents prints the events like 'kubectl get events'
+func formatEvents(events []corev1.Event) string {
+	var b strings.Builder
+	tableFormat := "%-22s%-10s%-28s%-64s%s\n"
+	fmt.Fprintf(&b, tableFormat, "LAST SEEN", "TYPE", "REASON", "OBJECT", "MESSAGE")
+	for _, event := range events {
+		object := fmt.Sprintf("%s %s/%s", strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Namespace, event.InvolvedObject.Name)
+		fmt.Fprintf(&b, tableFormat, getEventTime(event).UTC().Format(time.RFC3339), event.Type, event.Reason, object, strings.TrimSpace(event.Message))
+	}
+	return b.String()
+}
@@ -0,0 +1,123 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+)
+
+const namespace = "namespace1"
+
+func newEvent(name, kind, objectName, eventType, reason string, lastSeen time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      kind,
+			Name:      objectName,
+			Namespace: namespace,
+		},
+		Type:          eventType,
+		Reason:        reason,
+		Message:       reason + " message",
+		LastTimestamp: metav1.NewTime(lastSeen),
+	}
+}
+
+func TestEventsShouldContainReason(t *testing.T) {
+	now := time.Now()
+	objects := []runtime.Object{
+		newEvent("event1", "Deployment", "app", corev1.EventTypeNormal, "ScalingReplicaSet", now),
+		newEvent("event2", "Pod", "app", corev1.EventTypeWarning, "BackOff", now),
+		newEvent("event3", "Deployment", "other", corev1.EventTypeWarning, "FailedCreate", now),
+	}
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	tests := []struct {
+		name    string
+		kind    string
+		object  string
+		reason  string
+		not     bool
+		wantErr bool
+	}{
+		{name: "Positive Test: reason found with a lowercase kind", kind: "deployment", object: "app", reason: "ScalingReplicaSet"},
+		{name: "Negative Test: reason belongs to another kind", kind: "deployment", object: "app", reason: "BackOff", wantErr: true},
+		{name: "Negative Test: reason belongs to another object", kind: "deployment", object: "app", reason: "FailedCreate", wantErr: true},
+		{name: "Positive Test: reason not found", kind: "pod", object: "app", reason: "Killing", not: true},
+		{name: "Negative Test: reason found", kind: "pod", object: "app", reason: "BackOff", not: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fakeKube.NewSimpleClientset(objects...)
+			var err error
+			if tt.not {
+				err = EventsShouldNotContainReason(kubeClientset, tt.kind, tt.object, namespace, tt.reason)
+			} else {
+				err = EventsShouldContainReason(context.Background(), kubeClientset, w, tt.kind, tt.object, namespace, tt.reason)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EventsShould(Not)ContainReason() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExportEvents(t *testing.T) {
+	now := time.Now()
+	kubeClientset := fakeKube.NewSimpleClientset(
+		newEvent("old", "Pod", "app", corev1.EventTypeWarning, "OldFailure", now.Add(-time.Hour)),
+		newEvent("warning", "Pod", "app", corev1.EventTypeWarning, "BackOff", now),
+		newEvent("normal", "Deployment", "app", corev1.EventTypeNormal, "ScalingReplicaSet", now.Add(-time.Second)),
+	)
+	artifactsPath := t.TempDir()
+
+	filePath, err := ExportEvents(kubeClientset, artifactsPath, "Scenario: my test", now.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("ExportEvents() error = %v", err)
+	}
+	if !strings.HasPrefix(filepath.Base(filePath), "events-Scenario-my-test-") {
+		t.Errorf("ExportEvents() filePath = %v, want it named after the scenario", filePath)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if strings.Contains(content, "OldFailure") {
+		t.Errorf("ExportEvents() exported an event from before the scenario:\n%v", content)
+	}
+	scaling, backOff := strings.Index(content, "ScalingReplicaSet"), strings.Index(content, "BackOff")
+	if scaling == -1 || backOff == -1 || scaling > backOff {
+		t.Errorf("ExportEvents() want the recent events sorted by time:\n%v", content)
+	}
+
+	filePath, err = ExportEvents(kubeClientset, artifactsPath, "later", now.Add(time.Minute))
+	if err != nil || filePath != "" {
+		t.Errorf("ExportEvents() = %v, %v, want no file without events", filePath, err)
+	}
+	if _, err := ExportEvents(nil, artifactsPath, "nil", now); err == nil {
+		t.Errorf("ExportEvents() want an error with a nil clientset")
+	}
+}
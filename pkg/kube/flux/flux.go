@@ -0,0 +1,79 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flux
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/dynamic"
+)
+
+// ResourceShouldBeReady waits for the Ready condition of a Kustomization or HelmRelease, and for its last applied revision
+// to match the expected revision when one is given.
+func ResourceShouldBeReady(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, kind, name, namespace, revision string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+	gvr, err := getResource(kind)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for %v %v/%v to be ready", kind, namespace, name)
+		}
+		log.Infof("waiting for %v %v/%v to be ready", kind, namespace, name)
+		resource, err := getFluxResource(dynamicClient, gvr, name, namespace)
+		if err != nil {
+			return err
+		}
+		ready, message := common.GetReadyCondition(resource)
+		appliedRevision := getAppliedRevision(resource)
+		if ready && (revision == "" || revisionMatches(appliedRevision, revision)) {
+			log.Infof("%v %v/%v is ready at revision %v", kind, namespace, name, appliedRevision)
+			return nil
+		}
+		log.Infof("%v %v/%v ready: %v, revision: %v, message: '%v'", kind, namespace, name, ready, appliedRevision, message)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// Reconcile requests a reconciliation the same way 'flux reconcile' does, by setting the reconcile annotation.
+func Reconcile(dynamicClient dynamic.Interface, kind, name, namespace string) error {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+	gvr, err := getResource(kind)
+	if err != nil {
+		return err
+	}
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, reconcileRequestAnnotation, time.Now().Format(time.RFC3339Nano))
+	if err := patchFluxResource(dynamicClient, gvr, name, namespace, patch); err != nil {
+		return errors.Wrapf(err, "failed to reconcile %v %v/%v", kind, namespace, name)
+	}
+	log.Infof("%v %v/%v reconcile requested", kind, namespace, name)
+	return nil
+}
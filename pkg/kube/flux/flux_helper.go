@@ -0,0 +1,95 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flux
+
+import (
+	"context"
+	"strings"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	KindKustomization = "kustomization"
+	KindHelmRelease   = "helmrelease"
+
+	reconcileRequestAnnotation = "reconcile.fluxcd.io/requestedAt"
+)
+
+var (
+	KustomizationResource = schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}
+	HelmReleaseResource   = schema.GroupVersionResource{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"}
+)
+
+func getResource(kind string) (schema.GroupVersionResource, error) {
+	switch strings.ToLower(kind) {
+	case KindKustomization:
+		return KustomizationResource, nil
+	case KindHelmRelease:
+		return HelmReleaseResource, nil
+	default:
+		return schema.GroupVersionResource{}, common.Errorf(common.ErrUnsupportedOperation, "unsupported flux kind: '%s'", kind)
+	}
+}
+
+func getFluxResource(dynamicClient dynamic.Interface, gvr schema.GroupVersionResource, name, namespace string) (*unstructured.Unstructured, error) {
+	resource, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return dynamicClient.Resource(gvr).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get %v '%v'", gvr.Resource, name)
+	}
+	return resource.(*unstructured.Unstructured), nil
+}
+
+func patchFluxResource(dynamicClient dynamic.Interface, gvr schema.GroupVersionResource, name, namespace, patch string) error {
+	_, err := dynamicClient.Resource(gvr).Namespace(namespace).Patch(context.Background(), name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// getAppliedRevision returns the last applied source revision, or chart version for a HelmRelease without one
+func getAppliedRevision(resource *unstructured.Unstructured) string {
+	if revision, found, _ := unstructured.NestedString(resource.Object, "status", "lastAppliedRevision"); found {
+		return revision
+	}
+	history, _, _ := unstructured.NestedSlice(resource.Object, "status", "history")
+	if len(history) == 0 {
+		return ""
+	}
+	if snapshot, ok := history[0].(map[string]interface{}); ok {
+		if chartVersion, ok := snapshot["chartVersion"].(string); ok {
+			return chartVersion
+		}
+	}
+	return ""
+}
+
+// revisionMatches is true when the revision equals the expected revision, or its digest starts with it, e.g. 'main@sha1:4f2c1a9' matches '4f2c1a9'
+func revisionMatches(revision, expected string) bool {
+	if revision == expected {
+		return true
+	}
+	if i := strings.LastIndex(revision, ":"); i != -1 {
+		return strings.HasPrefix(revision[i+1:], expected)
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flux
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/internal/testutil"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	resourceName = "resource1"
+	namespace    = "flux-system"
+	revision     = "main@sha1:4f2c1a9e0b7d"
+)
+
+func TestResourceShouldBeReady(t *testing.T) {
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		kind          string
+		revision      string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: kustomization ready",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newKustomization("True", 1, 1)),
+			kind:          KindKustomization,
+		},
+		{
+			name:          "Positive Test: kustomization ready at revision",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newKustomization("True", 1, 1)),
+			kind:          KindKustomization,
+			revision:      revision,
+		},
+		{
+			name:          "Positive Test: kustomization ready at short revision",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newKustomization("True", 1, 1)),
+			kind:          KindKustomization,
+			revision:      "4f2c1a9",
+		},
+		{
+			name:          "Positive Test: helmrelease ready at chart version",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newHelmRelease("1.2.3")),
+			kind:          "HelmRelease",
+			revision:      "1.2.3",
+		},
+		{
+			name:          "Negative Test: helmrelease at different chart version",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newHelmRelease("1.2.3")),
+			kind:          KindHelmRelease,
+			revision:      "1.2.4",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: kustomization not ready",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newKustomization("False", 1, 1)),
+			kind:          KindKustomization,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: spec update not observed",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newKustomization("True", 2, 1)),
+			kind:          KindKustomization,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: unsupported kind",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources),
+			kind:          "gitrepository",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: not found",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources),
+			kind:          KindKustomization,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid client",
+			dynamicClient: nil,
+			kind:          KindKustomization,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ResourceShouldBeReady(context.Background(), tt.dynamicClient, common.NewWaiterConfig(1, time.Millisecond), tt.kind, resourceName, namespace, tt.revision); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceShouldBeReady() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: kustomization",
+			kind: KindKustomization,
+		},
+		{
+			name:    "Negative Test: helmrelease not found",
+			kind:    KindHelmRelease,
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: unsupported kind",
+			kind:    "gitrepository",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := testutil.NewFakeDynamicClient(t, fakeResources, newKustomization("True", 1, 1))
+			if err := Reconcile(dynamicClient, tt.kind, resourceName, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			kustomization, err := getFluxResource(dynamicClient, KustomizationResource, resourceName, namespace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := kustomization.GetAnnotations()[reconcileRequestAnnotation]; !ok {
+				t.Errorf("expected annotation %v to be set", reconcileRequestAnnotation)
+			}
+		})
+	}
+}
+
+// fakeResources are the resources of the kinds tracked by the fake dynamic client
+var fakeResources = map[string]schema.GroupVersionResource{
+	"Kustomization": KustomizationResource,
+	"HelmRelease":   HelmReleaseResource,
+}
+
+func newFluxResource(apiVersion, kind, readyStatus string, generation, observedGeneration int64) *unstructured.Unstructured {
+	resource := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":      resourceName,
+			"namespace": namespace,
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"type":               common.ConditionTypeReady,
+					"status":             readyStatus,
+					"observedGeneration": observedGeneration,
+					"message":            "Applied revision: " + revision,
+				},
+			},
+		},
+	}}
+	resource.SetGeneration(generation)
+	return resource
+}
+
+func newKustomization(readyStatus string, generation, observedGeneration int64) *unstructured.Unstructured {
+	kustomization := newFluxResource(KustomizationResource.GroupVersion().String(), "Kustomization", readyStatus, generation, observedGeneration)
+	_ = unstructured.SetNestedField(kustomization.Object, revision, "status", "lastAppliedRevision")
+	return kustomization
+}
+
+func newHelmRelease(chartVersion string) *unstructured.Unstructured {
+	helmRelease := newFluxResource(HelmReleaseResource.GroupVersion().String(), "HelmRelease", "True", 1, 1)
+	_ = unstructured.SetNestedSlice(helmRelease.Object, []interface{}{
+		map[string]interface{}{"chartVersion": chartVersion},
+	}, "status", "history")
+	return helmRelease
+}
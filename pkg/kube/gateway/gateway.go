@@ -0,0 +1,102 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/structured"
+	log "github.com/sirupsen/logrus"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+	"k8s.io/client-go/dynamic"
+)
+
+func GatewayShouldBeProgrammed(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, name, namespace string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for gateway to be programmed")
+		}
+		log.Infof("waiting for gateway %v/%v to be programmed", namespace, name)
+		gateway, err := getGateway(dynamicClient, name, namespace)
+		if err != nil {
+			return err
+		}
+		if hasTrueCondition(gateway.Object, conditionProgrammed, "status", "conditions") {
+			log.Infof("gateway %v/%v is programmed", namespace, name)
+			return nil
+		}
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func HTTPRouteShouldBeAccepted(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, name, namespace string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for httproute to be accepted")
+		}
+		log.Infof("waiting for httproute %v/%v to be accepted by its parents", namespace, name)
+		route, err := getHTTPRoute(dynamicClient, name, namespace)
+		if err != nil {
+			return err
+		}
+		if isAcceptedByAllParents(route) {
+			log.Infof("httproute %v/%v is accepted", namespace, name)
+			return nil
+		}
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func HTTPRouteAvailable(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, name, namespace string, port int, path string) error {
+	endpoint, header, err := GetHTTPRouteEndpoint(ctx, dynamicClient, w, name, namespace, port, path)
+	if err != nil {
+		return err
+	}
+	check, err := structured.NewHTTPStatusCodeCheck(strconv.Itoa(http.StatusOK))
+	if err != nil {
+		return err
+	}
+	_, err = structured.EndpointResponseShouldCtx(ctx, w, endpoint, structured.HTTPRequest{Header: header}, check)
+	return err
+}
+
+func SendTrafficToHTTPRoute(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, tps int, name, namespace string, port int, path string, duration int, durationUnits string, expectedErrors int) (*vegeta.Metrics, error) {
+	endpoint, header, err := GetHTTPRouteEndpoint(ctx, dynamicClient, w, name, namespace, port, path)
+	if err != nil {
+		return nil, err
+	}
+	return structured.SendTrafficToEndpoint(endpoint, header, tps, duration, durationUnits, expectedErrors)
+}
@@ -0,0 +1,175 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	gatewayGroup   = "gateway.networking.k8s.io"
+	gatewayVersion = "v1"
+
+	conditionProgrammed = "Programmed"
+	conditionAccepted   = "Accepted"
+)
+
+var (
+	GatewayResource   = schema.GroupVersionResource{Group: gatewayGroup, Version: gatewayVersion, Resource: "gateways"}
+	HTTPRouteResource = schema.GroupVersionResource{Group: gatewayGroup, Version: gatewayVersion, Resource: "httproutes"}
+)
+
+// GetGatewayEndpoint waits for the gateway to have an address in its status and returns 'http://<address>:<port><path>'.
+func GetGatewayEndpoint(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, name, namespace string, port int, path string) (string, error) {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return "", err
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return "", common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for gateway address")
+		}
+		gateway, err := getGateway(dynamicClient, name, namespace)
+		if err != nil {
+			return "", err
+		}
+		addresses, _, err := unstructured.NestedSlice(gateway.Object, "status", "addresses")
+		if err != nil {
+			return "", err
+		}
+		for _, a := range addresses {
+			address, ok := a.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if value, ok := address["value"].(string); ok && value != "" {
+				return fmt.Sprintf("http://%v:%v%v", value, port, path), nil
+			}
+		}
+		log.Infof("gateway %v/%v has no address yet", namespace, name)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return "", err
+		}
+	}
+}
+
+// GetHTTPRouteEndpoint resolves the endpoint of the first parent gateway of the httproute.
+// The returned header sets 'Host' to the first hostname of the httproute, if any.
+func GetHTTPRouteEndpoint(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, name, namespace string, port int, path string) (string, http.Header, error) {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return "", nil, err
+	}
+
+	route, err := getHTTPRoute(dynamicClient, name, namespace)
+	if err != nil {
+		return "", nil, err
+	}
+	parentRefs, _, err := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	if err != nil {
+		return "", nil, err
+	}
+	if len(parentRefs) == 0 {
+		return "", nil, errors.Errorf("httproute %v/%v has no parentRefs", namespace, name)
+	}
+	parentRef, ok := parentRefs[0].(map[string]interface{})
+	if !ok {
+		return "", nil, errors.Errorf("httproute %v/%v has an invalid parentRef: '%v'", namespace, name, parentRefs[0])
+	}
+	gatewayName, _, _ := unstructured.NestedString(parentRef, "name")
+	gatewayNamespace, _, _ := unstructured.NestedString(parentRef, "namespace")
+	if gatewayNamespace == "" {
+		gatewayNamespace = namespace
+	}
+	endpoint, err := GetGatewayEndpoint(ctx, dynamicClient, w, gatewayName, gatewayNamespace, port, path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	header := http.Header{}
+	hostnames, _, err := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+	if err != nil {
+		return "", nil, err
+	}
+	if len(hostnames) > 0 {
+		header.Set("Host", hostnames[0])
+	}
+	return endpoint, header, nil
+}
+
+func getGateway(dynamicClient dynamic.Interface, name, namespace string) (*unstructured.Unstructured, error) {
+	gateway, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return dynamicClient.Resource(GatewayResource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get gateway '%v'", name)
+	}
+	return gateway.(*unstructured.Unstructured), nil
+}
+
+func getHTTPRoute(dynamicClient dynamic.Interface, name, namespace string) (*unstructured.Unstructured, error) {
+	route, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return dynamicClient.Resource(HTTPRouteResource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get httproute '%v'", name)
+	}
+	return route.(*unstructured.Unstructured), nil
+}
+
+func isAcceptedByAllParents(route *unstructured.Unstructured) bool {
+	parents, _, err := unstructured.NestedSlice(route.Object, "status", "parents")
+	if err != nil || len(parents) == 0 {
+		return false
+	}
+	for _, p := range parents {
+		parent, ok := p.(map[string]interface{})
+		if !ok || !hasTrueCondition(parent, conditionAccepted, "conditions") {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTrueCondition(obj map[string]interface{}, conditionType string, conditionsPath ...string) bool {
+	conditions, _, err := unstructured.NestedSlice(obj, conditionsPath...)
+	if err != nil {
+		return false
+	}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == conditionType && condition["status"] == string(corev1.ConditionTrue) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/internal/testutil"
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	gatewayName = "gateway1"
+	routeName   = "route1"
+	namespace   = "namespace1"
+	hostname    = "some.host.com"
+)
+
+func TestGatewayShouldBeProgrammed(t *testing.T) {
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: gateway programmed",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newGateway("127.0.0.1", "True")),
+		},
+		{
+			name:          "Negative Test: gateway not programmed",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newGateway("127.0.0.1", "False")),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: gateway not found",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid client",
+			dynamicClient: nil,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := GatewayShouldBeProgrammed(context.Background(), tt.dynamicClient, common.NewWaiterConfig(1, time.Millisecond), gatewayName, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("GatewayShouldBeProgrammed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHTTPRouteShouldBeAccepted(t *testing.T) {
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: httproute accepted",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newHTTPRoute("True")),
+		},
+		{
+			name:          "Negative Test: httproute not accepted",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newHTTPRoute("False")),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: httproute not found",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources),
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := HTTPRouteShouldBeAccepted(context.Background(), tt.dynamicClient, common.NewWaiterConfig(1, time.Millisecond), routeName, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("HTTPRouteShouldBeAccepted() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHTTPRouteAvailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != hostname {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	host, port := testutil.GetHostAndPort(t, server.URL)
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: httproute available through gateway",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newGateway(host, "True"), newHTTPRoute("True")),
+		},
+		{
+			name:          "Negative Test: gateway has no address",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newGateway("", "True"), newHTTPRoute("True")),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: httproute not found",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newGateway(host, "True")),
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := HTTPRouteAvailable(context.Background(), tt.dynamicClient, common.NewWaiterConfig(1, time.Millisecond), routeName, namespace, port, "/"); (err != nil) != tt.wantErr {
+				t.Errorf("HTTPRouteAvailable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSendTrafficToHTTPRoute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	host, port := testutil.GetHostAndPort(t, server.URL)
+	dynamicClient := testutil.NewFakeDynamicClient(t, fakeResources, newGateway(host, "True"), newHTTPRoute("True"))
+	metrics, err := SendTrafficToHTTPRoute(context.Background(), dynamicClient, common.NewWaiterConfig(1, time.Millisecond), 2, routeName, namespace, port, "/", 1, util.DurationSeconds, 0)
+	if err != nil {
+		t.Errorf("SendTrafficToHTTPRoute() error = %v", err)
+	}
+	if metrics == nil || metrics.Requests == 0 {
+		t.Errorf("SendTrafficToHTTPRoute() expected requests to be sent, got metrics '%v'", metrics)
+	}
+}
+
+// fakeResources are the resources of the kinds tracked by the fake dynamic client
+var fakeResources = map[string]schema.GroupVersionResource{
+	"Gateway":   GatewayResource,
+	"HTTPRoute": HTTPRouteResource,
+}
+
+func newGateway(address, programmed string) *unstructured.Unstructured {
+	gateway := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": gatewayGroup + "/" + gatewayVersion,
+		"kind":       "Gateway",
+		"metadata": map[string]interface{}{
+			"name":      gatewayName,
+			"namespace": namespace,
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": conditionProgrammed, "status": programmed},
+			},
+		},
+	}}
+	if address != "" {
+		_ = unstructured.SetNestedSlice(gateway.Object, []interface{}{
+			map[string]interface{}{"type": "IPAddress", "value": address},
+		}, "status", "addresses")
+	}
+	return gateway
+}
+
+func newHTTPRoute(accepted string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": gatewayGroup + "/" + gatewayVersion,
+		"kind":       "HTTPRoute",
+		"metadata": map[string]interface{}{
+			"name":      routeName,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"parentRefs": []interface{}{
+				map[string]interface{}{"name": gatewayName},
+			},
+			"hostnames": []interface{}{hostname},
+		},
+		"status": map[string]interface{}{
+			"parents": []interface{}{
+				map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": conditionAccepted, "status": accepted},
+					},
+				},
+			},
+		},
+	}}
+}
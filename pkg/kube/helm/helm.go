@@ -0,0 +1,85 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+)
+
+func ReleaseShouldBeDeployed(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string) error {
+	return releaseShouldBeDeployed(ctx, kubeClientset, w, name, namespace, func(r *release) error {
+		return nil
+	})
+}
+
+func ReleaseShouldBeDeployedWithChartVersion(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, chartVersion string) error {
+	return releaseShouldBeDeployed(ctx, kubeClientset, w, name, namespace, func(r *release) error {
+		if r.Chart.Metadata.Version != chartVersion {
+			return errors.Errorf("release %v/%v has chart version '%v', expected '%v'", namespace, name, r.Chart.Metadata.Version, chartVersion)
+		}
+		return nil
+	})
+}
+
+func ReleaseShouldBeDeployedWithRevision(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, revision int) error {
+	return releaseShouldBeDeployed(ctx, kubeClientset, w, name, namespace, func(r *release) error {
+		if r.Version != revision {
+			return errors.Errorf("release %v/%v has revision '%d', expected '%d'", namespace, name, r.Version, revision)
+		}
+		return nil
+	})
+}
+
+func releaseShouldBeDeployed(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, validate func(r *release) error) error {
+	var (
+		counter int
+		lastErr error
+	)
+
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for helm release %v/%v: %v", namespace, name, lastErr)
+		}
+		log.Infof("waiting for helm release %v/%v to be deployed", namespace, name)
+		r, err := getLatestRelease(kubeClientset, name, namespace)
+		if err != nil {
+			return err
+		}
+		switch {
+		case r.Info.Status != statusDeployed:
+			lastErr = errors.Errorf("release %v/%v revision %d is '%v'", namespace, name, r.Version, r.Info.Status)
+		default:
+			lastErr = validate(r)
+		}
+		if lastErr == nil {
+			log.Infof("helm release %v/%v is deployed at revision %d with chart %v-%v", namespace, name, r.Version, r.Chart.Metadata.Name, r.Chart.Metadata.Version)
+			return nil
+		}
+		log.Info(lastErr)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	statusDeployed = "deployed"
+
+	releaseOwner   = "helm"
+	releaseDataKey = "release"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// release holds the fields kubedog needs from the release Helm 3 stores in its release secrets
+type release struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	Info    struct {
+		Status string `json:"status"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+		} `json:"metadata"`
+	} `json:"chart"`
+}
+
+func getLatestRelease(kubeClientset kubernetes.Interface, name, namespace string) (*release, error) {
+	opts := metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"owner": releaseOwner, "name": name}).String(),
+	}
+	secrets, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.CoreV1().Secrets(namespace).List(context.Background(), opts)
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list release secrets of '%v'", name)
+	}
+
+	var latest *corev1.Secret
+	latestVersion := -1
+	for i, secret := range secrets.(*corev1.SecretList).Items {
+		version, err := strconv.Atoi(secret.Labels["version"])
+		if err != nil {
+			continue
+		}
+		if version > latestVersion {
+			latest = &secrets.(*corev1.SecretList).Items[i]
+			latestVersion = version
+		}
+	}
+	if latest == nil {
+		return nil, common.Errorf(common.ErrResourceNotFound, "helm release %v/%v was not found", namespace, name)
+	}
+	return decodeRelease(latest.Data[releaseDataKey])
+}
+
+// decodeRelease decodes release data the way Helm encodes it: base64 encoded, optionally gzipped json
+func decodeRelease(data []byte) (*release, error) {
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode helm release")
+	}
+	if bytes.HasPrefix(decoded, gzipMagic) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decompress helm release")
+		}
+		defer reader.Close()
+		decoded, err = io.ReadAll(reader)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decompress helm release")
+		}
+	}
+	var r release
+	if err := json.Unmarshal(decoded, &r); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal helm release")
+	}
+	return &r, nil
+}
@@ -0,0 +1,126 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	releaseName = "release1"
+	namespace   = "namespace1"
+)
+
+func TestReleaseShouldBeDeployed(t *testing.T) {
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		fn      func(*fake.Clientset) error
+		wantErr bool
+	}{
+		{
+			name:    "Positive Test: latest revision deployed",
+			objects: []runtime.Object{newReleaseSecret(t, 1, "superseded", "1.0.0"), newReleaseSecret(t, 2, statusDeployed, "1.1.0")},
+			fn: func(c *fake.Clientset) error {
+				return ReleaseShouldBeDeployed(context.Background(), c, w, releaseName, namespace)
+			},
+		},
+		{
+			name:    "Positive Test: chart version",
+			objects: []runtime.Object{newReleaseSecret(t, 1, "superseded", "1.0.0"), newReleaseSecret(t, 2, statusDeployed, "1.1.0")},
+			fn: func(c *fake.Clientset) error {
+				return ReleaseShouldBeDeployedWithChartVersion(context.Background(), c, w, releaseName, namespace, "1.1.0")
+			},
+		},
+		{
+			name:    "Positive Test: revision",
+			objects: []runtime.Object{newReleaseSecret(t, 1, "superseded", "1.0.0"), newReleaseSecret(t, 2, statusDeployed, "1.1.0")},
+			fn: func(c *fake.Clientset) error {
+				return ReleaseShouldBeDeployedWithRevision(context.Background(), c, w, releaseName, namespace, 2)
+			},
+		},
+		{
+			name:    "Negative Test: unexpected chart version",
+			objects: []runtime.Object{newReleaseSecret(t, 1, statusDeployed, "1.0.0")},
+			fn: func(c *fake.Clientset) error {
+				return ReleaseShouldBeDeployedWithChartVersion(context.Background(), c, w, releaseName, namespace, "1.1.0")
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: latest revision failed",
+			objects: []runtime.Object{newReleaseSecret(t, 1, statusDeployed, "1.0.0"), newReleaseSecret(t, 2, "failed", "1.1.0")},
+			fn: func(c *fake.Clientset) error {
+				return ReleaseShouldBeDeployed(context.Background(), c, w, releaseName, namespace)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: release not found",
+			fn: func(c *fake.Clientset) error {
+				return ReleaseShouldBeDeployed(context.Background(), c, w, releaseName, namespace)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(fake.NewSimpleClientset(tt.objects...)); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func newReleaseSecret(t *testing.T, revision int, status, chartVersion string) *corev1.Secret {
+	payload := fmt.Sprintf(`{"name":"%s","version":%d,"info":{"status":"%s"},"chart":{"metadata":{"name":"chart1","version":"%s"}}}`, releaseName, revision, status, chartVersion)
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write([]byte(payload)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", releaseName, revision),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"owner":   releaseOwner,
+				"name":    releaseName,
+				"status":  status,
+				"version": fmt.Sprint(revision),
+			},
+		},
+		Type: "helm.sh/release.v1",
+		Data: map[string][]byte{
+			releaseDataKey: []byte(base64.StdEncoding.EncodeToString(compressed.Bytes())),
+		},
+	}
+}
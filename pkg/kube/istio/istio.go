@@ -0,0 +1,48 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/structured"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+func VirtualServiceAvailable(ctx context.Context, dynamicClient dynamic.Interface, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, gatewayService, gatewayNamespace string, port int, path string) error {
+	endpoint, header, err := GetVirtualServiceEndpoint(ctx, dynamicClient, kubeClientset, w, name, namespace, gatewayService, gatewayNamespace, port, path)
+	if err != nil {
+		return err
+	}
+	check, err := structured.NewHTTPStatusCodeCheck(strconv.Itoa(http.StatusOK))
+	if err != nil {
+		return err
+	}
+	_, err = structured.EndpointResponseShouldCtx(ctx, w, endpoint, structured.HTTPRequest{Header: header}, check)
+	return err
+}
+
+func SendTrafficToVirtualService(ctx context.Context, dynamicClient dynamic.Interface, kubeClientset kubernetes.Interface, w common.WaiterConfig, tps int, name, namespace, gatewayService, gatewayNamespace string, port int, path string, duration int, durationUnits string, expectedErrors int) (*vegeta.Metrics, error) {
+	endpoint, header, err := GetVirtualServiceEndpoint(ctx, dynamicClient, kubeClientset, w, name, namespace, gatewayService, gatewayNamespace, port, path)
+	if err != nil {
+		return nil, err
+	}
+	return structured.SendTrafficToEndpoint(endpoint, header, tps, duration, durationUnits, expectedErrors)
+}
@@ -0,0 +1,74 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/structured"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+var VirtualServiceResource = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1beta1", Resource: "virtualservices"}
+
+// GetVirtualServiceEndpoint resolves the load balancer endpoint of the ingress gateway service.
+// The returned header sets 'Host' to the first host of the virtualservice that is not a wildcard, so the gateway routes by it.
+func GetVirtualServiceEndpoint(ctx context.Context, dynamicClient dynamic.Interface, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, gatewayService, gatewayNamespace string, port int, path string) (string, http.Header, error) {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return "", nil, err
+	}
+	virtualService, err := getVirtualService(dynamicClient, name, namespace)
+	if err != nil {
+		return "", nil, err
+	}
+	hosts, _, err := unstructured.NestedStringSlice(virtualService.Object, "spec", "hosts")
+	if err != nil {
+		return "", nil, err
+	}
+	header := http.Header{}
+	for _, host := range hosts {
+		if host != "" && !strings.Contains(host, "*") {
+			header.Set("Host", host)
+			break
+		}
+	}
+	if header.Get("Host") == "" {
+		return "", nil, errors.Errorf("virtualservice %v/%v has no routable hosts: '%v'", namespace, name, hosts)
+	}
+	endpoint, err := structured.GetServiceLoadBalancerEndpoint(ctx, kubeClientset, w, gatewayService, gatewayNamespace, port, path)
+	if err != nil {
+		return "", nil, err
+	}
+	return endpoint, header, nil
+}
+
+func getVirtualService(dynamicClient dynamic.Interface, name, namespace string) (*unstructured.Unstructured, error) {
+	virtualService, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return dynamicClient.Resource(VirtualServiceResource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get virtualservice '%v'", name)
+	}
+	return virtualService.(*unstructured.Unstructured), nil
+}
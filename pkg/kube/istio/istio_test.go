@@ -0,0 +1,150 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/internal/testutil"
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	virtualServiceName = "virtualservice1"
+	namespace          = "namespace1"
+	gatewayService     = "istio-ingressgateway"
+	gatewayNamespace   = "istio-system"
+	hostname           = "some.host.com"
+)
+
+func TestVirtualServiceAvailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != hostname {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	address, port := testutil.GetHostAndPort(t, server.URL)
+
+	tests := []struct {
+		name           string
+		virtualService *unstructured.Unstructured
+		gateway        *corev1.Service
+		wantErr        bool
+	}{
+		{
+			name:           "Positive Test: routed by host",
+			virtualService: newVirtualService("*.wildcard.com", hostname),
+			gateway:        newGatewayService(address),
+		},
+		{
+			name:           "Negative Test: host not routed",
+			virtualService: newVirtualService("other.host.com"),
+			gateway:        newGatewayService(address),
+			wantErr:        true,
+		},
+		{
+			name:           "Negative Test: only wildcard hosts",
+			virtualService: newVirtualService("*"),
+			gateway:        newGatewayService(address),
+			wantErr:        true,
+		},
+		{
+			name:           "Negative Test: gateway without load balancer",
+			virtualService: newVirtualService(hostname),
+			gateway:        newGatewayService(""),
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme())
+			_ = dynamicClient.Tracker().Create(VirtualServiceResource, tt.virtualService, namespace)
+			kubeClientset := fake.NewSimpleClientset(tt.gateway)
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := VirtualServiceAvailable(context.Background(), dynamicClient, kubeClientset, w, virtualServiceName, namespace, gatewayService, gatewayNamespace, port, "/"); (err != nil) != tt.wantErr {
+				t.Errorf("VirtualServiceAvailable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSendTrafficToVirtualService(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	address, port := testutil.GetHostAndPort(t, server.URL)
+
+	dynamicClient := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	_ = dynamicClient.Tracker().Create(VirtualServiceResource, newVirtualService(hostname), namespace)
+	kubeClientset := fake.NewSimpleClientset(newGatewayService(address))
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	metrics, err := SendTrafficToVirtualService(context.Background(), dynamicClient, kubeClientset, w, 2, virtualServiceName, namespace, gatewayService, gatewayNamespace, port, "/", 1, util.DurationSeconds, 0)
+	if err != nil {
+		t.Fatalf("SendTrafficToVirtualService() error = %v", err)
+	}
+	if metrics.Requests == 0 {
+		t.Errorf("SendTrafficToVirtualService() sent no requests")
+	}
+}
+
+func newVirtualService(hosts ...string) *unstructured.Unstructured {
+	specHosts := []interface{}{}
+	for _, host := range hosts {
+		specHosts = append(specHosts, host)
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "networking.istio.io/v1beta1",
+		"kind":       "VirtualService",
+		"metadata": map[string]interface{}{
+			"name":      virtualServiceName,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"hosts":    specHosts,
+			"gateways": []interface{}{gatewayNamespace + "/ingressgateway"},
+		},
+	}}
+}
+
+func newGatewayService(address string) *corev1.Service {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gatewayService,
+			Namespace: gatewayNamespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+		},
+	}
+	if address != "" {
+		service.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: address}}
+	}
+	return service
+}
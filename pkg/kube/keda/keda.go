@@ -0,0 +1,99 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keda
+
+import (
+	"context"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/structured"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+func ScaledObjectShouldBeReady(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, name, namespace string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for scaledobject %v/%v to be ready", namespace, name)
+		}
+		log.Infof("waiting for scaledobject %v/%v to be ready", namespace, name)
+		scaledObject, err := getScaledObject(dynamicClient, name, namespace)
+		if err != nil {
+			return err
+		}
+		ready, message := common.GetReadyCondition(scaledObject)
+		if ready {
+			log.Infof("scaledobject %v/%v is ready", namespace, name)
+			return nil
+		}
+		log.Infof("scaledobject %v/%v is not ready: '%v'", namespace, name, message)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// ScaledObjectTargetShouldScale waits for the deployment targeted by the scaledobject to have ready replicas when scaling from zero,
+// or to have no replicas when scaling to zero.
+func ScaledObjectTargetShouldScale(ctx context.Context, dynamicClient dynamic.Interface, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, direction string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+	if direction != DirectionFromZero && direction != DirectionToZero {
+		return common.Errorf(common.ErrUnsupportedOperation, "unsupported scale direction: '%s'", direction)
+	}
+
+	scaledObject, err := getScaledObject(dynamicClient, name, namespace)
+	if err != nil {
+		return err
+	}
+	target, err := getScaleTargetDeployment(scaledObject)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for deployment %v/%v to scale %v", namespace, target, direction)
+		}
+		deployment, err := structured.GetDeployment(kubeClientset, target, namespace)
+		if err != nil {
+			return err
+		}
+		replicas, readyReplicas := deployment.Status.Replicas, deployment.Status.ReadyReplicas
+		if (direction == DirectionFromZero && readyReplicas > 0) || (direction == DirectionToZero && replicas == 0) {
+			log.Infof("deployment %v/%v scaled %v, %d ready replicas", namespace, target, direction, readyReplicas)
+			return nil
+		}
+		log.Infof("waiting for deployment %v/%v to scale %v, %d replicas, %d ready", namespace, target, direction, replicas, readyReplicas)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keda
+
+import (
+	"context"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	DirectionFromZero = "from zero"
+	DirectionToZero   = "to zero"
+
+	kindDeployment = "Deployment"
+)
+
+var ScaledObjectResource = schema.GroupVersionResource{Group: "keda.sh", Version: "v1alpha1", Resource: "scaledobjects"}
+
+func getScaledObject(dynamicClient dynamic.Interface, name, namespace string) (*unstructured.Unstructured, error) {
+	scaledObject, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return dynamicClient.Resource(ScaledObjectResource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get scaledobject '%v'", name)
+	}
+	return scaledObject.(*unstructured.Unstructured), nil
+}
+
+// getScaleTargetDeployment returns the name of the scale target, which defaults to a Deployment when no kind is set
+func getScaleTargetDeployment(scaledObject *unstructured.Unstructured) (string, error) {
+	name, _, _ := unstructured.NestedString(scaledObject.Object, "spec", "scaleTargetRef", "name")
+	if name == "" {
+		return "", errors.Errorf("scaledobject %v/%v has no spec.scaleTargetRef.name", scaledObject.GetNamespace(), scaledObject.GetName())
+	}
+	if kind, _, _ := unstructured.NestedString(scaledObject.Object, "spec", "scaleTargetRef", "kind"); kind != "" && kind != kindDeployment {
+		return "", common.Errorf(common.ErrUnsupportedOperation, "unsupported scaledobject target kind: '%s'", kind)
+	}
+	return name, nil
+}
@@ -0,0 +1,171 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keda
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/internal/testutil"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	scaledObjectName = "scaledobject1"
+	deploymentName   = "deployment1"
+	namespace        = "namespace1"
+)
+
+func TestScaledObjectShouldBeReady(t *testing.T) {
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: scaledobject ready",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newScaledObject("True", "")),
+		},
+		{
+			name:          "Negative Test: scaledobject not ready",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newScaledObject("False", "")),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: scaledobject not found",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid client",
+			dynamicClient: nil,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ScaledObjectShouldBeReady(context.Background(), tt.dynamicClient, common.NewWaiterConfig(1, time.Millisecond), scaledObjectName, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("ScaledObjectShouldBeReady() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScaledObjectTargetShouldScale(t *testing.T) {
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		kubeClientset kubernetes.Interface
+		direction     string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: scaled from zero",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newScaledObject("True", "")),
+			kubeClientset: fakeKube.NewSimpleClientset(newDeployment(2, 1)),
+			direction:     DirectionFromZero,
+		},
+		{
+			name:          "Positive Test: scaled to zero",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newScaledObject("True", kindDeployment)),
+			kubeClientset: fakeKube.NewSimpleClientset(newDeployment(0, 0)),
+			direction:     DirectionToZero,
+		},
+		{
+			name:          "Negative Test: no ready replicas",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newScaledObject("True", "")),
+			kubeClientset: fakeKube.NewSimpleClientset(newDeployment(1, 0)),
+			direction:     DirectionFromZero,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: unsupported target kind",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newScaledObject("True", "StatefulSet")),
+			kubeClientset: fakeKube.NewSimpleClientset(newDeployment(2, 1)),
+			direction:     DirectionFromZero,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: unsupported direction",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newScaledObject("True", "")),
+			kubeClientset: fakeKube.NewSimpleClientset(newDeployment(2, 1)),
+			direction:     "up",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: deployment not found",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newScaledObject("True", "")),
+			kubeClientset: fakeKube.NewSimpleClientset(),
+			direction:     DirectionFromZero,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ScaledObjectTargetShouldScale(context.Background(), tt.dynamicClient, tt.kubeClientset, common.NewWaiterConfig(1, time.Millisecond), scaledObjectName, namespace, tt.direction); (err != nil) != tt.wantErr {
+				t.Errorf("ScaledObjectTargetShouldScale() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fakeResources are the resources of the kinds tracked by the fake dynamic client
+var fakeResources = map[string]schema.GroupVersionResource{
+	"ScaledObject": ScaledObjectResource,
+}
+
+func newScaledObject(readyStatus, targetKind string) *unstructured.Unstructured {
+	scaleTargetRef := map[string]interface{}{"name": deploymentName}
+	if targetKind != "" {
+		scaleTargetRef["kind"] = targetKind
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": ScaledObjectResource.GroupVersion().String(),
+		"kind":       "ScaledObject",
+		"metadata": map[string]interface{}{
+			"name":      scaledObjectName,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"scaleTargetRef": scaleTargetRef,
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": common.ConditionTypeReady, "status": readyStatus},
+			},
+		},
+	}}
+}
+
+func newDeployment(replicas, readyReplicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploymentName,
+			Namespace: namespace,
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:      replicas,
+			ReadyReplicas: readyReplicas,
+		},
+	}
+}
@@ -15,38 +15,221 @@ limitations under the License.
 package kube
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"path/filepath"
+	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/keikoproj/kubedog/pkg/generic"
+	"github.com/keikoproj/kubedog/pkg/kube/argocd"
+	"github.com/keikoproj/kubedog/pkg/kube/audit"
+	"github.com/keikoproj/kubedog/pkg/kube/certmanager"
 	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/deprecation"
+	"github.com/keikoproj/kubedog/pkg/kube/events"
+	"github.com/keikoproj/kubedog/pkg/kube/flux"
+	"github.com/keikoproj/kubedog/pkg/kube/gateway"
+	"github.com/keikoproj/kubedog/pkg/kube/helm"
+	"github.com/keikoproj/kubedog/pkg/kube/istio"
+	"github.com/keikoproj/kubedog/pkg/kube/keda"
+	"github.com/keikoproj/kubedog/pkg/kube/metrics"
 	"github.com/keikoproj/kubedog/pkg/kube/pod"
+	"github.com/keikoproj/kubedog/pkg/kube/policy"
+	"github.com/keikoproj/kubedog/pkg/kube/prometheus"
+	"github.com/keikoproj/kubedog/pkg/kube/rollout"
 	"github.com/keikoproj/kubedog/pkg/kube/structured"
 	unstruct "github.com/keikoproj/kubedog/pkg/kube/unstructured"
+	"github.com/keikoproj/kubedog/pkg/kube/upgrademanager"
+	"github.com/keikoproj/kubedog/pkg/kube/vpa"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 type ClientSet struct {
-	KubeInterface    kubernetes.Interface
-	DynamicInterface dynamic.Interface
-	timestamps       map[string]time.Time
-	config           configuration
+	KubeInterface     kubernetes.Interface
+	DynamicInterface  dynamic.Interface
+	restConfig        *rest.Config
+	discoveryCache    discovery.CachedDiscoveryInterface
+	discoveryOf       kubernetes.Interface
+	timestamps        map[string]time.Time
+	trafficMetrics    *vegeta.Metrics
+	trafficTargets    []structured.TrafficTarget
+	trafficForwards   []chan struct{}
+	httpRequest       structured.HTTPRequest
+	httpExchange      *httpExchange
+	targetMetrics     map[string]*vegeta.Metrics
+	readyPodCounts    map[string]int
+	restartCounts     map[string]restartCountsSnapshot
+	pausedReplicaSets map[string][]string
+	clusterClients    map[string]dynamic.Interface
+	namespace         ephemeralNamespace
+	mutations         *audit.Log
+	config            configuration
+	injected          bool
+	ctx               context.Context
+}
+
+// ClientSetOption configures a ClientSet built with NewClientSet
+type ClientSetOption func(*ClientSet)
+
+// WithRestConfig sets the config used by the steps that port-forward or exec into pods
+func WithRestConfig(config *rest.Config) ClientSetOption {
+	return func(kc *ClientSet) {
+		kc.restConfig = config
+	}
+}
+
+func WithFilesPath(path string) ClientSetOption {
+	return func(kc *ClientSet) {
+		kc.SetFilesPath(path)
+	}
+}
+
+func WithArtifactsPath(path string) ClientSetOption {
+	return func(kc *ClientSet) {
+		kc.SetArtifactsPath(path)
+	}
+}
+
+func WithTemplateArguments(args interface{}) ClientSetOption {
+	return func(kc *ClientSet) {
+		kc.SetTemplateArguments(args)
+	}
+}
+
+func WithEphemeralNamespace(prefix string) ClientSetOption {
+	return func(kc *ClientSet) {
+		kc.SetEphemeralNamespace(prefix)
+	}
+}
+
+func WithWaiter(tries int, interval time.Duration) ClientSetOption {
+	return func(kc *ClientSet) {
+		kc.SetWaiterTries(tries)
+		kc.SetWaiterInterval(interval)
+	}
+}
+
+// NewClientSet returns a ClientSet using the given clients, such as fakes in unit tests or the clients of an embedding program.
+// DiscoverClients keeps them instead of reading the kubeconfig.
+func NewClientSet(kubeInterface kubernetes.Interface, dynamicInterface dynamic.Interface, opts ...ClientSetOption) *ClientSet {
+	kc := &ClientSet{
+		KubeInterface:    kubeInterface,
+		DynamicInterface: dynamicInterface,
+		injected:         true,
+	}
+	for _, opt := range opts {
+		opt(kc)
+	}
+	return kc
 }
 
 func (kc *ClientSet) SetFilesPath(path string) {
 	kc.config.filesPath = path
 }
 
+func (kc *ClientSet) SetArtifactsPath(path string) {
+	kc.config.artifactsPath = path
+}
+
+// SetSchemaValidation enables validating the resources against the OpenAPI v3 schemas of the cluster before using them, reporting every invalid field at once
+func (kc *ClientSet) SetSchemaValidation(enabled bool) {
+	kc.config.validateSchema = enabled
+}
+
+// SetMutationAuditLog enables recording every create, update, patch and delete sent by the clients discovered afterwards,
+// they are exported to the artifacts path at the end of each scenario
+func (kc *ClientSet) SetMutationAuditLog(enabled bool) {
+	kc.config.auditMutations = enabled
+}
+
+// SetIgnoredFields sets the fields ignored when comparing resources to expected files instead of the server populated
+// unstructured.DefaultIgnoredFields, nil restores them
+func (kc *ClientSet) SetIgnoredFields(fields []string) {
+	kc.config.ignoredFields = fields
+}
+
+// SetPrometheusURL sets the Prometheus URL used by query steps, when not set they port-forward to the prometheus-operated service in the monitoring namespace
+func (kc *ClientSet) SetPrometheusURL(url string) {
+	kc.config.prometheusURL = url
+}
+
+// SetAlertmanagerURL sets the Alertmanager URL used by alert steps, when not set they port-forward to the alertmanager-operated service in the monitoring namespace
+func (kc *ClientSet) SetAlertmanagerURL(url string) {
+	kc.config.alertmanagerURL = url
+}
+
+// SetContext sets the context the waiters return early on when it is done, SetScenario sets the context of each scenario
+func (kc *ClientSet) SetContext(ctx context.Context) {
+	kc.ctx = ctx
+}
+
+// ResetScenarioState clears what the steps of the previous scenario stored, SetScenario calls it before each scenario
+func (kc *ClientSet) ResetScenarioState() {
+	kc.trafficMetrics = nil
+	kc.trafficTargets = nil
+	kc.targetMetrics = nil
+	kc.httpRequest = structured.HTTPRequest{}
+	kc.httpExchange = nil
+}
+
 func (kc *ClientSet) SetTemplateArguments(args interface{}) {
 	kc.config.templateArguments = args
 }
 
+// SetEphemeralNamespace enables creating a namespace named after the prefix and a random suffix before each scenario,
+// it is the Namespace template argument during the scenario and is deleted after it
+func (kc *ClientSet) SetEphemeralNamespace(prefix string) {
+	kc.config.ephemeralNamespacePrefix = prefix
+}
+
+// EphemeralNamespace returns the namespace created for the current scenario, or an empty string
+func (kc *ClientSet) EphemeralNamespace() string {
+	return kc.namespace.name
+}
+
+// CreateEphemeralNamespace creates the namespace of the scenario when an ephemeral namespace prefix is set, discovering
+// the clients first when needed. SetScenario calls it before each scenario
+func (kc *ClientSet) CreateEphemeralNamespace() error {
+	if kc.config.ephemeralNamespacePrefix == "" {
+		return nil
+	}
+	if kc.KubeInterface == nil {
+		if err := kc.DiscoverClients(); err != nil {
+			return err
+		}
+	}
+	name := getEphemeralNamespaceName(kc.config.ephemeralNamespacePrefix)
+	templateArguments, err := withNamespaceTemplateArgument(kc.config.templateArguments, name)
+	if err != nil {
+		return err
+	}
+	if err := structured.CreateNamespace(kc.KubeInterface, name, map[string]string{ephemeralNamespaceLabel: "true"}); err != nil {
+		return err
+	}
+	kc.namespace = ephemeralNamespace{name: name, templateArguments: templateArguments}
+	return nil
+}
+
+// DeleteEphemeralNamespace deletes the namespace of the scenario, removing its finalizers if it is stuck terminating.
+// SetScenario calls it after each scenario
+func (kc *ClientSet) DeleteEphemeralNamespace() error {
+	if kc.namespace.name == "" {
+		return nil
+	}
+	name := kc.namespace.name
+	kc.namespace = ephemeralNamespace{}
+	return structured.DeleteNamespace(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name)
+}
+
 func (kc *ClientSet) SetWaiterInterval(duration time.Duration) {
 	kc.config.waiterInterval = duration
 }
@@ -56,21 +239,23 @@ func (kc *ClientSet) SetWaiterTries(tries int) {
 }
 
 func (kc *ClientSet) DiscoverClients() error {
-	var (
-		home, _        = os.UserHomeDir()
-		kubeconfigPath = filepath.Join(home, ".kube", "config")
-	)
-
-	if exported := os.Getenv("KUBECONFIG"); exported != "" {
-		kubeconfigPath = exported
+	if kc.injected {
+		return nil
 	}
-	if _, err := os.Stat(kubeconfigPath); os.IsNotExist(err) {
-		return errors.Errorf("expected kubeconfig to exist for create operation, '%v'", kubeconfigPath)
+	kubeconfigPath, err := getKubeconfigPath()
+	if err != nil {
+		return err
 	}
 	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
 	if err != nil {
 		return err
 	}
+	if kc.config.auditMutations {
+		if kc.mutations == nil {
+			kc.mutations = &audit.Log{}
+		}
+		config.Wrap(kc.mutations.Wrap)
+	}
 
 	dynClient, err := dynamic.NewForConfig(config)
 	if err != nil {
@@ -87,6 +272,7 @@ func (kc *ClientSet) DiscoverClients() error {
 
 	kc.DynamicInterface = dynClient
 	kc.KubeInterface = client
+	kc.restConfig = config
 
 	return nil
 }
@@ -101,6 +287,39 @@ func (kc *ClientSet) SetTimestamp(timestampName string) error {
 	return nil
 }
 
+// TimeSinceTimestampShouldBe validates less or more than the duration passed since the timestamp was stored
+func (kc *ClientSet) TimeSinceTimestampShouldBe(comparison string, duration int, durationUnits, timestampName string) error {
+	timestamp, err := kc.GetTimestamp(timestampName)
+	if err != nil {
+		return err
+	}
+	limit, err := getDuration(duration, durationUnits)
+	if err != nil {
+		return err
+	}
+
+	elapsed := time.Since(timestamp).Round(time.Second)
+	switch comparison {
+	case "less":
+		if elapsed >= limit {
+			return errors.Errorf("expected less than %v since '%s', but %v passed", limit, timestampName, elapsed)
+		}
+	case "more":
+		if elapsed <= limit {
+			return errors.Errorf("expected more than %v since '%s', but %v passed", limit, timestampName, elapsed)
+		}
+	default:
+		return errors.Errorf("parameter comparison can only be 'less' or 'more'")
+	}
+	log.Infof("%v passed since '%s'", elapsed, timestampName)
+	return nil
+}
+
+// WaitFor pauses the scenario for the duration, returning early when the context of the scenario is done
+func (kc *ClientSet) WaitFor(duration int, durationUnits string) error {
+	return generic.WaitForCtx(kc.getContext(), duration, durationUnits)
+}
+
 func (kc *ClientSet) KubernetesClusterShouldBe(state string) error {
 	switch state {
 	case common.StateCreated, common.StateUpgraded:
@@ -114,7 +333,7 @@ func (kc *ClientSet) KubernetesClusterShouldBe(state string) error {
 		}
 		return nil
 	default:
-		return fmt.Errorf("unsupported state: '%s'", state)
+		return common.Errorf(common.ErrUnsupportedOperation, "unsupported state: '%s'", state)
 	}
 }
 
@@ -122,11 +341,11 @@ func (kc *ClientSet) DeleteAllTestResources() error {
 	if err := kc.DiscoverClients(); err != nil {
 		return err
 	}
-	return unstruct.DeleteResourcesAtPath(kc.DynamicInterface, kc.getDiscoveryClient(), kc.config.templateArguments, kc.getWaiterConfig(), kc.getTemplatesPath())
+	return unstruct.DeleteResourcesAtPathCtx(kc.getContext(), kc.DynamicInterface, kc.getDiscoveryClient(), kc.getTemplateArguments(), kc.getWaiterConfig(), kc.getTemplatesPath())
 }
 
 func (kc *ClientSet) ResourceOperation(operation, resourceFileName string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+	resource, err := kc.getResource(resourceFileName)
 	if err != nil {
 		return err
 	}
@@ -135,7 +354,7 @@ func (kc *ClientSet) ResourceOperation(operation, resourceFileName string) error
 }
 
 func (kc *ClientSet) ResourceOperationInNamespace(operation, resourceFileName, namespace string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+	resource, err := kc.getResource(resourceFileName)
 	if err != nil {
 		return err
 	}
@@ -143,7 +362,15 @@ func (kc *ClientSet) ResourceOperationInNamespace(operation, resourceFileName, n
 }
 
 func (kc *ClientSet) ResourcesOperation(operation, resourcesFileName string) error {
-	resources, err := unstruct.GetResources(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourcesFileName))
+	resources, err := kc.getResources(resourcesFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourcesOperation(kc.DynamicInterface, resources, operation)
+}
+
+func (kc *ClientSet) KustomizeResourcesOperation(operation, kustomizationDir string) error {
+	resources, err := kc.getKustomizeResources(kustomizationDir)
 	if err != nil {
 		return err
 	}
@@ -151,15 +378,23 @@ func (kc *ClientSet) ResourcesOperation(operation, resourcesFileName string) err
 }
 
 func (kc *ClientSet) ResourcesOperationInNamespace(operation, resourcesFileName, namespace string) error {
-	resources, err := unstruct.GetResources(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourcesFileName))
+	resources, err := kc.getResources(resourcesFileName)
 	if err != nil {
 		return err
 	}
 	return unstruct.ResourcesOperationInNamespace(kc.DynamicInterface, resources, operation, namespace)
 }
 
+func (kc *ClientSet) ResourcesOperationAndWaitReady(operation, resourcesFileName string) error {
+	resources, err := kc.getResources(resourcesFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourcesOperationAndWaitReady(kc.DynamicInterface, resources, kc.getWaiterConfig(), operation)
+}
+
 func (kc *ClientSet) ResourceOperationWithResult(operation, resourceFileName, expectedResult string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+	resource, err := kc.getResource(resourceFileName)
 	if err != nil {
 		return err
 	}
@@ -167,57 +402,222 @@ func (kc *ClientSet) ResourceOperationWithResult(operation, resourceFileName, ex
 }
 
 func (kc *ClientSet) ResourceOperationWithResultInNamespace(operation, resourceFileName, namespace, expectedResult string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+	resource, err := kc.getResource(resourceFileName)
 	if err != nil {
 		return err
 	}
 	return unstruct.ResourceOperationWithResultInNamespace(kc.DynamicInterface, resource, operation, namespace, expectedResult)
 }
 
+func (kc *ClientSet) ResourceOperationShouldBeDenied(operation, resourceFileName, expectedMessage string) error {
+	return kc.ResourceOperationInNamespaceShouldBeDenied(operation, resourceFileName, "", expectedMessage)
+}
+
+func (kc *ClientSet) ResourceOperationInNamespaceShouldBeDenied(operation, resourceFileName, namespace, expectedMessage string) error {
+	resource, err := kc.getResource(resourceFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourceOperationShouldBeDenied(kc.DynamicInterface, resource, operation, namespace, expectedMessage)
+}
+
+func (kc *ClientSet) ResourceDryRunShouldHaveField(resourceFileName, selector string) error {
+	resource, err := kc.getResource(resourceFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourceDryRunShouldHaveField(kc.DynamicInterface, resource, selector)
+}
+
+func (kc *ClientSet) ResourceDryRunShouldBeDenied(resourceFileName, expectedMessage string) error {
+	resource, err := kc.getResource(resourceFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourceDryRunShouldBeDenied(kc.DynamicInterface, resource, expectedMessage)
+}
+
 func (kc *ClientSet) ResourceShouldBe(resourceFileName, state string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+	resource, err := kc.getResource(resourceFileName)
 	if err != nil {
 		return err
 	}
-	return unstruct.ResourceShouldBe(kc.DynamicInterface, resource, kc.getWaiterConfig(), state)
+	return unstruct.ResourceShouldBeCtx(kc.getContext(), kc.DynamicInterface, resource, kc.getWaiterConfig(), state)
 }
 
 func (kc *ClientSet) ResourceShouldConvergeToSelector(resourceFileName, selector string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+	resource, err := kc.getResource(resourceFileName)
 	if err != nil {
 		return err
 	}
-	return unstruct.ResourceShouldConvergeToSelector(kc.DynamicInterface, resource, kc.getWaiterConfig(), selector)
+	return unstruct.ResourceShouldConvergeToSelectorCtx(kc.getContext(), kc.DynamicInterface, resource, kc.getWaiterConfig(), selector)
+}
+
+func (kc *ClientSet) ResourcesShouldBe(resourcesFileName, state string) error {
+	resources, err := kc.getResources(resourcesFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourcesShouldBeCtx(kc.getContext(), kc.DynamicInterface, resources, kc.getWaiterConfig(), state)
+}
+
+func (kc *ClientSet) ResourcesShouldConvergeToSelector(resourcesFileName, selector string) error {
+	resources, err := kc.getResources(resourcesFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourcesShouldConvergeToSelectorCtx(kc.getContext(), kc.DynamicInterface, resources, kc.getWaiterConfig(), selector)
+}
+
+func (kc *ClientSet) ResourceShouldBeCurrentInClusters(resourceFileName, contexts string) error {
+	resource, err := kc.getResource(resourceFileName)
+	if err != nil {
+		return err
+	}
+	clusters, err := kc.getClusterClients(contexts)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourceShouldBeCurrentInClusters(kc.getContext(), clusters, resource, kc.getWaiterConfig())
 }
 
 func (kc *ClientSet) ResourceShouldConvergeToField(resourceFileName, selector string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+	resource, err := kc.getResource(resourceFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourceShouldConvergeToFieldCtx(kc.getContext(), kc.DynamicInterface, resource, kc.getWaiterConfig(), selector)
+}
+
+func (kc *ClientSet) ResourceFieldShouldBe(resourceFileName, key, comparison, value string) error {
+	resource, err := kc.getResource(resourceFileName)
+	if err != nil {
+		return err
+	}
+	switch comparison {
+	case "at least":
+		return unstruct.ResourceFieldShouldBeAtLeast(kc.getContext(), kc.DynamicInterface, resource, kc.getWaiterConfig(), key, value)
+	case "at most":
+		return unstruct.ResourceFieldShouldBeAtMost(kc.getContext(), kc.DynamicInterface, resource, kc.getWaiterConfig(), key, value)
+	default:
+		return errors.Errorf("parameter comparison can only be 'at least' or 'at most'")
+	}
+}
+
+func (kc *ClientSet) ResourceFieldShouldMatchJSONPath(resourceFileName, jsonPath, operator, value string) error {
+	resource, err := kc.getResource(resourceFileName)
 	if err != nil {
 		return err
 	}
-	return unstruct.ResourceShouldConvergeToField(kc.DynamicInterface, resource, kc.getWaiterConfig(), selector)
+	switch operator {
+	case "contain":
+		operator = "contains"
+	case "match":
+		operator = "matches"
+	}
+	return unstruct.ResourceFieldShouldMatchJSONPath(kc.getContext(), kc.DynamicInterface, resource, kc.getWaiterConfig(), jsonPath, operator, value)
 }
 
 func (kc *ClientSet) ResourceConditionShouldBe(resourceFileName, conditionType, conditionValue string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+	resource, err := kc.getResource(resourceFileName)
 	if err != nil {
 		return err
 	}
-	return unstruct.ResourceConditionShouldBe(kc.DynamicInterface, resource, kc.getWaiterConfig(), conditionType, conditionValue)
+	return unstruct.ResourceConditionShouldBeCtx(kc.getContext(), kc.DynamicInterface, resource, kc.getWaiterConfig(), conditionType, conditionValue)
+}
+
+func (kc *ClientSet) ResourceShouldBeCurrent(resourceFileName string) error {
+	resource, err := kc.getResource(resourceFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourceShouldBeCurrentCtx(kc.getContext(), kc.DynamicInterface, resource, kc.getWaiterConfig())
 }
 
 func (kc *ClientSet) UpdateResourceWithField(resourceFileName, key, value string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+	resource, err := kc.getResource(resourceFileName)
 	if err != nil {
 		return err
 	}
 	return unstruct.UpdateResourceWithField(kc.DynamicInterface, resource, key, value)
 }
 
+func (kc *ClientSet) ResourceFieldShouldBeOwnedBy(resourceFileName, field, manager string) error {
+	resource, err := kc.getResource(resourceFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourceFieldShouldBeOwnedBy(kc.DynamicInterface, resource, field, manager)
+}
+
+func (kc *ClientSet) UpdateResourceStatusWithField(resourceFileName, key, value string) error {
+	resource, err := kc.getResource(resourceFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.UpdateResourceStatusWithField(kc.DynamicInterface, resource, key, value)
+}
+
+func (kc *ClientSet) ResourcesShouldNotUseRemovedAPIs(scope, targetVersion string) error {
+	if err := deprecation.ManifestsShouldNotUseRemovedAPIs(kc.getTemplateArguments(), kc.getTemplatesPath(), targetVersion); err != nil {
+		return err
+	}
+	switch scope {
+	case "resources":
+		return nil
+	case "resources and cluster objects":
+		return deprecation.ClusterShouldNotUseRemovedAPIs(kc.DynamicInterface, kc.getDiscoveryClient(), targetVersion)
+	default:
+		return errors.Errorf("parameter scope can only be 'resources' or 'resources and cluster objects'")
+	}
+}
+
+func (kc *ClientSet) ResourceFieldShouldMatch(resourceType, name, namespace, sourceName, sourceNamespace, field, ignoredFields string) error {
+	return unstruct.ResourceFieldShouldMatch(kc.getContext(), kc.DynamicInterface, kc.getDiscoveryClient(), kc.getWaiterConfig(), resourceType, name, namespace, sourceName, sourceNamespace, field, ignoredFields)
+}
+
+// ResourceShouldMatchExpected waits until the resource has every field of the expected file, which is not validated
+// against the schema since it is usually partial, ignoring the comma separated fields as well
+func (kc *ClientSet) ResourceShouldMatchExpected(resourceFileName, expectedFileName, ignoredFields string) error {
+	resource, err := kc.getResource(resourceFileName)
+	if err != nil {
+		return err
+	}
+	expected, err := unstruct.GetResourceCtx(kc.getContext(), kc.getDiscoveryClient(), kc.getTemplateArguments(), kc.getResourcePath(expectedFileName))
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourceShouldMatchExpected(kc.getContext(), kc.DynamicInterface, resource, kc.getWaiterConfig(), expected.Resource, kc.getIgnoredFields(ignoredFields))
+}
+
+func (kc *ClientSet) DeleteResourceChildrenShouldBeGarbageCollected(resourceFileName, childKinds string) error {
+	resource, err := kc.getResource(resourceFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.DeleteResourceChildrenShouldBeGarbageCollected(kc.getContext(), kc.DynamicInterface, kc.getDiscoveryClient(), resource, kc.getWaiterConfig(), childKinds)
+}
+
+func (kc *ClientSet) ResourcesShouldBeUninstalled(resourcesPath string) error {
+	return unstruct.ResourcesAtPathShouldBeUninstalled(kc.getContext(), kc.DynamicInterface, kc.getTemplateArguments(), kc.getWaiterConfig(), kc.getResourcePath(resourcesPath))
+}
+
 func (kc *ClientSet) VerifyInstanceGroups() error {
 	return unstruct.VerifyInstanceGroups(kc.DynamicInterface)
 }
 
+func (kc *ClientSet) ScaleInstanceGroup(name, namespace string, minSize, maxSize int) error {
+	return unstruct.ScaleInstanceGroup(kc.DynamicInterface, name, namespace, int64(minSize), int64(maxSize))
+}
+
+func (kc *ClientSet) InstanceGroupShouldBe(name, namespace, state string) error {
+	return unstruct.InstanceGroupShouldBe(kc.getContext(), kc.DynamicInterface, kc.getWaiterConfig(), name, namespace, state)
+}
+
+func (kc *ClientSet) InstanceGroupNodesShouldMatchMinSize(name, namespace string) error {
+	return unstruct.InstanceGroupNodesShouldMatchMinSize(kc.getContext(), kc.KubeInterface, kc.DynamicInterface, kc.getWaiterConfig(), name, namespace)
+}
+
 func (kc *ClientSet) ListPods(namespace string) error {
 	// TODO: use ListPodsWithSelector like ListPods does, ListPods is redundant
 	return pod.ListPods(kc.KubeInterface, namespace)
@@ -231,6 +631,49 @@ func (kc *ClientSet) PodsWithSelectorHaveRestartCountLessThan(namespace, selecto
 	return pod.PodsWithSelectorHaveRestartCountLessThan(kc.KubeInterface, namespace, selector, restartCount)
 }
 
+// StoreRestartCounts stores the restart counts of the pods with the selector, and the current time, under the name
+func (kc *ClientSet) StoreRestartCounts(namespace, selector, name string) error {
+	restartCounts, err := pod.GetRestartCounts(kc.KubeInterface, namespace, selector)
+	if err != nil {
+		return err
+	}
+	if kc.restartCounts == nil {
+		kc.restartCounts = map[string]restartCountsSnapshot{}
+	}
+	kc.restartCounts[name] = restartCountsSnapshot{namespace: namespace, selector: selector, restartCounts: restartCounts}
+	return kc.SetTimestamp(name)
+}
+
+// RestartCountsShouldIncrease validates the restart counts increased by at most the change, either 'not increase' or
+// 'increase by at most N', between the restart counts stored as from and to, or since the ones stored as since
+func (kc *ClientSet) RestartCountsShouldIncrease(change, from, to, since string) error {
+	var maxIncrease int
+	if change != "not increase" {
+		if _, err := fmt.Sscanf(change, "increase by at most %d", &maxIncrease); err != nil {
+			return errors.Errorf("parameter change can only be 'not increase' or 'increase by at most N'")
+		}
+	}
+	if since != "" {
+		from = since
+	}
+	before, ok := kc.restartCounts[from]
+	if !ok {
+		return errors.Errorf("no restart counts stored as '%s'", from)
+	}
+	if since != "" {
+		after, err := pod.GetRestartCounts(kc.KubeInterface, before.namespace, before.selector)
+		if err != nil {
+			return err
+		}
+		return pod.RestartCountsShouldIncreaseByAtMost(before.restartCounts, after, maxIncrease)
+	}
+	after, ok := kc.restartCounts[to]
+	if !ok {
+		return errors.Errorf("no restart counts stored as '%s'", to)
+	}
+	return pod.RestartCountsShouldIncreaseByAtMost(before.restartCounts, after.restartCounts, maxIncrease)
+}
+
 func (kc *ClientSet) SomeOrAllPodsInNamespaceWithSelectorHaveStringInLogsSinceTime(someOrAll, namespace, selector, searchKeyword, sinceTime string) error {
 	timestamp, err := kc.GetTimestamp(sinceTime)
 	if err != nil {
@@ -272,12 +715,92 @@ func (kc *ClientSet) PodsInNamespaceWithSelectorShouldHaveLabels(namespace, sele
 	return pod.PodsInNamespaceWithSelectorShouldHaveLabels(kc.KubeInterface, namespace, selector, labels)
 }
 
+func (kc *ClientSet) PodsInNamespaceWithSelectorShouldHaveContainer(namespace, selector, containerName string) error {
+	return pod.PodsInNamespaceWithSelectorShouldHaveContainer(kc.KubeInterface, namespace, selector, containerName)
+}
+
+func (kc *ClientSet) PodsInNamespaceWithSelectorShouldBeAllocatedResource(namespace, selector, resourceName string) error {
+	return pod.PodsInNamespaceWithSelectorShouldBeAllocatedResource(kc.KubeInterface, namespace, selector, resourceName)
+}
+
+func (kc *ClientSet) PodsInNamespaceWithSelectorShouldRunOnFargate(namespace, selector string) error {
+	return pod.PodsInNamespaceWithSelectorShouldRunOnFargate(kc.KubeInterface, namespace, selector)
+}
+
+func (kc *ClientSet) DeleteRandomPods(count int, selector, namespace string) error {
+	readyCount, err := pod.DeleteRandomPods(kc.KubeInterface, namespace, selector, count)
+	if err != nil {
+		return err
+	}
+	if kc.readyPodCounts == nil {
+		kc.readyPodCounts = map[string]int{}
+	}
+	kc.readyPodCounts[namespace+"/"+selector] = readyCount
+	return nil
+}
+
+func (kc *ClientSet) PodsShouldRecoverReadyCount(selector, namespace string) error {
+	readyCount, ok := kc.readyPodCounts[namespace+"/"+selector]
+	if !ok {
+		return errors.Errorf("no ready count stored for pods with selector '%v' in namespace %v, random pods must be deleted first", selector, namespace)
+	}
+	return pod.PodsInNamespaceWithSelectorShouldBeReady(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), namespace, selector, readyCount)
+}
+
 func (kc *ClientSet) PodInNamespaceShouldHaveLabels(name, namespace, labels string) error {
 	return pod.PodInNamespaceShouldHaveLabels(kc.KubeInterface, name, namespace, labels)
 }
 
-func (kc *ClientSet) SecretOperationFromEnvironmentVariable(operation, name, namespace, environmentVariable string) error {
-	return structured.SecretOperationFromEnvironmentVariable(kc.KubeInterface, operation, name, namespace, environmentVariable)
+func (kc *ClientSet) ExecInPod(command, name, container, namespace string) error {
+	_, _, err := pod.ExecInPod(kc.KubeInterface, kc.restConfig, name, namespace, container, command)
+	return err
+}
+
+func (kc *ClientSet) ExecInPodOutputShouldContain(command, name, container, namespace, expected string) error {
+	return pod.ExecInPodOutputShouldContain(kc.KubeInterface, kc.restConfig, name, namespace, container, command, expected)
+}
+
+func (kc *ClientSet) EventsShouldContainReason(kind, name, namespace, reason string) error {
+	return events.EventsShouldContainReason(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), kind, name, namespace, reason)
+}
+
+func (kc *ClientSet) EventsShouldNotContainReason(kind, name, namespace, reason string) error {
+	return events.EventsShouldNotContainReason(kc.KubeInterface, kind, name, namespace, reason)
+}
+
+func (kc *ClientSet) CreateNamespace(name string) error {
+	return structured.CreateNamespace(kc.KubeInterface, name, nil)
+}
+
+func (kc *ClientSet) DeleteNamespace(name string) error {
+	return structured.DeleteNamespace(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name)
+}
+
+func (kc *ClientSet) SecretOperationFromEnvironmentVariable(operation, name, namespace, environmentVariables string) error {
+	return structured.SecretOperationFromEnvironmentVariable(kc.KubeInterface, operation, name, namespace, environmentVariables)
+}
+
+func (kc *ClientSet) SecretOperationFromFile(operation, name, namespace, key, fileName string) error {
+	return structured.SecretOperationFromFile(kc.KubeInterface, operation, name, namespace, key, kc.getResourcePath(fileName))
+}
+
+func (kc *ClientSet) SecretOperationFromLiterals(operation, name, namespace, literals string) error {
+	return structured.SecretOperationFromLiterals(kc.KubeInterface, operation, name, namespace, literals)
+}
+
+func (kc *ClientSet) SecretOperationFromFiles(operation, name, namespace, fileNames string) error {
+	return structured.SecretOperationFromFiles(kc.KubeInterface, operation, name, namespace, kc.getResourcePaths(fileNames))
+}
+
+func (kc *ClientSet) SecretKeyShouldHaveValue(name, namespace, key, encoding, value string) error {
+	switch encoding {
+	case "value":
+		return structured.SecretKeyShouldHaveValue(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, key, value, false)
+	case "base64 value":
+		return structured.SecretKeyShouldHaveValue(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, key, value, true)
+	default:
+		return common.Errorf(common.ErrUnsupportedOperation, "parameter encoding can only be 'value' or 'base64 value'")
+	}
 }
 
 func (kc *ClientSet) SecretDelete(name, namespace string) error {
@@ -286,7 +809,58 @@ func (kc *ClientSet) SecretDelete(name, namespace string) error {
 }
 
 func (kc *ClientSet) NodesWithSelectorShouldBe(expectedNodes int, selector, state string) error {
-	return structured.NodesWithSelectorShouldBe(kc.KubeInterface, kc.getWaiterConfig(), expectedNodes, selector, state)
+	return structured.NodesWithSelectorShouldBeCtx(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), expectedNodes, selector, state)
+}
+
+func (kc *ClientSet) NodeOperationWithSelector(operation, selector string) error {
+	return structured.NodeOperationWithSelector(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), operation, selector, -1)
+}
+
+func (kc *ClientSet) NodeDrainWithSelector(selector string, gracePeriod int) error {
+	return structured.NodeOperationWithSelector(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), "drain", selector, gracePeriod)
+}
+
+func (kc *ClientSet) NodeTaintWithSelector(operation, selector, taint string) error {
+	return structured.NodeTaintWithSelector(kc.KubeInterface, operation, selector, taint)
+}
+
+func (kc *ClientSet) NodeLabelWithSelector(operation, selector, label string) error {
+	return structured.NodeLabelWithSelector(kc.KubeInterface, operation, selector, label)
+}
+
+func (kc *ClientSet) NodeHasTaint(selector, state, taint string) error {
+	return structured.NodeHasTaint(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), selector, taint, state == "have")
+}
+
+func (kc *ClientSet) NodeHasLabel(selector, state, label string) error {
+	return structured.NodeHasLabel(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), selector, label, state == "have")
+}
+
+func (kc *ClientSet) NewNodeDisruption(selector string) (*structured.NodeDisruption, error) {
+	return structured.NewNodeDisruption(kc.KubeInterface, selector)
+}
+
+func (kc *ClientSet) NodeDisruptionShouldBeHealed(disruption *structured.NodeDisruption) error {
+	return structured.NodeDisruptionShouldBeHealed(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), disruption)
+}
+
+func (kc *ClientSet) ResourceQuotaUsageShouldBe(resourceName, name, namespace, operator, expectedValue string) error {
+	return structured.ResourceQuotaUsageShouldBe(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), resourceName, name, namespace, operator, expectedValue)
+}
+
+func (kc *ClientSet) LimitRangeDefaultsShouldBeApplied(namespace string) error {
+	return structured.LimitRangeDefaultsShouldBeApplied(kc.KubeInterface, namespace)
+}
+
+func (kc *ClientSet) ImagePullShouldBe(image, secretName, namespace, result string) error {
+	switch result {
+	case "run":
+		return structured.ImagePullShouldBe(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), image, secretName, namespace, true)
+	case "fail to pull":
+		return structured.ImagePullShouldBe(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), image, secretName, namespace, false)
+	default:
+		return errors.Errorf("parameter result can only be 'run' or 'fail to pull'")
+	}
 }
 
 func (kc *ClientSet) ResourceInNamespace(resourceType, name, isOrIsNot, namespace string) error {
@@ -308,26 +882,98 @@ func (kc *ClientSet) ValidatePrometheusVolumeClaimTemplatesName(statefulsetName,
 	return structured.ValidatePrometheusVolumeClaimTemplatesName(kc.KubeInterface, statefulsetName, namespace, volumeClaimTemplatesName)
 }
 
+func (kc *ClientSet) ServiceAccountShouldBeAbleTo(serviceAccount, serviceAccountNamespace, shouldOrNot, verb, resource, namespace string) error {
+	if serviceAccountNamespace != "" {
+		serviceAccount = fmt.Sprintf("%v/%v", serviceAccountNamespace, serviceAccount)
+	}
+	switch shouldOrNot {
+	case "should":
+		return structured.ServiceAccountShouldBeAbleTo(kc.KubeInterface, serviceAccount, verb, resource, namespace, true)
+	case "should not":
+		return structured.ServiceAccountShouldBeAbleTo(kc.KubeInterface, serviceAccount, verb, resource, namespace, false)
+	default:
+		return errors.Errorf("parameter shouldOrNot can only be 'should' or 'should not'")
+	}
+}
+
 func (kc *ClientSet) ListNodes() error {
 	return structured.ListNodes(kc.KubeInterface)
 }
 
+func (kc *ClientSet) SetStatefulSetPartition(name, namespace string, partition int) error {
+	return structured.SetStatefulSetPartition(kc.KubeInterface, name, namespace, int32(partition))
+}
+
+func (kc *ClientSet) StatefulSetPartitionShouldBeUpdated(name, namespace string) error {
+	return structured.StatefulSetPartitionShouldBeUpdated(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace)
+}
+
 func (kc *ClientSet) DaemonSetIsRunning(name, namespace string) error {
-	return structured.DaemonSetIsRunning(kc.KubeInterface, kc.getExpBackoff(), name, namespace)
+	return structured.DaemonSetRolloutCompleteCtx(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace)
 }
 
 func (kc *ClientSet) DeploymentIsRunning(name, namespace string) error {
 	return structured.DeploymentIsRunning(kc.KubeInterface, name, namespace)
 }
 
+func (kc *ClientSet) StatefulSetIsRunning(name, namespace string) error {
+	return structured.StatefulSetIsRunning(kc.KubeInterface, name, namespace)
+}
+
+func (kc *ClientSet) StatefulSetRolloutComplete(name, namespace string) error {
+	return structured.StatefulSetRolloutComplete(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace)
+}
+
+// WorkloadHasContainerImage waits until the container of the deployment, daemonset or statefulset and of all its pods has the image
+func (kc *ClientSet) WorkloadHasContainerImage(kind, name, namespace, container, image string) error {
+	switch kind {
+	case "deployment":
+		return structured.DeploymentHasContainerImage(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, container, image)
+	case "daemonset":
+		return structured.DaemonSetHasContainerImage(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, container, image)
+	case "statefulset":
+		return structured.StatefulSetHasContainerImage(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, container, image)
+	default:
+		return common.Errorf(common.ErrUnsupportedOperation, "unsupported workload kind: '%s'", kind)
+	}
+}
+
+func (kc *ClientSet) DeploymentShouldRunImageDigest(name, namespace, repository, digest string) error {
+	return structured.DeploymentShouldRunImageDigest(kc.KubeInterface, name, namespace, repository, digest)
+}
+
 func (kc *ClientSet) ConfigMapDataHasKeyAndValue(name, namespace, key, value string) error {
 	return structured.ConfigMapDataHasKeyAndValue(kc.KubeInterface, name, namespace, key, value)
 }
 
+func (kc *ClientSet) ConfigMapOperationFromLiterals(operation, name, namespace, literals string) error {
+	return structured.ConfigMapOperationFromLiterals(kc.KubeInterface, operation, name, namespace, literals)
+}
+
+func (kc *ClientSet) ConfigMapOperationFromFiles(operation, name, namespace, fileNames string) error {
+	return structured.ConfigMapOperationFromFiles(kc.KubeInterface, operation, name, namespace, kc.getResourcePaths(fileNames))
+}
+
+func (kc *ClientSet) ConfigMapDelete(name, namespace string) error {
+	return structured.ConfigMapDelete(kc.KubeInterface, name, namespace)
+}
+
+func (kc *ClientSet) ConfigMapKeyConvergesTo(name, namespace, key, value string) error {
+	return structured.ConfigMapKeyConvergesTo(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, key, value)
+}
+
 func (kc *ClientSet) PersistentVolExists(name, expectedPhase string) error {
 	return structured.PersistentVolExists(kc.KubeInterface, name, expectedPhase)
 }
 
+func (kc *ClientSet) WebhookConfigurationShouldBeReady(webhookType, name string) error {
+	return structured.WebhookConfigurationShouldBeReady(kc.KubeInterface, webhookType, name)
+}
+
+func (kc *ClientSet) StorageClassShouldProvisionVolume(storageClassName, namespace string) error {
+	return structured.StorageClassShouldProvisionVolume(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), storageClassName, namespace)
+}
+
 func (kc *ClientSet) PersistentVolClaimExists(name, expectedPhase string, namespace string) error {
 	return structured.PersistentVolClaimExists(kc.KubeInterface, name, expectedPhase, namespace)
 }
@@ -337,9 +983,399 @@ func (kc *ClientSet) ClusterRbacIsFound(resourceType, name string) error {
 }
 
 func (kc *ClientSet) IngressAvailable(name, namespace string, port int, path string) error {
-	return structured.IngressAvailable(kc.KubeInterface, kc.getWaiterConfig(), name, namespace, port, path)
+	return structured.IngressAvailableCtx(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, port, path)
+}
+
+func (kc *ClientSet) IngressAvailableWithRequest(name, namespace string, port int, path, method, headers, body string, expectedStatusCode int) error {
+	return structured.IngressAvailableWithRequestCtx(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, port, path, method, headers, body, expectedStatusCode)
+}
+
+func (kc *ClientSet) IngressResponseBodyShould(name, namespace string, port int, path, operator, expected string) error {
+	return structured.IngressResponseBodyShould(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, port, path, operator, expected)
+}
+
+func (kc *ClientSet) IngressResponseBodyJSONPathShouldBe(name, namespace string, port int, path, jsonPath, expectedValue string) error {
+	return structured.IngressResponseBodyJSONPathShouldBe(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, port, path, jsonPath, expectedValue)
+}
+
+func (kc *ClientSet) SetHTTPRequestHeader(key, value string) error {
+	if kc.httpRequest.Header == nil {
+		kc.httpRequest.Header = http.Header{}
+	}
+	kc.httpRequest.Header.Add(key, value)
+	return nil
+}
+
+func (kc *ClientSet) SetHTTPRequestBody(body string) error {
+	kc.httpRequest.Body = body
+	return nil
+}
+
+func (kc *ClientSet) SetHTTPRequestTimeout(timeout int, timeoutUnits string) error {
+	d, err := getDuration(timeout, timeoutUnits)
+	if err != nil {
+		return err
+	}
+	kc.httpRequest.Timeout = d
+	return nil
+}
+
+func (kc *ClientSet) SkipHTTPRequestTLSVerification() error {
+	kc.httpRequest.InsecureSkipVerify = true
+	return nil
+}
+
+func (kc *ClientSet) SendHTTPRequestToURL(method, url string) error {
+	return kc.sendHTTPRequest(method, func() (string, func(), error) {
+		return url, func() {}, nil
+	})
+}
+
+func (kc *ClientSet) SendHTTPRequestToIngress(method, name, namespace string, port int, path string) error {
+	return kc.sendHTTPRequest(method, func() (string, func(), error) {
+		endpoint, err := structured.GetIngressEndpointCtx(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, port, path)
+		return endpoint, func() {}, err
+	})
+}
+
+func (kc *ClientSet) SendHTTPRequestToService(method, name, namespace string, port int, path string) error {
+	return kc.sendHTTPRequest(method, func() (string, func(), error) {
+		endpoint, stopChan, err := structured.GetServiceEndpoint(kc.KubeInterface, kc.restConfig, name, namespace, port, path)
+		if err != nil {
+			return "", nil, err
+		}
+		return endpoint, func() { close(stopChan) }, nil
+	})
+}
+
+func (kc *ClientSet) HTTPResponseStatusCodeShouldBe(statusCodes string) error {
+	check, err := structured.NewHTTPStatusCodeCheck(statusCodes)
+	if err != nil {
+		return err
+	}
+	return kc.httpResponseShould(check)
+}
+
+func (kc *ClientSet) HTTPResponseBodyShould(operator, expected string) error {
+	check, err := structured.NewResponseBodyCheck(operator, expected)
+	if err != nil {
+		return err
+	}
+	return kc.httpResponseShould(structured.NewHTTPResponseBodyCheck(check))
+}
+
+func (kc *ClientSet) HTTPResponseBodyJSONPathShouldBe(jsonPath, expectedValue string) error {
+	check, err := structured.NewResponseBodyJSONPathCheck(jsonPath, expectedValue)
+	if err != nil {
+		return err
+	}
+	return kc.httpResponseShould(structured.NewHTTPResponseBodyCheck(check))
 }
 
 func (kc *ClientSet) SendTrafficToIngress(tps int, name, namespace string, port int, path string, duration int, durationUnits string, expectedErrors int) error {
-	return structured.SendTrafficToIngress(kc.KubeInterface, kc.getWaiterConfig(), tps, name, namespace, port, path, duration, durationUnits, expectedErrors)
+	metrics, err := structured.SendTrafficToIngressWithMetrics(kc.KubeInterface, kc.getWaiterConfig(), tps, name, namespace, port, path, duration, durationUnits, expectedErrors)
+	kc.setTrafficMetrics(metrics, fmt.Sprintf("ingress-%s-%s", namespace, name))
+	return err
+}
+
+func (kc *ClientSet) ServiceShouldResolveInCluster(name, namespace string) error {
+	return structured.ServiceShouldResolveInCluster(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace)
+}
+
+func (kc *ClientSet) ServiceHasEndpoints(name, namespace string, minEndpoints int) error {
+	return structured.ServiceHasEndpoints(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, minEndpoints)
+}
+
+func (kc *ClientSet) RunJobShouldExitWithCode(image, command, namespace string, exitCode int) error {
+	return structured.RunJobShouldExitWithCode(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), image, command, namespace, exitCode, kc.getArtifactsPath())
+}
+
+func (kc *ClientSet) JobIsCompleted(name, namespace string) error {
+	return structured.JobIsCompleted(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, kc.getArtifactsPath())
+}
+
+func (kc *ClientSet) JobSucceededWithinDuration(name, namespace string, duration int, durationUnits string) error {
+	within, err := getDuration(duration, durationUnits)
+	if err != nil {
+		return err
+	}
+	return structured.JobSucceededWithinDuration(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, within, kc.getArtifactsPath())
+}
+
+func (kc *ClientSet) CronJobHasLastScheduleTime(name, namespace string) error {
+	return structured.CronJobHasLastScheduleTime(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace)
+}
+
+func (kc *ClientSet) ServiceShouldBeReachable(name, namespace, shouldOrNot string, port int, sourceNamespace string) error {
+	switch shouldOrNot {
+	case "should":
+		return structured.ServiceShouldBeReachable(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, port, sourceNamespace, true)
+	case "should not":
+		return structured.ServiceShouldBeReachable(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, port, sourceNamespace, false)
+	default:
+		return errors.Errorf("parameter shouldOrNot can only be 'should' or 'should not'")
+	}
+}
+
+func (kc *ClientSet) ServiceAvailable(name, namespace string, port int, path string) error {
+	return structured.ServiceAvailable(kc.getContext(), kc.KubeInterface, kc.restConfig, kc.getWaiterConfig(), name, namespace, port, path)
+}
+
+func (kc *ClientSet) ServiceAvailableWithRequest(name, namespace string, port int, path, method, headers, body string, expectedStatusCode int) error {
+	return structured.ServiceAvailableWithRequest(kc.getContext(), kc.KubeInterface, kc.restConfig, kc.getWaiterConfig(), name, namespace, port, path, method, headers, body, expectedStatusCode)
+}
+
+func (kc *ClientSet) SendTrafficToService(tps int, name, namespace string, port int, path string, duration int, durationUnits string, expectedErrors int) error {
+	metrics, err := structured.SendTrafficToService(kc.KubeInterface, kc.restConfig, tps, name, namespace, port, path, duration, durationUnits, expectedErrors)
+	kc.setTrafficMetrics(metrics, fmt.Sprintf("service-%s-%s", namespace, name))
+	return err
+}
+
+func (kc *ClientSet) TrafficLatencyShouldBeLessThan(percentile, maxLatency string) error {
+	return structured.TrafficLatencyShouldBeLessThan(kc.trafficMetrics, percentile, maxLatency)
+}
+
+func (kc *ClientSet) TrafficSuccessRatioShouldBeAtLeast(minRatio string) error {
+	return structured.TrafficSuccessRatioShouldBeAtLeast(kc.trafficMetrics, minRatio)
+}
+
+func (kc *ClientSet) TrafficStatusCodePercentageShouldBeAtLeast(minPercentage int, statusCode string) error {
+	return structured.TrafficStatusCodePercentageShouldBeAtLeast(kc.trafficMetrics, minPercentage, statusCode)
+}
+
+func (kc *ClientSet) TrafficThroughputShouldBeAtLeast(minThroughput string) error {
+	return structured.TrafficThroughputShouldBeAtLeast(kc.trafficMetrics, minThroughput)
+}
+
+// TrafficMetrics returns the metrics of the last traffic test, nil when no traffic was sent
+func (kc *ClientSet) TrafficMetrics() *vegeta.Metrics {
+	return kc.trafficMetrics
+}
+
+func (kc *ClientSet) AddIngressTrafficTarget(name, namespace string, port int, path, targetName string, tps int) error {
+	return kc.AddIngressTrafficTargetWithHeaders(name, namespace, port, path, targetName, tps, "")
+}
+
+func (kc *ClientSet) AddIngressTrafficTargetWithHeaders(name, namespace string, port int, path, targetName string, tps int, headers string) error {
+	target, err := structured.NewIngressTrafficTarget(kc.KubeInterface, kc.getWaiterConfig(), targetName, tps, name, namespace, port, path, headers)
+	if err != nil {
+		return err
+	}
+	kc.trafficTargets = append(kc.trafficTargets, target)
+	return nil
+}
+
+// AddTrafficTarget adds the ingress or service as a traffic target sent requests with the method to the weighted paths,
+// the header, body, timeout and TLS settings of the HTTP request steps are used and cleared
+func (kc *ClientSet) AddTrafficTarget(kind, name, namespace string, port int, paths, targetName string, tps int, method, scheme string) error {
+	return kc.addTrafficTarget(kind, name, namespace, port, paths, targetName, tps, method, scheme)
+}
+
+// SendTrafficToTargets consumes the added traffic targets, they need to be added again for another traffic test
+func (kc *ClientSet) SendTrafficToTargets(duration int, durationUnits string, expectedErrors int) error {
+	targets := kc.trafficTargets
+	kc.trafficTargets = nil
+	defer kc.stopTrafficForwards()
+	metrics, err := structured.SendTrafficToEndpoints(targets, duration, durationUnits, expectedErrors)
+	kc.targetMetrics = metrics
+	for targetName, targetMetrics := range metrics {
+		kc.setTrafficMetrics(targetMetrics, fmt.Sprintf("target-%s", targetName))
+	}
+	return err
+}
+
+func (kc *ClientSet) TrafficTargetLatencyShouldBeLessThan(percentile, targetName, maxLatency string) error {
+	return structured.TrafficLatencyShouldBeLessThan(kc.targetMetrics[targetName], percentile, maxLatency)
+}
+
+func (kc *ClientSet) TrafficTargetSuccessRatioShouldBeAtLeast(targetName, minRatio string) error {
+	return structured.TrafficSuccessRatioShouldBeAtLeast(kc.targetMetrics[targetName], minRatio)
+}
+
+func (kc *ClientSet) TrafficTargetStatusCodePercentageShouldBeAtLeast(minPercentage int, targetName, statusCode string) error {
+	return structured.TrafficStatusCodePercentageShouldBeAtLeast(kc.targetMetrics[targetName], minPercentage, statusCode)
+}
+
+func (kc *ClientSet) TrafficTargetThroughputShouldBeAtLeast(targetName, minThroughput string) error {
+	return structured.TrafficThroughputShouldBeAtLeast(kc.targetMetrics[targetName], minThroughput)
+}
+
+// TrafficTargetMetrics returns the metrics of the traffic target in the last traffic test sent to the targets
+func (kc *ClientSet) TrafficTargetMetrics(targetName string) *vegeta.Metrics {
+	return kc.targetMetrics[targetName]
+}
+
+func (kc *ClientSet) GatewayShouldBeProgrammed(name, namespace string) error {
+	return gateway.GatewayShouldBeProgrammed(kc.getContext(), kc.DynamicInterface, kc.getWaiterConfig(), name, namespace)
+}
+
+func (kc *ClientSet) HTTPRouteShouldBeAccepted(name, namespace string) error {
+	return gateway.HTTPRouteShouldBeAccepted(kc.getContext(), kc.DynamicInterface, kc.getWaiterConfig(), name, namespace)
+}
+
+func (kc *ClientSet) HTTPRouteAvailable(name, namespace string, port int, path string) error {
+	return gateway.HTTPRouteAvailable(kc.getContext(), kc.DynamicInterface, kc.getWaiterConfig(), name, namespace, port, path)
+}
+
+func (kc *ClientSet) SendTrafficToHTTPRoute(tps int, name, namespace string, port int, path string, duration int, durationUnits string, expectedErrors int) error {
+	metrics, err := gateway.SendTrafficToHTTPRoute(kc.getContext(), kc.DynamicInterface, kc.getWaiterConfig(), tps, name, namespace, port, path, duration, durationUnits, expectedErrors)
+	kc.setTrafficMetrics(metrics, fmt.Sprintf("httproute-%s-%s", namespace, name))
+	return err
+}
+
+func (kc *ClientSet) RolloutShouldBe(name, namespace, phase string) error {
+	return rollout.RolloutShouldBe(kc.getContext(), kc.DynamicInterface, kc.getWaiterConfig(), name, namespace, phase)
+}
+
+func (kc *ClientSet) RolloutOperation(operation, name, namespace string) error {
+	if err := rollout.RolloutOperation(kc.DynamicInterface, operation, name, namespace); err != nil {
+		return err
+	}
+	if operation == rollout.OperationPause {
+		return kc.storePausedReplicaSets("rollout", name, namespace)
+	}
+	return nil
+}
+
+func (kc *ClientSet) DeploymentPauseOperation(operation, name, namespace string) error {
+	switch operation {
+	case "pause":
+		if err := structured.PauseDeployment(kc.KubeInterface, name, namespace, true); err != nil {
+			return err
+		}
+		return kc.storePausedReplicaSets("deployment", name, namespace)
+	case "resume":
+		return structured.PauseDeployment(kc.KubeInterface, name, namespace, false)
+	default:
+		return errors.Errorf("parameter operation can only be 'pause' or 'resume'")
+	}
+}
+
+func (kc *ClientSet) ReplicaSetsShouldNotBeCreatedWhilePaused(kind, name, namespace string) error {
+	existing, ok := kc.pausedReplicaSets[pausedReplicaSetsKey(kind, name, namespace)]
+	if !ok {
+		return errors.Errorf("%s '%s/%s' was not paused in this scenario", kind, namespace, name)
+	}
+	return structured.ReplicaSetsShouldNotBeCreated(kc.KubeInterface, kind, name, namespace, existing)
+}
+
+func (kc *ClientSet) ApplicationShouldBe(name, namespace, status string) error {
+	return argocd.ApplicationShouldBe(kc.getContext(), kc.DynamicInterface, kc.getWaiterConfig(), name, namespace, status)
+}
+
+func (kc *ClientSet) SyncApplication(name, namespace string) error {
+	return argocd.SyncApplication(kc.DynamicInterface, name, namespace)
+}
+
+func (kc *ClientSet) HelmReleaseShouldBeDeployed(name, namespace string) error {
+	return helm.ReleaseShouldBeDeployed(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace)
+}
+
+func (kc *ClientSet) HelmReleaseShouldBeDeployedWithChartVersion(name, namespace, chartVersion string) error {
+	return helm.ReleaseShouldBeDeployedWithChartVersion(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, chartVersion)
+}
+
+func (kc *ClientSet) HelmReleaseShouldBeDeployedWithRevision(name, namespace string, revision int) error {
+	return helm.ReleaseShouldBeDeployedWithRevision(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace, revision)
+}
+
+func (kc *ClientSet) CertificateShouldBeReady(name, namespace string) error {
+	return certmanager.CertificateShouldBeReady(kc.getContext(), kc.DynamicInterface, kc.getWaiterConfig(), name, namespace)
+}
+
+func (kc *ClientSet) CertificateSecretShouldCoverDNSNames(name, namespace, dnsNames string) error {
+	return certmanager.CertificateSecretShouldCoverDNSNames(kc.DynamicInterface, kc.KubeInterface, name, namespace, dnsNames)
+}
+
+func (kc *ClientSet) CertificateSecretShouldBeValidFor(name, namespace, minValidity string) error {
+	return certmanager.CertificateSecretShouldBeValidFor(kc.DynamicInterface, kc.KubeInterface, name, namespace, minValidity)
+}
+
+func (kc *ClientSet) VirtualServiceAvailable(name, namespace, gatewayService, gatewayNamespace string, port int, path string) error {
+	return istio.VirtualServiceAvailable(kc.getContext(), kc.DynamicInterface, kc.KubeInterface, kc.getWaiterConfig(), name, namespace, gatewayService, gatewayNamespace, port, path)
+}
+
+func (kc *ClientSet) SendTrafficToVirtualService(tps int, name, namespace, gatewayService, gatewayNamespace string, port int, path string, duration int, durationUnits string, expectedErrors int) error {
+	metrics, err := istio.SendTrafficToVirtualService(kc.getContext(), kc.DynamicInterface, kc.KubeInterface, kc.getWaiterConfig(), tps, name, namespace, gatewayService, gatewayNamespace, port, path, duration, durationUnits, expectedErrors)
+	kc.setTrafficMetrics(metrics, fmt.Sprintf("virtualservice-%s-%s", namespace, name))
+	return err
+}
+
+func (kc *ClientSet) CreateRollingUpgrade(name, namespace, asgName string) error {
+	return upgrademanager.CreateRollingUpgrade(kc.DynamicInterface, name, namespace, asgName)
+}
+
+func (kc *ClientSet) RollingUpgradeShouldBe(name, namespace, status string) error {
+	return upgrademanager.RollingUpgradeShouldBe(kc.getContext(), kc.DynamicInterface, kc.getWaiterConfig(), name, namespace, status)
+}
+
+func (kc *ClientSet) PrometheusQueryShouldReturnValue(query, operator string, value float64, within string) error {
+	return prometheus.QueryShouldReturnValue(kc.getContext(), kc.KubeInterface, kc.restConfig, kc.getWaiterConfig(), kc.config.prometheusURL, query, operator, value, within)
+}
+
+func (kc *ClientSet) AlertFiringSinceTime(alertName, shouldOrNot, sinceTime string) error {
+	timestamp, err := kc.GetTimestamp(sinceTime)
+	if err != nil {
+		return err
+	}
+	switch shouldOrNot {
+	case "should":
+		return prometheus.AlertFiringShouldBe(kc.getContext(), kc.KubeInterface, kc.restConfig, kc.getWaiterConfig(), kc.config.alertmanagerURL, alertName, true, timestamp)
+	case "should not":
+		return prometheus.AlertFiringShouldBe(kc.getContext(), kc.KubeInterface, kc.restConfig, kc.getWaiterConfig(), kc.config.alertmanagerURL, alertName, false, timestamp)
+	default:
+		return errors.Errorf("parameter shouldOrNot can only be 'should' or 'should not'")
+	}
+}
+
+func (kc *ClientSet) PolicyReportsShouldHaveNoViolations(namespace string) error {
+	return policy.PolicyReportsShouldHaveNoViolations(kc.DynamicInterface, namespace)
+}
+
+func (kc *ClientSet) ConstraintShouldHaveNoViolations(kind, name, namespace string) error {
+	return policy.ConstraintShouldHaveNoViolations(kc.DynamicInterface, kind, name, namespace)
+}
+
+func (kc *ClientSet) FluxResourceShouldBeReady(kind, name, namespace, revision string) error {
+	return flux.ResourceShouldBeReady(kc.getContext(), kc.DynamicInterface, kc.getWaiterConfig(), kind, name, namespace, revision)
+}
+
+func (kc *ClientSet) FluxReconcile(kind, name, namespace string) error {
+	return flux.Reconcile(kc.DynamicInterface, kind, name, namespace)
+}
+
+func (kc *ClientSet) ScaledObjectShouldBeReady(name, namespace string) error {
+	return keda.ScaledObjectShouldBeReady(kc.getContext(), kc.DynamicInterface, kc.getWaiterConfig(), name, namespace)
+}
+
+func (kc *ClientSet) ScaledObjectTargetShouldScale(name, namespace, direction string) error {
+	return keda.ScaledObjectTargetShouldScale(kc.getContext(), kc.DynamicInterface, kc.KubeInterface, kc.getWaiterConfig(), name, namespace, direction)
+}
+
+func (kc *ClientSet) VerticalPodAutoscalerShouldProvideRecommendation(name, namespace, containerName string) error {
+	return vpa.RecommendationShouldBeProvided(kc.getContext(), kc.DynamicInterface, kc.getWaiterConfig(), name, namespace, containerName)
+}
+
+func (kc *ClientSet) VerticalPodAutoscalerRecommendationShouldBeBetween(resourceName, containerName, name, namespace, min, max string) error {
+	return vpa.RecommendationShouldBeBetween(kc.getContext(), kc.DynamicInterface, kc.getWaiterConfig(), resourceName, containerName, name, namespace, min, max)
+}
+
+func (kc *ClientSet) CustomMetricShouldBeBetween(metricName, resourceType, name, namespace, min, max string) error {
+	return metrics.CustomMetricShouldBeBetween(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), metricName, resourceType, name, namespace, min, max)
+}
+
+func (kc *ClientSet) ExternalMetricShouldBeBetween(metricName, namespace, min, max string) error {
+	return metrics.ExternalMetricShouldBeBetween(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), metricName, namespace, min, max)
+}
+
+// ServiceMetricShouldBe validates the metric the service exposes on /metrics, on the port named metrics when port is empty
+func (kc *ClientSet) ServiceMetricShouldBe(metricName, selector, serviceName, namespace, port, comparison string, value float64) error {
+	var servicePort int
+	if port != "" {
+		var err error
+		if servicePort, err = strconv.Atoi(port); err != nil {
+			return errors.Wrapf(err, "failed to parse port '%v'", port)
+		}
+	}
+	return metrics.ServiceMetricShouldBe(kc.getContext(), kc.KubeInterface, kc.restConfig, kc.getWaiterConfig(), metricName, selector, serviceName, namespace, servicePort, comparison, value)
 }
@@ -15,22 +15,105 @@ limitations under the License.
 package kube
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/keikoproj/kubedog/internal/util"
 	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/events"
+	"github.com/keikoproj/kubedog/pkg/kube/structured"
+	unstruct "github.com/keikoproj/kubedog/pkg/kube/unstructured"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
+// restartCountsSnapshot is the restart counts of the containers of the pods with a selector at a point in time
+type restartCountsSnapshot struct {
+	namespace     string
+	selector      string
+	restartCounts map[string]int32
+}
+
 type configuration struct {
-	filesPath         string
-	templateArguments interface{}
-	waiterInterval    time.Duration
-	waiterTries       int
+	filesPath                string
+	artifactsPath            string
+	prometheusURL            string
+	alertmanagerURL          string
+	templateArguments        interface{}
+	ephemeralNamespacePrefix string
+	ignoredFields            []string
+	validateSchema           bool
+	auditMutations           bool
+	waiterInterval           time.Duration
+	waiterTries              int
+}
+
+// ephemeralNamespace is the namespace created for the current scenario and the template arguments with it as the Namespace
+type ephemeralNamespace struct {
+	name              string
+	templateArguments map[string]interface{}
+}
+
+const (
+	ephemeralNamespaceLabel = "kubedog.keikoproj.io/ephemeral"
+	// the random suffix and its separator take 6 of the 63 characters of a namespace name
+	ephemeralNamespacePrefixMaxLength = 57
+)
+
+func getEphemeralNamespaceName(prefix string) string {
+	prefix = strings.TrimSuffix(strings.ToLower(prefix), "-")
+	if len(prefix) > ephemeralNamespacePrefixMaxLength {
+		prefix = prefix[:ephemeralNamespacePrefixMaxLength]
+	}
+	return fmt.Sprintf("%s-%s", prefix, utilrand.String(5))
+}
+
+// withNamespaceTemplateArgument returns a copy of the template arguments with the namespace as Namespace, they must be a map
+func withNamespaceTemplateArgument(templateArguments interface{}, namespace string) (map[string]interface{}, error) {
+	arguments := map[string]interface{}{}
+	switch values := templateArguments.(type) {
+	case nil:
+	case map[string]interface{}:
+		for key, value := range values {
+			arguments[key] = value
+		}
+	case map[string]string:
+		for key, value := range values {
+			arguments[key] = value
+		}
+	default:
+		return nil, errors.Errorf("template arguments of type %T can't take the ephemeral namespace, use a map", templateArguments)
+	}
+	arguments["Namespace"] = namespace
+	return arguments, nil
+}
+
+// getTemplateArguments returns the template arguments with the ephemeral namespace of the scenario when there is one
+func (kc *ClientSet) getTemplateArguments() interface{} {
+	if kc.namespace.name != "" {
+		return kc.namespace.templateArguments
+	}
+	return kc.config.templateArguments
+}
+
+// getIgnoredFields returns the fields ignored when comparing resources to expected files, with the comma separated fields
+func (kc *ClientSet) getIgnoredFields(fields string) []string {
+	ignoredFields := unstruct.DefaultIgnoredFields
+	if kc.config.ignoredFields != nil {
+		ignoredFields = kc.config.ignoredFields
+	}
+	return append(append([]string{}, ignoredFields...), util.DeleteEmpty(strings.Split(fields, ","))...)
 }
 
 func (kc *ClientSet) GetTimestamp(timestampName string) (time.Time, error) {
@@ -45,6 +128,48 @@ func (kc *ClientSet) GetTimestamp(timestampName string) (time.Time, error) {
 	return timestamp, nil
 }
 
+func (kc *ClientSet) GetServiceLoadBalancerHostname(name, namespace string) (string, error) {
+	return structured.GetServiceLoadBalancerHostname(kc.getContext(), kc.KubeInterface, kc.getWaiterConfig(), name, namespace)
+}
+
+func (kc *ClientSet) getResource(resourceFileName string) (unstruct.UnstructuredResource, error) {
+	resourcePath := kc.getResourcePath(resourceFileName)
+	resource, err := unstruct.GetResourceCtx(kc.getContext(), kc.getDiscoveryClient(), kc.getTemplateArguments(), resourcePath)
+	if err != nil {
+		return resource, err
+	}
+	return resource, kc.validateResources(resourcePath, resource)
+}
+
+func (kc *ClientSet) getResources(resourcesFileName string) ([]unstruct.UnstructuredResource, error) {
+	resourcesPath := kc.getResourcePath(resourcesFileName)
+	resources, err := unstruct.GetResourcesCtx(kc.getContext(), kc.getDiscoveryClient(), kc.getTemplateArguments(), resourcesPath)
+	if err != nil {
+		return nil, err
+	}
+	return resources, kc.validateResources(resourcesPath, resources...)
+}
+
+func (kc *ClientSet) getKustomizeResources(kustomizationDir string) ([]unstruct.UnstructuredResource, error) {
+	kustomizationPath := kc.getResourcePath(kustomizationDir)
+	resources, err := unstruct.GetKustomizeResources(kc.getContext(), kc.getDiscoveryClient(), kc.getTemplateArguments(), kustomizationPath)
+	if err != nil {
+		return nil, err
+	}
+	return resources, kc.validateResources(kustomizationPath, resources...)
+}
+
+func (kc *ClientSet) validateResources(resourcesPath string, resources ...unstruct.UnstructuredResource) error {
+	if !kc.config.validateSchema {
+		return nil
+	}
+	dc := kc.getDiscoveryClient()
+	if dc == nil {
+		return common.Errorf(common.ErrClientNotInitialized, "'k8s.io/client-go/discovery.DiscoveryInterface' is nil.")
+	}
+	return unstruct.ValidateResources(dc.OpenAPIV3(), resourcesPath, resources...)
+}
+
 func (kc *ClientSet) getResourcePath(resourceFileName string) string {
 	templatesPath := kc.getTemplatesPath()
 	return filepath.Join(templatesPath, resourceFileName)
@@ -58,6 +183,160 @@ func (kc *ClientSet) getTemplatesPath() string {
 	return defaultFilePath
 }
 
+func (kc *ClientSet) getArtifactsPath() string {
+	defaultArtifactsPath := "artifacts"
+	if kc.config.artifactsPath != "" {
+		return kc.config.artifactsPath
+	}
+	return defaultArtifactsPath
+}
+
+// setTrafficMetrics stores the metrics of the last traffic test and exports them as json and html artifacts
+func (kc *ClientSet) setTrafficMetrics(metrics *vegeta.Metrics, target string) {
+	kc.trafficMetrics = metrics
+	if metrics == nil {
+		return
+	}
+	for _, export := range []func(*vegeta.Metrics, string, string) (string, error){structured.ExportTrafficMetrics, structured.ExportTrafficReport} {
+		filePath, err := export(metrics, kc.getArtifactsPath(), target)
+		if err != nil {
+			log.Warnf("failed to export traffic metrics: %v", err)
+			continue
+		}
+		log.Infof("exported traffic metrics to '%s'", filePath)
+	}
+}
+
+// ExportMutationAuditLog writes the mutations recorded since the last export to the artifacts path, named after the scenario
+func (kc *ClientSet) ExportMutationAuditLog(name string) {
+	if kc.mutations == nil {
+		return
+	}
+	filePath, err := kc.mutations.Export(kc.getArtifactsPath(), name)
+	if err != nil {
+		log.Warnf("failed to export mutation audit log: %v", err)
+		return
+	}
+	if filePath != "" {
+		log.Infof("exported mutation audit log to '%s'", filePath)
+	}
+}
+
+// ExportEvents writes the events seen since the time to the artifacts path, named after the scenario, to debug a failed scenario
+func (kc *ClientSet) ExportEvents(name string, since time.Time) {
+	if kc.KubeInterface == nil {
+		return
+	}
+	filePath, err := events.ExportEvents(kc.KubeInterface, kc.getArtifactsPath(), name, since)
+	if err != nil {
+		log.Warnf("failed to export events: %v", err)
+		return
+	}
+	if filePath != "" {
+		log.Infof("exported events to '%s'", filePath)
+	}
+}
+
+func (kc *ClientSet) addTrafficTarget(kind, name, namespace string, port int, paths, targetName string, tps int, method, scheme string) error {
+	weightedPaths, err := structured.ParseWeightedPaths(paths)
+	if err != nil {
+		return err
+	}
+	request := kc.httpRequest
+	kc.httpRequest = structured.HTTPRequest{}
+
+	var target structured.TrafficTarget
+	switch kind {
+	case "ingress":
+		target, err = structured.NewIngressTrafficTarget(kc.KubeInterface, kc.getWaiterConfig(), targetName, tps, name, namespace, port, "", "")
+	case "service":
+		var stopChan chan struct{}
+		target, stopChan, err = structured.NewServiceTrafficTarget(kc.KubeInterface, kc.restConfig, targetName, tps, name, namespace, port, "", "")
+		if err == nil {
+			kc.trafficForwards = append(kc.trafficForwards, stopChan)
+		}
+	default:
+		return common.Errorf(common.ErrUnsupportedOperation, "unsupported traffic target kind: '%s'", kind)
+	}
+	if err != nil {
+		return err
+	}
+	if scheme == "https" {
+		target.Endpoint = "https" + strings.TrimPrefix(target.Endpoint, "http")
+	}
+	target.Header = request.Header
+	target.Timeout = request.Timeout
+	target.InsecureSkipVerify = request.InsecureSkipVerify
+	target.Targeter = structured.TrafficRequests{Method: method, Body: []byte(request.Body), Paths: weightedPaths}
+	kc.trafficTargets = append(kc.trafficTargets, target)
+	return nil
+}
+
+// stopTrafficForwards stops the port-forwards of the service traffic targets
+func (kc *ClientSet) stopTrafficForwards() {
+	for _, stopChan := range kc.trafficForwards {
+		close(stopChan)
+	}
+	kc.trafficForwards = nil
+}
+
+// httpExchange is the last request sent by the HTTP steps and its response, the endpoint is resolved for every attempt
+// so port-forwards to services are not kept open between steps
+type httpExchange struct {
+	endpoint func() (string, func(), error)
+	request  structured.HTTPRequest
+	response *structured.HTTPResponse
+}
+
+// sendHTTPRequest consumes the configured HTTP request, they need to be configured again for another request
+func (kc *ClientSet) sendHTTPRequest(method string, endpoint func() (string, func(), error)) error {
+	request := kc.httpRequest
+	request.Method = method
+	kc.httpRequest = structured.HTTPRequest{}
+	kc.httpExchange = &httpExchange{endpoint: endpoint, request: request}
+
+	url, done, err := endpoint()
+	if err != nil {
+		return err
+	}
+	defer done()
+	kc.httpExchange.response, err = structured.SendHTTPRequestCtx(kc.getContext(), kc.getWaiterConfig(), url, request)
+	return err
+}
+
+// httpResponseShould validates the last HTTP response, sending the request again until a response passes the check
+func (kc *ClientSet) httpResponseShould(check structured.HTTPResponseCheck) error {
+	exchange := kc.httpExchange
+	if exchange == nil || exchange.response == nil {
+		return errors.New("no HTTP response was received, send an HTTP request first")
+	}
+	if err := check.Validate(exchange.response); err == nil {
+		return nil
+	}
+
+	url, done, err := exchange.endpoint()
+	if err != nil {
+		return err
+	}
+	defer done()
+	response, err := structured.EndpointResponseShouldCtx(kc.getContext(), kc.getWaiterConfig(), url, exchange.request, check)
+	if response != nil {
+		exchange.response = response
+	}
+	return err
+}
+
+func getDuration(duration int, durationUnits string) (time.Duration, error) {
+	switch durationUnits {
+	case util.DurationMinutes:
+		return time.Duration(duration) * time.Minute, nil
+	case util.DurationSeconds:
+		return time.Duration(duration) * time.Second, nil
+	default:
+		return 0, common.Errorf(common.ErrUnsupportedOperation, "unsupported duration units: '%s'", durationUnits)
+	}
+}
+
 func (kc *ClientSet) getWaiterInterval() time.Duration {
 	defaultWaiterInterval := time.Second * 30
 	if kc.config.waiterInterval > 0 {
@@ -74,6 +353,14 @@ func (kc *ClientSet) getWaiterTries() int {
 	return defaultWaiterTries
 }
 
+func (kc *ClientSet) getResourcePaths(fileNames string) []string {
+	var paths []string
+	for _, fileName := range util.DeleteEmpty(strings.Split(fileNames, ",")) {
+		paths = append(paths, kc.getResourcePath(fileName))
+	}
+	return paths
+}
+
 func (kc *ClientSet) getWaiterConfig() common.WaiterConfig {
 	return common.NewWaiterConfig(kc.getWaiterTries(), kc.getWaiterInterval())
 }
@@ -82,9 +369,88 @@ func (kc *ClientSet) getExpBackoff() wait.Backoff {
 	return util.GetExpBackoff(kc.getWaiterTries())
 }
 
+func (kc *ClientSet) getContext() context.Context {
+	if kc.ctx == nil {
+		return context.Background()
+	}
+	return kc.ctx
+}
+
+// getDiscoveryClient returns the discovery of KubeInterface cached in memory, so the REST mappings of the resources are
+// discovered once per client instead of for every resource
 func (kc *ClientSet) getDiscoveryClient() discovery.DiscoveryInterface {
-	if kc.KubeInterface != nil {
-		return kc.KubeInterface.Discovery()
+	if kc.KubeInterface == nil {
+		return nil
+	}
+	if kc.discoveryCache == nil || kc.discoveryOf != kc.KubeInterface {
+		kc.discoveryCache = memory.NewMemCacheClient(kc.KubeInterface.Discovery())
+		kc.discoveryOf = kc.KubeInterface
+	}
+	return kc.discoveryCache
+}
+
+func (kc *ClientSet) storePausedReplicaSets(kind, name, namespace string) error {
+	replicaSets, err := structured.GetOwnedReplicaSets(kc.KubeInterface, kind, name, namespace)
+	if err != nil {
+		return err
 	}
+	if kc.pausedReplicaSets == nil {
+		kc.pausedReplicaSets = map[string][]string{}
+	}
+	kc.pausedReplicaSets[pausedReplicaSetsKey(kind, name, namespace)] = replicaSets
 	return nil
 }
+
+func pausedReplicaSetsKey(kind, name, namespace string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+func getKubeconfigPath() (string, error) {
+	var (
+		home, _        = os.UserHomeDir()
+		kubeconfigPath = filepath.Join(home, ".kube", "config")
+	)
+
+	if exported := os.Getenv("KUBECONFIG"); exported != "" {
+		kubeconfigPath = exported
+	}
+	if _, err := os.Stat(kubeconfigPath); os.IsNotExist(err) {
+		return "", errors.Errorf("expected kubeconfig to exist for create operation, '%v'", kubeconfigPath)
+	}
+	return kubeconfigPath, nil
+}
+
+// getClusterClients returns a dynamic client for each of the comma separated contexts of the kubeconfig, clients are kept for later steps
+func (kc *ClientSet) getClusterClients(contexts string) (map[string]dynamic.Interface, error) {
+	clusters := map[string]dynamic.Interface{}
+	for _, name := range util.DeleteEmpty(strings.Split(contexts, ",")) {
+		if dynamicClient, ok := kc.clusterClients[name]; ok {
+			clusters[name] = dynamicClient
+			continue
+		}
+		kubeconfigPath, err := getKubeconfigPath()
+		if err != nil {
+			return nil, err
+		}
+		config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath},
+			&clientcmd.ConfigOverrides{CurrentContext: name},
+		).ClientConfig()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load the kubeconfig context %v", name)
+		}
+		dynamicClient, err := dynamic.NewForConfig(config)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create a client for the kubeconfig context %v", name)
+		}
+		if kc.clusterClients == nil {
+			kc.clusterClients = map[string]dynamic.Interface{}
+		}
+		kc.clusterClients[name] = dynamicClient
+		clusters[name] = dynamicClient
+	}
+	if len(clusters) == 0 {
+		return nil, errors.Errorf("expected at least one kubeconfig context, got '%v'", contexts)
+	}
+	return clusters, nil
+}
@@ -0,0 +1,129 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/structured"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// CustomMetricShouldBeBetween waits for every value of the custom metric of the described objects to be within [min, max],
+// name can be '*' to select every object of the resource type in the namespace.
+func CustomMetricShouldBeBetween(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, metricName, resourceType, name, namespace, min, max string) error {
+	path := customMetricPath(resourceType, name, namespace, metricName)
+	return metricShouldBeBetween(ctx, kubeClientset, w, path, metricName, min, max)
+}
+
+// ExternalMetricShouldBeBetween waits for every value of the external metric to be within [min, max].
+func ExternalMetricShouldBeBetween(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, metricName, namespace, min, max string) error {
+	path := externalMetricPath(namespace, metricName)
+	return metricShouldBeBetween(ctx, kubeClientset, w, path, metricName, min, max)
+}
+
+func metricShouldBeBetween(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, path, metricName, min, max string) error {
+	var counter int
+
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+	minQuantity, err := resource.ParseQuantity(min)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse minimum value '%v'", min)
+	}
+	maxQuantity, err := resource.ParseQuantity(max)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse maximum value '%v'", max)
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for metric %v to be between %v and %v", metricName, min, max)
+		}
+		values, err := getMetricValues(kubeClientset, path)
+		if err != nil {
+			return err
+		}
+		if valuesBetween(values, minQuantity, maxQuantity) {
+			log.Infof("metric %v values %v are between %v and %v", metricName, formatQuantities(values), min, max)
+			return nil
+		}
+		log.Infof("metric %v values %v are not between %v and %v", metricName, formatQuantities(values), min, max)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// ServiceMetricShouldBe port-forwards to the service and waits for every sample of the metric it exposes on /metrics
+// with labels matching the selector to be greater or less than the value, port 0 selects the metrics port of the service.
+func ServiceMetricShouldBe(ctx context.Context, kubeClientset kubernetes.Interface, restConfig *rest.Config, w common.WaiterConfig, metricName, selector, serviceName, namespace string, port int, comparison string, value float64) error {
+	if port == 0 {
+		var err error
+		if port, err = getServiceMetricsPort(kubeClientset, serviceName, namespace); err != nil {
+			return err
+		}
+	}
+	endpoint, stopChan, err := structured.GetServiceEndpoint(kubeClientset, restConfig, serviceName, namespace, port, metricsPath)
+	if err != nil {
+		return err
+	}
+	defer close(stopChan)
+	return EndpointMetricShouldBe(ctx, endpoint, w, metricName, selector, comparison, value)
+}
+
+// EndpointMetricShouldBe waits for every sample of the metric scraped from the endpoint with labels matching the selector,
+// such as 'code=200,method!=GET', to be greater or less than the value.
+func EndpointMetricShouldBe(ctx context.Context, endpoint string, w common.WaiterConfig, metricName, selector, comparison string, value float64) error {
+	var counter int
+
+	matchers, err := parseLabelMatchers(selector)
+	if err != nil {
+		return err
+	}
+	if _, err := compareSample(0, comparison, value); err != nil {
+		return err
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for metric %v{%v} of %v to be %v than %v", metricName, selector, endpoint, comparison, value)
+		}
+		samples, err := scrapeMetric(endpoint, metricName, matchers)
+		if err != nil {
+			return err
+		}
+		ok, err := samplesCompare(samples, comparison, value)
+		if err != nil {
+			return err
+		}
+		if ok {
+			log.Infof("metric %v{%v} values %v are %v than %v", metricName, selector, samples, comparison, value)
+			return nil
+		}
+		log.Infof("metric %v{%v} values %v are not %v than %v", metricName, selector, samples, comparison, value)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,275 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/structured"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	customMetricsAPIPath   = "/apis/custom.metrics.k8s.io/v1beta1"
+	externalMetricsAPIPath = "/apis/external.metrics.k8s.io/v1beta1"
+
+	metricsPath     = "/metrics"
+	metricsPortName = "metrics"
+
+	ComparisonGreater = "greater"
+	ComparisonLess    = "less"
+)
+
+// metricValueList holds the fields shared by the MetricValueList and ExternalMetricValueList of the metrics APIs
+type metricValueList struct {
+	Items []struct {
+		MetricName string            `json:"metricName"`
+		Value      resource.Quantity `json:"value"`
+	} `json:"items"`
+}
+
+func customMetricPath(resourceType, name, namespace, metricName string) string {
+	return fmt.Sprintf("%v/namespaces/%v/%v/%v/%v", customMetricsAPIPath, namespace, resourceType, name, metricName)
+}
+
+func externalMetricPath(namespace, metricName string) string {
+	return fmt.Sprintf("%v/namespaces/%v/%v", externalMetricsAPIPath, namespace, metricName)
+}
+
+func getMetricValues(kubeClientset kubernetes.Interface, path string) ([]resource.Quantity, error) {
+	body, err := kubeClientset.Discovery().RESTClient().Get().AbsPath(path).DoRaw(context.Background())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get metric from %v", path)
+	}
+	list := metricValueList{}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode metric from %v", path)
+	}
+	values := make([]resource.Quantity, 0, len(list.Items))
+	for _, item := range list.Items {
+		values = append(values, item.Value)
+	}
+	return values, nil
+}
+
+// valuesBetween is true when there is at least one value and every value is within [min, max]
+func valuesBetween(values []resource.Quantity, min, max resource.Quantity) bool {
+	if len(values) == 0 {
+		return false
+	}
+	for _, value := range values {
+		if value.Cmp(min) < 0 || value.Cmp(max) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func formatQuantities(values []resource.Quantity) string {
+	formatted := make([]string, 0, len(values))
+	for i := range values {
+		formatted = append(formatted, values[i].String())
+	}
+	return fmt.Sprintf("[%v]", strings.Join(formatted, ", "))
+}
+
+// labelMatcher matches the samples with the label equal to, or not equal to, the value
+type labelMatcher struct {
+	name     string
+	value    string
+	notEqual bool
+}
+
+func (m labelMatcher) matches(labels map[string]string) bool {
+	return (labels[m.name] == m.value) != m.notEqual
+}
+
+// parseLabelMatchers parses comma separated label matchers such as 'code=200,method!=GET', an empty selector matches every sample
+func parseLabelMatchers(selector string) ([]labelMatcher, error) {
+	var matchers []labelMatcher
+	if selector == "" {
+		return matchers, nil
+	}
+	for _, term := range strings.Split(selector, ",") {
+		matcher := labelMatcher{}
+		name, value, found := strings.Cut(term, "!=")
+		if found {
+			matcher.notEqual = true
+		} else if name, value, found = strings.Cut(term, "="); !found {
+			return nil, errors.Errorf("invalid label selector '%v', expected comma separated name=value or name!=value terms", selector)
+		}
+		matcher.name, matcher.value = strings.TrimSpace(name), strings.Trim(strings.TrimSpace(value), `"`)
+		if matcher.name == "" {
+			return nil, errors.Errorf("invalid label selector '%v', a term has no label name", selector)
+		}
+		matchers = append(matchers, matcher)
+	}
+	return matchers, nil
+}
+
+// getServiceMetricsPort returns the port of the service named metrics, or ending in metrics, or its only port
+func getServiceMetricsPort(kubeClientset kubernetes.Interface, name, namespace string) (int, error) {
+	service, err := structured.GetService(kubeClientset, name, namespace)
+	if err != nil {
+		return 0, err
+	}
+	for _, port := range service.Spec.Ports {
+		if strings.HasSuffix(port.Name, metricsPortName) {
+			return int(port.Port), nil
+		}
+	}
+	if len(service.Spec.Ports) == 1 {
+		return int(service.Spec.Ports[0].Port), nil
+	}
+	return 0, errors.Errorf("service %v/%v has %d ports and none is named %v, the port must be given", namespace, name, len(service.Spec.Ports), metricsPortName)
+}
+
+func scrapeMetric(endpoint, metricName string, matchers []labelMatcher) ([]float64, error) {
+	client := http.Client{
+		Timeout: 10 * time.Second,
+	}
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to scrape metrics from %v", endpoint)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to scrape metrics from %v, status code %d", endpoint, resp.StatusCode)
+	}
+	values, err := parseMetricSamples(resp.Body, metricName, matchers)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse metrics from %v", endpoint)
+	}
+	return values, nil
+}
+
+// parseMetricSamples returns the values of the samples of the metric in the Prometheus text format with labels matching every matcher
+func parseMetricSamples(body io.Reader, metricName string, matchers []labelMatcher) ([]float64, error) {
+	values := make([]float64, 0)
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		nameEnd := strings.IndexAny(line, "{ \t")
+		if nameEnd < 0 || line[:nameEnd] != metricName {
+			continue
+		}
+		labels, rest, err := parseSampleLabels(line[nameEnd:])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid sample '%v'", line)
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return nil, errors.Errorf("invalid sample '%v', it has no value", line)
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid sample '%v'", line)
+		}
+		if samplesMatch(labels, matchers) {
+			values = append(values, value)
+		}
+	}
+	return values, scanner.Err()
+}
+
+// parseSampleLabels parses the labels in braces at the start of s, if any, returning the rest of s after them
+func parseSampleLabels(s string) (map[string]string, string, error) {
+	labels := map[string]string{}
+	if !strings.HasPrefix(s, "{") {
+		return labels, s, nil
+	}
+	i := 1
+	for {
+		for i < len(s) && (s[i] == ' ' || s[i] == ',') {
+			i++
+		}
+		if i >= len(s) {
+			return nil, "", errors.New("unterminated labels")
+		}
+		if s[i] == '}' {
+			return labels, s[i+1:], nil
+		}
+		nameEnd := strings.Index(s[i:], "=")
+		if nameEnd < 0 || i+nameEnd+1 >= len(s) || s[i+nameEnd+1] != '"' {
+			return nil, "", errors.New("expected a quoted label value")
+		}
+		name := strings.TrimSpace(s[i : i+nameEnd])
+		var value strings.Builder
+		for i += nameEnd + 2; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				if s[i] == 'n' {
+					value.WriteByte('\n')
+					continue
+				}
+			}
+			value.WriteByte(s[i])
+		}
+		if i >= len(s) {
+			return nil, "", errors.Errorf("unterminated value of label %v", name)
+		}
+		labels[name] = value.String()
+		i++
+	}
+}
+
+func samplesMatch(labels map[string]string, matchers []labelMatcher) bool {
+	for _, matcher := range matchers {
+		if !matcher.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// samplesCompare is true when there is at least one value and every value is greater or less than the expected value
+func samplesCompare(values []float64, comparison string, expected float64) (bool, error) {
+	if len(values) == 0 {
+		return false, nil
+	}
+	for _, value := range values {
+		ok, err := compareSample(value, comparison, expected)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func compareSample(value float64, comparison string, expected float64) (bool, error) {
+	switch comparison {
+	case ComparisonGreater:
+		return value > expected, nil
+	case ComparisonLess:
+		return value < expected, nil
+	default:
+		return false, common.Errorf(common.ErrUnsupportedOperation, "unsupported comparison: '%s', expected one of '%s' or '%s'", comparison, ComparisonGreater, ComparisonLess)
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestMetricShouldBeBetween(t *testing.T) {
+	const (
+		customPath   = "/apis/custom.metrics.k8s.io/v1beta1/namespaces/namespace1/pods/*/http_requests"
+		externalPath = "/apis/external.metrics.k8s.io/v1beta1/namespaces/namespace1/sqs_messages_visible"
+	)
+	responses := map[string]string{
+		customPath:   `{"kind":"MetricValueList","items":[{"metricName":"http_requests","value":"150m"},{"metricName":"http_requests","value":"2"}]}`,
+		externalPath: `{"kind":"ExternalMetricValueList","items":[{"metricName":"sqs_messages_visible","value":"5"}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, response)
+	}))
+	defer server.Close()
+	kubeClientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := common.NewWaiterConfig(1, time.Millisecond)
+
+	tests := []struct {
+		name    string
+		fn      func() error
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: custom metric",
+			fn: func() error {
+				return CustomMetricShouldBeBetween(context.Background(), kubeClientset, w, "http_requests", "pods", "*", "namespace1", "100m", "2")
+			},
+		},
+		{
+			name: "Negative Test: custom metric out of range",
+			fn: func() error {
+				return CustomMetricShouldBeBetween(context.Background(), kubeClientset, w, "http_requests", "pods", "*", "namespace1", "200m", "2")
+			},
+			wantErr: true,
+		},
+		{
+			name: "Positive Test: external metric",
+			fn: func() error {
+				return ExternalMetricShouldBeBetween(context.Background(), kubeClientset, w, "sqs_messages_visible", "namespace1", "1", "10")
+			},
+		},
+		{
+			name: "Negative Test: external metric not found",
+			fn: func() error {
+				return ExternalMetricShouldBeBetween(context.Background(), kubeClientset, w, "sqs_messages_sent", "namespace1", "1", "10")
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid range",
+			fn: func() error {
+				return ExternalMetricShouldBeBetween(context.Background(), kubeClientset, w, "sqs_messages_visible", "namespace1", "one", "10")
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid client",
+			fn: func() error {
+				return ExternalMetricShouldBeBetween(context.Background(), nil, w, "sqs_messages_visible", "namespace1", "1", "10")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); (err != nil) != tt.wantErr {
+				t.Errorf("MetricShouldBeBetween() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEndpointMetricShouldBe(t *testing.T) {
+	const exposition = `# HELP controller_runtime_reconcile_total Total number of reconciliations per controller
+# TYPE controller_runtime_reconcile_total counter
+controller_runtime_reconcile_total{controller="rollout",result="success"} 12
+controller_runtime_reconcile_total{controller="rollout",result="error"} 0
+controller_runtime_reconcile_total{controller="service, \"internal\"",result="error"} 3 1700000000000
+workqueue_depth{name="rollout"} 2.5e+00
+process_open_fds 9
+`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != metricsPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, exposition)
+	}))
+	defer server.Close()
+	endpoint := server.URL + metricsPath
+	w := common.NewWaiterConfig(1, time.Millisecond)
+
+	tests := []struct {
+		name       string
+		endpoint   string
+		metricName string
+		selector   string
+		comparison string
+		value      float64
+		wantErr    bool
+		wantCause  error
+	}{
+		{
+			name:       "Positive Test: every sample",
+			metricName: "controller_runtime_reconcile_total",
+			comparison: ComparisonLess,
+			value:      13,
+		},
+		{
+			name:       "Positive Test: samples matching labels",
+			metricName: "controller_runtime_reconcile_total",
+			selector:   "controller=rollout,result!=error",
+			comparison: ComparisonGreater,
+			value:      10,
+		},
+		{
+			name:       "Positive Test: escaped label value",
+			metricName: "controller_runtime_reconcile_total",
+			selector:   "result=error,controller!=rollout",
+			comparison: ComparisonGreater,
+			value:      2,
+		},
+		{
+			name:       "Positive Test: metric without labels",
+			metricName: "process_open_fds",
+			comparison: ComparisonGreater,
+			value:      8,
+		},
+		{
+			name:       "Positive Test: exponent value",
+			metricName: "workqueue_depth",
+			selector:   "name=rollout",
+			comparison: ComparisonLess,
+			value:      3,
+		},
+		{
+			name:       "Negative Test: a sample is not less",
+			metricName: "controller_runtime_reconcile_total",
+			comparison: ComparisonLess,
+			value:      12,
+			wantErr:    true,
+			wantCause:  common.ErrWaiterTimeout,
+		},
+		{
+			name:       "Negative Test: no samples match",
+			metricName: "controller_runtime_reconcile_total",
+			selector:   "controller=deployment",
+			comparison: ComparisonGreater,
+			value:      -1,
+			wantErr:    true,
+			wantCause:  common.ErrWaiterTimeout,
+		},
+		{
+			name:       "Negative Test: prefix of a metric name",
+			metricName: "controller_runtime_reconcile",
+			comparison: ComparisonGreater,
+			value:      -1,
+			wantErr:    true,
+			wantCause:  common.ErrWaiterTimeout,
+		},
+		{
+			name:       "Negative Test: invalid selector",
+			metricName: "workqueue_depth",
+			selector:   "name",
+			comparison: ComparisonLess,
+			value:      3,
+			wantErr:    true,
+		},
+		{
+			name:       "Negative Test: unsupported comparison",
+			metricName: "workqueue_depth",
+			comparison: "equal",
+			value:      3,
+			wantErr:    true,
+			wantCause:  common.ErrUnsupportedOperation,
+		},
+		{
+			name:       "Negative Test: metrics not found",
+			endpoint:   server.URL + "/stats",
+			metricName: "workqueue_depth",
+			comparison: ComparisonLess,
+			value:      3,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.endpoint == "" {
+				tt.endpoint = endpoint
+			}
+			err := EndpointMetricShouldBe(context.Background(), tt.endpoint, w, tt.metricName, tt.selector, tt.comparison, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EndpointMetricShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantCause != nil && !errors.Is(err, tt.wantCause) {
+				t.Errorf("EndpointMetricShouldBe() error = %v, want cause %v", err, tt.wantCause)
+			}
+		})
+	}
+}
+
+func TestGetServiceMetricsPort(t *testing.T) {
+	newService := func(name string, ports ...corev1.ServicePort) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "namespace1"},
+			Spec:       corev1.ServiceSpec{Ports: ports},
+		}
+	}
+	kubeClientset := fake.NewSimpleClientset(
+		newService("named", corev1.ServicePort{Name: "http", Port: 80}, corev1.ServicePort{Name: "http-metrics", Port: 8080}),
+		newService("single", corev1.ServicePort{Name: "http", Port: 80}),
+		newService("unnamed", corev1.ServicePort{Name: "http", Port: 80}, corev1.ServicePort{Name: "grpc", Port: 9090}),
+	)
+	tests := []struct {
+		name    string
+		service string
+		want    int
+		wantErr bool
+	}{
+		{
+			name:    "Positive Test: port named metrics",
+			service: "named",
+			want:    8080,
+		},
+		{
+			name:    "Positive Test: only port",
+			service: "single",
+			want:    80,
+		},
+		{
+			name:    "Negative Test: no port named metrics",
+			service: "unnamed",
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: service not found",
+			service: "missing",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getServiceMetricsPort(kubeClientset, tt.service, "namespace1")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getServiceMetricsPort() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getServiceMetricsPort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
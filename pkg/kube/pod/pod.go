@@ -15,9 +15,12 @@ limitations under the License.
 package pod
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"math/rand"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -29,6 +32,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
 )
 
 func ListPods(kubeClientset kubernetes.Interface, namespace string) error {
@@ -52,7 +57,7 @@ func ListPodsWithSelector(kubeClientset kubernetes.Interface, namespace, selecto
 	}
 
 	if len(pods.Items) == 0 {
-		return errors.Errorf("No pods matched selector '%s'", selector)
+		return common.Errorf(common.ErrResourceNotFound, "No pods matched selector '%s'", selector)
 	}
 	tableFormat := "%-64s%-12s%-24s"
 	log.Infof(tableFormat, "NAME", "READY", "STATUS")
@@ -69,7 +74,7 @@ func PodsWithSelectorHaveRestartCountLessThan(kubeClientset kubernetes.Interface
 	}
 
 	if len(pods.Items) == 0 {
-		return errors.Errorf("No pods matched selector '%s'", selector)
+		return common.Errorf(common.ErrResourceNotFound, "No pods matched selector '%s'", selector)
 	}
 
 	for _, pod := range pods.Items {
@@ -86,6 +91,44 @@ func PodsWithSelectorHaveRestartCountLessThan(kubeClientset kubernetes.Interface
 	return nil
 }
 
+// GetRestartCounts returns the restart count of every container of the pods with the selector, keyed by pod/container
+func GetRestartCounts(kubeClientset kubernetes.Interface, namespace, selector string) (map[string]int32, error) {
+	pods, err := GetPodListWithLabelSelector(kubeClientset, namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods.Items) == 0 {
+		return nil, common.Errorf(common.ErrResourceNotFound, "No pods matched selector '%s'", selector)
+	}
+	restartCounts := map[string]int32{}
+	for _, pod := range pods.Items {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			restartCounts[pod.Name+"/"+containerStatus.Name] = containerStatus.RestartCount
+		}
+	}
+	return restartCounts, nil
+}
+
+// RestartCountsShouldIncreaseByAtMost validates no container restarted more than maxIncrease times between the before and after
+// restart counts, every restart of a container missing in before, e.g. of a replaced pod, counts as an increase
+func RestartCountsShouldIncreaseByAtMost(before, after map[string]int32, maxIncrease int) error {
+	var increased []string
+	for container, restartCount := range after {
+		increase := int(restartCount - before[container])
+		if increase > 0 {
+			log.Infof("Container '%s' restarted %d times", container, increase)
+		}
+		if increase > maxIncrease {
+			increased = append(increased, fmt.Sprintf("container '%s' restarted %d times", container, increase))
+		}
+	}
+	if len(increased) > 0 {
+		sort.Strings(increased)
+		return errors.Errorf("expected restart counts to increase by at most %d, but:\n%s", maxIncrease, strings.Join(increased, "\n"))
+	}
+	return nil
+}
+
 func PodsInNamespaceWithLabelSelectorConvergeToFieldSelector(kubeClientset kubernetes.Interface, expBackoff wait.Backoff, namespace, labelSelector, fieldSelector string) error {
 	return util.RetryOnAnyError(&expBackoff, func() error {
 		podList, err := GetPodListWithLabelSelector(kubeClientset, namespace, labelSelector)
@@ -94,7 +137,7 @@ func PodsInNamespaceWithLabelSelectorConvergeToFieldSelector(kubeClientset kuber
 		}
 		n := len(podList.Items)
 		if n == 0 {
-			return fmt.Errorf("no pods matched label selector '%s'", labelSelector)
+			return common.Errorf(common.ErrResourceNotFound, "no pods matched label selector '%s'", labelSelector)
 		}
 		log.Infof("found '%d' pods with label selector '%s'", n, labelSelector)
 
@@ -104,7 +147,7 @@ func PodsInNamespaceWithLabelSelectorConvergeToFieldSelector(kubeClientset kuber
 		}
 		m := len(podListWithSelector.Items)
 		if m == 0 {
-			return fmt.Errorf("no pods matched label selector '%s' and field selector '%s'", labelSelector, fieldSelector)
+			return common.Errorf(common.ErrResourceNotFound, "no pods matched label selector '%s' and field selector '%s'", labelSelector, fieldSelector)
 		}
 		log.Infof("found '%d' pods with label selector '%s' and field selector '%s'", m, labelSelector, fieldSelector)
 
@@ -133,7 +176,7 @@ func SomeOrAllPodsInNamespaceWithSelectorHaveStringInLogsSinceTime(kubeClientset
 			return err
 		}
 		if len(pods.Items) == 0 {
-			return fmt.Errorf("no pods matched selector '%s'", selector)
+			return common.Errorf(common.ErrResourceNotFound, "no pods matched selector '%s'", selector)
 		}
 
 		const (
@@ -175,7 +218,7 @@ func SomePodsInNamespaceWithSelectorDontHaveStringInLogsSinceTime(kubeClientset
 	}
 
 	if len(pods.Items) == 0 {
-		return errors.Errorf("No pods matched selector '%s'", selector)
+		return common.Errorf(common.ErrResourceNotFound, "No pods matched selector '%s'", selector)
 	}
 	for _, pod := range pods.Items {
 		count, err := countStringInPodLogs(kubeClientset, pod, since, searchkeyword)
@@ -195,7 +238,7 @@ func PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime(kubeClientset kubern
 		return err
 	}
 	if len(pods.Items) == 0 {
-		return errors.Errorf("No pods matched selector '%s'", selector)
+		return common.Errorf(common.ErrResourceNotFound, "No pods matched selector '%s'", selector)
 	}
 
 	for _, pod := range pods.Items {
@@ -261,7 +304,7 @@ func PodsInNamespaceWithSelectorShouldHaveLabels(kubeClientset kubernetes.Interf
 	}
 
 	if len(podList.Items) == 0 {
-		return fmt.Errorf("no pods matched selector '%s'", selector)
+		return common.Errorf(common.ErrResourceNotFound, "no pods matched selector '%s'", selector)
 	}
 
 	for _, pod := range podList.Items {
@@ -289,3 +332,186 @@ func PodsInNamespaceWithSelectorShouldHaveLabels(kubeClientset kubernetes.Interf
 
 	return nil
 }
+
+// PodsInNamespaceWithSelectorShouldHaveContainer validates every pod has the container, either as a regular or an init container (e.g. sidecars injected as native sidecars).
+func PodsInNamespaceWithSelectorShouldHaveContainer(kubeClientset kubernetes.Interface, namespace, selector, containerName string) error {
+	podList, err := GetPodListWithLabelSelector(kubeClientset, namespace, selector)
+	if err != nil {
+		return fmt.Errorf("error getting pods with selector %q: %v", selector, err)
+	}
+
+	if len(podList.Items) == 0 {
+		return common.Errorf(common.ErrResourceNotFound, "no pods matched selector '%s'", selector)
+	}
+
+	for _, pod := range podList.Items {
+		if !hasContainer(pod, containerName) {
+			return fmt.Errorf("container %s missing in pod/namespace %s", containerName, pod.Name+"/"+namespace)
+		}
+	}
+
+	return nil
+}
+
+// PodsInNamespaceWithSelectorShouldRunOnFargate validates every pod is scheduled on a node with the Fargate compute type.
+func PodsInNamespaceWithSelectorShouldRunOnFargate(kubeClientset kubernetes.Interface, namespace, selector string) error {
+	podList, err := GetPodListWithLabelSelector(kubeClientset, namespace, selector)
+	if err != nil {
+		return fmt.Errorf("error getting pods with selector %q: %v", selector, err)
+	}
+
+	if len(podList.Items) == 0 {
+		return common.Errorf(common.ErrResourceNotFound, "no pods matched selector '%s'", selector)
+	}
+
+	for _, pod := range podList.Items {
+		onFargate, err := isScheduledOnFargate(kubeClientset, pod)
+		if err != nil {
+			return err
+		}
+		if !onFargate {
+			return fmt.Errorf("pod/namespace %s is not scheduled on a Fargate node, node: '%s'", pod.Name+"/"+namespace, pod.Spec.NodeName)
+		}
+	}
+
+	return nil
+}
+
+// PodsInNamespaceWithSelectorShouldBeAllocatedResource validates the pods with the selector are running with a limit of the extended
+// resource, e.g. nvidia.com/gpu, on nodes whose allocatable capacity of the resource covers the limits of the pods scheduled on them
+func PodsInNamespaceWithSelectorShouldBeAllocatedResource(kubeClientset kubernetes.Interface, namespace, selector, resourceName string) error {
+	podList, err := GetPodListWithLabelSelector(kubeClientset, namespace, selector)
+	if err != nil {
+		return fmt.Errorf("error getting pods with selector %q: %v", selector, err)
+	}
+
+	if len(podList.Items) == 0 {
+		return common.Errorf(common.ErrResourceNotFound, "no pods matched selector '%s'", selector)
+	}
+
+	allocatedByNode := map[string]int64{}
+	for _, pod := range podList.Items {
+		allocated := getPodResourceLimit(pod, corev1.ResourceName(resourceName))
+		if allocated == 0 {
+			return fmt.Errorf("pod/namespace %s has no limit of %s", pod.Name+"/"+namespace, resourceName)
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return fmt.Errorf("pod/namespace %s is %s, not %s", pod.Name+"/"+namespace, pod.Status.Phase, corev1.PodRunning)
+		}
+		allocatedByNode[pod.Spec.NodeName] += allocated
+		log.Infof("pod/namespace %s is allocated %d %s on node '%s'", pod.Name+"/"+namespace, allocated, resourceName, pod.Spec.NodeName)
+	}
+
+	for nodeName, allocated := range allocatedByNode {
+		node, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+			return kubeClientset.CoreV1().Nodes().Get(context.Background(), nodeName, metav1.GetOptions{})
+		})
+		if err != nil {
+			return errors.Wrapf(common.WrapNotFound(err), "failed to get node %s", nodeName)
+		}
+		allocatable := node.(*corev1.Node).Status.Allocatable[corev1.ResourceName(resourceName)]
+		if allocatable.Value() < allocated {
+			return fmt.Errorf("node '%s' advertises %d allocatable %s, but its pods with selector '%s' are allocated %d", nodeName, allocatable.Value(), resourceName, selector, allocated)
+		}
+	}
+
+	return nil
+}
+
+// DeleteRandomPods deletes count random pods matching the selector and returns how many of them were ready before the deletion
+func DeleteRandomPods(kubeClientset kubernetes.Interface, namespace, selector string, count int) (int, error) {
+	podList, err := GetPodListWithLabelSelector(kubeClientset, namespace, selector)
+	if err != nil {
+		return 0, fmt.Errorf("error getting pods with selector %q: %v", selector, err)
+	}
+
+	pods := getRunningPods(podList.Items)
+	if len(pods) < count {
+		return 0, fmt.Errorf("cannot delete %d pods, only %d pods matched selector '%s'", count, len(pods), selector)
+	}
+	readyCount := countReadyPods(pods)
+
+	rand.Shuffle(len(pods), func(i, j int) { pods[i], pods[j] = pods[j], pods[i] })
+	for _, pod := range pods[:count] {
+		if err := kubeClientset.CoreV1().Pods(namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{}); err != nil {
+			return 0, errors.Wrapf(err, "failed to delete pod %s/%s", namespace, pod.Name)
+		}
+		log.Infof("deleted pod %s/%s", namespace, pod.Name)
+	}
+	return readyCount, nil
+}
+
+// PodsInNamespaceWithSelectorShouldBeReady waits until at least readyCount pods matching the selector are ready, pods being deleted are not counted
+func PodsInNamespaceWithSelectorShouldBeReady(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, namespace, selector string, readyCount int) error {
+	var counter int
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for %d pods with selector '%s' in namespace %s to be ready", readyCount, selector, namespace)
+		}
+		podList, err := GetPodListWithLabelSelector(kubeClientset, namespace, selector)
+		if err != nil {
+			return fmt.Errorf("error getting pods with selector %q: %v", selector, err)
+		}
+		current := countReadyPods(getRunningPods(podList.Items))
+		if current >= readyCount {
+			log.Infof("%d/%d pods with selector '%s' in namespace %s are ready", current, readyCount, selector, namespace)
+			return nil
+		}
+		log.Infof("waiting for pods with selector '%s' in namespace %s to be ready, %d/%d ready", selector, namespace, current, readyCount)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// ExecInPod runs the command in the container of the pod, the command is split on spaces outside of single or double quotes.
+// It returns the stdout and stderr of the command and fails if the command could not be run or exited with a non-zero code.
+func ExecInPod(kubeClientset kubernetes.Interface, restConfig *rest.Config, name, namespace, container, command string) (string, string, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return "", "", err
+	}
+	if restConfig == nil {
+		return "", "", common.Errorf(common.ErrClientNotInitialized, "'rest.Config' is nil, discover clients first")
+	}
+
+	args, err := splitCommand(command)
+	if err != nil {
+		return "", "", err
+	}
+	pod, err := kubeClientset.CoreV1().Pods(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", "", errors.Wrapf(common.WrapNotFound(err), "failed to get pod %v/%v", namespace, name)
+	}
+	if !hasContainer(*pod, container) {
+		return "", "", errors.Errorf("pod %v/%v has no container %v", namespace, name, container)
+	}
+
+	executor, err := newExecutor(kubeClientset, restConfig, pod, container, args)
+	if err != nil {
+		return "", "", err
+	}
+	var stdout, stderr bytes.Buffer
+	err = executor.StreamWithContext(context.Background(), remotecommand.StreamOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	log.Infof("exec '%v' in pod %v/%v container %v:\n%v%v", command, namespace, name, container, stdout.String(), stderr.String())
+	if err != nil {
+		return stdout.String(), stderr.String(), errors.Wrapf(err, "failed to exec '%v' in pod %v/%v container %v: %v", command, namespace, name, container, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), stderr.String(), nil
+}
+
+// ExecInPodOutputShouldContain runs the command in the container of the pod and validates its stdout or stderr contains the string
+func ExecInPodOutputShouldContain(kubeClientset kubernetes.Interface, restConfig *rest.Config, name, namespace, container, command, expected string) error {
+	stdout, stderr, err := ExecInPod(kubeClientset, restConfig, name, namespace, container, command)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(stdout, expected) && !strings.Contains(stderr, expected) {
+		return errors.Errorf("output of '%v' in pod %v/%v container %v does not contain '%v':\n%v%v", command, namespace, name, container, expected, stdout, stderr)
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ package pod
 import (
 	"bufio"
 	"context"
+	"net/http"
 	"strings"
 	"time"
 
@@ -27,6 +28,14 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const (
+	computeTypeLabel   = "eks.amazonaws.com/compute-type"
+	computeTypeFargate = "fargate"
 )
 
 func GetPodListWithLabelSelector(kubeClientset kubernetes.Interface, namespace, labelSelector string) (*corev1.PodList, error) {
@@ -80,3 +89,117 @@ func countStringInPodLogs(kubeClientset kubernetes.Interface, pod corev1.Pod, si
 	}
 	return foundCount, nil
 }
+
+func hasContainer(pod corev1.Pod, containerName string) bool {
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, container := range containers {
+			if container.Name == containerName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isScheduledOnFargate(kubeClientset kubernetes.Interface, pod corev1.Pod) (bool, error) {
+	if pod.Spec.NodeName == "" {
+		return false, nil
+	}
+	node, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.CoreV1().Nodes().Get(context.Background(), pod.Spec.NodeName, metav1.GetOptions{})
+	})
+	if err != nil {
+		return false, errors.Wrapf(common.WrapNotFound(err), "failed to get node %s of pod %s", pod.Spec.NodeName, pod.Name)
+	}
+	return node.(*corev1.Node).Labels[computeTypeLabel] == computeTypeFargate, nil
+}
+
+// getPodResourceLimit returns the sum of the limits of the resource of the containers of the pod, extended resources
+// can only be requested with a limit, and init containers reuse the resources of the containers
+func getPodResourceLimit(pod corev1.Pod, resourceName corev1.ResourceName) int64 {
+	var limit int64
+	for _, container := range pod.Spec.Containers {
+		if quantity, ok := container.Resources.Limits[resourceName]; ok {
+			limit += quantity.Value()
+		}
+	}
+	return limit
+}
+
+// getRunningPods returns the pods that are not being deleted
+func getRunningPods(pods []corev1.Pod) []corev1.Pod {
+	running := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil {
+			running = append(running, pod)
+		}
+	}
+	return running
+}
+
+func countReadyPods(pods []corev1.Pod) int {
+	var readyCount int
+	for _, pod := range pods {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				readyCount++
+			}
+		}
+	}
+	return readyCount
+}
+
+// newExecutor is a variable so tests can replace the SPDY executor, which needs a real API server
+var newExecutor = func(kubeClientset kubernetes.Interface, restConfig *rest.Config, pod *corev1.Pod, container string, command []string) (remotecommand.Executor, error) {
+	req := kubeClientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	return remotecommand.NewSPDYExecutor(restConfig, http.MethodPost, req.URL())
+}
+
+// splitCommand splits the command on spaces outside of single or double quotes, which are removed
+func splitCommand(command string) ([]string, error) {
+	var (
+		args  []string
+		arg   strings.Builder
+		inArg bool
+		quote rune
+	)
+	for _, r := range command {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.Errorf("command '%v' has an unterminated %c quote", command, quote)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	if len(args) == 0 {
+		return nil, errors.Errorf("command is empty")
+	}
+	return args, nil
+}
@@ -15,10 +15,16 @@ limitations under the License.
 package pod
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -27,6 +33,8 @@ import (
 	fakeDynamic "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
 )
 
 func Test_PodsInNamespaceWithSelectorShouldHaveLabels(t *testing.T) {
@@ -134,6 +142,116 @@ func Test_PodsInNamespaceWithSelectorShouldHaveLabels(t *testing.T) {
 	}
 }
 
+func TestPodsInNamespaceWithSelectorShouldHaveContainer(t *testing.T) {
+	newPod := func(name string, initContainers, containers []string) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "foo",
+				Labels:    map[string]string{"app": "foo"},
+			},
+		}
+		for _, c := range initContainers {
+			pod.Spec.InitContainers = append(pod.Spec.InitContainers, v1.Container{Name: c})
+		}
+		for _, c := range containers {
+			pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: c})
+		}
+		return pod
+	}
+	tests := []struct {
+		name          string
+		kubeClientset kubernetes.Interface
+		selector      string
+		wantErr       bool
+	}{
+		{
+			name:          "Pods should have sidecar container",
+			kubeClientset: fake.NewSimpleClientset(newPod("pod-1", nil, []string{"app", "istio-proxy"}), newPod("pod-2", []string{"istio-init", "istio-proxy"}, []string{"app"})),
+			selector:      "app=foo",
+		},
+		{
+			name:          "Error from pod missing sidecar container",
+			kubeClientset: fake.NewSimpleClientset(newPod("pod-1", nil, []string{"app", "istio-proxy"}), newPod("pod-2", []string{"istio-init"}, []string{"app"})),
+			selector:      "app=foo",
+			wantErr:       true,
+		},
+		{
+			name:          "No pods found",
+			kubeClientset: fake.NewSimpleClientset(newPod("pod-1", nil, []string{"app", "istio-proxy"})),
+			selector:      "app=doesnotexist",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := PodsInNamespaceWithSelectorShouldHaveContainer(tt.kubeClientset, "foo", tt.selector, "istio-proxy"); (err != nil) != tt.wantErr {
+				t.Errorf("PodsInNamespaceWithSelectorShouldHaveContainer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPodsInNamespaceWithSelectorShouldRunOnFargate(t *testing.T) {
+	newPod := func(name, nodeName string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "foo",
+				Labels:    map[string]string{"app": "foo"},
+			},
+			Spec: v1.PodSpec{NodeName: nodeName},
+		}
+	}
+	newNode := func(name, computeType string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"eks.amazonaws.com/compute-type": computeType},
+			},
+		}
+	}
+	fargateNode := newNode("fargate-ip-10-0-0-1.ec2.internal", "fargate")
+	ec2Node := newNode("ip-10-0-0-2.ec2.internal", "ec2")
+	tests := []struct {
+		name          string
+		kubeClientset kubernetes.Interface
+		selector      string
+		wantErr       bool
+	}{
+		{
+			name:          "Pods scheduled on Fargate",
+			kubeClientset: fake.NewSimpleClientset(fargateNode, newPod("pod-1", fargateNode.Name)),
+			selector:      "app=foo",
+		},
+		{
+			name:          "Error from pod scheduled on EC2",
+			kubeClientset: fake.NewSimpleClientset(fargateNode, ec2Node, newPod("pod-1", fargateNode.Name), newPod("pod-2", ec2Node.Name)),
+			selector:      "app=foo",
+			wantErr:       true,
+		},
+		{
+			name:          "Error from pod not scheduled",
+			kubeClientset: fake.NewSimpleClientset(newPod("pod-1", "")),
+			selector:      "app=foo",
+			wantErr:       true,
+		},
+		{
+			name:          "No pods found",
+			kubeClientset: fake.NewSimpleClientset(fargateNode, newPod("pod-1", fargateNode.Name)),
+			selector:      "app=doesnotexist",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := PodsInNamespaceWithSelectorShouldRunOnFargate(tt.kubeClientset, "foo", tt.selector); (err != nil) != tt.wantErr {
+				t.Errorf("PodsInNamespaceWithSelectorShouldRunOnFargate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestPodsInNamespaceWithLabelSelectorConvergeToFieldSelector(t *testing.T) {
 	type args struct {
 		kubeClientset kubernetes.Interface
@@ -191,3 +309,316 @@ func TestPodsInNamespaceWithLabelSelectorConvergeToFieldSelector(t *testing.T) {
 		})
 	}
 }
+
+func newReadyPod(name string, ready bool) *v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "foo",
+			Labels:    map[string]string{"app": "foo"},
+		},
+		Status: v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}}},
+	}
+}
+
+func TestDeleteRandomPods(t *testing.T) {
+	tests := []struct {
+		name           string
+		count          int
+		wantReadyCount int
+		wantRemaining  int
+		wantErr        bool
+	}{
+		{
+			name:           "Delete some pods",
+			count:          2,
+			wantReadyCount: 2,
+			wantRemaining:  1,
+		},
+		{
+			name:          "Error from deleting more pods than matched",
+			count:         4,
+			wantRemaining: 3,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(newReadyPod("pod-1", true), newReadyPod("pod-2", true), newReadyPod("pod-3", false))
+			readyCount, err := DeleteRandomPods(kubeClientset, "foo", "app=foo", tt.count)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteRandomPods() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if readyCount != tt.wantReadyCount {
+				t.Errorf("DeleteRandomPods() readyCount = %v, want %v", readyCount, tt.wantReadyCount)
+			}
+			pods, err := GetPodListWithLabelSelector(kubeClientset, "foo", "app=foo")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pods.Items) != tt.wantRemaining {
+				t.Errorf("DeleteRandomPods() remaining pods = %v, want %v", len(pods.Items), tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestPodsInNamespaceWithSelectorShouldBeReady(t *testing.T) {
+	terminating := newReadyPod("pod-3", true)
+	terminating.DeletionTimestamp = &metav1.Time{}
+	tests := []struct {
+		name          string
+		kubeClientset kubernetes.Interface
+		readyCount    int
+		wantErr       bool
+	}{
+		{
+			name:          "Pods ready",
+			kubeClientset: fake.NewSimpleClientset(newReadyPod("pod-1", true), newReadyPod("pod-2", true)),
+			readyCount:    2,
+		},
+		{
+			name:          "Error from pods not ready",
+			kubeClientset: fake.NewSimpleClientset(newReadyPod("pod-1", true), newReadyPod("pod-2", false)),
+			readyCount:    2,
+			wantErr:       true,
+		},
+		{
+			name:          "Error from terminating pod",
+			kubeClientset: fake.NewSimpleClientset(newReadyPod("pod-1", true), terminating),
+			readyCount:    2,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := PodsInNamespaceWithSelectorShouldBeReady(context.Background(), tt.kubeClientset, w, "foo", "app=foo", tt.readyCount); (err != nil) != tt.wantErr {
+				t.Errorf("PodsInNamespaceWithSelectorShouldBeReady() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRestartCounts(t *testing.T) {
+	newRestartedPod := func(name string, restartCount int32) *v1.Pod {
+		p := newReadyPod(name, true)
+		p.Status.ContainerStatuses = []v1.ContainerStatus{{Name: "app", RestartCount: restartCount}}
+		return p
+	}
+	before, err := GetRestartCounts(fake.NewSimpleClientset(newRestartedPod("pod-1", 1), newRestartedPod("pod-2", 0)), "foo", "app=foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetRestartCounts(fake.NewSimpleClientset(), "foo", "app=foo"); err == nil {
+		t.Errorf("expected an error when no pods match the selector")
+	}
+	tests := []struct {
+		name        string
+		after       []runtime.Object
+		maxIncrease int
+		wantErr     bool
+	}{
+		{
+			name:  "Positive Test: no restarts",
+			after: []runtime.Object{newRestartedPod("pod-1", 1), newRestartedPod("pod-2", 0)},
+		},
+		{
+			name:        "Positive Test: restarts within the limit",
+			after:       []runtime.Object{newRestartedPod("pod-1", 2), newRestartedPod("pod-2", 1)},
+			maxIncrease: 1,
+		},
+		{
+			name:    "Negative Test: restarted",
+			after:   []runtime.Object{newRestartedPod("pod-1", 2), newRestartedPod("pod-2", 0)},
+			wantErr: true,
+		},
+		{
+			name:        "Negative Test: new pod restarted",
+			after:       []runtime.Object{newRestartedPod("pod-1", 1), newRestartedPod("pod-3", 2)},
+			maxIncrease: 1,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after, err := GetRestartCounts(fake.NewSimpleClientset(tt.after...), "foo", "app=foo")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := RestartCountsShouldIncreaseByAtMost(before, after, tt.maxIncrease); (err != nil) != tt.wantErr {
+				t.Errorf("RestartCountsShouldIncreaseByAtMost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPodsInNamespaceWithSelectorShouldBeAllocatedResource(t *testing.T) {
+	const gpu = "nvidia.com/gpu"
+	newGPUPod := func(name, nodeName, limit string, phase v1.PodPhase) *v1.Pod {
+		p := newReadyPod(name, true)
+		p.Spec.NodeName = nodeName
+		p.Spec.Containers = []v1.Container{{Name: "app"}}
+		if limit != "" {
+			p.Spec.Containers[0].Resources.Limits = v1.ResourceList{gpu: resource.MustParse(limit)}
+		}
+		p.Status.Phase = phase
+		return p
+	}
+	newGPUNode := func(name, allocatable string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     v1.NodeStatus{Allocatable: v1.ResourceList{gpu: resource.MustParse(allocatable)}},
+		}
+	}
+	tests := []struct {
+		name          string
+		kubeClientset kubernetes.Interface
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test",
+			kubeClientset: fake.NewSimpleClientset(newGPUNode("node-1", "4"), newGPUPod("pod-1", "node-1", "2", v1.PodRunning), newGPUPod("pod-2", "node-1", "2", v1.PodRunning)),
+		},
+		{
+			name:          "Negative Test: no limit",
+			kubeClientset: fake.NewSimpleClientset(newGPUNode("node-1", "4"), newGPUPod("pod-1", "node-1", "", v1.PodRunning)),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: not running",
+			kubeClientset: fake.NewSimpleClientset(newGPUPod("pod-1", "", "1", v1.PodPending)),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: node capacity exceeded",
+			kubeClientset: fake.NewSimpleClientset(newGPUNode("node-1", "2"), newGPUPod("pod-1", "node-1", "2", v1.PodRunning), newGPUPod("pod-2", "node-1", "1", v1.PodRunning)),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: no pods",
+			kubeClientset: fake.NewSimpleClientset(newGPUNode("node-1", "2")),
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := PodsInNamespaceWithSelectorShouldBeAllocatedResource(tt.kubeClientset, "foo", "app=foo", gpu); (err != nil) != tt.wantErr {
+				t.Errorf("PodsInNamespaceWithSelectorShouldBeAllocatedResource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type fakeExecutor struct {
+	stdout, stderr string
+	err            error
+	command        []string
+}
+
+func (e *fakeExecutor) Stream(options remotecommand.StreamOptions) error {
+	return e.StreamWithContext(context.Background(), options)
+}
+
+func (e *fakeExecutor) StreamWithContext(_ context.Context, options remotecommand.StreamOptions) error {
+	_, _ = options.Stdout.Write([]byte(e.stdout))
+	_, _ = options.Stderr.Write([]byte(e.stderr))
+	return e.err
+}
+
+func TestExecInPodOutputShouldContain(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-foo", Namespace: "foo"},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app"}}},
+	}
+	restConfig := &rest.Config{}
+	tests := []struct {
+		name        string
+		executor    *fakeExecutor
+		container   string
+		command     string
+		expected    string
+		noConfig    bool
+		wantCommand []string
+		wantErr     bool
+	}{
+		{
+			name:        "Positive Test: stdout contains the string",
+			executor:    &fakeExecutor{stdout: "Name: kubernetes.default\nAddress: 10.0.0.1\n"},
+			container:   "app",
+			command:     "nslookup kubernetes.default",
+			expected:    "Address",
+			wantCommand: []string{"nslookup", "kubernetes.default"},
+		},
+		{
+			name:        "Positive Test: stderr contains the string and quotes group arguments",
+			executor:    &fakeExecutor{stderr: "settings.yaml"},
+			container:   "app",
+			command:     "sh -c 'ls /etc/config'",
+			expected:    "settings.yaml",
+			wantCommand: []string{"sh", "-c", "ls /etc/config"},
+		},
+		{
+			name:      "Negative Test: output does not contain the string",
+			executor:  &fakeExecutor{stdout: "nothing here"},
+			container: "app",
+			command:   "ls",
+			expected:  "settings.yaml",
+			wantErr:   true,
+		},
+		{
+			name:      "Negative Test: command exits with an error",
+			executor:  &fakeExecutor{stderr: "no such file", err: errors.New("command terminated with exit code 1")},
+			container: "app",
+			command:   "cat /missing",
+			wantErr:   true,
+		},
+		{
+			name:      "Negative Test: container does not exist",
+			executor:  &fakeExecutor{},
+			container: "sidecar",
+			command:   "ls",
+			wantErr:   true,
+		},
+		{
+			name:      "Negative Test: unterminated quote",
+			executor:  &fakeExecutor{},
+			container: "app",
+			command:   "sh -c 'ls",
+			wantErr:   true,
+		},
+		{
+			name:      "Negative Test: rest.Config is nil",
+			executor:  &fakeExecutor{},
+			container: "app",
+			command:   "ls",
+			noConfig:  true,
+			wantErr:   true,
+		},
+	}
+	defaultExecutor := newExecutor
+	defer func() { newExecutor = defaultExecutor }()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newExecutor = func(_ kubernetes.Interface, _ *rest.Config, _ *v1.Pod, _ string, command []string) (remotecommand.Executor, error) {
+				tt.executor.command = command
+				return tt.executor, nil
+			}
+			config := restConfig
+			if tt.noConfig {
+				config = nil
+			}
+			err := ExecInPodOutputShouldContain(fake.NewSimpleClientset(pod), config, pod.Name, pod.Namespace, tt.container, tt.command, tt.expected)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExecInPodOutputShouldContain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantCommand != nil && !reflect.DeepEqual(tt.executor.command, tt.wantCommand) {
+				t.Errorf("ExecInPodOutputShouldContain() command = %q, want %q", tt.executor.command, tt.wantCommand)
+			}
+		})
+	}
+}
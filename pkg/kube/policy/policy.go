@@ -0,0 +1,63 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"strings"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/dynamic"
+)
+
+func PolicyReportsShouldHaveNoViolations(dynamicClient dynamic.Interface, namespace string) error {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	reports, err := listPolicyReports(dynamicClient, namespace)
+	if err != nil {
+		return err
+	}
+
+	var violations []string
+	for _, report := range reports.Items {
+		violations = append(violations, getPolicyReportViolations(&report)...)
+	}
+	if len(violations) != 0 {
+		return errors.Errorf("expected no policy violations in namespace %v, found %d: %v", namespace, len(violations), strings.Join(violations, "; "))
+	}
+	log.Infof("policy reports in namespace %v have no violations", namespace)
+	return nil
+}
+
+func ConstraintShouldHaveNoViolations(dynamicClient dynamic.Interface, kind, name, namespace string) error {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	constraint, err := getConstraint(dynamicClient, kind, name)
+	if err != nil {
+		return err
+	}
+
+	violations := getConstraintViolations(constraint, namespace)
+	if len(violations) != 0 {
+		return errors.Errorf("expected no violations of constraint %v/%v in namespace %v, found %d: %v", kind, name, namespace, len(violations), strings.Join(violations, "; "))
+	}
+	log.Infof("constraint %v/%v has no violations in namespace %v", kind, name, namespace)
+	return nil
+}
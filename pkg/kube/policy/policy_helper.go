@@ -0,0 +1,96 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	resultFail  = "fail"
+	resultError = "error"
+
+	constraintsGroup   = "constraints.gatekeeper.sh"
+	constraintsVersion = "v1beta1"
+)
+
+// PolicyReportResource is the policy report of the Kubernetes policy working group, produced by Kyverno
+var PolicyReportResource = schema.GroupVersionResource{Group: "wgpolicyk8s.io", Version: "v1alpha2", Resource: "policyreports"}
+
+// ConstraintResource returns the resource of a Gatekeeper constraint, every ConstraintTemplate creates a kind in the constraints group
+func ConstraintResource(kind string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: constraintsGroup, Version: constraintsVersion, Resource: strings.ToLower(kind)}
+}
+
+func listPolicyReports(dynamicClient dynamic.Interface, namespace string) (*unstructured.UnstructuredList, error) {
+	reports, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return dynamicClient.Resource(PolicyReportResource).Namespace(namespace).List(context.Background(), metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list policy reports in namespace '%v'", namespace)
+	}
+	return reports.(*unstructured.UnstructuredList), nil
+}
+
+func getConstraint(dynamicClient dynamic.Interface, kind, name string) (*unstructured.Unstructured, error) {
+	constraint, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return dynamicClient.Resource(ConstraintResource(kind)).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get constraint '%v/%v'", kind, name)
+	}
+	return constraint.(*unstructured.Unstructured), nil
+}
+
+// getPolicyReportViolations returns the failed and errored results of the report
+func getPolicyReportViolations(report *unstructured.Unstructured) []string {
+	results, _, _ := unstructured.NestedSlice(report.Object, "results")
+	var violations []string
+	for _, r := range results {
+		result, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch result["result"] {
+		case resultFail, resultError:
+			violations = append(violations, fmt.Sprintf("%v/%v: %v", result["policy"], result["rule"], result["message"]))
+		}
+	}
+	return violations
+}
+
+// getConstraintViolations returns the audit violations of the constraint in namespace
+func getConstraintViolations(constraint *unstructured.Unstructured, namespace string) []string {
+	auditViolations, _, _ := unstructured.NestedSlice(constraint.Object, "status", "violations")
+	var violations []string
+	for _, v := range auditViolations {
+		violation, ok := v.(map[string]interface{})
+		if !ok || violation["namespace"] != namespace {
+			continue
+		}
+		violations = append(violations, fmt.Sprintf("%v/%v: %v", violation["kind"], violation["name"], violation["message"]))
+	}
+	return violations
+}
@@ -0,0 +1,157 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/keikoproj/kubedog/internal/testutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	namespace      = "namespace1"
+	constraintKind = "K8sRequiredLabels"
+	constraintName = "require-team-label"
+)
+
+func TestPolicyReportsShouldHaveNoViolations(t *testing.T) {
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: all results pass",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newPolicyReport("report1", "pass", "skip")),
+		},
+		{
+			name:          "Positive Test: no reports",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources),
+		},
+		{
+			name:          "Negative Test: failed result",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newPolicyReport("report1", "pass"), newPolicyReport("report2", "fail")),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: errored result",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newPolicyReport("report1", "error")),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid client",
+			dynamicClient: nil,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := PolicyReportsShouldHaveNoViolations(tt.dynamicClient, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("PolicyReportsShouldHaveNoViolations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConstraintShouldHaveNoViolations(t *testing.T) {
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: no violations",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newConstraint()),
+		},
+		{
+			name:          "Positive Test: violations in other namespace",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newConstraint("namespace2")),
+		},
+		{
+			name:          "Negative Test: violations in namespace",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newConstraint("namespace2", namespace)),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: constraint not found",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid client",
+			dynamicClient: nil,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ConstraintShouldHaveNoViolations(tt.dynamicClient, constraintKind, constraintName, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("ConstraintShouldHaveNoViolations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fakeResources are the resources of the kinds tracked by the fake dynamic client
+var fakeResources = map[string]schema.GroupVersionResource{
+	"PolicyReport": PolicyReportResource,
+	constraintKind: ConstraintResource(constraintKind),
+}
+
+func newPolicyReport(name string, results ...string) *unstructured.Unstructured {
+	reportResults := []interface{}{}
+	for _, result := range results {
+		reportResults = append(reportResults, map[string]interface{}{
+			"policy":  "require-labels",
+			"rule":    "check-team",
+			"message": "label 'team' is required",
+			"result":  result,
+		})
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": PolicyReportResource.GroupVersion().String(),
+		"kind":       "PolicyReport",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"results": reportResults,
+	}}
+}
+
+func newConstraint(violationNamespaces ...string) *unstructured.Unstructured {
+	violations := []interface{}{}
+	for _, ns := range violationNamespaces {
+		violations = append(violations, map[string]interface{}{
+			"kind":      "Deployment",
+			"name":      "deployment1",
+			"namespace": ns,
+			"message":   "you must provide labels: {\"team\"}",
+		})
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": ConstraintResource(constraintKind).GroupVersion().String(),
+		"kind":       constraintKind,
+		"metadata": map[string]interface{}{
+			"name": constraintName,
+		},
+		"status": map[string]interface{}{
+			"violations": violations,
+		},
+	}}
+}
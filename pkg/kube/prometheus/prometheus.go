@@ -0,0 +1,115 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheus
+
+import (
+	"context"
+	"time"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func QueryShouldReturnValue(ctx context.Context, kubeClientset kubernetes.Interface, restConfig *rest.Config, w common.WaiterConfig, prometheusURL, query, operator string, expectedValue float64, within string) error {
+	endpoint, stopChan, err := GetPrometheusEndpoint(kubeClientset, restConfig, prometheusURL)
+	if err != nil {
+		return err
+	}
+	if stopChan != nil {
+		defer close(stopChan)
+	}
+	return EndpointQueryShouldReturnValue(ctx, endpoint, w, query, operator, expectedValue, within)
+}
+
+func EndpointQueryShouldReturnValue(ctx context.Context, endpoint string, w common.WaiterConfig, query, operator string, expectedValue float64, within string) error {
+	timeout, err := time.ParseDuration(within)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse duration '%v'", within)
+	}
+	if _, err := compareValue(0, operator, expectedValue); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		values, err := queryPrometheus(endpoint, query)
+		if err != nil {
+			return err
+		}
+		ok, err := valuesSatisfy(values, operator, expectedValue)
+		if err != nil {
+			return err
+		}
+		if ok {
+			log.Infof("prometheus query '%v' returned %v, all %v %v", query, values, operator, expectedValue)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("prometheus query '%v' returned %v, expected a value %v %v within %v", query, values, operator, expectedValue, within)
+		}
+		log.Infof("prometheus query '%v' returned %v, waiting for a value %v %v", query, values, operator, expectedValue)
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func AlertFiringShouldBe(ctx context.Context, kubeClientset kubernetes.Interface, restConfig *rest.Config, w common.WaiterConfig, alertmanagerURL, alertName string, firing bool, since time.Time) error {
+	endpoint, stopChan, err := GetAlertmanagerEndpoint(kubeClientset, restConfig, alertmanagerURL)
+	if err != nil {
+		return err
+	}
+	if stopChan != nil {
+		defer close(stopChan)
+	}
+	return EndpointAlertFiringShouldBe(ctx, endpoint, w, alertName, firing, since)
+}
+
+// EndpointAlertFiringShouldBe waits for alertName to be firing, or to stop firing, alerts that started before since are ignored
+func EndpointAlertFiringShouldBe(ctx context.Context, endpoint string, w common.WaiterConfig, alertName string, firing bool, since time.Time) error {
+	var counter int
+
+	for {
+		if counter >= w.GetTries() {
+			if firing {
+				return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for alert '%v' to be firing since %v", alertName, since)
+			}
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for alert '%v' to not be firing since %v", alertName, since)
+		}
+		alerts, err := getActiveAlerts(endpoint, alertName)
+		if err != nil {
+			return err
+		}
+		isFiring := false
+		for _, alert := range alerts {
+			if !alert.StartsAt.Before(since) {
+				isFiring = true
+				break
+			}
+		}
+		if isFiring == firing {
+			log.Infof("alert '%v' firing since %v: %v", alertName, since, isFiring)
+			return nil
+		}
+		log.Infof("waiting for alert '%v' firing since %v to be %v", alertName, since, firing)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheus
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/structured"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// the service created by prometheus-operator for every Prometheus instance
+	DefaultServiceName      = "prometheus-operated"
+	DefaultServiceNamespace = "monitoring"
+	DefaultServicePort      = 9090
+
+	// the service created by prometheus-operator for every Alertmanager instance
+	DefaultAlertmanagerServiceName = "alertmanager-operated"
+	DefaultAlertmanagerServicePort = 9093
+
+	alertStateActive = "active"
+
+	resultTypeVector = "vector"
+	resultTypeScalar = "scalar"
+)
+
+type queryResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+type vectorSample struct {
+	Metric map[string]string `json:"metric"`
+	Value  []interface{}     `json:"value"`
+}
+
+type alert struct {
+	Labels   map[string]string `json:"labels"`
+	StartsAt time.Time         `json:"startsAt"`
+	Status   struct {
+		State string `json:"state"`
+	} `json:"status"`
+}
+
+// GetPrometheusEndpoint returns prometheusURL when set, otherwise it port-forwards to the default prometheus service,
+// the returned channel is nil when no port-forward was started.
+func GetPrometheusEndpoint(kubeClientset kubernetes.Interface, restConfig *rest.Config, prometheusURL string) (string, chan struct{}, error) {
+	if prometheusURL != "" {
+		return strings.TrimSuffix(prometheusURL, "/"), nil, nil
+	}
+	return structured.GetServiceEndpoint(kubeClientset, restConfig, DefaultServiceName, DefaultServiceNamespace, DefaultServicePort, "")
+}
+
+// GetAlertmanagerEndpoint returns alertmanagerURL when set, otherwise it port-forwards to the default alertmanager service,
+// the returned channel is nil when no port-forward was started.
+func GetAlertmanagerEndpoint(kubeClientset kubernetes.Interface, restConfig *rest.Config, alertmanagerURL string) (string, chan struct{}, error) {
+	if alertmanagerURL != "" {
+		return strings.TrimSuffix(alertmanagerURL, "/"), nil, nil
+	}
+	return structured.GetServiceEndpoint(kubeClientset, restConfig, DefaultAlertmanagerServiceName, DefaultServiceNamespace, DefaultAlertmanagerServicePort, "")
+}
+
+func getActiveAlerts(endpoint, alertName string) ([]alert, error) {
+	client := http.Client{
+		Timeout: 10 * time.Second,
+	}
+	params := url.Values{
+		"filter":    []string{fmt.Sprintf("alertname=%q", alertName)},
+		"active":    []string{"true"},
+		"silenced":  []string{"false"},
+		"inhibited": []string{"false"},
+	}
+	resp, err := client.Get(fmt.Sprintf("%v/api/v2/alerts?%v", endpoint, params.Encode()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get alerts from alertmanager at %v", endpoint)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get alerts from alertmanager at %v, status code %d", endpoint, resp.StatusCode)
+	}
+
+	var alerts []alert
+	if err := json.NewDecoder(resp.Body).Decode(&alerts); err != nil {
+		return nil, errors.Wrap(err, "failed to decode alertmanager response")
+	}
+	active := make([]alert, 0, len(alerts))
+	for _, a := range alerts {
+		if a.Labels["alertname"] == alertName && a.Status.State == alertStateActive {
+			active = append(active, a)
+		}
+	}
+	return active, nil
+}
+
+func queryPrometheus(endpoint, query string) ([]float64, error) {
+	client := http.Client{
+		Timeout: 10 * time.Second,
+	}
+	queryURL := fmt.Sprintf("%v/api/v1/query?%v", endpoint, url.Values{"query": []string{query}}.Encode())
+	resp, err := client.Get(queryURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query prometheus at %v", endpoint)
+	}
+	defer resp.Body.Close()
+
+	response := queryResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode prometheus response, status code %d", resp.StatusCode)
+	}
+	if response.Status != "success" {
+		return nil, errors.Errorf("prometheus query '%v' failed: %v: %v", query, response.ErrorType, response.Error)
+	}
+	return parseQueryResult(response.Data.ResultType, response.Data.Result)
+}
+
+func parseQueryResult(resultType string, result json.RawMessage) ([]float64, error) {
+	switch resultType {
+	case resultTypeScalar:
+		var sample []interface{}
+		if err := json.Unmarshal(result, &sample); err != nil {
+			return nil, err
+		}
+		value, err := parseSampleValue(sample)
+		if err != nil {
+			return nil, err
+		}
+		return []float64{value}, nil
+	case resultTypeVector:
+		var samples []vectorSample
+		if err := json.Unmarshal(result, &samples); err != nil {
+			return nil, err
+		}
+		values := make([]float64, 0, len(samples))
+		for _, sample := range samples {
+			value, err := parseSampleValue(sample.Value)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	default:
+		return nil, common.Errorf(common.ErrUnsupportedOperation, "unsupported prometheus result type: '%s'", resultType)
+	}
+}
+
+// parseSampleValue parses a [<unix timestamp>, "<value>"] sample
+func parseSampleValue(sample []interface{}) (float64, error) {
+	if len(sample) != 2 {
+		return 0, errors.Errorf("invalid prometheus sample: %v", sample)
+	}
+	value, ok := sample[1].(string)
+	if !ok {
+		return 0, errors.Errorf("invalid prometheus sample value: %v", sample[1])
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// valuesSatisfy is true when the query returned at least one value and every value satisfies the comparison
+func valuesSatisfy(values []float64, operator string, expectedValue float64) (bool, error) {
+	if len(values) == 0 {
+		return false, nil
+	}
+	for _, value := range values {
+		ok, err := compareValue(value, operator, expectedValue)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func compareValue(value float64, operator string, expectedValue float64) (bool, error) {
+	switch operator {
+	case "<":
+		return value < expectedValue, nil
+	case "<=":
+		return value <= expectedValue, nil
+	case ">":
+		return value > expectedValue, nil
+	case ">=":
+		return value >= expectedValue, nil
+	case "==":
+		return value == expectedValue, nil
+	case "!=":
+		return value != expectedValue, nil
+	default:
+		return false, common.Errorf(common.ErrUnsupportedOperation, "unsupported operator: '%s'", operator)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+)
+
+func TestEndpointQueryShouldReturnValue(t *testing.T) {
+	const (
+		vectorResponse = `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"pod":"a"},"value":[1700000000.1,"3"]},{"metric":{"pod":"b"},"value":[1700000000.1,"5"]}]}}`
+		scalarResponse = `{"status":"success","data":{"resultType":"scalar","result":[1700000000.1,"0.5"]}}`
+		emptyResponse  = `{"status":"success","data":{"resultType":"vector","result":[]}}`
+		matrixResponse = `{"status":"success","data":{"resultType":"matrix","result":[]}}`
+		errorResponse  = `{"status":"error","errorType":"bad_data","error":"parse error"}`
+	)
+	tests := []struct {
+		name          string
+		response      string
+		operator      string
+		expectedValue float64
+		within        string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: all vector values satisfy",
+			response:      vectorResponse,
+			operator:      ">=",
+			expectedValue: 3,
+			within:        "1ms",
+		},
+		{
+			name:          "Positive Test: scalar",
+			response:      scalarResponse,
+			operator:      "<",
+			expectedValue: 1,
+			within:        "1ms",
+		},
+		{
+			name:          "Negative Test: some vector value does not satisfy",
+			response:      vectorResponse,
+			operator:      ">",
+			expectedValue: 3,
+			within:        "1ms",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: empty result",
+			response:      emptyResponse,
+			operator:      "==",
+			expectedValue: 0,
+			within:        "1ms",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: unsupported result type",
+			response:      matrixResponse,
+			operator:      "==",
+			expectedValue: 0,
+			within:        "1ms",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: query error",
+			response:      errorResponse,
+			operator:      "==",
+			expectedValue: 0,
+			within:        "1ms",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: unsupported operator",
+			response:      vectorResponse,
+			operator:      "=~",
+			expectedValue: 0,
+			within:        "1ms",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid duration",
+			response:      vectorResponse,
+			operator:      ">",
+			expectedValue: 0,
+			within:        "soon",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/query" || r.URL.Query().Get("query") == "" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				fmt.Fprint(w, tt.response)
+			}))
+			defer server.Close()
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := EndpointQueryShouldReturnValue(context.Background(), server.URL, w, `sum(up{job="kubelet"})`, tt.operator, tt.expectedValue, tt.within); (err != nil) != tt.wantErr {
+				t.Errorf("EndpointQueryShouldReturnValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetPrometheusEndpoint(t *testing.T) {
+	endpoint, stopChan, err := GetPrometheusEndpoint(nil, nil, "http://prometheus.example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stopChan != nil {
+		t.Errorf("GetPrometheusEndpoint() expected no port-forward for a configured URL")
+	}
+	if want := "http://prometheus.example.com"; endpoint != want {
+		t.Errorf("GetPrometheusEndpoint() = %v, want %v", endpoint, want)
+	}
+	if _, _, err := GetPrometheusEndpoint(nil, nil, ""); err == nil {
+		t.Errorf("GetPrometheusEndpoint() expected error without a rest config")
+	}
+}
+
+func TestEndpointAlertFiringShouldBe(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	newAlertsResponse := func(startsAt time.Time, state string) string {
+		return fmt.Sprintf(`[{"labels":{"alertname":"KubeNodeNotReady"},"startsAt":%q,"status":{"state":%q}}]`, startsAt.Format(time.RFC3339), state)
+	}
+	tests := []struct {
+		name     string
+		response string
+		firing   bool
+		wantErr  bool
+	}{
+		{
+			name:     "Positive Test: alert firing since timestamp",
+			response: newAlertsResponse(since.Add(time.Minute), alertStateActive),
+			firing:   true,
+		},
+		{
+			name:     "Positive Test: alert not firing",
+			response: `[]`,
+			firing:   false,
+		},
+		{
+			name:     "Positive Test: alert started before timestamp is ignored",
+			response: newAlertsResponse(since.Add(-time.Minute), alertStateActive),
+			firing:   false,
+		},
+		{
+			name:     "Negative Test: alert suppressed",
+			response: newAlertsResponse(since.Add(time.Minute), "suppressed"),
+			firing:   true,
+			wantErr:  true,
+		},
+		{
+			name:     "Negative Test: alert firing",
+			response: newAlertsResponse(since.Add(time.Minute), alertStateActive),
+			firing:   false,
+			wantErr:  true,
+		},
+		{
+			name:     "Negative Test: invalid response",
+			response: `{}`,
+			firing:   true,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v2/alerts" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				fmt.Fprint(w, tt.response)
+			}))
+			defer server.Close()
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := EndpointAlertFiringShouldBe(context.Background(), server.URL, w, "KubeNodeNotReady", tt.firing, since); (err != nil) != tt.wantErr {
+				t.Errorf("EndpointAlertFiringShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"context"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/dynamic"
+)
+
+func RolloutShouldBe(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, name, namespace, phase string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+	if !isSupportedPhase(phase) {
+		return common.Errorf(common.ErrUnsupportedOperation, "unsupported rollout phase: '%s'", phase)
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for rollout %v/%v to be %v", namespace, name, phase)
+		}
+		log.Infof("waiting for rollout %v/%v to be %v", namespace, name, phase)
+		rollout, err := getRollout(dynamicClient, name, namespace)
+		if err != nil {
+			return err
+		}
+		currentPhase, message := getPhase(rollout)
+		if currentPhase == phase {
+			log.Infof("rollout %v/%v is %v", namespace, name, phase)
+			return nil
+		}
+		log.Infof("rollout %v/%v is %v: '%v'", namespace, name, currentPhase, message)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func RolloutOperation(dynamicClient dynamic.Interface, operation, name, namespace string) error {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	switch operation {
+	case OperationPromote:
+		if err := patchRollout(dynamicClient, name, namespace, unpausePatch); err != nil {
+			return errors.Wrapf(err, "failed to promote rollout %v/%v", namespace, name)
+		}
+		if err := patchRolloutStatus(dynamicClient, name, namespace, clearPauseConditionsPatch); err != nil {
+			return errors.Wrapf(err, "failed to promote rollout %v/%v", namespace, name)
+		}
+	case OperationAbort:
+		if err := patchRolloutStatus(dynamicClient, name, namespace, abortPatch); err != nil {
+			return errors.Wrapf(err, "failed to abort rollout %v/%v", namespace, name)
+		}
+	case OperationPause:
+		if err := patchRollout(dynamicClient, name, namespace, pausePatch); err != nil {
+			return errors.Wrapf(err, "failed to pause rollout %v/%v", namespace, name)
+		}
+	case OperationResume:
+		if err := patchRollout(dynamicClient, name, namespace, unpausePatch); err != nil {
+			return errors.Wrapf(err, "failed to resume rollout %v/%v", namespace, name)
+		}
+	default:
+		return common.Errorf(common.ErrUnsupportedOperation, "unsupported operation: '%s'", operation)
+	}
+	log.Infof("rollout %v/%v %s operation was successful", namespace, name, operation)
+	return nil
+}
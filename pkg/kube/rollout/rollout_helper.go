@@ -0,0 +1,89 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	OperationPromote = "promote"
+	OperationAbort   = "abort"
+	OperationPause   = "pause"
+	OperationResume  = "resume"
+
+	PhaseHealthy     = "Healthy"
+	PhaseProgressing = "Progressing"
+	PhasePaused      = "Paused"
+	PhaseDegraded    = "Degraded"
+
+	// the same patches 'kubectl argo rollouts' applies to promote, abort and pause
+	pausePatch                = `{"spec":{"paused":true}}`
+	unpausePatch              = `{"spec":{"paused":false}}`
+	clearPauseConditionsPatch = `{"status":{"pauseConditions":null}}`
+	abortPatch                = `{"status":{"abort":true}}`
+)
+
+var RolloutResource = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}
+
+func getRollout(dynamicClient dynamic.Interface, name, namespace string) (*unstructured.Unstructured, error) {
+	rollout, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return dynamicClient.Resource(RolloutResource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get rollout '%v'", name)
+	}
+	return rollout.(*unstructured.Unstructured), nil
+}
+
+func patchRollout(dynamicClient dynamic.Interface, name, namespace, patch string) error {
+	_, err := dynamicClient.Resource(RolloutResource).Namespace(namespace).Patch(context.Background(), name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+func patchRolloutStatus(dynamicClient dynamic.Interface, name, namespace, patch string) error {
+	_, err := dynamicClient.Resource(RolloutResource).Namespace(namespace).Patch(context.Background(), name, types.MergePatchType, []byte(patch), metav1.PatchOptions{}, "status")
+	return err
+}
+
+// getPhase returns the phase and message of the rollout status, a rollout whose spec was not yet observed by the controller is 'Progressing'
+func getPhase(rollout *unstructured.Unstructured) (string, string) {
+	observedGeneration, found, _ := unstructured.NestedFieldNoCopy(rollout.Object, "status", "observedGeneration")
+	if found && fmt.Sprint(observedGeneration) != fmt.Sprint(rollout.GetGeneration()) {
+		return PhaseProgressing, "waiting for rollout spec update to be observed"
+	}
+	phase, _, _ := unstructured.NestedString(rollout.Object, "status", "phase")
+	message, _, _ := unstructured.NestedString(rollout.Object, "status", "message")
+	return phase, message
+}
+
+func isSupportedPhase(phase string) bool {
+	switch phase {
+	case PhaseHealthy, PhaseProgressing, PhasePaused, PhaseDegraded:
+		return true
+	default:
+		return false
+	}
+}
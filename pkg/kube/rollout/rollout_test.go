@@ -0,0 +1,190 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/internal/testutil"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	rolloutName = "rollout1"
+	namespace   = "namespace1"
+)
+
+func TestRolloutShouldBe(t *testing.T) {
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		phase         string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: rollout healthy",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newRollout(PhaseHealthy, 1, 1)),
+			phase:         PhaseHealthy,
+		},
+		{
+			name:          "Positive Test: rollout paused",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newRollout(PhasePaused, 1, 1)),
+			phase:         PhasePaused,
+		},
+		{
+			name:          "Negative Test: rollout degraded",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newRollout(PhaseDegraded, 1, 1)),
+			phase:         PhaseHealthy,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: spec update not observed",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newRollout(PhaseHealthy, 2, 1)),
+			phase:         PhaseHealthy,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: unsupported phase",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newRollout(PhaseHealthy, 1, 1)),
+			phase:         "Running",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: rollout not found",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources),
+			phase:         PhaseHealthy,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid client",
+			dynamicClient: nil,
+			phase:         PhaseHealthy,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RolloutShouldBe(context.Background(), tt.dynamicClient, common.NewWaiterConfig(1, time.Millisecond), rolloutName, namespace, tt.phase); (err != nil) != tt.wantErr {
+				t.Errorf("RolloutShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRolloutOperation(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation string
+		validate  func(t *testing.T, rollout *unstructured.Unstructured)
+		wantErr   bool
+	}{
+		{
+			name:      "Positive Test: promote",
+			operation: OperationPromote,
+			validate: func(t *testing.T, rollout *unstructured.Unstructured) {
+				if paused, _, _ := unstructured.NestedBool(rollout.Object, "spec", "paused"); paused {
+					t.Errorf("expected rollout to be unpaused")
+				}
+				if _, found, _ := unstructured.NestedSlice(rollout.Object, "status", "pauseConditions"); found {
+					t.Errorf("expected rollout pause conditions to be cleared")
+				}
+			},
+		},
+		{
+			name:      "Positive Test: abort",
+			operation: OperationAbort,
+			validate: func(t *testing.T, rollout *unstructured.Unstructured) {
+				if abort, _, _ := unstructured.NestedBool(rollout.Object, "status", "abort"); !abort {
+					t.Errorf("expected rollout to be aborted")
+				}
+			},
+		},
+		{
+			name:      "Positive Test: pause",
+			operation: OperationPause,
+			validate: func(t *testing.T, rollout *unstructured.Unstructured) {
+				if paused, _, _ := unstructured.NestedBool(rollout.Object, "spec", "paused"); !paused {
+					t.Errorf("expected rollout to be paused")
+				}
+			},
+		},
+		{
+			name:      "Positive Test: resume",
+			operation: OperationResume,
+			validate: func(t *testing.T, rollout *unstructured.Unstructured) {
+				if paused, _, _ := unstructured.NestedBool(rollout.Object, "spec", "paused"); paused {
+					t.Errorf("expected rollout to be resumed")
+				}
+			},
+		},
+		{
+			name:      "Negative Test: unsupported operation",
+			operation: "restart",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := testutil.NewFakeDynamicClient(t, fakeResources, newPausedRollout())
+			if err := RolloutOperation(dynamicClient, tt.operation, rolloutName, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("RolloutOperation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.validate == nil {
+				return
+			}
+			rollout, err := getRollout(dynamicClient, rolloutName, namespace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.validate(t, rollout)
+		})
+	}
+}
+
+// fakeResources are the resources of the kinds tracked by the fake dynamic client
+var fakeResources = map[string]schema.GroupVersionResource{
+	"Rollout": RolloutResource,
+}
+
+func newRollout(phase string, generation, observedGeneration int64) *unstructured.Unstructured {
+	rollout := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata": map[string]interface{}{
+			"name":      rolloutName,
+			"namespace": namespace,
+		},
+		"status": map[string]interface{}{
+			"phase":              phase,
+			"observedGeneration": observedGeneration,
+		},
+	}}
+	rollout.SetGeneration(generation)
+	return rollout
+}
+
+func newPausedRollout() *unstructured.Unstructured {
+	rollout := newRollout(PhasePaused, 1, 1)
+	_ = unstructured.SetNestedField(rollout.Object, true, "spec", "paused")
+	_ = unstructured.SetNestedSlice(rollout.Object, []interface{}{
+		map[string]interface{}{"reason": "CanaryPauseStep"},
+	}, "status", "pauseConditions")
+	return rollout
+}
@@ -15,12 +15,18 @@ limitations under the License.
 package structured
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"math/rand"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -30,15 +36,24 @@ import (
 	"github.com/keikoproj/kubedog/pkg/kube/pod"
 	"github.com/pkg/errors"
 	vegeta "github.com/tsenart/vegeta/v12/lib"
+	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 func NodesWithSelectorShouldBe(kubeClientset kubernetes.Interface, w common.WaiterConfig, expectedNodes int, labelSelector, state string) error {
+	return NodesWithSelectorShouldBeCtx(context.Background(), kubeClientset, w, expectedNodes, labelSelector, state)
+}
+
+// NodesWithSelectorShouldBeCtx is NodesWithSelectorShouldBe, returning early when the context is done
+func NodesWithSelectorShouldBeCtx(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, expectedNodes int, labelSelector, state string) error {
 	var (
 		counter int
 		found   bool
@@ -57,10 +72,10 @@ func NodesWithSelectorShouldBe(kubeClientset kubernetes.Interface, w common.Wait
 		)
 
 		if counter >= w.GetTries() {
-			return errors.New("waiter timed out waiting for nodes")
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for nodes")
 		}
 
-		nodes, err := kubeClientset.CoreV1().Nodes().List(context.Background(), opts)
+		nodes, err := kubeClientset.CoreV1().Nodes().List(ctx, opts)
 		if err != nil {
 			return err
 		}
@@ -91,11 +106,212 @@ func NodesWithSelectorShouldBe(kubeClientset kubernetes.Interface, w common.Wait
 		log.Infof("found %v nodes, waiting for %v nodes to be %v with selector %v", nodesCount, expectedNodes, state, labelSelector)
 
 		counter++
-		time.Sleep(w.GetInterval())
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NodeCordon marks the node unschedulable
+func NodeCordon(kubeClientset kubernetes.Interface, nodeName string) error {
+	return setNodeUnschedulable(kubeClientset, nodeName, true)
+}
+
+// NodeUncordon marks the node schedulable
+func NodeUncordon(kubeClientset kubernetes.Interface, nodeName string) error {
+	return setNodeUnschedulable(kubeClientset, nodeName, false)
+}
+
+// NodeDrain cordons the node and evicts its pods, other than DaemonSet and mirror pods, like 'kubectl drain --ignore-daemonsets';
+// evictions refused by a PodDisruptionBudget are retried until the pods are deleted. The grace period is in seconds, a
+// negative grace period uses the one of each pod
+func NodeDrain(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, nodeName string, gracePeriod int) error {
+	var counter int
+
+	if err := NodeCordon(kubeClientset, nodeName); err != nil {
+		return err
+	}
+	deleteOptions := &metav1.DeleteOptions{}
+	if gracePeriod >= 0 {
+		gracePeriodSeconds := int64(gracePeriod)
+		deleteOptions.GracePeriodSeconds = &gracePeriodSeconds
+	}
+	for {
+		pods, err := getPodsToDrain(ctx, kubeClientset, nodeName)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			log.Infof("node %v is drained", nodeName)
+			return nil
+		}
+		var pending []string
+		for _, pod := range pods {
+			eviction := &policyv1.Eviction{
+				ObjectMeta:    metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+				DeleteOptions: deleteOptions,
+			}
+			err := kubeClientset.CoreV1().Pods(pod.Namespace).EvictV1(ctx, eviction)
+			switch {
+			case err == nil, kerrors.IsNotFound(err):
+			case kerrors.IsTooManyRequests(err):
+				log.Infof("eviction of pod %v/%v is refused by a PodDisruptionBudget", pod.Namespace, pod.Name)
+			default:
+				return errors.Wrapf(err, "failed to evict pod %v/%v", pod.Namespace, pod.Name)
+			}
+			pending = append(pending, fmt.Sprintf("%v/%v", pod.Namespace, pod.Name))
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for node %v to be drained, pods not deleted: %v", nodeName, strings.Join(pending, ", "))
+		}
+		log.Infof("waiting for node %v to be drained, pods not deleted: %v", nodeName, strings.Join(pending, ", "))
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// NodeOperationWithSelector cordons, uncordons or drains every node with the selector, see NodeDrain for the grace period
+func NodeOperationWithSelector(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, operation, selector string, gracePeriod int) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+	nodes, err := kubeClientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list nodes with selector '%v'", selector)
+	}
+	if len(nodes.Items) == 0 {
+		return common.Errorf(common.ErrResourceNotFound, "no nodes matched selector '%v'", selector)
+	}
+	for _, node := range nodes.Items {
+		switch operation {
+		case nodeOperationCordon:
+			err = NodeCordon(kubeClientset, node.Name)
+		case nodeOperationUncordon:
+			err = NodeUncordon(kubeClientset, node.Name)
+		case nodeOperationDrain:
+			err = NodeDrain(ctx, kubeClientset, w, node.Name, gracePeriod)
+		default:
+			return common.Errorf(common.ErrUnsupportedOperation, "unsupported node operation: '%s'", operation)
+		}
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// NodeTaintWithSelector adds or removes the taint, formatted as key=value:effect or key:effect, on every node with the
+// selector; an added taint replaces the value of a taint with the same key and effect, a removed one matches the key and effect
+func NodeTaintWithSelector(kubeClientset kubernetes.Interface, operation, selector, taint string) error {
+	t, err := parseTaint(taint)
+	if err != nil {
+		return err
+	}
+	var mutate func(*corev1.Node) bool
+	switch operation {
+	case nodeOperationTaint:
+		mutate = func(node *corev1.Node) bool {
+			for i, existing := range node.Spec.Taints {
+				if existing.MatchTaint(&t) {
+					if existing.Value == t.Value {
+						return false
+					}
+					node.Spec.Taints[i].Value = t.Value
+					return true
+				}
+			}
+			node.Spec.Taints = append(node.Spec.Taints, t)
+			return true
+		}
+	case nodeOperationUntaint:
+		mutate = func(node *corev1.Node) bool {
+			var taints []corev1.Taint
+			for _, existing := range node.Spec.Taints {
+				if !existing.MatchTaint(&t) {
+					taints = append(taints, existing)
+				}
+			}
+			if len(taints) == len(node.Spec.Taints) {
+				return false
+			}
+			node.Spec.Taints = taints
+			return true
+		}
+	default:
+		return common.Errorf(common.ErrUnsupportedOperation, "unsupported node taint operation: '%s'", operation)
+	}
+	return updateNodesWithSelector(kubeClientset, selector, mutate)
+}
+
+// NodeLabelWithSelector adds the label, formatted as key=value, or removes the label key from every node with the selector
+func NodeLabelWithSelector(kubeClientset kubernetes.Interface, operation, selector, label string) error {
+	key, value, hasValue := strings.Cut(label, "=")
+	if key == "" {
+		return errors.Errorf("invalid label '%v', expected key=value", label)
+	}
+	var mutate func(*corev1.Node) bool
+	switch operation {
+	case nodeOperationLabel:
+		if !hasValue {
+			return errors.Errorf("invalid label '%v', expected key=value", label)
+		}
+		mutate = func(node *corev1.Node) bool {
+			if existing, ok := node.Labels[key]; ok && existing == value {
+				return false
+			}
+			if node.Labels == nil {
+				node.Labels = map[string]string{}
+			}
+			node.Labels[key] = value
+			return true
+		}
+	case nodeOperationUnlabel:
+		mutate = func(node *corev1.Node) bool {
+			if _, ok := node.Labels[key]; !ok {
+				return false
+			}
+			delete(node.Labels, key)
+			return true
+		}
+	default:
+		return common.Errorf(common.ErrUnsupportedOperation, "unsupported node label operation: '%s'", operation)
+	}
+	return updateNodesWithSelector(kubeClientset, selector, mutate)
+}
+
+// NodeHasTaint waits until every node with the selector has, or does not have, the taint formatted as key=value:effect or
+// key:effect, the value must match as well when it is given
+func NodeHasTaint(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, selector, taint string, expected bool) error {
+	t, err := parseTaint(taint)
+	if err != nil {
+		return err
+	}
+	return nodesWithSelectorShouldMatch(ctx, kubeClientset, w, selector, "taint "+taint, expected, func(node corev1.Node) bool {
+		for _, existing := range node.Spec.Taints {
+			if existing.MatchTaint(&t) && (t.Value == "" || existing.Value == t.Value) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// NodeHasLabel waits until every node with the selector has, or does not have, the label formatted as key=value or key,
+// only the key is checked when no value is given
+func NodeHasLabel(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, selector, label string, expected bool) error {
+	key, value, hasValue := strings.Cut(label, "=")
+	if key == "" {
+		return errors.Errorf("invalid label '%v', expected key=value or key", label)
+	}
+	return nodesWithSelectorShouldMatch(ctx, kubeClientset, w, selector, "label "+label, expected, func(node corev1.Node) bool {
+		existing, ok := node.Labels[key]
+		return ok && (!hasValue || existing == value)
+	})
+}
+
 func ScaleDeployment(kubeClientset kubernetes.Interface, name, namespace string, replicas int32) error {
 	if err := common.ValidateClientset(kubeClientset); err != nil {
 		return err
@@ -118,6 +334,124 @@ func ScaleDeployment(kubeClientset kubernetes.Interface, name, namespace string,
 	return nil
 }
 
+// SetStatefulSetPartition sets the partition of the rolling update strategy of the statefulset, only the pods with an ordinal
+// greater than or equal to the partition are updated to a new revision
+func SetStatefulSetPartition(kubeClientset kubernetes.Interface, name, namespace string, partition int32) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	sts, err := kubeClientset.AppsV1().StatefulSets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return common.WrapNotFound(err)
+	}
+	sts.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+		Type: appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+			Partition: &partition,
+			// keep the max unavailable of the current strategy
+			MaxUnavailable: getStatefulSetMaxUnavailable(sts),
+		},
+	}
+	if _, err := kubeClientset.AppsV1().StatefulSets(namespace).Update(context.Background(), sts, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	log.Infof("set the partition of statefulset '%s/%s' to %d", namespace, name, partition)
+	return nil
+}
+
+// StatefulSetPartitionShouldBeUpdated waits until the pods of the statefulset with an ordinal greater than or equal to its partition
+// are ready with the update revision, and fails as soon as a pod with a lower ordinal is updated
+func StatefulSetPartitionShouldBeUpdated(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string) error {
+	var counter int
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	for {
+		sts, err := kubeClientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return common.WrapNotFound(err)
+		}
+		pending, err := getStatefulSetPartitionPendingPods(kubeClientset, sts)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			log.Infof("statefulset '%s/%s' updated the pods from partition %d to revision %s", namespace, name, getStatefulSetPartition(sts), sts.Status.UpdateRevision)
+			return nil
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for statefulset '%s/%s' to update the pods from partition %d, pending:\n%s", namespace, name, getStatefulSetPartition(sts), strings.Join(pending, "\n"))
+		}
+		log.Infof("waiting for statefulset '%s/%s' to update the pods from partition %d, pending: %v", namespace, name, getStatefulSetPartition(sts), pending)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// PauseDeployment sets spec.paused of the deployment, changes to the template of a paused deployment are not rolled out
+func PauseDeployment(kubeClientset kubernetes.Interface, name, namespace string, paused bool) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"paused":%t}}`, paused)
+	if _, err := kubeClientset.AppsV1().Deployments(namespace).Patch(context.Background(), name, types.MergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+		return err
+	}
+	log.Infof("set paused of deployment '%s/%s' to %t", namespace, name, paused)
+	return nil
+}
+
+// GetOwnedReplicaSets returns the names of the replicasets owned by the deployment or rollout
+func GetOwnedReplicaSets(kubeClientset kubernetes.Interface, ownerKind, ownerName, namespace string) ([]string, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	replicaSets, err := kubeClientset.AppsV1().ReplicaSets(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	owned := make([]string, 0)
+	for _, rs := range replicaSets.Items {
+		for _, ref := range rs.OwnerReferences {
+			if strings.EqualFold(ref.Kind, ownerKind) && ref.Name == ownerName {
+				owned = append(owned, rs.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(owned)
+	return owned, nil
+}
+
+// ReplicaSetsShouldNotBeCreated validates the deployment or rollout owns no replicaset besides the existing ones
+func ReplicaSetsShouldNotBeCreated(kubeClientset kubernetes.Interface, ownerKind, ownerName, namespace string, existing []string) error {
+	owned, err := GetOwnedReplicaSets(kubeClientset, ownerKind, ownerName, namespace)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(existing))
+	for _, rs := range existing {
+		known[rs] = true
+	}
+	var created []string
+	for _, rs := range owned {
+		if !known[rs] {
+			created = append(created, rs)
+		}
+	}
+	if len(created) > 0 {
+		return errors.Errorf("%s '%s/%s' created the replicasets %v", ownerKind, namespace, ownerName, created)
+	}
+	log.Infof("%s '%s/%s' created no replicasets", ownerKind, namespace, ownerName)
+	return nil
+}
+
 func ClusterRbacIsFound(kubeClientset kubernetes.Interface, resourceType, name string) error {
 	var err error
 	if err := common.ValidateClientset(kubeClientset); err != nil {
@@ -139,6 +473,157 @@ func ClusterRbacIsFound(kubeClientset kubernetes.Interface, resourceType, name s
 	return nil
 }
 
+// ServiceAccountShouldBeAbleTo asks the API server with a SubjectAccessReview whether the service account, as
+// name or namespace/name, is allowed to run the verb on the resource, e.g. pods, pods/log or deployments.apps
+func ServiceAccountShouldBeAbleTo(kubeClientset kubernetes.Interface, serviceAccount, verb, resource, namespace string, allowed bool) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	review, err := newServiceAccountAccessReview(serviceAccount, verb, resource, namespace)
+	if err != nil {
+		return err
+	}
+	user := review.Spec.User
+	review, err = kubeClientset.AuthorizationV1().SubjectAccessReviews().Create(context.Background(), review, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to review access of %v", user)
+	}
+
+	if review.Status.Allowed != allowed {
+		reason := review.Status.Reason
+		if review.Status.EvaluationError != "" {
+			reason = fmt.Sprintf("%v, evaluation error: %v", reason, review.Status.EvaluationError)
+		}
+		return errors.Errorf("expected %v to be allowed to %v %v in namespace '%v' to be %v, but it was %v: %v", user, verb, resource, namespace, allowed, review.Status.Allowed, reason)
+	}
+	log.Infof("%v allowed to %v %v in namespace '%v' is %v", user, verb, resource, namespace, allowed)
+	return nil
+}
+
+// NodeDisruption is a node selected to be disrupted, with what must be healed once it is gone
+type NodeDisruption struct {
+	NodeName       string
+	InstanceID     string
+	selector       string
+	readyNodes     int
+	podControllers map[podController]int
+}
+
+// NewNodeDisruption selects a random ready node with the selector, and counts the ready nodes with the selector
+// and the ready pods of every controller, other than DaemonSets, running pods on the node
+func NewNodeDisruption(kubeClientset kubernetes.Interface, selector string) (*NodeDisruption, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	nodes, err := kubeClientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list nodes with selector '%v'", selector)
+	}
+	var readyNodes []corev1.Node
+	for _, node := range nodes.Items {
+		if isNodeReady(node) {
+			readyNodes = append(readyNodes, node)
+		}
+	}
+	if len(readyNodes) == 0 {
+		return nil, common.Errorf(common.ErrResourceNotFound, "no ready nodes matched selector '%v'", selector)
+	}
+	node := readyNodes[rand.Intn(len(readyNodes))]
+	instanceID, err := getNodeInstanceID(node)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := kubeClientset.CoreV1().Pods(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pods")
+	}
+	podControllers := map[podController]int{}
+	for _, p := range pods.Items {
+		if controller, ok := getPodController(p); ok && p.Spec.NodeName == node.Name {
+			podControllers[controller] = 0
+		}
+	}
+	countReadyPodsOfControllers(pods.Items, podControllers, "")
+
+	log.Infof("selected node %v backed by EC2 instance %v, running pods of %v controllers", node.Name, instanceID, len(podControllers))
+	return &NodeDisruption{
+		NodeName:       node.Name,
+		InstanceID:     instanceID,
+		selector:       selector,
+		readyNodes:     len(readyNodes),
+		podControllers: podControllers,
+	}, nil
+}
+
+// NodeDisruptionShouldBeHealed waits for the disrupted node to be deleted, for the ready nodes with its selector to be
+// as many as before, and for the controllers that ran pods on it to have as many ready pods as before on other nodes
+func NodeDisruptionShouldBeHealed(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, d *NodeDisruption) error {
+	var counter int
+
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	for {
+		healed, reason, err := d.isHealed(kubeClientset)
+		if err != nil {
+			return err
+		}
+		if healed {
+			log.Infof("node %v was replaced and its pods were rescheduled", d.NodeName)
+			return nil
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for node %v to be replaced: %v", d.NodeName, reason)
+		}
+		log.Infof("waiting for node %v to be replaced: %v", d.NodeName, reason)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// ResourceQuotaUsageShouldBe waits for the used amount of the resource in the quota to compare with the expected value,
+// either a quantity like 2Gi or a percentage of the hard limit like 80% of hard
+func ResourceQuotaUsageShouldBe(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, resourceName, name, namespace, operator, expectedValue string) error {
+	var counter int
+
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	for {
+		quota, err := kubeClientset.CoreV1().ResourceQuotas(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(common.WrapNotFound(err), "failed to get resourcequota %v/%v", namespace, name)
+		}
+		used, expected, err := getResourceQuotaUsage(quota, corev1.ResourceName(resourceName), expectedValue)
+		if err != nil {
+			return err
+		}
+		ok, err := compareQuantities(used, operator, expected)
+		if err != nil {
+			return err
+		}
+		if ok {
+			log.Infof("used %v of resourcequota %v/%v is %v, %v %v", resourceName, namespace, name, used.String(), operator, expected.String())
+			return nil
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "expected used %v of resourcequota %v/%v to be %v %v, but it is %v", resourceName, namespace, name, operator, expectedValue, used.String())
+		}
+		log.Infof("waiting for used %v of resourcequota %v/%v to be %v %v, it is %v", resourceName, namespace, name, operator, expectedValue, used.String())
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
 func ListNodes(kubeClientset kubernetes.Interface) error {
 
 	var readyStatus = func(conditions []corev1.NodeCondition) string {
@@ -174,25 +659,46 @@ func ListNodes(kubeClientset kubernetes.Interface) error {
 	return nil
 }
 
+// DaemonSetIsRunning waits until the daemonset is rolled out like DaemonSetRolloutComplete, the steps and the initial
+// duration of the backoff are used as the tries and the interval of the waiter. The waiter polls at a fixed interval, so
+// the factor, the jitter and the cap of the backoff are ignored
 func DaemonSetIsRunning(kubeClientset kubernetes.Interface, expBackoff wait.Backoff, name, namespace string) error {
-	err := util.RetryOnAnyError(&expBackoff, func() error {
+	return DaemonSetRolloutComplete(kubeClientset, common.NewWaiterConfig(expBackoff.Steps, expBackoff.Duration), name, namespace)
+}
+
+// DaemonSetRolloutComplete waits until the daemonset is rolled out: its latest generation is observed and every desired
+// pod is scheduled, updated and available. On timeout it fails with the status of the pod on each node
+func DaemonSetRolloutComplete(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string) error {
+	return DaemonSetRolloutCompleteCtx(context.Background(), kubeClientset, w, name, namespace)
+}
+
+// DaemonSetRolloutCompleteCtx is DaemonSetRolloutComplete, returning early when the context is done
+func DaemonSetRolloutCompleteCtx(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string) error {
+	var counter int
+	for {
 		ds, err := GetDaemonSet(kubeClientset, name, namespace)
 		if err != nil {
 			return err
 		}
 
-		if ds.Status.DesiredNumberScheduled != ds.Status.CurrentNumberScheduled {
-			return fmt.Errorf("daemonset '%s/%s' not updated. status: '%s'", namespace, name, ds.Status.String())
+		message := getDaemonSetRolloutMessage(ds)
+		if message == "" {
+			log.Infof("daemonset '%s/%s' is rolled out", namespace, name)
+			return nil
+		}
+		if counter >= w.GetTries() {
+			podStatuses, err := getDaemonSetPodStatuses(kubeClientset, ds)
+			if err != nil {
+				log.Warnf("failed to get the pods of daemonset '%s/%s': %v", namespace, name, err)
+			}
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for daemonset '%s/%s' to be rolled out, %s, pods:\n%s", namespace, name, message, strings.Join(podStatuses, "\n"))
+		}
+		log.Infof("waiting for daemonset '%s/%s' to be rolled out, %s", namespace, name, message)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
 		}
-
-		return nil
-	})
-	if err != nil {
-		// Print Pods after failure
-		_ = pod.ListPods(kubeClientset, namespace)
-		return fmt.Errorf("daemonset '%s/%s' not updated: '%v'", namespace, name, err)
 	}
-	return nil
 }
 
 func DeploymentIsRunning(kubeClientset kubernetes.Interface, name, namespace string) error {
@@ -211,28 +717,539 @@ func DeploymentIsRunning(kubeClientset kubernetes.Interface, name, namespace str
 	return nil
 }
 
-func ConfigMapDataHasKeyAndValue(kubeClientset kubernetes.Interface, configMapName, namespace, key, value string) error {
-
-	currentData, err := GetConfigMap(kubeClientset, configMapName, namespace)
+// StatefulSetIsRunning validates the statefulset is rolled out: its latest generation is observed, every pod is ready
+// and the pods are updated to the update revision
+func StatefulSetIsRunning(kubeClientset kubernetes.Interface, name, namespace string) error {
+	sts, err := GetStatefulSet(kubeClientset, name, namespace)
 	if err != nil {
 		return err
 	}
-	if currentData.Data[key] != value {
-		return fmt.Errorf("configMap %s/%s does not have the expected data", namespace, configMapName)
+	if message := getStatefulSetRolloutMessage(sts); message != "" {
+		return errors.Errorf("statefulset %s/%s is not rolled out, %s", namespace, name, message)
 	}
-
 	return nil
 }
 
-func PersistentVolExists(kubeClientset kubernetes.Interface, name, expectedPhase string) error {
-	vol, err := GetPersistentVolume(kubeClientset, name)
-	if err != nil {
-		return err
-	}
-	phase := string(vol.Status.Phase)
-	if phase != expectedPhase {
-		return fmt.Errorf("persistentvolume had unexpected phase %v, expected phase %v", phase, expectedPhase)
-	}
+// StatefulSetRolloutComplete waits until the statefulset is rolled out like StatefulSetIsRunning validates
+func StatefulSetRolloutComplete(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string) error {
+	var counter int
+	for {
+		sts, err := GetStatefulSet(kubeClientset, name, namespace)
+		if err != nil {
+			return err
+		}
+
+		message := getStatefulSetRolloutMessage(sts)
+		if message == "" {
+			log.Infof("statefulset '%s/%s' is rolled out to revision %s", namespace, name, sts.Status.UpdateRevision)
+			return nil
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for statefulset '%s/%s' to be rolled out, %s", namespace, name, message)
+		}
+		log.Infof("waiting for statefulset '%s/%s' to be rolled out, %s", namespace, name, message)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// DeploymentShouldRunImageDigest validates the containers of every pod of the deployment running an image of the repository run the digest.
+func DeploymentShouldRunImageDigest(kubeClientset kubernetes.Interface, name, namespace, repository, digest string) error {
+	deploy, err := GetDeployment(kubeClientset, name, namespace)
+	if err != nil {
+		return err
+	}
+	podList, err := pod.GetPodListWithLabelSelector(kubeClientset, namespace, metav1.FormatLabelSelector(deploy.Spec.Selector))
+	if err != nil {
+		return err
+	}
+	var found int
+	for _, p := range podList.Items {
+		for _, status := range p.Status.ContainerStatuses {
+			if !isImageOfRepository(status.Image, repository) {
+				continue
+			}
+			found++
+			if !strings.HasSuffix(status.ImageID, "@"+digest) {
+				return fmt.Errorf("container %s of pod %s/%s runs image %s, expected digest %s", status.Name, namespace, p.Name, status.ImageID, digest)
+			}
+		}
+	}
+	if found == 0 {
+		return fmt.Errorf("no running container of deployment %s/%s uses an image of repository %s", namespace, name, repository)
+	}
+	return nil
+}
+
+// DeploymentHasContainerImage waits until the container of the deployment and of every pod of the deployment has the image,
+// so an upgrade is known to be rolled out rather than only ready
+func DeploymentHasContainerImage(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, container, image string) error {
+	return workloadHasContainerImage(ctx, kubeClientset, w, "deployment", name, namespace, container, image, func() (*metav1.LabelSelector, *corev1.PodTemplateSpec, error) {
+		deploy, err := GetDeployment(kubeClientset, name, namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		return deploy.Spec.Selector, &deploy.Spec.Template, nil
+	})
+}
+
+// DaemonSetHasContainerImage waits until the container of the daemonset and of every pod of the daemonset has the image
+func DaemonSetHasContainerImage(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, container, image string) error {
+	return workloadHasContainerImage(ctx, kubeClientset, w, "daemonset", name, namespace, container, image, func() (*metav1.LabelSelector, *corev1.PodTemplateSpec, error) {
+		ds, err := GetDaemonSet(kubeClientset, name, namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		return ds.Spec.Selector, &ds.Spec.Template, nil
+	})
+}
+
+// StatefulSetHasContainerImage waits until the container of the statefulset and of every pod of the statefulset has the image,
+// the pods below the partition of a partitioned rolling update keep the previous image so they never do
+func StatefulSetHasContainerImage(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, container, image string) error {
+	return workloadHasContainerImage(ctx, kubeClientset, w, "statefulset", name, namespace, container, image, func() (*metav1.LabelSelector, *corev1.PodTemplateSpec, error) {
+		sts, err := GetStatefulSet(kubeClientset, name, namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sts.Spec.Selector, &sts.Spec.Template, nil
+	})
+}
+
+func ConfigMapDataHasKeyAndValue(kubeClientset kubernetes.Interface, configMapName, namespace, key, value string) error {
+
+	currentData, err := GetConfigMap(kubeClientset, configMapName, namespace)
+	if err != nil {
+		return err
+	}
+	if currentData.Data[key] != value {
+		return fmt.Errorf("configMap %s/%s does not have the expected data", namespace, configMapName)
+	}
+
+	return nil
+}
+
+// ConfigMapOperationFromLiterals runs the operation on the configmap with a key per literal of the comma separated key=value literals
+func ConfigMapOperationFromLiterals(kubeClientset kubernetes.Interface, operation, name, namespace, literals string) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+	var data map[string][]byte
+	if operation != common.OperationDelete {
+		var err error
+		if data, err = parseLiterals(literals); err != nil {
+			return err
+		}
+	}
+	return configMapOperation(kubeClientset, operation, name, namespace, data)
+}
+
+// ConfigMapOperationFromFiles runs the operation on the configmap with a key per file named after it, including the files of
+// the directories, like 'kubectl create configmap --from-file'; files that are not valid UTF-8 are set as binary data
+func ConfigMapOperationFromFiles(kubeClientset kubernetes.Interface, operation, name, namespace string, paths []string) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+	var data map[string][]byte
+	if operation != common.OperationDelete {
+		var err error
+		if data, err = readFiles(paths); err != nil {
+			return err
+		}
+	}
+	return configMapOperation(kubeClientset, operation, name, namespace, data)
+}
+
+func ConfigMapDelete(kubeClientset kubernetes.Interface, name, namespace string) error {
+	return ConfigMapOperationFromLiterals(kubeClientset, common.OperationDelete, name, namespace, "")
+}
+
+// ConfigMapKeyConvergesTo waits until the key of the configmap has the value, for operators that write their status into configmaps
+func ConfigMapKeyConvergesTo(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, key, value string) error {
+	var counter int
+
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+	for {
+		var state string
+		configMap, err := kubeClientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case kerrors.IsNotFound(err):
+			state = "the configmap is not found"
+		case err != nil:
+			return err
+		default:
+			actual, ok := configMap.Data[key]
+			switch {
+			case !ok:
+				state = "the key is not found"
+			case actual != value:
+				state = fmt.Sprintf("the value is '%v'", actual)
+			default:
+				log.Infof("key %v of configmap %v/%v has the value '%v'", key, namespace, name, value)
+				return nil
+			}
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for key %v of configmap %v/%v to have the value '%v', %v", key, namespace, name, value, state)
+		}
+		log.Infof("waiting for key %v of configmap %v/%v to have the value '%v', %v", key, namespace, name, value, state)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func PersistentVolExists(kubeClientset kubernetes.Interface, name, expectedPhase string) error {
+	vol, err := GetPersistentVolume(kubeClientset, name)
+	if err != nil {
+		return err
+	}
+	phase := string(vol.Status.Phase)
+	if phase != expectedPhase {
+		return fmt.Errorf("persistentvolume had unexpected phase %v, expected phase %v", phase, expectedPhase)
+	}
+	return nil
+}
+
+// StorageClassShouldProvisionVolume creates a claim against the storage class, writes and reads data through a pod mounting it,
+// and validates the bound persistentvolume was dynamically provisioned. The pod and claim are deleted afterwards.
+func StorageClassShouldProvisionVolume(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, storageClassName, namespace string) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("kubedog-storage-test-%d", time.Now().Unix())
+	claim := newStorageTestClaim(name, namespace, storageClassName)
+	if _, err := kubeClientset.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, claim, metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to create persistentvolumeclaim %v/%v", namespace, name)
+	}
+	defer cleanupStorageTest(kubeClientset, name, namespace)
+
+	// the pod is created right away, claims of storage classes with WaitForFirstConsumer binding only bind once scheduled
+	if _, err := kubeClientset.CoreV1().Pods(namespace).Create(ctx, newStorageTestPod(name, namespace, name), metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to create pod %v/%v", namespace, name)
+	}
+	if err := waitForPodCompletion(ctx, kubeClientset, w, name, namespace); err != nil {
+		return err
+	}
+
+	claim, err := GetPersistentVolumeClaim(kubeClientset, name, namespace)
+	if err != nil {
+		return err
+	}
+	if claim.Status.Phase != corev1.ClaimBound || claim.Spec.VolumeName == "" {
+		return errors.Errorf("persistentvolumeclaim %v/%v is not bound, phase %v", namespace, name, claim.Status.Phase)
+	}
+	volume, err := GetPersistentVolume(kubeClientset, claim.Spec.VolumeName)
+	if err != nil {
+		return err
+	}
+	provisioner, ok := volume.Annotations[provisionedByAnnotation]
+	if !ok {
+		return errors.Errorf("persistentvolume %v was not dynamically provisioned", volume.Name)
+	}
+	log.Infof("persistentvolume %v was provisioned by %v for storageclass %v", volume.Name, provisioner, storageClassName)
+	return nil
+}
+
+// ServiceShouldResolveInCluster runs a pod resolving the service DNS name, and validates the records include its ClusterIP
+// unless the service is headless.
+// LimitRangeDefaultsShouldBeApplied creates a pod without resources with a server side dry run and validates its container got
+// the default limits and requests of the limitranges in the namespace
+func LimitRangeDefaultsShouldBeApplied(kubeClientset kubernetes.Interface, namespace string) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	limitRanges, err := kubeClientset.CoreV1().LimitRanges(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list limitranges in namespace %v", namespace)
+	}
+	expected := getLimitRangeDefaults(limitRanges.Items)
+	if len(expected.Limits) == 0 && len(expected.Requests) == 0 {
+		return errors.Errorf("no limitrange in namespace %v has container defaults", namespace)
+	}
+
+	name := fmt.Sprintf("kubedog-limitrange-test-%d", time.Now().Unix())
+	p, err := kubeClientset.CoreV1().Pods(namespace).Create(context.Background(), newLimitRangeTestPod(name, namespace), metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create pod %v/%v", namespace, name)
+	}
+
+	actual := p.Spec.Containers[0].Resources
+	var missing []string
+	missing = append(missing, getMissingResources("limits", expected.Limits, actual.Limits)...)
+	missing = append(missing, getMissingResources("requests", expected.Requests, actual.Requests)...)
+	if len(missing) != 0 {
+		return errors.Errorf("limitrange defaults were not applied to pod %v/%v: %v", namespace, name, strings.Join(missing, ", "))
+	}
+	log.Infof("limitrange defaults were applied to pod %v/%v, limits: %v, requests: %v", namespace, name, actual.Limits, actual.Requests)
+	return nil
+}
+
+// ServiceShouldBeReachable creates a pod in the source namespace that opens a TCP connection to the port of the service,
+// and validates it connects, or that it cannot connect when reachable is false, e.g. because of a NetworkPolicy
+func ServiceShouldBeReachable(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, port int, sourceNamespace string, reachable bool) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	host := fmt.Sprintf("%v.%v.svc.%v", name, namespace, clusterDomain)
+	podName := fmt.Sprintf("kubedog-reachability-test-%d", time.Now().Unix())
+	if _, err := kubeClientset.CoreV1().Pods(sourceNamespace).Create(ctx, newReachabilityTestPod(podName, sourceNamespace, host, port), metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to create pod %v/%v", sourceNamespace, podName)
+	}
+	defer func() {
+		if err := kubeClientset.CoreV1().Pods(sourceNamespace).Delete(context.Background(), podName, metav1.DeleteOptions{}); err != nil {
+			log.Warnf("failed to delete pod %v/%v: %v", sourceNamespace, podName, err)
+		}
+	}()
+
+	p, err := waitForPodTermination(ctx, kubeClientset, w, podName, sourceNamespace)
+	if err != nil {
+		return err
+	}
+	if connected := p.Status.Phase == corev1.PodSucceeded; connected != reachable {
+		return errors.Errorf("expected %v:%v to be reachable from namespace %v to be %v, but it was %v", host, port, sourceNamespace, reachable, connected)
+	}
+	log.Infof("%v:%v reachable from namespace %v is %v", host, port, sourceNamespace, reachable)
+	return nil
+}
+
+// RunJobShouldExitWithCode runs the command with sh in a Job with the image, exports the output of its pod to the artifacts path
+// and validates the exit code of the command
+func RunJobShouldExitWithCode(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, image, command, namespace string, exitCode int, artifactsPath string) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("kubedog-run-%d", time.Now().Unix())
+	if _, err := kubeClientset.BatchV1().Jobs(namespace).Create(ctx, newRunJob(name, namespace, image, command), metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to create job %v/%v", namespace, name)
+	}
+	defer func() {
+		propagationPolicy := metav1.DeletePropagationBackground
+		if err := kubeClientset.BatchV1().Jobs(namespace).Delete(context.Background(), name, metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}); err != nil {
+			log.Warnf("failed to delete job %v/%v: %v", namespace, name, err)
+		}
+	}()
+
+	p, actualExitCode, err := waitForJobPodTermination(ctx, kubeClientset, w, name, namespace)
+	if err != nil {
+		return err
+	}
+	if filePath, err := exportPodLogs(kubeClientset, p, runJobContainerName, artifactsPath); err != nil {
+		log.Warnf("failed to export logs of job %v/%v: %v", namespace, name, err)
+	} else {
+		log.Infof("exported logs of job %v/%v to '%s'", namespace, name, filePath)
+	}
+	if int(actualExitCode) != exitCode {
+		return errors.Errorf("expected job %v/%v to exit with code %d, but it exited with code %d", namespace, name, exitCode, actualExitCode)
+	}
+	log.Infof("job %v/%v exited with code %d", namespace, name, exitCode)
+	return nil
+}
+
+// JobIsCompleted waits for the Job to complete, when it fails or the waiter times out the logs of its pods are exported to the artifacts path
+func JobIsCompleted(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, artifactsPath string) error {
+	_, err := waitForJobCompletion(ctx, kubeClientset, w, name, namespace, artifactsPath)
+	return err
+}
+
+// JobSucceededWithinDuration waits for the Job to complete and validates it ran for at most the duration between its start and completion
+func JobSucceededWithinDuration(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, within time.Duration, artifactsPath string) error {
+	job, err := waitForJobCompletion(ctx, kubeClientset, w, name, namespace, artifactsPath)
+	if err != nil {
+		return err
+	}
+	if job.Status.StartTime == nil || job.Status.CompletionTime == nil {
+		return errors.Errorf("job %v/%v is complete but has no start or completion time", namespace, name)
+	}
+	took := job.Status.CompletionTime.Sub(job.Status.StartTime.Time)
+	if took > within {
+		return errors.Errorf("expected job %v/%v to succeed within %v, but it took %v", namespace, name, within, took)
+	}
+	log.Infof("job %v/%v succeeded in %v", namespace, name, took)
+	return nil
+}
+
+// CronJobHasLastScheduleTime waits for the CronJob to schedule a Job
+func CronJobHasLastScheduleTime(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string) error {
+	var counter int
+
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	for {
+		cronJob, err := kubeClientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(common.WrapNotFound(err), "failed to get cronjob %v/%v", namespace, name)
+		}
+		if cronJob.Status.LastScheduleTime != nil {
+			log.Infof("cronjob %v/%v last scheduled a job at %v", namespace, name, cronJob.Status.LastScheduleTime.Time)
+			return nil
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for cronjob %v/%v with schedule '%v' to schedule a job", namespace, name, cronJob.Spec.Schedule)
+		}
+		log.Infof("waiting for cronjob %v/%v to schedule a job", namespace, name)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// ImagePullShouldBe creates a pod running the image with the image pull secret, and waits for the image to be pulled, so the
+// container is running or terminated, or for the pull to fail when pulled is false
+func ImagePullShouldBe(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, image, secretName, namespace string, pulled bool) error {
+	var counter int
+
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	secret, err := kubeClientset.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(common.WrapNotFound(err), "failed to get secret %v/%v", namespace, secretName)
+	}
+	if secret.Type != corev1.SecretTypeDockerConfigJson && secret.Type != corev1.SecretTypeDockercfg {
+		return errors.Errorf("secret %v/%v is of type %v, not an image pull secret", namespace, secretName, secret.Type)
+	}
+
+	name := fmt.Sprintf("kubedog-image-pull-test-%d", time.Now().Unix())
+	if _, err := kubeClientset.CoreV1().Pods(namespace).Create(ctx, newImagePullTestPod(name, namespace, image, secretName), metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to create pod %v/%v", namespace, name)
+	}
+	defer func() {
+		// the pod is deleted even when the context is done
+		if err := kubeClientset.CoreV1().Pods(namespace).Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil {
+			log.Warnf("failed to delete pod %v/%v: %v", namespace, name, err)
+		}
+	}()
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for pod %v/%v to pull image %v", namespace, name, image)
+		}
+		p, err := kubeClientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return common.WrapNotFound(err)
+		}
+		state, reason := getImagePullState(p)
+		switch {
+		case state == imagePullStatePulled && pulled, state == imagePullStateFailed && !pulled:
+			log.Infof("pod %v/%v using image pull secret %v: %v", namespace, name, secretName, reason)
+			return nil
+		case state != imagePullStatePending:
+			return errors.Errorf("expected image %v to be pulled with secret %v/%v to be %v: %v", image, namespace, secretName, pulled, reason)
+		}
+		log.Infof("waiting for pod %v/%v to pull image %v: %v", namespace, name, image, reason)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func ServiceShouldResolveInCluster(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string) error {
+	service, err := GetService(kubeClientset, name, namespace)
+	if err != nil {
+		return err
+	}
+
+	var expectedAddress string
+	if service.Spec.ClusterIP != corev1.ClusterIPNone {
+		expectedAddress = service.Spec.ClusterIP
+	}
+	hostname := fmt.Sprintf("%v.%v.svc.%v", name, namespace, clusterDomain)
+	podName := fmt.Sprintf("kubedog-dns-test-%d", time.Now().Unix())
+	if _, err := kubeClientset.CoreV1().Pods(namespace).Create(ctx, newDNSTestPod(podName, namespace, hostname, expectedAddress), metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to create pod %v/%v", namespace, podName)
+	}
+	defer func() {
+		if err := kubeClientset.CoreV1().Pods(namespace).Delete(context.Background(), podName, metav1.DeleteOptions{}); err != nil {
+			log.Warnf("failed to delete pod %v/%v: %v", namespace, podName, err)
+		}
+	}()
+
+	if err := waitForPodCompletion(ctx, kubeClientset, w, podName, namespace); err != nil {
+		return errors.Wrapf(err, "failed to resolve %v to '%v'", hostname, expectedAddress)
+	}
+	log.Infof("%v resolved to '%v' from within the cluster", hostname, expectedAddress)
+	return nil
+}
+
+// ServiceHasEndpoints waits until the service has at least minEndpoints ready endpoints, from its EndpointSlices or,
+// when it has none, its Endpoints; unlike ResourceInNamespace this validates the service routes to pods
+func ServiceHasEndpoints(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, minEndpoints int) error {
+	var counter int
+
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+	for {
+		var state string
+		_, err := kubeClientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case kerrors.IsNotFound(err):
+			state = "the service is not found"
+		case err != nil:
+			return err
+		default:
+			readyEndpoints, err := getServiceReadyEndpoints(kubeClientset, name, namespace)
+			if err != nil {
+				return err
+			}
+			if readyEndpoints >= minEndpoints {
+				log.Infof("service %v/%v has %d ready endpoints", namespace, name, readyEndpoints)
+				return nil
+			}
+			state = fmt.Sprintf("%d ready endpoints", readyEndpoints)
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for service %v/%v to have %d ready endpoints, %v", namespace, name, minEndpoints, state)
+		}
+		log.Infof("waiting for service %v/%v to have %d ready endpoints, %v", namespace, name, minEndpoints, state)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// WebhookConfigurationShouldBeReady validates every webhook of the validating or mutating webhook configuration has a CA bundle,
+// and a URL or a service with ready endpoints.
+func WebhookConfigurationShouldBeReady(kubeClientset kubernetes.Interface, webhookType, name string) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	clientConfigs, err := getWebhookClientConfigs(kubeClientset, webhookType, name)
+	if err != nil {
+		return err
+	}
+	for webhookName, clientConfig := range clientConfigs {
+		if len(clientConfig.CABundle) == 0 {
+			return errors.Errorf("webhook %v of %v webhook configuration %v has no caBundle", webhookName, webhookType, name)
+		}
+		if clientConfig.URL != nil {
+			continue
+		}
+		if clientConfig.Service == nil {
+			return errors.Errorf("webhook %v of %v webhook configuration %v has no url or service", webhookName, webhookType, name)
+		}
+		if err := serviceShouldHaveReadyEndpoints(kubeClientset, clientConfig.Service.Name, clientConfig.Service.Namespace); err != nil {
+			return errors.Wrapf(err, "webhook %v of %v webhook configuration %v is not reachable", webhookName, webhookType, name)
+		}
+	}
+	log.Infof("%v webhook configuration %v is ready", webhookType, name)
 	return nil
 }
 
@@ -305,101 +1322,264 @@ func SecretDelete(kubeClientset kubernetes.Interface, name, namespace string) er
 	return SecretOperationFromEnvironmentVariable(kubeClientset, common.OperationDelete, name, namespace, "")
 }
 
-func SecretOperationFromEnvironmentVariable(kubeClientset kubernetes.Interface, operation, name, namespace, environmentVariable string) error {
-	var (
-		secretValue string
-		ok          bool
-	)
+// SecretOperationFromEnvironmentVariable runs the operation on the secret with a key per environment variable, environmentVariables
+// is a comma separated list of names or globs like PREFIX_*
+func SecretOperationFromEnvironmentVariable(kubeClientset kubernetes.Interface, operation, name, namespace, environmentVariables string) error {
 	if err := common.ValidateClientset(kubeClientset); err != nil {
 		return err
 	}
+	var data map[string][]byte
 	if operation != common.OperationDelete {
-		secretValue, ok = os.LookupEnv(environmentVariable)
-		if !ok {
-			return errors.Errorf("couldn't lookup environment variable '%s'", environmentVariable)
+		var err error
+		if data, err = lookupEnvironmentVariables(environmentVariables); err != nil {
+			return err
 		}
 	}
-	switch operation {
-	case common.OperationCreate, common.OperationSubmit:
-		secret := &corev1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: name,
-			},
-			Data: map[string][]byte{
-				environmentVariable: []byte(secretValue),
-			},
-		}
-		_, err := kubeClientset.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
-		if kerrors.IsAlreadyExists(err) {
-			return fmt.Errorf("secret '%s' already created", name)
-		}
-		return err
-	case common.OperationUpdate:
-		currentSecret, err := kubeClientset.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	return secretOperation(kubeClientset, operation, name, namespace, data)
+}
+
+// SecretOperationFromFile runs the operation on the secret with the content of the file as the value of the key, binary content is kept as is
+func SecretOperationFromFile(kubeClientset kubernetes.Interface, operation, name, namespace, key, filePath string) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+	var data map[string][]byte
+	if operation != common.OperationDelete {
+		content, err := os.ReadFile(filePath)
 		if err != nil {
-			return err
+			return errors.Wrapf(err, "couldn't read file '%s'", filePath)
 		}
-		secret := currentSecret.DeepCopy()
-		if len(secret.Data) == 0 {
-			secret.Data = map[string][]byte{}
+		data = map[string][]byte{
+			key: content,
 		}
-		secret.Data[environmentVariable] = []byte(secretValue)
-		_, err = kubeClientset.CoreV1().Secrets(namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+	}
+	return secretOperation(kubeClientset, operation, name, namespace, data)
+}
+
+// SecretOperationFromLiterals runs the operation on the secret with a key per literal of the comma separated key=value literals
+func SecretOperationFromLiterals(kubeClientset kubernetes.Interface, operation, name, namespace, literals string) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
 		return err
-	case common.OperationDelete:
-		err := kubeClientset.CoreV1().Secrets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
-		if kerrors.IsNotFound(err) {
-			log.Infof("secret '%s' was not found", name)
-			return nil
+	}
+	var data map[string][]byte
+	if operation != common.OperationDelete {
+		var err error
+		if data, err = parseLiterals(literals); err != nil {
+			return err
 		}
+	}
+	return secretOperation(kubeClientset, operation, name, namespace, data)
+}
+
+// SecretOperationFromFiles runs the operation on the secret with a key per file named after it, including the files of
+// the directories, like 'kubectl create secret generic --from-file'
+func SecretOperationFromFiles(kubeClientset kubernetes.Interface, operation, name, namespace string, paths []string) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
 		return err
-	default:
-		return fmt.Errorf("unsupported operation: '%s'", operation)
 	}
+	var data map[string][]byte
+	if operation != common.OperationDelete {
+		var err error
+		if data, err = readFiles(paths); err != nil {
+			return err
+		}
+	}
+	return secretOperation(kubeClientset, operation, name, namespace, data)
 }
 
-func IngressAvailable(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, port int, path string) error {
-	var (
-		counter int
-	)
-	endpoint, err := GetIngressEndpoint(kubeClientset, w, name, namespace, port, path)
-	if err != nil {
+// SecretKeyShouldHaveValue waits until the key of the secret has the value, which is base64 encoded when base64Encoded is set,
+// the values are never logged
+func SecretKeyShouldHaveValue(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, key, value string, base64Encoded bool) error {
+	var counter int
+
+	if err := common.ValidateClientset(kubeClientset); err != nil {
 		return err
 	}
+	expected := []byte(value)
+	if base64Encoded {
+		var err error
+		if expected, err = base64.StdEncoding.DecodeString(value); err != nil {
+			return errors.Wrapf(err, "the expected value of key %v of secret %v/%v is not base64 encoded", key, namespace, name)
+		}
+	}
 	for {
-		log.Info("waiting for ingress availability")
-		if counter >= w.GetTries() {
-			return errors.New("waiter timed out waiting for resource state")
+		var state string
+		secret, err := kubeClientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case kerrors.IsNotFound(err):
+			state = "the secret is not found"
+		case err != nil:
+			return err
+		default:
+			actual, ok := secret.Data[key]
+			switch {
+			case !ok:
+				state = "the key is not found"
+			case !bytes.Equal(actual, expected):
+				state = "the value differs"
+			default:
+				log.Infof("key %v of secret %v/%v has the expected value", key, namespace, name)
+				return nil
+			}
 		}
-		log.Infof("waiting for endpoint %v to become available", endpoint)
-		client := http.Client{
-			Timeout: 10 * time.Second,
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for key %v of secret %v/%v to have the expected value, %v", key, namespace, name, state)
 		}
-		req, err := http.NewRequest(http.MethodGet, endpoint, nil)
-		if err != nil {
+		log.Infof("waiting for key %v of secret %v/%v to have the expected value, %v", key, namespace, name, state)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
 			return err
 		}
-		if resp, err := client.Do(req); resp != nil {
-			if resp.StatusCode == 200 {
-				log.Infof("endpoint %v is available", endpoint)
-				time.Sleep(w.GetInterval())
-				return nil
-			}
-		} else {
+	}
+}
+
+func IngressAvailable(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, port int, path string) error {
+	return IngressAvailableCtx(context.Background(), kubeClientset, w, name, namespace, port, path)
+}
+
+// IngressAvailableCtx is IngressAvailable, returning early when the context is done
+func IngressAvailableCtx(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, port int, path string) error {
+	return IngressAvailableWithRequestCtx(ctx, kubeClientset, w, name, namespace, port, path, http.MethodGet, "", "", http.StatusOK)
+}
+
+func IngressAvailableWithRequest(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, port int, path, method, headers, body string, expectedStatusCode int) error {
+	return IngressAvailableWithRequestCtx(context.Background(), kubeClientset, w, name, namespace, port, path, method, headers, body, expectedStatusCode)
+}
+
+// IngressAvailableWithRequestCtx is IngressAvailableWithRequest, returning early when the context is done
+func IngressAvailableWithRequestCtx(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, port int, path, method, headers, body string, expectedStatusCode int) error {
+	header, err := parseHTTPHeaders(headers)
+	if err != nil {
+		return err
+	}
+	endpoint, err := GetIngressEndpointCtx(ctx, kubeClientset, w, name, namespace, port, path)
+	if err != nil {
+		return err
+	}
+	return endpointAvailable(ctx, w, endpoint, HTTPRequest{Method: method, Header: header, Body: body}, expectedStatusCode)
+}
+
+func IngressResponseBodyShould(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, port int, path, operator, expected string) error {
+	check, err := NewResponseBodyCheck(operator, expected)
+	if err != nil {
+		return err
+	}
+	return ingressResponseShould(ctx, kubeClientset, w, name, namespace, port, path, NewHTTPResponseBodyCheck(check))
+}
+
+func IngressResponseBodyJSONPathShouldBe(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, port int, path, jsonPath, expectedValue string) error {
+	check, err := NewResponseBodyJSONPathCheck(jsonPath, expectedValue)
+	if err != nil {
+		return err
+	}
+	return ingressResponseShould(ctx, kubeClientset, w, name, namespace, port, path, NewHTTPResponseBodyCheck(check))
+}
+
+// HTTPRequest is a request sent to an endpoint by SendHTTPRequestCtx and EndpointResponseShouldCtx
+type HTTPRequest struct {
+	Method string
+	Header http.Header
+	Body   string
+	// Timeout of each attempt, defaultHTTPTimeout when not set
+	Timeout time.Duration
+	// InsecureSkipVerify skips verifying the certificate of HTTPS endpoints
+	InsecureSkipVerify bool
+}
+
+// HTTPResponse is the response returned by an endpoint to an HTTPRequest
+type HTTPResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// SendHTTPRequestCtx sends the request to the endpoint until it responds with any status code and returns the response
+func SendHTTPRequestCtx(ctx context.Context, w common.WaiterConfig, endpoint string, request HTTPRequest) (*HTTPResponse, error) {
+	return EndpointResponseShouldCtx(ctx, w, endpoint, request, HTTPResponseCheck{"is received", func(*HTTPResponse) error {
+		return nil
+	}})
+}
+
+// EndpointResponseShouldCtx sends the request to the endpoint until its response passes the check, the last response is
+// returned even when the waiter times out
+func EndpointResponseShouldCtx(ctx context.Context, w common.WaiterConfig, endpoint string, request HTTPRequest, check HTTPResponseCheck) (*HTTPResponse, error) {
+	var (
+		counter  int
+		response *HTTPResponse
+	)
+	for {
+		log.Infof("waiting for the response of endpoint %v to %v request that %v", endpoint, request.Method, check)
+		resp, err := sendHTTPRequest(ctx, endpoint, request)
+		switch {
+		case err != nil:
 			log.Infof("endpoint %v is not available yet: %v", endpoint, err)
+		default:
+			response = resp
+			err = check.Validate(response)
+			if err == nil {
+				log.Infof("endpoint %v returned a response that %v", endpoint, check)
+				return response, nil
+			}
+			log.Infof("endpoint %v returned status %d with unexpected response: %v", endpoint, response.StatusCode, err)
+		}
+		if counter >= w.GetTries() {
+			return response, common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for endpoint %v to return a response that %v: %v", endpoint, check, err)
 		}
 		counter++
-		time.Sleep(w.GetInterval())
+		if err := w.Sleep(ctx); err != nil {
+			return response, err
+		}
 	}
 }
 
 func SendTrafficToIngress(kubeClientset kubernetes.Interface, w common.WaiterConfig, tps int, name, namespace string, port int, path string, duration int, durationUnits string, expectedErrors int) error {
+	_, err := SendTrafficToIngressWithMetrics(kubeClientset, w, tps, name, namespace, port, path, duration, durationUnits, expectedErrors)
+	return err
+}
+
+func SendTrafficToIngressWithMetrics(kubeClientset kubernetes.Interface, w common.WaiterConfig, tps int, name, namespace string, port int, path string, duration int, durationUnits string, expectedErrors int) (*vegeta.Metrics, error) {
 	endpoint, err := GetIngressEndpoint(kubeClientset, w, name, namespace, port, path)
+	if err != nil {
+		return nil, err
+	}
+	return SendTrafficToEndpoint(endpoint, http.Header{}, tps, duration, durationUnits, expectedErrors)
+}
+
+func ServiceAvailable(ctx context.Context, kubeClientset kubernetes.Interface, restConfig *rest.Config, w common.WaiterConfig, name, namespace string, port int, path string) error {
+	return ServiceAvailableWithRequest(ctx, kubeClientset, restConfig, w, name, namespace, port, path, http.MethodGet, "", "", http.StatusOK)
+}
+
+func ServiceAvailableWithRequest(ctx context.Context, kubeClientset kubernetes.Interface, restConfig *rest.Config, w common.WaiterConfig, name, namespace string, port int, path, method, headers, body string, expectedStatusCode int) error {
+	header, err := parseHTTPHeaders(headers)
 	if err != nil {
 		return err
 	}
-	log.Infof("sending traffic to %v with rate of %v tps for %v %s...", endpoint, tps, duration, durationUnits)
-	rate := vegeta.Rate{Freq: tps, Per: time.Second}
+	endpoint, stopChan, err := GetServiceEndpoint(kubeClientset, restConfig, name, namespace, port, path)
+	if err != nil {
+		return err
+	}
+	defer close(stopChan)
+	return endpointAvailable(ctx, w, endpoint, HTTPRequest{Method: method, Header: header, Body: body}, expectedStatusCode)
+}
+
+func SendTrafficToService(kubeClientset kubernetes.Interface, restConfig *rest.Config, tps int, name, namespace string, port int, path string, duration int, durationUnits string, expectedErrors int) (*vegeta.Metrics, error) {
+	endpoint, stopChan, err := GetServiceEndpoint(kubeClientset, restConfig, name, namespace, port, path)
+	if err != nil {
+		return nil, err
+	}
+	defer close(stopChan)
+	return SendTrafficToEndpoint(endpoint, http.Header{}, tps, duration, durationUnits, expectedErrors)
+}
+
+func SendTrafficToEndpoint(endpoint string, header http.Header, tps int, duration int, durationUnits string, expectedErrors int) (*vegeta.Metrics, error) {
+	return SendTrafficToTarget(TrafficTarget{Endpoint: endpoint, Header: header, TPS: tps}, duration, durationUnits, expectedErrors)
+}
+
+// SendTrafficToTarget attacks the endpoint of the target at its rate with the requests of its targeter, GET requests to
+// the endpoint when it has none
+func SendTrafficToTarget(target TrafficTarget, duration int, durationUnits string, expectedErrors int) (*vegeta.Metrics, error) {
+	log.Infof("sending traffic to %v with rate of %v tps for %v %s...", target.Endpoint, target.TPS, duration, durationUnits)
+	rate := vegeta.Rate{Freq: target.TPS, Per: time.Second}
 	var d time.Duration
 	switch durationUnits {
 	case util.DurationMinutes:
@@ -407,21 +1587,276 @@ func SendTrafficToIngress(kubeClientset kubernetes.Interface, w common.WaiterCon
 	case util.DurationSeconds:
 		d = time.Second * time.Duration(duration)
 	default:
-		return fmt.Errorf("unsupported duration units: '%s'", durationUnits)
+		return nil, common.Errorf(common.ErrUnsupportedOperation, "unsupported duration units: '%s'", durationUnits)
 	}
-	targeter := vegeta.NewStaticTargeter(vegeta.Target{
-		Method: "GET",
-		URL:    endpoint,
-	})
-	attacker := vegeta.NewAttacker()
-	var metrics vegeta.Metrics
-	for res := range attacker.Attack(targeter, rate, d, namespace+"/"+name) {
+	builder := target.Targeter
+	if builder == nil {
+		builder = TrafficRequests{}
+	}
+	targeter, err := builder.Targeter(target.Endpoint, target.Header)
+	if err != nil {
+		return nil, err
+	}
+	attacker := newTrafficAttacker(target)
+	metrics := vegeta.Metrics{
+		Histogram: &vegeta.Histogram{Buckets: trafficHistogramBuckets},
+	}
+	for res := range attacker.Attack(targeter, rate, d, target.Endpoint) {
 		metrics.Add(res)
 	}
 	metrics.Close()
+	log.Infof("traffic test sent %d requests with success ratio %v and latencies p50 '%v', p95 '%v', p99 '%v'", metrics.Requests, metrics.Success, metrics.Latencies.P50, metrics.Latencies.P95, metrics.Latencies.P99)
+	logTrafficReport(&metrics)
 	if len(metrics.Errors) > expectedErrors {
-		return errors.Errorf("traffic test had '%v' errors but expected '%d'", metrics.Errors, expectedErrors)
+		return &metrics, errors.Errorf("traffic test had '%v' errors but expected '%d'", metrics.Errors, expectedErrors)
+	}
+	return &metrics, nil
+}
+
+// TrafficTarget is an endpoint attacked at its own rate by SendTrafficToEndpoints.
+type TrafficTarget struct {
+	Name     string
+	Endpoint string
+	Header   http.Header
+	TPS      int
+	// Targeter builds the requests sent to the endpoint, GET requests to the endpoint when not set
+	Targeter TargeterBuilder
+	// Timeout of each request, the vegeta default when not set
+	Timeout time.Duration
+	// InsecureSkipVerify skips verifying the certificate of HTTPS endpoints
+	InsecureSkipVerify bool
+}
+
+// TargeterBuilder builds the targeter of the requests a traffic test sends to the endpoint of a target with the header,
+// implement it to send requests TrafficRequests does not build, e.g. read from a vegeta targets file.
+type TargeterBuilder interface {
+	Targeter(endpoint string, header http.Header) (vegeta.Targeter, error)
+}
+
+// TargeterBuilderFunc is a function used as a TargeterBuilder.
+type TargeterBuilderFunc func(endpoint string, header http.Header) (vegeta.Targeter, error)
+
+func (f TargeterBuilderFunc) Targeter(endpoint string, header http.Header) (vegeta.Targeter, error) {
+	return f(endpoint, header)
+}
+
+// WeightedPath is a path of an endpoint sent a share of the requests of a traffic test proportional to its weight.
+type WeightedPath struct {
+	Path   string
+	Weight int
+}
+
+// TrafficRequests is the TargeterBuilder of the traffic steps, it sends requests with the method and body to the paths
+// of the endpoint in proportion to their weights, or to the endpoint itself when there are no paths.
+type TrafficRequests struct {
+	Method string
+	Body   []byte
+	Paths  []WeightedPath
+}
+
+func (r TrafficRequests) Targeter(endpoint string, header http.Header) (vegeta.Targeter, error) {
+	method := strings.ToUpper(r.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	if len(r.Paths) == 0 {
+		return vegeta.NewStaticTargeter(vegeta.Target{Method: method, URL: endpoint, Header: header, Body: r.Body}), nil
+	}
+	var (
+		targets = make([]vegeta.Target, len(r.Paths))
+		weights = make([]int, len(r.Paths))
+	)
+	for i, path := range r.Paths {
+		if path.Weight <= 0 {
+			return nil, errors.Errorf("weight of path '%s' should be greater than 0", path.Path)
+		}
+		targets[i] = vegeta.Target{Method: method, URL: strings.TrimSuffix(endpoint, "/") + path.Path, Header: header, Body: r.Body}
+		weights[i] = path.Weight
+	}
+	return newWeightedTargeter(targets, weights), nil
+}
+
+// ParseWeightedPaths parses comma separated paths with an optional weight after a colon, e.g. '/api/orders:3,/healthz:1',
+// a path without weight has weight 1.
+func ParseWeightedPaths(paths string) ([]WeightedPath, error) {
+	var weightedPaths []WeightedPath
+	for _, path := range util.DeleteEmpty(strings.Split(paths, ",")) {
+		weightedPath := WeightedPath{Path: path, Weight: 1}
+		if i := strings.LastIndex(path, ":"); i >= 0 {
+			if weight, err := strconv.Atoi(path[i+1:]); err == nil {
+				weightedPath = WeightedPath{Path: path[:i], Weight: weight}
+			}
+		}
+		if !strings.HasPrefix(weightedPath.Path, "/") {
+			return nil, errors.Errorf("path '%s' should start with '/'", weightedPath.Path)
+		}
+		if weightedPath.Weight <= 0 {
+			return nil, errors.Errorf("weight of path '%s' should be greater than 0", weightedPath.Path)
+		}
+		weightedPaths = append(weightedPaths, weightedPath)
+	}
+	if len(weightedPaths) == 0 {
+		return nil, errors.Errorf("no paths found in '%s'", paths)
+	}
+	return weightedPaths, nil
+}
+
+func NewIngressTrafficTarget(kubeClientset kubernetes.Interface, w common.WaiterConfig, targetName string, tps int, name, namespace string, port int, path, headers string) (TrafficTarget, error) {
+	header, err := parseHTTPHeaders(headers)
+	if err != nil {
+		return TrafficTarget{}, err
+	}
+	endpoint, err := GetIngressEndpoint(kubeClientset, w, name, namespace, port, path)
+	if err != nil {
+		return TrafficTarget{}, err
+	}
+	return TrafficTarget{
+		Name:     targetName,
+		Endpoint: endpoint,
+		Header:   header,
+		TPS:      tps,
+	}, nil
+}
+
+// NewServiceTrafficTarget port-forwards a local port to a ready pod backing the service, the returned channel needs to be
+// closed once the traffic is sent to stop forwarding.
+func NewServiceTrafficTarget(kubeClientset kubernetes.Interface, restConfig *rest.Config, targetName string, tps int, name, namespace string, port int, path, headers string) (TrafficTarget, chan struct{}, error) {
+	header, err := parseHTTPHeaders(headers)
+	if err != nil {
+		return TrafficTarget{}, nil, err
+	}
+	endpoint, stopChan, err := GetServiceEndpoint(kubeClientset, restConfig, name, namespace, port, path)
+	if err != nil {
+		return TrafficTarget{}, nil, err
 	}
+	return TrafficTarget{
+		Name:     targetName,
+		Endpoint: endpoint,
+		Header:   header,
+		TPS:      tps,
+	}, stopChan, nil
+}
+
+// SendTrafficToEndpoints attacks all targets concurrently for the same duration and returns the metrics of each target by name.
+func SendTrafficToEndpoints(targets []TrafficTarget, duration int, durationUnits string, expectedErrors int) (map[string]*vegeta.Metrics, error) {
+	if len(targets) == 0 {
+		return nil, errors.New("no traffic targets found, add traffic targets first")
+	}
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		metrics    = map[string]*vegeta.Metrics{}
+		targetErrs []string
+	)
+	for _, target := range targets {
+		if _, ok := metrics[target.Name]; ok {
+			return nil, errors.Errorf("traffic target '%s' was added more than once", target.Name)
+		}
+		metrics[target.Name] = nil
+	}
+	for _, target := range targets {
+		wg.Add(1)
+		go func(target TrafficTarget) {
+			defer wg.Done()
+			targetMetrics, err := SendTrafficToTarget(target, duration, durationUnits, expectedErrors)
+			mu.Lock()
+			defer mu.Unlock()
+			metrics[target.Name] = targetMetrics
+			if err != nil {
+				targetErrs = append(targetErrs, fmt.Sprintf("target '%s': %v", target.Name, err))
+			}
+		}(target)
+	}
+	wg.Wait()
+	for name, targetMetrics := range metrics {
+		if targetMetrics != nil {
+			log.Infof("traffic target '%s' sent %d requests with success ratio %v and p99 latency '%v'", name, targetMetrics.Requests, targetMetrics.Success, targetMetrics.Latencies.P99)
+		}
+	}
+	if len(targetErrs) > 0 {
+		sort.Strings(targetErrs)
+		return metrics, errors.Errorf("traffic test failed for %d target(s): %s", len(targetErrs), strings.Join(targetErrs, "; "))
+	}
+	return metrics, nil
+}
+
+// ExportTrafficMetrics writes the traffic metrics as json under artifactsPath and returns the file path.
+func ExportTrafficMetrics(metrics *vegeta.Metrics, artifactsPath, name string) (string, error) {
+	return exportTrafficArtifact(metrics, artifactsPath, name, "json", func(w io.Writer) error {
+		return vegeta.NewJSONReporter(metrics).Report(w)
+	})
+}
+
+// ExportTrafficReport writes the traffic metrics as an html report under artifactsPath and returns the file path, the report
+// has the latencies, throughput, success ratio, status codes, errors and, when recorded, the latency histogram.
+func ExportTrafficReport(metrics *vegeta.Metrics, artifactsPath, name string) (string, error) {
+	return exportTrafficArtifact(metrics, artifactsPath, name, "html", func(w io.Writer) error {
+		return trafficReportTemplate.Execute(w, newTrafficReport(metrics, name))
+	})
+}
+
+func TrafficLatencyShouldBeLessThan(metrics *vegeta.Metrics, percentile, maxLatency string) error {
+	if metrics == nil {
+		return errors.New("no traffic metrics found, send traffic first")
+	}
+	threshold, err := time.ParseDuration(maxLatency)
+	if err != nil {
+		return err
+	}
+	latency, err := getLatency(metrics, percentile)
+	if err != nil {
+		return err
+	}
+	if latency >= threshold {
+		return errors.Errorf("traffic test '%s' latency was '%v' but expected less than '%v'", percentile, latency, threshold)
+	}
+	log.Infof("traffic test '%s' latency was '%v'", percentile, latency)
+	return nil
+}
+
+func TrafficSuccessRatioShouldBeAtLeast(metrics *vegeta.Metrics, minRatio string) error {
+	if metrics == nil {
+		return errors.New("no traffic metrics found, send traffic first")
+	}
+	ratio, err := strconv.ParseFloat(minRatio, 64)
+	if err != nil {
+		return err
+	}
+	if metrics.Success < ratio {
+		return errors.Errorf("traffic test success ratio was '%v' but expected at least '%v'", metrics.Success, ratio)
+	}
+	log.Infof("traffic test success ratio was '%v'", metrics.Success)
+	return nil
+}
+
+// TrafficThroughputShouldBeAtLeast validates the rate of successful requests per second of the traffic test
+func TrafficThroughputShouldBeAtLeast(metrics *vegeta.Metrics, minThroughput string) error {
+	if metrics == nil {
+		return errors.New("no traffic metrics found, send traffic first")
+	}
+	throughput, err := strconv.ParseFloat(minThroughput, 64)
+	if err != nil {
+		return err
+	}
+	if metrics.Throughput < throughput {
+		return errors.Errorf("traffic test throughput was '%.2f' requests per second but expected at least '%v'", metrics.Throughput, throughput)
+	}
+	log.Infof("traffic test throughput was '%.2f' requests per second", metrics.Throughput)
+	return nil
+}
+
+func TrafficStatusCodePercentageShouldBeAtLeast(metrics *vegeta.Metrics, minPercentage int, statusCode string) error {
+	if metrics == nil {
+		return errors.New("no traffic metrics found, send traffic first")
+	}
+	if metrics.Requests == 0 {
+		return errors.New("traffic test did not send any requests")
+	}
+	percentage := float64(metrics.StatusCodes[statusCode]) * 100 / float64(metrics.Requests)
+	if percentage < float64(minPercentage) {
+		return errors.Errorf("traffic test had '%v%%' of responses with status code '%s' but expected at least '%d%%', status codes: '%v'", percentage, statusCode, minPercentage, metrics.StatusCodes)
+	}
+	log.Infof("traffic test had '%v%%' of responses with status code '%s'", percentage, statusCode)
 	return nil
 }
 
@@ -461,3 +1896,52 @@ func ResourceNotInNamespace(kubeClientset kubernetes.Interface, resourceType, na
 	}
 	return nil
 }
+
+// CreateNamespace creates the namespace with the labels, it fails if the namespace already exists
+func CreateNamespace(kubeClientset kubernetes.Interface, name string, labels map[string]string) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	if _, err := kubeClientset.CoreV1().Namespaces().Create(context.Background(), namespace, metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to create namespace %v", name)
+	}
+	log.Infof("created namespace %v", name)
+	return nil
+}
+
+// DeleteNamespace deletes the namespace and waits for it to be gone, a namespace still terminating once the waiter
+// times out has its finalizers removed so it does not outlive the test, leaving whatever blocked it behind
+func DeleteNamespace(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name string) error {
+	var counter int
+
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	propagationPolicy := metav1.DeletePropagationForeground
+	err := kubeClientset.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagationPolicy})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete namespace %v", name)
+	}
+	for {
+		namespace, err := kubeClientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			log.Infof("deleted namespace %v", name)
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get namespace %v", name)
+		}
+		if counter >= w.GetTries() {
+			log.Warnf("namespace %v is still %v, removing its finalizers: %v", name, namespace.Status.Phase, getNamespaceConditionMessages(namespace))
+			return removeNamespaceFinalizers(kubeClientset, namespace)
+		}
+		log.Infof("waiting for namespace %v to be deleted", name)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
@@ -15,19 +15,48 @@ limitations under the License.
 package structured
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/keikoproj/kubedog/internal/util"
 	"github.com/keikoproj/kubedog/pkg/kube/common"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/client-go/util/retry"
 )
 
 func GetNodeList(kubeClientset kubernetes.Interface) (*corev1.NodeList, error) {
@@ -54,11 +83,145 @@ func GetDaemonSet(kubeClientset kubernetes.Interface, name, namespace string) (*
 		return kubeClientset.AppsV1().DaemonSets(namespace).Get(context.Background(), name, metav1.GetOptions{})
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get daemonset")
+		return nil, errors.Wrap(common.WrapNotFound(err), "failed to get daemonset")
 	}
 	return ds.(*appsv1.DaemonSet), nil
 }
 
+// getDaemonSetRolloutMessage returns why the daemonset is not rolled out yet, or an empty string when it is
+func getDaemonSetRolloutMessage(ds *appsv1.DaemonSet) string {
+	status := ds.Status
+	switch {
+	case status.ObservedGeneration < ds.Generation:
+		return fmt.Sprintf("observed generation %d of %d", status.ObservedGeneration, ds.Generation)
+	case status.UpdatedNumberScheduled != status.DesiredNumberScheduled:
+		return fmt.Sprintf("%d of %d pods updated", status.UpdatedNumberScheduled, status.DesiredNumberScheduled)
+	case status.NumberUnavailable != 0:
+		return fmt.Sprintf("%d pods unavailable", status.NumberUnavailable)
+	case status.CurrentNumberScheduled != status.DesiredNumberScheduled:
+		return fmt.Sprintf("%d of %d pods scheduled", status.CurrentNumberScheduled, status.DesiredNumberScheduled)
+	}
+	return ""
+}
+
+// getDaemonSetPodStatuses returns the node, phase, readiness and revision of each pod of the daemonset
+func getDaemonSetPodStatuses(kubeClientset kubernetes.Interface, ds *appsv1.DaemonSet) ([]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := kubeClientset.CoreV1().Pods(ds.Namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	podStatuses := make([]string, 0, len(pods.Items))
+	for _, p := range pods.Items {
+		podStatuses = append(podStatuses, fmt.Sprintf("node '%s': pod '%s' %s, ready: %v, revision: %s",
+			p.Spec.NodeName, p.Name, p.Status.Phase, isPodReady(&p), p.Labels[appsv1.DefaultDaemonSetUniqueLabelKey]))
+	}
+	sort.Strings(podStatuses)
+	return podStatuses, nil
+}
+
+func getStatefulSetPartition(sts *appsv1.StatefulSet) int32 {
+	if rollingUpdate := sts.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil {
+		return *rollingUpdate.Partition
+	}
+	return 0
+}
+
+// getStatefulSetRolloutMessage returns why the statefulset is not rolled out yet, or an empty string when it is. Like
+// 'kubectl rollout status', only the pods from the partition must be updated and an OnDelete statefulset only needs ready pods
+func getStatefulSetRolloutMessage(sts *appsv1.StatefulSet) string {
+	var replicas int32 = 1
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	status := sts.Status
+	switch {
+	case status.ObservedGeneration < sts.Generation:
+		return fmt.Sprintf("observed generation %d of %d", status.ObservedGeneration, sts.Generation)
+	case status.ReadyReplicas < replicas:
+		return fmt.Sprintf("%d of %d pods ready", status.ReadyReplicas, replicas)
+	case sts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType:
+		return ""
+	}
+	if partition := getStatefulSetPartition(sts); partition > 0 {
+		if status.UpdatedReplicas < replicas-partition {
+			return fmt.Sprintf("%d of %d pods from partition %d updated", status.UpdatedReplicas, replicas-partition, partition)
+		}
+		return ""
+	}
+	switch {
+	case status.UpdatedReplicas < replicas:
+		return fmt.Sprintf("%d of %d pods updated", status.UpdatedReplicas, replicas)
+	case status.CurrentRevision != status.UpdateRevision:
+		return fmt.Sprintf("current revision %s is not the update revision %s", status.CurrentRevision, status.UpdateRevision)
+	}
+	return ""
+}
+
+func getStatefulSetMaxUnavailable(sts *appsv1.StatefulSet) *intstr.IntOrString {
+	if rollingUpdate := sts.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil {
+		return rollingUpdate.MaxUnavailable
+	}
+	return nil
+}
+
+// getStatefulSetPartitionPendingPods returns the pods of the statefulset at or above its partition that are not ready with
+// the update revision yet, it fails when a pod below the partition is updated
+func getStatefulSetPartitionPendingPods(kubeClientset kubernetes.Interface, sts *appsv1.StatefulSet) ([]string, error) {
+	if sts.Status.ObservedGeneration < sts.Generation || sts.Status.UpdateRevision == "" {
+		return []string{fmt.Sprintf("statefulset generation %d not observed", sts.Generation)}, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := kubeClientset.CoreV1().Pods(sts.Namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	var replicas int32 = 1
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	partition := getStatefulSetPartition(sts)
+	podsByOrdinal := map[int]*corev1.Pod{}
+	for i, p := range pods.Items {
+		if !strings.HasPrefix(p.Name, sts.Name+"-") {
+			continue
+		}
+		ordinal, err := strconv.Atoi(strings.TrimPrefix(p.Name, sts.Name+"-"))
+		if err != nil {
+			continue
+		}
+		podsByOrdinal[ordinal] = &pods.Items[i]
+	}
+
+	pending := make([]string, 0)
+	for ordinal := 0; ordinal < int(replicas); ordinal++ {
+		p, ok := podsByOrdinal[ordinal]
+		if !ok {
+			pending = append(pending, fmt.Sprintf("pod '%s-%d' not found", sts.Name, ordinal))
+			continue
+		}
+		revision := p.Labels[appsv1.StatefulSetRevisionLabel]
+		updated := revision == sts.Status.UpdateRevision
+		if int32(ordinal) < partition {
+			if updated && sts.Status.UpdateRevision != sts.Status.CurrentRevision {
+				return nil, errors.Errorf("pod '%s' below partition %d was updated to revision %s", p.Name, partition, revision)
+			}
+			continue
+		}
+		if !updated || !isPodReady(p) {
+			pending = append(pending, fmt.Sprintf("pod '%s' revision: %s, ready: %v", p.Name, revision, isPodReady(p)))
+		}
+	}
+	return pending, nil
+}
+
 func GetDeployment(kubeClientset kubernetes.Interface, name, namespace string) (*appsv1.Deployment, error) {
 	if err := common.ValidateClientset(kubeClientset); err != nil {
 		return nil, err
@@ -68,7 +231,7 @@ func GetDeployment(kubeClientset kubernetes.Interface, name, namespace string) (
 		return kubeClientset.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get deployment")
+		return nil, errors.Wrap(common.WrapNotFound(err), "failed to get deployment")
 	}
 	return deploy.(*appsv1.Deployment), nil
 }
@@ -76,7 +239,7 @@ func GetDeployment(kubeClientset kubernetes.Interface, name, namespace string) (
 func GetConfigMap(kubeClientset kubernetes.Interface, name, namespace string) (*corev1.ConfigMap, error) {
 	configmaps, err := kubeClientset.CoreV1().ConfigMaps(namespace).Get(context.Background(), name, metav1.GetOptions{})
 	if err != nil || configmaps.Name != name {
-		return nil, errors.Wrap(err, "failed to get configmap")
+		return nil, errors.Wrap(common.WrapNotFound(err), "failed to get configmap")
 	}
 
 	return configmaps, nil
@@ -91,7 +254,7 @@ func GetPersistentVolume(kubeClientset kubernetes.Interface, name string) (*core
 		return kubeClientset.CoreV1().PersistentVolumes().Get(context.Background(), name, metav1.GetOptions{})
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get persistentvolume")
+		return nil, errors.Wrap(common.WrapNotFound(err), "failed to get persistentvolume")
 	}
 	return pvs.(*corev1.PersistentVolume), nil
 }
@@ -105,11 +268,25 @@ func GetPersistentVolumeClaim(kubeClientset kubernetes.Interface, name string, n
 		return kubeClientset.CoreV1().PersistentVolumeClaims(namespace).Get(context.Background(), name, metav1.GetOptions{})
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get persistentvolumeclaim")
+		return nil, errors.Wrap(common.WrapNotFound(err), "failed to get persistentvolumeclaim")
 	}
 	return pvc.(*corev1.PersistentVolumeClaim), nil
 }
 
+func GetStatefulSet(kubeClientset kubernetes.Interface, name, namespace string) (*appsv1.StatefulSet, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	sts, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.AppsV1().StatefulSets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrap(common.WrapNotFound(err), "failed to get statefulset")
+	}
+	return sts.(*appsv1.StatefulSet), nil
+}
+
 func GetStatefulSetList(kubeClientset kubernetes.Interface, namespace string) (*appsv1.StatefulSetList, error) {
 	if err := common.ValidateClientset(kubeClientset); err != nil {
 		return nil, err
@@ -147,20 +324,250 @@ func GetIngress(kubeClientset kubernetes.Interface, name, namespace string) (*ne
 		return kubeClientset.NetworkingV1().Ingresses(namespace).Get(context.Background(), name, metav1.GetOptions{})
 	})
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get ingress '%v'", name)
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get ingress '%v'", name)
 	}
 	return ingress.(*networkingv1.Ingress), nil
 }
 
+func GetService(kubeClientset kubernetes.Interface, name, namespace string) (*corev1.Service, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	service, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.CoreV1().Services(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get service '%v'", name)
+	}
+	return service.(*corev1.Service), nil
+}
+
+// GetServiceLoadBalancerEndpoint waits for the load balancer of the service and returns 'http://<hostname or ip>:<port><path>'.
+func GetServiceLoadBalancerEndpoint(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, port int, path string) (string, error) {
+	var (
+		counter int
+	)
+	for {
+		if counter >= w.GetTries() {
+			return "", common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for service load balancer")
+		}
+		service, err := GetService(kubeClientset, name, namespace)
+		if err != nil {
+			return "", err
+		}
+		for _, lb := range service.Status.LoadBalancer.Ingress {
+			if address := lb.Hostname; address != "" {
+				return fmt.Sprintf("http://%v:%v%v", address, port, path), nil
+			}
+			if address := lb.IP; address != "" {
+				return fmt.Sprintf("http://%v:%v%v", address, port, path), nil
+			}
+		}
+		log.Infof("service %v/%v has no load balancer yet", namespace, name)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return "", err
+		}
+	}
+}
+
+// GetServiceLoadBalancerHostname waits for the load balancer of the service and returns its hostname.
+func GetServiceLoadBalancerHostname(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string) (string, error) {
+	var (
+		counter int
+	)
+	for {
+		if counter >= w.GetTries() {
+			return "", common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for service load balancer hostname")
+		}
+		service, err := GetService(kubeClientset, name, namespace)
+		if err != nil {
+			return "", err
+		}
+		if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			return "", errors.Errorf("service %v/%v is of type %v, expected %v", namespace, name, service.Spec.Type, corev1.ServiceTypeLoadBalancer)
+		}
+		for _, lb := range service.Status.LoadBalancer.Ingress {
+			if lb.Hostname != "" {
+				return lb.Hostname, nil
+			}
+		}
+		log.Infof("service %v/%v has no load balancer hostname yet", namespace, name)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return "", err
+		}
+	}
+}
+
+// GetServiceEndpoint port-forwards a local port to a ready pod backing the service and returns a localhost endpoint,
+// the returned channel must be closed to stop forwarding once the endpoint is no longer needed.
+func GetServiceEndpoint(kubeClientset kubernetes.Interface, restConfig *rest.Config, name, namespace string, port int, path string) (string, chan struct{}, error) {
+	if restConfig == nil {
+		return "", nil, common.Errorf(common.ErrClientNotInitialized, "'rest.Config' is nil, discover clients first")
+	}
+	service, err := GetService(kubeClientset, name, namespace)
+	if err != nil {
+		return "", nil, err
+	}
+	servicePod, targetPort, err := getServiceTargetPod(kubeClientset, service, port)
+	if err != nil {
+		return "", nil, err
+	}
+	localPort, stopChan, err := portForwardPod(kubeClientset, restConfig, servicePod, targetPort)
+	if err != nil {
+		return "", nil, err
+	}
+	log.Infof("forwarding local port %d to pod %v/%v port %d for service %v", localPort, servicePod.Namespace, servicePod.Name, targetPort, name)
+	return fmt.Sprintf("http://localhost:%d%v", localPort, path), stopChan, nil
+}
+
+func getServiceTargetPod(kubeClientset kubernetes.Interface, service *corev1.Service, port int) (*corev1.Pod, int, error) {
+	var servicePort *corev1.ServicePort
+	for i := range service.Spec.Ports {
+		if int(service.Spec.Ports[i].Port) == port {
+			servicePort = &service.Spec.Ports[i]
+			break
+		}
+	}
+	if servicePort == nil {
+		return nil, 0, errors.Errorf("service %v/%v does not expose port %d", service.Namespace, service.Name, port)
+	}
+	if len(service.Spec.Selector) == 0 {
+		return nil, 0, errors.Errorf("service %v/%v has no selector", service.Namespace, service.Name)
+	}
+
+	opts := metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+	}
+	pods, err := kubeClientset.CoreV1().Pods(service.Namespace).List(context.Background(), opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range pods.Items {
+		p := &pods.Items[i]
+		if p.Status.Phase != corev1.PodRunning || !isPodReady(p) {
+			continue
+		}
+		targetPort, err := getPodTargetPort(p, servicePort)
+		if err != nil {
+			return nil, 0, err
+		}
+		return p, targetPort, nil
+	}
+	return nil, 0, errors.Errorf("service %v/%v has no running and ready pods", service.Namespace, service.Name)
+}
+
+func getPodTargetPort(p *corev1.Pod, servicePort *corev1.ServicePort) (int, error) {
+	switch {
+	case servicePort.TargetPort.Type == intstr.String:
+		for _, container := range p.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == servicePort.TargetPort.StrVal {
+					return int(containerPort.ContainerPort), nil
+				}
+			}
+		}
+		return 0, errors.Errorf("pod %v/%v has no container port named '%s'", p.Namespace, p.Name, servicePort.TargetPort.StrVal)
+	case servicePort.TargetPort.IntVal != 0:
+		return int(servicePort.TargetPort.IntVal), nil
+	default:
+		return int(servicePort.Port), nil
+	}
+}
+
+func portForwardPod(kubeClientset kubernetes.Interface, restConfig *rest.Config, p *corev1.Pod, targetPort int) (uint16, chan struct{}, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(restConfig)
+	if err != nil {
+		return 0, nil, err
+	}
+	req := kubeClientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(p.Namespace).
+		Name(p.Name).
+		SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	stopChan, readyChan := make(chan struct{}), make(chan struct{})
+	forwarder, err := portforward.New(dialer, []string{fmt.Sprintf("0:%d", targetPort)}, stopChan, readyChan, io.Discard, io.Discard)
+	if err != nil {
+		return 0, nil, err
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- forwarder.ForwardPorts()
+	}()
+	select {
+	case err := <-errChan:
+		return 0, nil, errors.Wrapf(err, "failed to port-forward to pod %v/%v", p.Namespace, p.Name)
+	case <-readyChan:
+	}
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		close(stopChan)
+		return 0, nil, err
+	}
+	return ports[0].Local, stopChan, nil
+}
+
+const externalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+
+// GetExternalDNSHostnames returns the hostnames external-dns creates records for, from the hostname annotation
+// of an ingress or service, falling back to the rule hosts of an ingress.
+func GetExternalDNSHostnames(kubeClientset kubernetes.Interface, resourceType, name, namespace string) ([]string, error) {
+	var (
+		annotation string
+		hostnames  []string
+	)
+	switch resourceType {
+	case "ingress":
+		ingress, err := GetIngress(kubeClientset, name, namespace)
+		if err != nil {
+			return nil, err
+		}
+		annotation = ingress.Annotations[externalDNSHostnameAnnotation]
+		if annotation == "" {
+			for _, rule := range ingress.Spec.Rules {
+				if rule.Host != "" {
+					hostnames = append(hostnames, rule.Host)
+				}
+			}
+		}
+	case "service":
+		service, err := GetService(kubeClientset, name, namespace)
+		if err != nil {
+			return nil, err
+		}
+		annotation = service.Annotations[externalDNSHostnameAnnotation]
+	default:
+		return nil, common.Errorf(common.ErrUnsupportedOperation, "unsupported resource type for external-dns: '%s'", resourceType)
+	}
+	for _, hostname := range strings.Split(annotation, ",") {
+		if hostname = strings.TrimSpace(hostname); hostname != "" {
+			hostnames = append(hostnames, hostname)
+		}
+	}
+	if len(hostnames) == 0 {
+		return nil, errors.Errorf("%s %v/%v has no external-dns hostnames", resourceType, namespace, name)
+	}
+	return hostnames, nil
+}
+
 // TODO: remove use of service.beta.kubernetes.io/aws-load-balancer-subnets or make generic
 func GetIngressEndpoint(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, port int, path string) (string, error) {
+	return GetIngressEndpointCtx(context.Background(), kubeClientset, w, name, namespace, port, path)
+}
+
+// GetIngressEndpointCtx is GetIngressEndpoint, returning early when the context is done
+func GetIngressEndpointCtx(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, port int, path string) (string, error) {
 	var (
 		counter int
 	)
 	for {
 		log.Info("waiting for ingress availability")
 		if counter >= w.GetTries() {
-			return "", errors.New("waiter timed out waiting for resource state")
+			return "", common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for resource state")
 		}
 		ingress, err := GetIngress(kubeClientset, name, namespace)
 		if err != nil {
@@ -182,7 +589,9 @@ func GetIngressEndpoint(kubeClientset kubernetes.Interface, w common.WaiterConfi
 			return endpoint, nil
 		}
 		counter++
-		time.Sleep(w.GetInterval())
+		if err := w.Sleep(ctx); err != nil {
+			return "", err
+		}
 	}
 }
 
@@ -204,13 +613,1579 @@ func validatePrometheusPVLabels(kubeClientset kubernetes.Interface, volumeClaimT
 	return nil
 }
 
-func isNodeReady(n corev1.Node) bool {
-	for _, condition := range n.Status.Conditions {
-		if condition.Type == "Ready" {
-			if condition.Status == "True" {
-				return true
+// parseHTTPHeaders parses headers of the form '<key>: <value>; <key>: <value>'.
+func parseHTTPHeaders(headers string) (http.Header, error) {
+	header := http.Header{}
+	for _, h := range strings.Split(headers, ";") {
+		if strings.TrimSpace(h) == "" {
+			continue
+		}
+		key, value, found := strings.Cut(h, ":")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, errors.Errorf("header '%s' should meet format '<key>: <value>'", h)
+		}
+		header.Add(key, strings.TrimSpace(value))
+	}
+	return header, nil
+}
+
+func newHTTPRequest(method, endpoint string, header http.Header, body string) (*http.Request, error) {
+	req, err := http.NewRequest(strings.ToUpper(method), endpoint, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header.Clone()
+	// the Host header is ignored by the client unless set on the request itself
+	if host := header.Get("Host"); host != "" {
+		req.Host = host
+	}
+	return req, nil
+}
+
+// defaultHTTPTimeout is the timeout of an HTTPRequest that does not set one
+const defaultHTTPTimeout = 10 * time.Second
+
+func sendHTTPRequest(ctx context.Context, endpoint string, request HTTPRequest) (*HTTPResponse, error) {
+	method := request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := newHTTPRequest(method, endpoint, request.Header, request.Body)
+	if err != nil {
+		return nil, err
+	}
+	timeout := request.Timeout
+	if timeout == 0 {
+		timeout = defaultHTTPTimeout
+	}
+	client := http.Client{
+		Timeout: timeout,
+	}
+	if request.InsecureSkipVerify {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		client.Transport = transport
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the response body of endpoint %v", endpoint)
+	}
+	return &HTTPResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+}
+
+// endpointAvailable sends the request to the endpoint until it responds with the expected status code, and waits for an
+// interval once it does
+func endpointAvailable(ctx context.Context, w common.WaiterConfig, endpoint string, request HTTPRequest, expectedStatusCode int) error {
+	check, err := NewHTTPStatusCodeCheck(strconv.Itoa(expectedStatusCode))
+	if err != nil {
+		return err
+	}
+	if _, err := EndpointResponseShouldCtx(ctx, w, endpoint, request, check); err != nil {
+		return err
+	}
+	log.Infof("endpoint %v is available", endpoint)
+	return w.Sleep(ctx)
+}
+
+// ingressResponseShould sends a GET request to the ingress until its response passes the check
+func ingressResponseShould(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, port int, path string, check HTTPResponseCheck) error {
+	endpoint, err := GetIngressEndpointCtx(ctx, kubeClientset, w, name, namespace, port, path)
+	if err != nil {
+		return err
+	}
+	_, err = EndpointResponseShouldCtx(ctx, w, endpoint, HTTPRequest{}, check)
+	return err
+}
+
+// HTTPResponseCheck validates the response returned by an endpoint.
+type HTTPResponseCheck struct {
+	description string
+	validate    func(response *HTTPResponse) error
+}
+
+func (c HTTPResponseCheck) Validate(response *HTTPResponse) error {
+	return c.validate(response)
+}
+
+func (c HTTPResponseCheck) String() string {
+	return c.description
+}
+
+// NewHTTPStatusCodeCheck accepts a status code like '200', a class like '2xx' or an inclusive range like '200-299'.
+func NewHTTPStatusCodeCheck(statusCodes string) (HTTPResponseCheck, error) {
+	var (
+		minStatusCode, maxStatusCode int
+		err                          error
+	)
+	if first, last, found := strings.Cut(statusCodes, "-"); found {
+		if minStatusCode, err = strconv.Atoi(first); err == nil {
+			maxStatusCode, err = strconv.Atoi(last)
+		}
+	} else if class, found := strings.CutSuffix(strings.ToLower(statusCodes), "xx"); found && len(class) == 1 {
+		minStatusCode, err = strconv.Atoi(class)
+		minStatusCode *= 100
+		maxStatusCode = minStatusCode + 99
+	} else {
+		minStatusCode, err = strconv.Atoi(statusCodes)
+		maxStatusCode = minStatusCode
+	}
+	if err != nil || minStatusCode < 100 || maxStatusCode > 599 || minStatusCode > maxStatusCode {
+		return HTTPResponseCheck{}, errors.Errorf("status code '%s' should be a status code like 200, a class like 2xx or a range like 200-299", statusCodes)
+	}
+	description := fmt.Sprintf("has status %s", statusCodes)
+	return HTTPResponseCheck{description, func(response *HTTPResponse) error {
+		if response.StatusCode < minStatusCode || response.StatusCode > maxStatusCode {
+			return errors.Errorf("status %d is not %s", response.StatusCode, statusCodes)
+		}
+		return nil
+	}}, nil
+}
+
+// NewHTTPResponseBodyCheck validates the body of the response with the check.
+func NewHTTPResponseBodyCheck(check ResponseBodyCheck) HTTPResponseCheck {
+	description := fmt.Sprintf("has a body that %s", check)
+	return HTTPResponseCheck{description, func(response *HTTPResponse) error {
+		return check.Validate(response.Body)
+	}}
+}
+
+const (
+	responseBodyContains = "contains"
+	responseBodyMatches  = "matches"
+)
+
+// ResponseBodyCheck validates the body returned by an endpoint.
+type ResponseBodyCheck struct {
+	description string
+	validate    func(body []byte) error
+}
+
+func (c ResponseBodyCheck) Validate(body []byte) error {
+	return c.validate(body)
+}
+
+func (c ResponseBodyCheck) String() string {
+	return c.description
+}
+
+func NewResponseBodyCheck(operator, expected string) (ResponseBodyCheck, error) {
+	description := fmt.Sprintf("%s '%s'", operator, expected)
+	switch operator {
+	case responseBodyContains:
+		return ResponseBodyCheck{description, func(body []byte) error {
+			if !strings.Contains(string(body), expected) {
+				return errors.Errorf("response body '%s' does not contain '%s'", body, expected)
 			}
+			return nil
+		}}, nil
+	case responseBodyMatches:
+		re, err := regexp.Compile(expected)
+		if err != nil {
+			return ResponseBodyCheck{}, errors.Wrapf(err, "failed to compile regex '%s'", expected)
 		}
+		return ResponseBodyCheck{description, func(body []byte) error {
+			if !re.Match(body) {
+				return errors.Errorf("response body '%s' does not match '%s'", body, expected)
+			}
+			return nil
+		}}, nil
+	default:
+		return ResponseBodyCheck{}, common.Errorf(common.ErrUnsupportedOperation, "unsupported response body operator: '%s'", operator)
 	}
-	return false
+}
+
+// NewResponseBodyJSONPathCheck accepts kubectl style expressions with or without braces, e.g. '.status' or '{.items[0].name}'.
+func NewResponseBodyJSONPathCheck(jsonPath, expectedValue string) (ResponseBodyCheck, error) {
+	expression := jsonPath
+	if !strings.HasPrefix(expression, "{") {
+		expression = fmt.Sprintf("{%s}", expression)
+	}
+	parser := jsonpath.New("response-body")
+	if err := parser.Parse(expression); err != nil {
+		return ResponseBodyCheck{}, errors.Wrapf(err, "failed to parse json path '%s'", jsonPath)
+	}
+	description := fmt.Sprintf("has json path '%s' with value '%s'", jsonPath, expectedValue)
+	return ResponseBodyCheck{description, func(body []byte) error {
+		var data interface{}
+		if err := json.Unmarshal(body, &data); err != nil {
+			return errors.Wrapf(err, "response body '%s' is not valid json", body)
+		}
+		var value bytes.Buffer
+		if err := parser.Execute(&value, data); err != nil {
+			return err
+		}
+		if value.String() != expectedValue {
+			return errors.Errorf("json path '%s' of response body has value '%s' but expected '%s'", jsonPath, value.String(), expectedValue)
+		}
+		return nil
+	}}, nil
+}
+
+var trafficHistogramBuckets = vegeta.Buckets{
+	0,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+func newTrafficAttacker(target TrafficTarget) *vegeta.Attacker {
+	var opts []func(*vegeta.Attacker)
+	if target.Timeout > 0 {
+		opts = append(opts, vegeta.Timeout(target.Timeout))
+	}
+	if target.InsecureSkipVerify {
+		opts = append(opts, vegeta.TLSConfig(&tls.Config{InsecureSkipVerify: true}))
+	}
+	return vegeta.NewAttacker(opts...)
+}
+
+// newWeightedTargeter returns the targets in proportion to their weights with smooth weighted round-robin, so the
+// targets are interleaved rather than sent in bursts
+func newWeightedTargeter(targets []vegeta.Target, weights []int) vegeta.Targeter {
+	var (
+		mu      sync.Mutex
+		current = make([]int, len(targets))
+		total   int
+	)
+	for _, weight := range weights {
+		total += weight
+	}
+	return func(tgt *vegeta.Target) error {
+		if tgt == nil {
+			return vegeta.ErrNilTarget
+		}
+		mu.Lock()
+		next := 0
+		for i, weight := range weights {
+			current[i] += weight
+			if current[i] > current[next] {
+				next = i
+			}
+		}
+		current[next] -= total
+		mu.Unlock()
+		*tgt = targets[next]
+		return nil
+	}
+}
+
+func exportTrafficArtifact(metrics *vegeta.Metrics, artifactsPath, name, extension string, report func(w io.Writer) error) (string, error) {
+	if metrics == nil {
+		return "", errors.New("no traffic metrics found, send traffic first")
+	}
+	if err := os.MkdirAll(artifactsPath, 0755); err != nil {
+		return "", errors.Wrapf(err, "failed to create artifacts directory '%s'", artifactsPath)
+	}
+	filePath := filepath.Join(artifactsPath, fmt.Sprintf("traffic-%s-%d.%s", name, time.Now().Unix(), extension))
+	f, err := os.Create(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if err := report(f); err != nil {
+		return "", errors.Wrapf(err, "failed to write traffic metrics to '%s'", filePath)
+	}
+	return filePath, nil
+}
+
+// trafficReport is the data of trafficReportTemplate, with the status codes sorted and the histogram buckets labeled
+type trafficReport struct {
+	Name        string
+	Metrics     *vegeta.Metrics
+	Success     string
+	StatusCodes []trafficReportRow
+	Histogram   []trafficReportRow
+}
+
+type trafficReportRow struct {
+	Label string
+	Count uint64
+	Ratio string
+}
+
+func newTrafficReport(metrics *vegeta.Metrics, name string) trafficReport {
+	report := trafficReport{
+		Name:    name,
+		Metrics: metrics,
+		Success: fmt.Sprintf("%.2f%%", metrics.Success*100),
+	}
+	ratio := func(count, total uint64) string {
+		if total == 0 {
+			return "0.00%"
+		}
+		return fmt.Sprintf("%.2f%%", float64(count)*100/float64(total))
+	}
+	statusCodes := make([]string, 0, len(metrics.StatusCodes))
+	for statusCode := range metrics.StatusCodes {
+		statusCodes = append(statusCodes, statusCode)
+	}
+	sort.Strings(statusCodes)
+	for _, statusCode := range statusCodes {
+		count := uint64(metrics.StatusCodes[statusCode])
+		report.StatusCodes = append(report.StatusCodes, trafficReportRow{statusCode, count, ratio(count, metrics.Requests)})
+	}
+	if metrics.Histogram != nil {
+		for i, count := range metrics.Histogram.Counts {
+			left, right := metrics.Histogram.Buckets.Nth(i)
+			report.Histogram = append(report.Histogram, trafficReportRow{fmt.Sprintf("[%s, %s]", left, right), count, ratio(count, metrics.Histogram.Total)})
+		}
+	}
+	return report
+}
+
+var trafficReportTemplate = template.Must(template.New("traffic-report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Traffic test {{.Name}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 12px; text-align: left; }
+</style>
+</head>
+<body>
+<h1>Traffic test {{.Name}}</h1>
+<table>
+<tr><th>Requests</th><td>{{.Metrics.Requests}}</td></tr>
+<tr><th>Rate</th><td>{{printf "%.2f" .Metrics.Rate}} requests per second</td></tr>
+<tr><th>Throughput</th><td>{{printf "%.2f" .Metrics.Throughput}} requests per second</td></tr>
+<tr><th>Success ratio</th><td>{{.Success}}</td></tr>
+<tr><th>Duration</th><td>{{.Metrics.Duration}}</td></tr>
+<tr><th>Wait</th><td>{{.Metrics.Wait}}</td></tr>
+<tr><th>Bytes in</th><td>{{.Metrics.BytesIn.Total}}</td></tr>
+<tr><th>Bytes out</th><td>{{.Metrics.BytesOut.Total}}</td></tr>
+</table>
+<h2>Latencies</h2>
+<table>
+<tr><th>min</th><th>mean</th><th>p50</th><th>p90</th><th>p95</th><th>p99</th><th>max</th></tr>
+<tr><td>{{.Metrics.Latencies.Min}}</td><td>{{.Metrics.Latencies.Mean}}</td><td>{{.Metrics.Latencies.P50}}</td><td>{{.Metrics.Latencies.P90}}</td><td>{{.Metrics.Latencies.P95}}</td><td>{{.Metrics.Latencies.P99}}</td><td>{{.Metrics.Latencies.Max}}</td></tr>
+</table>
+<h2>Status codes</h2>
+<table>
+<tr><th>Status code</th><th>Count</th><th>Ratio</th></tr>
+{{- range .StatusCodes}}
+<tr><td>{{.Label}}</td><td>{{.Count}}</td><td>{{.Ratio}}</td></tr>
+{{- end}}
+</table>
+{{- if .Histogram}}
+<h2>Latency histogram</h2>
+<table>
+<tr><th>Bucket</th><th>Count</th><th>Ratio</th></tr>
+{{- range .Histogram}}
+<tr><td>{{.Label}}</td><td>{{.Count}}</td><td>{{.Ratio}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Metrics.Errors}}
+<h2>Errors</h2>
+<ul>
+{{- range .Metrics.Errors}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+func logTrafficReport(metrics *vegeta.Metrics) {
+	var report bytes.Buffer
+	if err := vegeta.NewTextReporter(metrics).Report(&report); err != nil {
+		log.Warnf("failed to build traffic report: %v", err)
+		return
+	}
+	if metrics.Histogram != nil {
+		report.WriteString("\n")
+		if err := vegeta.NewHistogramReporter(metrics.Histogram).Report(&report); err != nil {
+			log.Warnf("failed to build traffic histogram: %v", err)
+			return
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSpace(report.String()), "\n") {
+		log.Info(line)
+	}
+}
+
+func getLatency(metrics *vegeta.Metrics, percentile string) (time.Duration, error) {
+	switch percentile {
+	case "p50":
+		return metrics.Latencies.P50, nil
+	case "p90":
+		return metrics.Latencies.P90, nil
+	case "p95":
+		return metrics.Latencies.P95, nil
+	case "p99":
+		return metrics.Latencies.P99, nil
+	case "mean":
+		return metrics.Latencies.Mean, nil
+	case "max":
+		return metrics.Latencies.Max, nil
+	default:
+		return 0, common.Errorf(common.ErrUnsupportedOperation, "unsupported latency percentile: '%s'", percentile)
+	}
+}
+
+func newServiceAccountAccessReview(serviceAccount, verb, resource, namespace string) (*authorizationv1.SubjectAccessReview, error) {
+	serviceAccountNamespace, serviceAccountName := namespace, serviceAccount
+	if parts := strings.SplitN(serviceAccount, "/", 2); len(parts) == 2 {
+		serviceAccountNamespace, serviceAccountName = parts[0], parts[1]
+	}
+	if serviceAccountNamespace == "" || serviceAccountName == "" {
+		return nil, errors.Errorf("service account '%v' must be given as namespace/name when the namespace is not set", serviceAccount)
+	}
+
+	resourceAttributes := &authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      verb,
+	}
+	resourceAttributes.Resource, resourceAttributes.Subresource, _ = strings.Cut(resource, "/")
+	resourceAttributes.Resource, resourceAttributes.Group, _ = strings.Cut(resourceAttributes.Resource, ".")
+
+	return &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: resourceAttributes,
+			User:               fmt.Sprintf("system:serviceaccount:%v:%v", serviceAccountNamespace, serviceAccountName),
+			Groups: []string{
+				"system:serviceaccounts",
+				fmt.Sprintf("system:serviceaccounts:%v", serviceAccountNamespace),
+				"system:authenticated",
+			},
+		},
+	}, nil
+}
+
+type podController struct {
+	namespace string
+	kind      string
+	name      string
+	uid       types.UID
+}
+
+func (c podController) String() string {
+	return fmt.Sprintf("%v %v/%v", c.kind, c.namespace, c.name)
+}
+
+// getPodController returns the controller of the pod, unless it is a DaemonSet which does not reschedule its pods
+func getPodController(p corev1.Pod) (podController, bool) {
+	owner := metav1.GetControllerOf(&p)
+	if owner == nil || owner.Kind == "DaemonSet" {
+		return podController{}, false
+	}
+	return podController{namespace: p.Namespace, kind: owner.Kind, name: owner.Name, uid: owner.UID}, true
+}
+
+// countReadyPodsOfControllers counts the ready pods of the controllers, ignoring the pods on excludedNode
+func countReadyPodsOfControllers(pods []corev1.Pod, controllers map[podController]int, excludedNode string) {
+	for _, p := range pods {
+		controller, ok := getPodController(p)
+		if _, tracked := controllers[controller]; !ok || !tracked {
+			continue
+		}
+		if p.Spec.NodeName != excludedNode && p.DeletionTimestamp == nil && isPodReady(&p) {
+			controllers[controller]++
+		}
+	}
+}
+
+// getNodeInstanceID returns the EC2 instance ID from the provider ID of the node, e.g. aws:///us-west-2a/i-0123456789abcdef0
+func getNodeInstanceID(node corev1.Node) (string, error) {
+	providerID := node.Spec.ProviderID
+	instanceID := providerID[strings.LastIndex(providerID, "/")+1:]
+	if !strings.HasPrefix(providerID, "aws://") || !strings.HasPrefix(instanceID, "i-") {
+		return "", errors.Errorf("node %v is not backed by an EC2 instance, provider ID: '%v'", node.Name, providerID)
+	}
+	return instanceID, nil
+}
+
+func (d *NodeDisruption) isHealed(kubeClientset kubernetes.Interface) (bool, string, error) {
+	_, err := kubeClientset.CoreV1().Nodes().Get(context.Background(), d.NodeName, metav1.GetOptions{})
+	if err == nil {
+		return false, "node is not deleted yet", nil
+	}
+	if !kerrors.IsNotFound(err) {
+		return false, "", err
+	}
+
+	nodes, err := kubeClientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{LabelSelector: d.selector})
+	if err != nil {
+		return false, "", errors.Wrapf(err, "failed to list nodes with selector '%v'", d.selector)
+	}
+	var readyNodes int
+	for _, node := range nodes.Items {
+		if isNodeReady(node) {
+			readyNodes++
+		}
+	}
+	if readyNodes < d.readyNodes {
+		return false, fmt.Sprintf("%v/%v nodes with selector '%v' are ready", readyNodes, d.readyNodes, d.selector), nil
+	}
+
+	pods, err := kubeClientset.CoreV1().Pods(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return false, "", errors.Wrap(err, "failed to list pods")
+	}
+	readyPods := map[podController]int{}
+	for controller := range d.podControllers {
+		readyPods[controller] = 0
+	}
+	countReadyPodsOfControllers(pods.Items, readyPods, d.NodeName)
+	for controller, expected := range d.podControllers {
+		if readyPods[controller] < expected {
+			return false, fmt.Sprintf("%v has %v/%v ready pods", controller, readyPods[controller], expected), nil
+		}
+	}
+	return true, "", nil
+}
+
+// getResourceQuotaUsage returns the used quantity of the resource in the quota and the expected quantity, which is
+// a percentage of the hard limit when the expected value ends with "% of hard"
+func getResourceQuotaUsage(quota *corev1.ResourceQuota, resourceName corev1.ResourceName, expectedValue string) (resource.Quantity, resource.Quantity, error) {
+	hard, ok := quota.Status.Hard[resourceName]
+	if !ok {
+		return resource.Quantity{}, resource.Quantity{}, errors.Errorf("resourcequota %v/%v has no hard limit for %v", quota.Namespace, quota.Name, resourceName)
+	}
+	used := quota.Status.Used[resourceName]
+
+	if percentage, ok := strings.CutSuffix(expectedValue, "% of hard"); ok {
+		ratio, err := strconv.ParseFloat(percentage, 64)
+		if err != nil {
+			return resource.Quantity{}, resource.Quantity{}, errors.Wrapf(err, "failed to parse percentage '%v'", expectedValue)
+		}
+		expected := resource.NewMilliQuantity(int64(float64(hard.MilliValue())*ratio/100), hard.Format)
+		return used, *expected, nil
+	}
+	expected, err := resource.ParseQuantity(expectedValue)
+	if err != nil {
+		return resource.Quantity{}, resource.Quantity{}, errors.Wrapf(err, "failed to parse quantity '%v'", expectedValue)
+	}
+	return used, expected, nil
+}
+
+func compareQuantities(value resource.Quantity, operator string, expectedValue resource.Quantity) (bool, error) {
+	switch cmp := value.Cmp(expectedValue); operator {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	default:
+		return false, common.Errorf(common.ErrUnsupportedOperation, "unsupported operator: '%s'", operator)
+	}
+}
+
+func isPodReady(p *corev1.Pod) bool {
+	for _, condition := range p.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+const (
+	nodeOperationCordon   = "cordon"
+	nodeOperationUncordon = "uncordon"
+	nodeOperationDrain    = "drain"
+	nodeOperationTaint    = "taint"
+	nodeOperationUntaint  = "untaint"
+	nodeOperationLabel    = "label"
+	nodeOperationUnlabel  = "unlabel"
+)
+
+// parseTaint parses a taint formatted as key=value:effect or key:effect
+func parseTaint(taint string) (corev1.Taint, error) {
+	keyValue, effect, ok := strings.Cut(taint, ":")
+	key, value, _ := strings.Cut(keyValue, "=")
+	if !ok || key == "" {
+		return corev1.Taint{}, errors.Errorf("invalid taint '%v', expected key=value:effect or key:effect", taint)
+	}
+	switch corev1.TaintEffect(effect) {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		return corev1.Taint{}, errors.Errorf("invalid taint effect '%v', expected one of %v, %v or %v", effect,
+			corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute)
+	}
+	return corev1.Taint{Key: key, Value: value, Effect: corev1.TaintEffect(effect)}, nil
+}
+
+// updateNodesWithSelector applies the mutation to every node with the selector, updating the nodes it reports as changed
+func updateNodesWithSelector(kubeClientset kubernetes.Interface, selector string, mutate func(*corev1.Node) bool) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+	nodes, err := kubeClientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list nodes with selector '%v'", selector)
+	}
+	if len(nodes.Items) == 0 {
+		return common.Errorf(common.ErrResourceNotFound, "no nodes matched selector '%v'", selector)
+	}
+	for _, n := range nodes.Items {
+		nodeName := n.Name
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			node, err := kubeClientset.CoreV1().Nodes().Get(context.Background(), nodeName, metav1.GetOptions{})
+			if err != nil {
+				return common.WrapNotFound(err)
+			}
+			if !mutate(node) {
+				return nil
+			}
+			_, err = kubeClientset.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{})
+			return err
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to update node %v", nodeName)
+		}
+		log.Infof("updated node %v", nodeName)
+	}
+	return nil
+}
+
+// nodesWithSelectorShouldMatch waits until the condition is, or is not, met by every node with the selector
+func nodesWithSelectorShouldMatch(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, selector, description string, expected bool, condition func(corev1.Node) bool) error {
+	var counter int
+
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+	state := "have"
+	if !expected {
+		state = "not have"
+	}
+	for {
+		var mismatched []string
+		nodes, err := kubeClientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return errors.Wrapf(err, "failed to list nodes with selector '%v'", selector)
+		}
+		for _, node := range nodes.Items {
+			if condition(node) != expected {
+				mismatched = append(mismatched, node.Name)
+			}
+		}
+		if len(nodes.Items) > 0 && len(mismatched) == 0 {
+			log.Infof("nodes with selector '%v' %v the %v", selector, state, description)
+			return nil
+		}
+		if counter >= w.GetTries() {
+			if len(nodes.Items) == 0 {
+				return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for nodes with selector '%v', no nodes matched", selector)
+			}
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for nodes with selector '%v' to %v the %v, mismatched nodes: %v",
+				selector, state, description, strings.Join(mismatched, ", "))
+		}
+		log.Infof("waiting for nodes with selector '%v' to %v the %v, mismatched nodes: %v", selector, state, description, strings.Join(mismatched, ", "))
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func setNodeUnschedulable(kubeClientset kubernetes.Interface, nodeName string, unschedulable bool) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := kubeClientset.CoreV1().Nodes().Get(context.Background(), nodeName, metav1.GetOptions{})
+		if err != nil {
+			return common.WrapNotFound(err)
+		}
+		if node.Spec.Unschedulable == unschedulable {
+			return nil
+		}
+		node.Spec.Unschedulable = unschedulable
+		if _, err := kubeClientset.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		log.Infof("set node %v unschedulable to %v", nodeName, unschedulable)
+		return nil
+	})
+}
+
+// getPodsToDrain returns the pods of the node that a drain evicts, DaemonSet pods are recreated on the node and mirror
+// pods are managed by the kubelet so both are skipped
+func getPodsToDrain(ctx context.Context, kubeClientset kubernetes.Interface, nodeName string) ([]corev1.Pod, error) {
+	pods, err := kubeClientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("spec.nodeName=%v", nodeName),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list pods of node %v", nodeName)
+	}
+	var drained []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != nodeName {
+			continue
+		}
+		if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+			continue
+		}
+		if owner := metav1.GetControllerOf(&pod); owner != nil && owner.Kind == "DaemonSet" {
+			continue
+		}
+		drained = append(drained, pod)
+	}
+	return drained, nil
+}
+
+func isNodeReady(n corev1.Node) bool {
+	for _, condition := range n.Status.Conditions {
+		if condition.Type == "Ready" {
+			if condition.Status == "True" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+const (
+	storageTestImage     = "busybox:1.36"
+	clusterDomain        = "cluster.local"
+	storageTestMountPath = "/data"
+	storageTestData      = "kubedog"
+
+	reachabilityTestTimeoutSeconds = 5
+
+	// set by the external-provisioner on every dynamically provisioned persistentvolume
+	provisionedByAnnotation = "pv.kubernetes.io/provisioned-by"
+
+	webhookTypeValidating = "validating"
+	webhookTypeMutating   = "mutating"
+)
+
+func newStorageTestClaim(name, namespace, storageClassName string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClassName,
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	}
+}
+
+// newStorageTestPod returns a pod that writes to the claim and reads the data back, it only succeeds when the data matches
+func newStorageTestPod(name, namespace, claimName string) *corev1.Pod {
+	file := fmt.Sprintf("%v/%v", storageTestMountPath, name)
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:    "storage-test",
+					Image:   storageTestImage,
+					Command: []string{"sh", "-c", fmt.Sprintf("echo %v > %v && grep -qx %v %v", storageTestData, file, storageTestData, file)},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "data", MountPath: storageTestMountPath},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newLimitRangeTestPod returns a pod without resources, so the limitranges of the namespace default them
+func newLimitRangeTestPod(name, namespace string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:    "limitrange-test",
+					Image:   storageTestImage,
+					Command: []string{"true"},
+				},
+			},
+		},
+	}
+}
+
+// getLimitRangeDefaults returns the container defaults the LimitRanger admission plugin applies, the first limitrange
+// setting a resource wins and the default limit is also the default request when there is none
+func getLimitRangeDefaults(limitRanges []corev1.LimitRange) corev1.ResourceRequirements {
+	defaults := corev1.ResourceRequirements{Limits: corev1.ResourceList{}, Requests: corev1.ResourceList{}}
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			for resourceName, quantity := range item.Default {
+				if _, ok := defaults.Limits[resourceName]; !ok {
+					defaults.Limits[resourceName] = quantity
+				}
+			}
+			for resourceName, quantity := range item.DefaultRequest {
+				if _, ok := defaults.Requests[resourceName]; !ok {
+					defaults.Requests[resourceName] = quantity
+				}
+			}
+		}
+	}
+	for resourceName, quantity := range defaults.Limits {
+		if _, ok := defaults.Requests[resourceName]; !ok {
+			defaults.Requests[resourceName] = quantity
+		}
+	}
+	return defaults
+}
+
+func getMissingResources(kind string, expected, actual corev1.ResourceList) []string {
+	var missing []string
+	for resourceName, quantity := range expected {
+		got, ok := actual[resourceName]
+		if !ok {
+			missing = append(missing, fmt.Sprintf("%v %v is not set, expected %v", kind, resourceName, quantity.String()))
+		} else if got.Cmp(quantity) != 0 {
+			missing = append(missing, fmt.Sprintf("%v %v is %v, expected %v", kind, resourceName, got.String(), quantity.String()))
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+type imagePullState string
+
+const (
+	imagePullStatePending imagePullState = "Pending"
+	imagePullStatePulled  imagePullState = "Pulled"
+	imagePullStateFailed  imagePullState = "Failed"
+)
+
+// newImagePullTestPod returns a pod running the image with the image pull secret, the image is always pulled so the secret is used
+func newImagePullTestPod(name, namespace, image, secretName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:    corev1.RestartPolicyNever,
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: secretName}},
+			Containers: []corev1.Container{
+				{
+					Name:            "image-pull-test",
+					Image:           image,
+					ImagePullPolicy: corev1.PullAlways,
+				},
+			},
+		},
+	}
+}
+
+// getImagePullState tells whether the image of the pod was pulled, which is the case once its container is running or terminated
+func getImagePullState(p *corev1.Pod) (imagePullState, string) {
+	for _, status := range p.Status.ContainerStatuses {
+		switch {
+		case status.State.Running != nil:
+			return imagePullStatePulled, "container is running"
+		case status.State.Terminated != nil:
+			return imagePullStatePulled, fmt.Sprintf("container terminated with exit code %v", status.State.Terminated.ExitCode)
+		case status.State.Waiting != nil:
+			switch waiting := status.State.Waiting; waiting.Reason {
+			case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+				return imagePullStateFailed, fmt.Sprintf("%v: %v", waiting.Reason, waiting.Message)
+			default:
+				return imagePullStatePending, fmt.Sprintf("container is waiting: %v", waiting.Reason)
+			}
+		}
+	}
+	return imagePullStatePending, fmt.Sprintf("pod is %v", p.Status.Phase)
+}
+
+func waitForPodCompletion(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string) error {
+	p, err := waitForPodTermination(ctx, kubeClientset, w, name, namespace)
+	if err != nil {
+		return err
+	}
+	if p.Status.Phase == corev1.PodFailed {
+		return errors.Errorf("pod %v/%v failed: %v", namespace, name, p.Status.Message)
+	}
+	return nil
+}
+
+// waitForPodTermination waits for the pod to succeed or fail
+func waitForPodTermination(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string) (*corev1.Pod, error) {
+	var counter int
+	for {
+		if counter >= w.GetTries() {
+			return nil, common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for pod %v/%v to complete", namespace, name)
+		}
+		p, err := kubeClientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, common.WrapNotFound(err)
+		}
+		switch p.Status.Phase {
+		case corev1.PodSucceeded, corev1.PodFailed:
+			return p, nil
+		}
+		log.Infof("pod %v/%v is %v", namespace, name, p.Status.Phase)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// newDNSTestPod returns a pod that resolves the hostname, and only succeeds when the records include expectedAddress if one is given
+func newDNSTestPod(name, namespace, hostname, expectedAddress string) *corev1.Pod {
+	command := fmt.Sprintf("nslookup %v", hostname)
+	if expectedAddress != "" {
+		command = fmt.Sprintf("nslookup %v | tee /dev/stderr | grep -qw %v", hostname, expectedAddress)
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:    "dns-test",
+					Image:   storageTestImage,
+					Command: []string{"sh", "-c", command},
+				},
+			},
+		},
+	}
+}
+
+// newReachabilityTestPod returns a pod that only succeeds when it can open a TCP connection to the host and port
+func newReachabilityTestPod(name, namespace, host string, port int) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:    "reachability-test",
+					Image:   storageTestImage,
+					Command: []string{"nc", "-z", "-w", strconv.Itoa(reachabilityTestTimeoutSeconds), host, strconv.Itoa(port)},
+				},
+			},
+		},
+	}
+}
+
+const (
+	runJobContainerName = "run"
+	// set by the job controller on the pods of a Job
+	jobNameLabel = "job-name"
+)
+
+func newRunJob(name, namespace, image, command string) *batchv1.Job {
+	var backoffLimit int32
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    runJobContainerName,
+							Image:   image,
+							Command: []string{"sh", "-c", command},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// waitForJobPodTermination waits for the container of a pod of the Job to terminate
+func waitForJobPodTermination(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string) (*corev1.Pod, int32, error) {
+	var counter int
+	for {
+		if counter >= w.GetTries() {
+			return nil, 0, common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for job %v/%v to complete", namespace, name)
+		}
+		pods, err := kubeClientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%v=%v", jobNameLabel, name),
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		for i, p := range pods.Items {
+			for _, status := range p.Status.ContainerStatuses {
+				if status.Name == runJobContainerName && status.State.Terminated != nil {
+					return &pods.Items[i], status.State.Terminated.ExitCode, nil
+				}
+			}
+		}
+		log.Infof("waiting for job %v/%v to complete", namespace, name)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return nil, 0, err
+		}
+	}
+}
+
+// waitForJobCompletion waits for the Job to have the Complete condition and fails early when it has the Failed condition
+func waitForJobCompletion(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, artifactsPath string) (*batchv1.Job, error) {
+	var counter int
+
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	for {
+		job, err := kubeClientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get job %v/%v", namespace, name)
+		}
+		if condition := getJobCondition(job, batchv1.JobComplete); condition != nil {
+			log.Infof("job %v/%v is complete", namespace, name)
+			return job, nil
+		}
+		if condition := getJobCondition(job, batchv1.JobFailed); condition != nil {
+			exportJobPodLogs(kubeClientset, job, artifactsPath)
+			return nil, errors.Errorf("job %v/%v failed: %v, %v", namespace, name, condition.Reason, condition.Message)
+		}
+		if counter >= w.GetTries() {
+			exportJobPodLogs(kubeClientset, job, artifactsPath)
+			return nil, common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for job %v/%v to complete, %d active, %d succeeded and %d failed pods", namespace, name, job.Status.Active, job.Status.Succeeded, job.Status.Failed)
+		}
+		log.Infof("waiting for job %v/%v to complete", namespace, name)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// getJobCondition returns the condition of the type when it is true
+func getJobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i, condition := range job.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// exportJobPodLogs writes the logs of every container of the pods of the Job to the artifacts path
+func exportJobPodLogs(kubeClientset kubernetes.Interface, job *batchv1.Job, artifactsPath string) {
+	pods, err := kubeClientset.CoreV1().Pods(job.Namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%v=%v", jobNameLabel, job.Name),
+	})
+	if err != nil {
+		log.Warnf("failed to list pods of job %v/%v: %v", job.Namespace, job.Name, err)
+		return
+	}
+	for i := range pods.Items {
+		p := &pods.Items[i]
+		for _, container := range p.Spec.Containers {
+			filePath, err := exportPodLogs(kubeClientset, p, container.Name, artifactsPath)
+			if err != nil {
+				log.Warnf("failed to export logs of job %v/%v: %v", job.Namespace, job.Name, err)
+				continue
+			}
+			log.Infof("exported logs of job %v/%v to '%s'", job.Namespace, job.Name, filePath)
+		}
+	}
+}
+
+// exportPodLogs writes the logs of the container of the pod to the artifacts path
+func exportPodLogs(kubeClientset kubernetes.Interface, p *corev1.Pod, container, artifactsPath string) (string, error) {
+	if err := os.MkdirAll(artifactsPath, 0755); err != nil {
+		return "", errors.Wrapf(err, "failed to create artifacts directory '%s'", artifactsPath)
+	}
+	logs, err := kubeClientset.CoreV1().Pods(p.Namespace).GetLogs(p.Name, &corev1.PodLogOptions{Container: container}).Stream(context.Background())
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get logs of pod %v/%v", p.Namespace, p.Name)
+	}
+	defer logs.Close()
+	fileName := p.Name
+	if len(p.Spec.Containers) > 1 {
+		fileName = fmt.Sprintf("%s-%s", p.Name, container)
+	}
+	filePath := filepath.Join(artifactsPath, fmt.Sprintf("%s.log", fileName))
+	f, err := os.Create(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, logs); err != nil {
+		return "", errors.Wrapf(err, "failed to write logs to '%s'", filePath)
+	}
+	return filePath, nil
+}
+
+// lookupEnvironmentVariables returns the values of a comma separated list of environment variable names or globs like PREFIX_*,
+// every name must be set and every glob must match at least one environment variable
+func lookupEnvironmentVariables(environmentVariables string) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	for _, environmentVariable := range util.DeleteEmpty(strings.Split(environmentVariables, ",")) {
+		if !strings.ContainsAny(environmentVariable, "*?[") {
+			value, ok := os.LookupEnv(environmentVariable)
+			if !ok {
+				return nil, errors.Errorf("couldn't lookup environment variable '%s'", environmentVariable)
+			}
+			data[environmentVariable] = []byte(value)
+			continue
+		}
+		var found bool
+		for _, env := range os.Environ() {
+			key, value, _ := strings.Cut(env, "=")
+			matched, err := filepath.Match(environmentVariable, key)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid environment variable glob '%s'", environmentVariable)
+			}
+			if matched {
+				data[key] = []byte(value)
+				found = true
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("couldn't find environment variables matching '%s'", environmentVariable)
+		}
+	}
+	if len(data) == 0 {
+		return nil, errors.Errorf("couldn't lookup environment variable '%s'", environmentVariables)
+	}
+	return data, nil
+}
+
+// parseLiterals parses comma separated key=value literals, a value can contain '=' but not ','
+func parseLiterals(literals string) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	for _, literal := range util.DeleteEmpty(strings.Split(literals, ",")) {
+		key, value, found := strings.Cut(literal, "=")
+		if !found || key == "" {
+			return nil, errors.Errorf("invalid literal '%s', expected key=value", literal)
+		}
+		data[key] = []byte(value)
+	}
+	if len(data) == 0 {
+		return nil, errors.Errorf("no literals found in '%s'", literals)
+	}
+	return data, nil
+}
+
+// readFiles returns the content of the files keyed by their names, a directory adds its regular files
+func readFiles(paths []string) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	add := func(path string) error {
+		key := filepath.Base(path)
+		if _, ok := data[key]; ok {
+			return errors.Errorf("more than one file is named '%s'", key)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "couldn't read file '%s'", path)
+		}
+		data[key] = content
+		return nil
+	}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "couldn't read file '%s'", path)
+		}
+		if !info.IsDir() {
+			if err := add(path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "couldn't read directory '%s'", path)
+		}
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() {
+				continue
+			}
+			if err := add(filepath.Join(path, entry.Name())); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(data) == 0 {
+		return nil, errors.Errorf("no files found in '%s'", strings.Join(paths, ","))
+	}
+	return data, nil
+}
+
+// configMapOperation runs the operation on the configmap, create sets its data and update adds the keys to its existing data;
+// values that are not valid UTF-8 are set as binary data
+func configMapOperation(kubeClientset kubernetes.Interface, operation, name, namespace string, data map[string][]byte) error {
+	switch operation {
+	case common.OperationCreate, common.OperationSubmit:
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+		setConfigMapData(configMap, data)
+		_, err := kubeClientset.CoreV1().ConfigMaps(namespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
+		if kerrors.IsAlreadyExists(err) {
+			return fmt.Errorf("configmap '%s' already created", name)
+		}
+		return err
+	case common.OperationUpdate:
+		currentConfigMap, err := kubeClientset.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return common.WrapNotFound(err)
+		}
+		configMap := currentConfigMap.DeepCopy()
+		setConfigMapData(configMap, data)
+		_, err = kubeClientset.CoreV1().ConfigMaps(namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{})
+		return err
+	case common.OperationDelete:
+		err := kubeClientset.CoreV1().ConfigMaps(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+		if kerrors.IsNotFound(err) {
+			log.Infof("configmap '%s' was not found", name)
+			return nil
+		}
+		return err
+	default:
+		return common.Errorf(common.ErrUnsupportedOperation, "unsupported operation: '%s'", operation)
+	}
+}
+
+// setConfigMapData sets the keys in the data or binary data of the configmap, a key is removed from the other one
+func setConfigMapData(configMap *corev1.ConfigMap, data map[string][]byte) {
+	for key, value := range data {
+		if utf8.Valid(value) {
+			if configMap.Data == nil {
+				configMap.Data = map[string]string{}
+			}
+			configMap.Data[key] = string(value)
+			delete(configMap.BinaryData, key)
+			continue
+		}
+		if configMap.BinaryData == nil {
+			configMap.BinaryData = map[string][]byte{}
+		}
+		configMap.BinaryData[key] = value
+		delete(configMap.Data, key)
+	}
+}
+
+// secretOperation runs the operation on the secret, create sets its data and update adds the keys to its existing data
+func secretOperation(kubeClientset kubernetes.Interface, operation, name, namespace string, data map[string][]byte) error {
+	switch operation {
+	case common.OperationCreate, common.OperationSubmit:
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Data: data,
+		}
+		_, err := kubeClientset.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+		if kerrors.IsAlreadyExists(err) {
+			return fmt.Errorf("secret '%s' already created", name)
+		}
+		return err
+	case common.OperationUpdate:
+		currentSecret, err := kubeClientset.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return common.WrapNotFound(err)
+		}
+		secret := currentSecret.DeepCopy()
+		if len(secret.Data) == 0 {
+			secret.Data = map[string][]byte{}
+		}
+		for key, value := range data {
+			secret.Data[key] = value
+		}
+		_, err = kubeClientset.CoreV1().Secrets(namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+		return err
+	case common.OperationDelete:
+		err := kubeClientset.CoreV1().Secrets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+		if kerrors.IsNotFound(err) {
+			log.Infof("secret '%s' was not found", name)
+			return nil
+		}
+		return err
+	default:
+		return common.Errorf(common.ErrUnsupportedOperation, "unsupported operation: '%s'", operation)
+	}
+}
+
+func getWebhookClientConfigs(kubeClientset kubernetes.Interface, webhookType, name string) (map[string]admissionregistrationv1.WebhookClientConfig, error) {
+	clientConfigs := map[string]admissionregistrationv1.WebhookClientConfig{}
+	switch webhookType {
+	case webhookTypeValidating:
+		configuration, err := kubeClientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, common.WrapNotFound(err)
+		}
+		for _, webhook := range configuration.Webhooks {
+			clientConfigs[webhook.Name] = webhook.ClientConfig
+		}
+	case webhookTypeMutating:
+		configuration, err := kubeClientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, common.WrapNotFound(err)
+		}
+		for _, webhook := range configuration.Webhooks {
+			clientConfigs[webhook.Name] = webhook.ClientConfig
+		}
+	default:
+		return nil, common.Errorf(common.ErrUnsupportedOperation, "unsupported webhook type: '%s'", webhookType)
+	}
+	return clientConfigs, nil
+}
+
+func serviceShouldHaveReadyEndpoints(kubeClientset kubernetes.Interface, name, namespace string) error {
+	readyEndpoints, err := getServiceReadyEndpoints(kubeClientset, name, namespace)
+	if err != nil {
+		return err
+	}
+	if readyEndpoints == 0 {
+		return errors.Errorf("service %v/%v has no ready endpoints", namespace, name)
+	}
+	return nil
+}
+
+// getServiceReadyEndpoints counts the ready endpoints of the service in its EndpointSlices, the endpoints of the slices of
+// each address type are counted once; services without EndpointSlices fall back to the addresses of their Endpoints
+func getServiceReadyEndpoints(kubeClientset kubernetes.Interface, name, namespace string) (int, error) {
+	slices, err := kubeClientset.DiscoveryV1().EndpointSlices(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%v=%v", discoveryv1.LabelServiceName, name),
+	})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return 0, err
+	}
+	if err == nil && len(slices.Items) != 0 {
+		ready := map[string]bool{}
+		for _, slice := range slices.Items {
+			for _, endpoint := range slice.Endpoints {
+				// a nil ready condition means ready
+				if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+					continue
+				}
+				if endpoint.TargetRef != nil {
+					ready[fmt.Sprintf("%v/%v/%v", endpoint.TargetRef.Kind, endpoint.TargetRef.Namespace, endpoint.TargetRef.Name)] = true
+				} else if len(endpoint.Addresses) != 0 {
+					ready[endpoint.Addresses[0]] = true
+				}
+			}
+		}
+		return len(ready), nil
+	}
+
+	endpoints, err := kubeClientset.CoreV1().Endpoints(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	ready := map[string]bool{}
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			ready[address.IP] = true
+		}
+	}
+	return len(ready), nil
+}
+
+func cleanupStorageTest(kubeClientset kubernetes.Interface, name, namespace string) {
+	if err := kubeClientset.CoreV1().Pods(namespace).Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil {
+		log.Warnf("failed to delete pod %v/%v: %v", namespace, name, err)
+	}
+	if err := kubeClientset.CoreV1().PersistentVolumeClaims(namespace).Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil {
+		log.Warnf("failed to delete persistentvolumeclaim %v/%v: %v", namespace, name, err)
+	}
+}
+
+func workloadHasContainerImage(ctx context.Context, kubeClientset kubernetes.Interface, w common.WaiterConfig, kind, name, namespace, container, image string, getWorkload func() (*metav1.LabelSelector, *corev1.PodTemplateSpec, error)) error {
+	var counter int
+
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+	for {
+		labelSelector, template, err := getWorkload()
+		if err != nil {
+			return err
+		}
+		pending, err := getContainersWithoutImage(ctx, kubeClientset, labelSelector, template, namespace, container, image)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the images of %v %v/%v", kind, namespace, name)
+		}
+		if len(pending) == 0 {
+			log.Infof("container %v of %v %v/%v and its pods has image %v", container, kind, namespace, name, image)
+			return nil
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for container %v of %v %v/%v to have image %v, pending:\n%v", container, kind, namespace, name, image, strings.Join(pending, "\n"))
+		}
+		log.Infof("waiting for container %v of %v %v/%v to have image %v, pending: %v", container, kind, namespace, name, image, pending)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// getContainersWithoutImage describes the template and the pods that are not deleted whose container does not have the image yet,
+// the pods of previous revisions still running are pending until they are replaced
+func getContainersWithoutImage(ctx context.Context, kubeClientset kubernetes.Interface, labelSelector *metav1.LabelSelector, template *corev1.PodTemplateSpec, namespace, container, image string) ([]string, error) {
+	var templateImage string
+	for _, c := range template.Spec.Containers {
+		if c.Name == container {
+			templateImage = c.Image
+		}
+	}
+	if templateImage == "" {
+		return nil, common.Errorf(common.ErrResourceNotFound, "the pod template has no container %v", container)
+	}
+	pending := []string{}
+	if !imagesMatch(templateImage, image) {
+		pending = append(pending, fmt.Sprintf("the pod template has image %v", templateImage))
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := kubeClientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	var running int
+	for _, p := range pods.Items {
+		if p.DeletionTimestamp != nil {
+			continue
+		}
+		running++
+		podImage := ""
+		for _, status := range p.Status.ContainerStatuses {
+			if status.Name == container {
+				podImage = status.Image
+			}
+		}
+		switch {
+		case podImage == "":
+			pending = append(pending, fmt.Sprintf("pod %v has not started the container", p.Name))
+		case !imagesMatch(podImage, image):
+			pending = append(pending, fmt.Sprintf("pod %v runs image %v", p.Name, podImage))
+		}
+	}
+	if running == 0 {
+		pending = append(pending, "no pods")
+	}
+	return pending, nil
+}
+
+// imagesMatch returns true if the image references are the same once the default Docker Hub registry, library
+// repository and latest tag the container runtime adds are removed
+func imagesMatch(image, expected string) bool {
+	return normalizeImage(image) == normalizeImage(expected)
+}
+
+func normalizeImage(image string) string {
+	for _, prefix := range []string{"docker.io/", "index.docker.io/", "library/"} {
+		image = strings.TrimPrefix(image, prefix)
+	}
+	if !strings.Contains(image, "@") && strings.LastIndex(image, ":") <= strings.LastIndex(image, "/") {
+		image += ":latest"
+	}
+	return image
+}
+
+// isImageOfRepository returns true if the image reference, without its tag or digest, ends with the repository
+func isImageOfRepository(image, repository string) bool {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image == repository || strings.HasSuffix(image, "/"+repository)
+}
+
+// getNamespaceConditionMessages returns the messages of the true conditions of a terminating namespace, they tell what blocks its deletion
+func getNamespaceConditionMessages(namespace *corev1.Namespace) []string {
+	messages := []string{}
+	for _, condition := range namespace.Status.Conditions {
+		if condition.Status == corev1.ConditionTrue {
+			messages = append(messages, condition.Message)
+		}
+	}
+	return messages
+}
+
+// removeNamespaceFinalizers removes the finalizers of the namespace object and the spec finalizers the namespace controller waits on
+func removeNamespaceFinalizers(kubeClientset kubernetes.Interface, namespace *corev1.Namespace) error {
+	if len(namespace.Finalizers) != 0 {
+		namespace.Finalizers = nil
+		updated, err := kubeClientset.CoreV1().Namespaces().Update(context.Background(), namespace, metav1.UpdateOptions{})
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove the finalizers of namespace %v", namespace.Name)
+		}
+		namespace = updated
+	}
+	if len(namespace.Spec.Finalizers) != 0 {
+		namespace.Spec.Finalizers = nil
+		_, err := kubeClientset.CoreV1().Namespaces().Finalize(context.Background(), namespace, metav1.UpdateOptions{})
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to finalize namespace %v", namespace.Name)
+		}
+	}
+	log.Infof("removed the finalizers of namespace %v", namespace.Name)
+	return nil
 }
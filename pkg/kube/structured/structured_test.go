@@ -15,24 +15,45 @@ limitations under the License.
 package structured
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/keikoproj/kubedog/internal/util"
 	"github.com/keikoproj/kubedog/pkg/kube/common"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	v2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	v1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	kTesting "k8s.io/client-go/testing"
 )
 
 const (
@@ -97,6 +118,20 @@ func TestNodesWithSelectorShouldBe(t *testing.T) {
 	}
 }
 
+func TestNodesWithSelectorShouldBeCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := NodesWithSelectorShouldBeCtx(ctx, fake.NewSimpleClientset(), common.NewWaiterConfig(5, time.Minute), 1, "role=worker", common.StateFound)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("NodesWithSelectorShouldBeCtx() error = %v, want %v", err, context.Canceled)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("NodesWithSelectorShouldBeCtx() waited %v after the context was canceled", time.Since(start))
+	}
+}
+
 func TestResourceInNamespace(t *testing.T) {
 	type args struct {
 		kubeClientset kubernetes.Interface
@@ -270,6 +305,57 @@ func TestScaleDeployment(t *testing.T) {
 	}
 }
 
+func TestPauseDeployment(t *testing.T) {
+	deploymentName := "deployment1"
+	namespace := "namespace1"
+	newReplicaSet := func(name, ownerKind, ownerName string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       namespace,
+				OwnerReferences: []metav1.OwnerReference{{Kind: ownerKind, Name: ownerName}},
+			},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		getResourceWithNamespace(t, deploymentType, deploymentName, namespace),
+		newReplicaSet("deployment1-abc", "Deployment", deploymentName),
+		newReplicaSet("deployment2-abc", "Deployment", "deployment2"),
+		newReplicaSet("rollout1-abc", "Rollout", deploymentName),
+	)
+
+	if err := PauseDeployment(client, deploymentName, namespace, true); err != nil {
+		t.Fatalf("PauseDeployment() error = %v", err)
+	}
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.Background(), deploymentName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deployment.Spec.Paused {
+		t.Errorf("PauseDeployment() expected deployment to be paused")
+	}
+	if err := PauseDeployment(client, "deployment2", namespace, true); err == nil {
+		t.Errorf("PauseDeployment() expected error for a missing deployment")
+	}
+
+	existing, err := GetOwnedReplicaSets(client, "deployment", deploymentName, namespace)
+	if err != nil {
+		t.Fatalf("GetOwnedReplicaSets() error = %v", err)
+	}
+	if !reflect.DeepEqual(existing, []string{"deployment1-abc"}) {
+		t.Errorf("GetOwnedReplicaSets() = %v, want [deployment1-abc]", existing)
+	}
+	if err := ReplicaSetsShouldNotBeCreated(client, "deployment", deploymentName, namespace, existing); err != nil {
+		t.Errorf("ReplicaSetsShouldNotBeCreated() error = %v", err)
+	}
+	if _, err := client.AppsV1().ReplicaSets(namespace).Create(context.Background(), newReplicaSet("deployment1-def", "Deployment", deploymentName), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReplicaSetsShouldNotBeCreated(client, "deployment", deploymentName, namespace, existing); err == nil {
+		t.Errorf("ReplicaSetsShouldNotBeCreated() expected error for a new replicaset")
+	}
+}
+
 func TestClusterRbacIsFound(t *testing.T) {
 	type args struct {
 		kubeClientset kubernetes.Interface
@@ -310,241 +396,603 @@ func TestClusterRbacIsFound(t *testing.T) {
 	}
 }
 
-func TestListNodes(t *testing.T) {
-	type args struct {
-		kubeClientset kubernetes.Interface
+func TestServiceAccountShouldBeAbleTo(t *testing.T) {
+	// only operator in namespace operators can list deployments in any namespace
+	newClient := func() *fake.Clientset {
+		client := fake.NewSimpleClientset()
+		client.PrependReactor("create", "subjectaccessreviews", func(action kTesting.Action) (bool, runtime.Object, error) {
+			review := action.(kTesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+			attributes := review.Spec.ResourceAttributes
+			review.Status.Allowed = review.Spec.User == "system:serviceaccount:operators:operator" &&
+				attributes.Verb == "list" && attributes.Resource == "deployments" && attributes.Group == "apps" && attributes.Subresource == ""
+			return true, review, nil
+		})
+		return client
 	}
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name           string
+		kubeClientset  kubernetes.Interface
+		serviceAccount string
+		verb           string
+		resource       string
+		namespace      string
+		allowed        bool
+		wantErr        bool
 	}{
-		// TODO: add negative tests and other positive test cases
 		{
-			name: "Positive Test",
-			args: args{
-				kubeClientset: fake.NewSimpleClientset(getResource(t, nodeType, "node1")),
-			},
+			name:           "Positive Test: allowed",
+			kubeClientset:  newClient(),
+			serviceAccount: "operators/operator",
+			verb:           "list",
+			resource:       "deployments.apps",
+			namespace:      "default",
+			allowed:        true,
+		},
+		{
+			name:           "Positive Test: denied",
+			kubeClientset:  newClient(),
+			serviceAccount: "operator",
+			verb:           "list",
+			resource:       "deployments.apps",
+			namespace:      "default",
+		},
+		{
+			name:           "Positive Test: denied subresource",
+			kubeClientset:  newClient(),
+			serviceAccount: "operators/operator",
+			verb:           "list",
+			resource:       "deployments.apps/scale",
+			namespace:      "default",
+		},
+		{
+			name:           "Negative Test: expected allowed",
+			kubeClientset:  newClient(),
+			serviceAccount: "operator",
+			verb:           "list",
+			resource:       "deployments",
+			namespace:      "operators",
+			allowed:        true,
+			wantErr:        true,
+		},
+		{
+			name:           "Negative Test: service account without namespace",
+			kubeClientset:  newClient(),
+			serviceAccount: "operator",
+			verb:           "list",
+			resource:       "nodes",
+			wantErr:        true,
+		},
+		{
+			name:    "Negative Test: invalid client",
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := ListNodes(tt.args.kubeClientset); (err != nil) != tt.wantErr {
-				t.Errorf("ListNodes() error = %v, wantErr %v", err, tt.wantErr)
+			if err := ServiceAccountShouldBeAbleTo(tt.kubeClientset, tt.serviceAccount, tt.verb, tt.resource, tt.namespace, tt.allowed); (err != nil) != tt.wantErr {
+				t.Errorf("ServiceAccountShouldBeAbleTo() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func TestDaemonSetIsRunning(t *testing.T) {
-	type args struct {
-		kubeClientset kubernetes.Interface
-		expBackoff    wait.Backoff
-		name          string
-		namespace     string
+func TestNodeDisruption(t *testing.T) {
+	newNode := func(name, role, providerID string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"role": role}},
+			Spec:       corev1.NodeSpec{ProviderID: providerID},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}},
+		}
 	}
-	daemonsetName := "daemonset1"
-	namespace := "namespace1"
-	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
-	}{
-		// TODO: add negative tests and other positive test cases
-		{
-			name: "Positive Test",
-			args: args{
-				kubeClientset: fake.NewSimpleClientset(getResourceWithNamespace(t, daemonSetType, daemonsetName, namespace)),
-				expBackoff:    util.DefaultRetry,
-				name:          daemonsetName,
-				namespace:     namespace,
+	newPod := func(name, nodeName, ownerKind string) *corev1.Pod {
+		controller := true
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "namespace1",
+				OwnerReferences: []metav1.OwnerReference{{Kind: ownerKind, Name: "owner1", UID: "uid1", Controller: &controller}},
 			},
-		},
+			Spec:   corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		}
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if err := DaemonSetIsRunning(tt.args.kubeClientset, tt.args.expBackoff, tt.args.name, tt.args.namespace); (err != nil) != tt.wantErr {
-				t.Errorf("DaemonSetIsRunning() error = %v, wantErr %v", err, tt.wantErr)
+	w := common.NewWaiterConfig(1, time.Millisecond)
+
+	// no ready nodes
+	_, err := NewNodeDisruption(fake.NewSimpleClientset(newNode("node1", "system", "aws:///us-west-2a/i-1")), "role=worker")
+	if err == nil {
+		t.Errorf("NewNodeDisruption() expected error without nodes matching the selector")
+	}
+
+	// not an EC2 instance
+	_, err = NewNodeDisruption(fake.NewSimpleClientset(newNode("node1", "worker", "kind://docker/kind/node1")), "role=worker")
+	if err == nil {
+		t.Errorf("NewNodeDisruption() expected error for a node not backed by an EC2 instance")
+	}
+
+	client := fake.NewSimpleClientset(
+		newNode("node1", "worker", "aws:///us-west-2a/i-1"),
+		newNode("node2", "system", "aws:///us-west-2b/i-2"),
+		newPod("pod1", "node1", "ReplicaSet"),
+		newPod("pod2", "node2", "ReplicaSet"),
+		newPod("daemon1", "node1", "DaemonSet"),
+	)
+	disruption, err := NewNodeDisruption(client, "role=worker")
+	if err != nil {
+		t.Fatalf("NewNodeDisruption() error = %v", err)
+	}
+	if disruption.NodeName != "node1" || disruption.InstanceID != "i-1" {
+		t.Errorf("NewNodeDisruption() = %v/%v, want node1/i-1", disruption.NodeName, disruption.InstanceID)
+	}
+	if len(disruption.podControllers) != 1 {
+		t.Errorf("NewNodeDisruption() pod controllers = %v, want only the ReplicaSet", disruption.podControllers)
+	}
+
+	// node not deleted yet
+	if err := NodeDisruptionShouldBeHealed(context.Background(), client, w, disruption); err == nil {
+		t.Errorf("NodeDisruptionShouldBeHealed() expected error before the node is deleted")
+	}
+
+	// node replaced, pod not rescheduled yet
+	ctx := context.Background()
+	if err := client.CoreV1().Nodes().Delete(ctx, "node1", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CoreV1().Pods("namespace1").Delete(ctx, "pod1", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CoreV1().Nodes().Create(ctx, newNode("node3", "worker", "aws:///us-west-2a/i-3"), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := NodeDisruptionShouldBeHealed(context.Background(), client, w, disruption); err == nil {
+		t.Errorf("NodeDisruptionShouldBeHealed() expected error before the pod is rescheduled")
+	}
+
+	// pod rescheduled
+	if _, err := client.CoreV1().Pods("namespace1").Create(ctx, newPod("pod3", "node3", "ReplicaSet"), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := NodeDisruptionShouldBeHealed(context.Background(), client, w, disruption); err != nil {
+		t.Errorf("NodeDisruptionShouldBeHealed() error = %v", err)
+	}
+}
+
+func TestNodeOperationWithSelector(t *testing.T) {
+	newNode := func(name, role string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"role": role}}}
+	}
+	newPod := func(name, nodeName, ownerKind string, annotations map[string]string) *corev1.Pod {
+		controller := true
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "namespace1",
+				Annotations:     annotations,
+				OwnerReferences: []metav1.OwnerReference{{Kind: ownerKind, Name: "owner1", UID: "uid1", Controller: &controller}},
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+		}
+	}
+	newClient := func() *fake.Clientset {
+		client := fake.NewSimpleClientset(
+			newNode("node1", "worker"),
+			newNode("node2", "system"),
+			newPod("pod1", "node1", "ReplicaSet", nil),
+			newPod("pod2", "node2", "ReplicaSet", nil),
+			newPod("daemon1", "node1", "DaemonSet", nil),
+			newPod("mirror1", "node1", "Node", map[string]string{corev1.MirrorPodAnnotationKey: "hash"}),
+			newPod("protected1", "node1", "StatefulSet", nil),
+		)
+		// the fake clientset does not delete evicted pods, pods named protected are guarded by a PodDisruptionBudget
+		client.PrependReactor("create", "pods", func(action kTesting.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() != "eviction" {
+				return false, nil, nil
+			}
+			eviction := action.(kTesting.CreateAction).GetObject().(*v1.Eviction)
+			if strings.HasPrefix(eviction.Name, "protected") {
+				return true, nil, kerrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
 			}
+			return true, nil, client.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), eviction.Namespace, eviction.Name)
 		})
+		return client
 	}
-}
+	isUnschedulable := func(client kubernetes.Interface, name string) bool {
+		node, err := client.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return node.Spec.Unschedulable
+	}
+	podExists := func(client kubernetes.Interface, name string) bool {
+		_, err := client.CoreV1().Pods("namespace1").Get(context.Background(), name, metav1.GetOptions{})
+		return err == nil
+	}
+	w := common.NewWaiterConfig(1, time.Millisecond)
 
-func TestDeploymentIsRunning(t *testing.T) {
-	type args struct {
-		kubeClientset kubernetes.Interface
-		name          string
-		namespace     string
+	client := newClient()
+	if err := NodeOperationWithSelector(context.Background(), client, w, "cordon", "role=worker", -1); err != nil {
+		t.Fatalf("NodeOperationWithSelector() cordon error = %v", err)
 	}
-	deploymentName := "deployment1"
-	namespace := "namespace1"
+	if !isUnschedulable(client, "node1") || isUnschedulable(client, "node2") {
+		t.Errorf("NodeOperationWithSelector() cordon expected only node1 to be unschedulable")
+	}
+	if err := NodeOperationWithSelector(context.Background(), client, w, "uncordon", "role=worker", -1); err != nil {
+		t.Fatalf("NodeOperationWithSelector() uncordon error = %v", err)
+	}
+	if isUnschedulable(client, "node1") {
+		t.Errorf("NodeOperationWithSelector() uncordon expected node1 to be schedulable")
+	}
+
+	// drain blocked by the PodDisruptionBudget
+	err := NodeOperationWithSelector(context.Background(), client, w, "drain", "role=worker", 30)
+	if !errors.Is(err, common.ErrWaiterTimeout) || !strings.Contains(err.Error(), "namespace1/protected1") {
+		t.Errorf("NodeOperationWithSelector() drain error = %v, want a timeout listing namespace1/protected1", err)
+	}
+	if !isUnschedulable(client, "node1") {
+		t.Errorf("NodeOperationWithSelector() drain expected node1 to be cordoned")
+	}
+	for pod, exists := range map[string]bool{"pod1": false, "pod2": true, "daemon1": true, "mirror1": true, "protected1": true} {
+		if podExists(client, pod) != exists {
+			t.Errorf("NodeOperationWithSelector() drain pod %v exists = %v, want %v", pod, !exists, exists)
+		}
+	}
+
+	// drain once the PodDisruptionBudget allows it
+	if err := client.CoreV1().Pods("namespace1").Delete(context.Background(), "protected1", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := NodeOperationWithSelector(context.Background(), client, w, "drain", "role=worker", -1); err != nil {
+		t.Errorf("NodeOperationWithSelector() drain error = %v", err)
+	}
+
+	if err := NodeOperationWithSelector(context.Background(), client, w, "drain", "role=missing", -1); !errors.Is(err, common.ErrResourceNotFound) {
+		t.Errorf("NodeOperationWithSelector() error = %v, want %v", err, common.ErrResourceNotFound)
+	}
+	if err := NodeOperationWithSelector(context.Background(), client, w, "reboot", "role=worker", -1); !errors.Is(err, common.ErrUnsupportedOperation) {
+		t.Errorf("NodeOperationWithSelector() error = %v, want %v", err, common.ErrUnsupportedOperation)
+	}
+	if err := NodeCordon(client, "node3"); !kerrors.IsNotFound(err) {
+		t.Errorf("NodeCordon() error = %v, want not found", err)
+	}
+}
+
+func TestNodeTaintsAndLabels(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"role": "worker"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{"role": "worker"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node3", Labels: map[string]string{"role": "system"}}},
+	)
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	ctx := context.Background()
+
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name      string
+		mutate    func() error
+		assert    func() error
+		expectErr error
 	}{
-		// TODO: add negative tests and other positive test cases
 		{
-			name: "Positive Test",
-			args: args{
-				kubeClientset: fake.NewSimpleClientset(getResourceWithNamespace(t, deploymentType, deploymentName, namespace)),
-				name:          deploymentName,
-				namespace:     namespace,
-			},
+			name:   "Positive Test: taint",
+			mutate: func() error { return NodeTaintWithSelector(client, "taint", "role=worker", "dedicated=gpu:NoSchedule") },
+			assert: func() error { return NodeHasTaint(ctx, client, w, "role=worker", "dedicated=gpu:NoSchedule", true) },
+		},
+		{
+			name:   "Positive Test: taint overwrites the value",
+			mutate: func() error { return NodeTaintWithSelector(client, "taint", "role=worker", "dedicated=ml:NoSchedule") },
+			assert: func() error { return NodeHasTaint(ctx, client, w, "role=worker", "dedicated=gpu:NoSchedule", false) },
+		},
+		{
+			name:   "Positive Test: taint without value",
+			mutate: func() error { return nil },
+			assert: func() error { return NodeHasTaint(ctx, client, w, "role=worker", "dedicated:NoSchedule", true) },
+		},
+		{
+			name:      "Negative Test: taint on other nodes",
+			mutate:    func() error { return nil },
+			assert:    func() error { return NodeHasTaint(ctx, client, w, "role=system", "dedicated:NoSchedule", true) },
+			expectErr: common.ErrWaiterTimeout,
+		},
+		{
+			name:   "Positive Test: untaint",
+			mutate: func() error { return NodeTaintWithSelector(client, "untaint", "role=worker", "dedicated:NoSchedule") },
+			assert: func() error { return NodeHasTaint(ctx, client, w, "role=worker", "dedicated:NoSchedule", false) },
+		},
+		{
+			name:   "Positive Test: label",
+			mutate: func() error { return NodeLabelWithSelector(client, "label", "role=worker", "accelerator=nvidia") },
+			assert: func() error { return NodeHasLabel(ctx, client, w, "role=worker", "accelerator=nvidia", true) },
+		},
+		{
+			name:      "Negative Test: label value mismatch",
+			mutate:    func() error { return nil },
+			assert:    func() error { return NodeHasLabel(ctx, client, w, "role=worker", "accelerator=amd", true) },
+			expectErr: common.ErrWaiterTimeout,
+		},
+		{
+			name:   "Positive Test: unlabel",
+			mutate: func() error { return NodeLabelWithSelector(client, "unlabel", "role=worker", "accelerator") },
+			assert: func() error { return NodeHasLabel(ctx, client, w, "role=worker", "accelerator", false) },
+		},
+		{
+			name:      "Negative Test: no nodes matched",
+			mutate:    func() error { return nil },
+			assert:    func() error { return NodeHasLabel(ctx, client, w, "role=missing", "accelerator", false) },
+			expectErr: common.ErrWaiterTimeout,
+		},
+		{
+			name:      "Negative Test: mutate no nodes matched",
+			mutate:    func() error { return NodeLabelWithSelector(client, "label", "role=missing", "accelerator=nvidia") },
+			assert:    func() error { return nil },
+			expectErr: common.ErrResourceNotFound,
+		},
+		{
+			name:      "Negative Test: unsupported operation",
+			mutate:    func() error { return NodeTaintWithSelector(client, "label", "role=worker", "dedicated:NoSchedule") },
+			assert:    func() error { return nil },
+			expectErr: common.ErrUnsupportedOperation,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := DeploymentIsRunning(tt.args.kubeClientset, tt.args.name, tt.args.namespace); (err != nil) != tt.wantErr {
-				t.Errorf("DeploymentIsRunning() error = %v, wantErr %v", err, tt.wantErr)
+			err := tt.mutate()
+			if err == nil {
+				err = tt.assert()
+			}
+			if tt.expectErr == nil && err != nil {
+				t.Errorf("unexpected error = %v", err)
+			}
+			if tt.expectErr != nil && !errors.Is(err, tt.expectErr) {
+				t.Errorf("error = %v, want %v", err, tt.expectErr)
 			}
 		})
 	}
+
+	for _, taint := range []string{"dedicated=gpu", ":NoSchedule", "dedicated=gpu:NoWhere"} {
+		if err := NodeTaintWithSelector(client, "taint", "role=worker", taint); err == nil {
+			t.Errorf("NodeTaintWithSelector() expected error for invalid taint %v", taint)
+		}
+	}
+	if err := NodeLabelWithSelector(client, "label", "role=worker", "accelerator"); err == nil {
+		t.Errorf("NodeLabelWithSelector() expected error for a label without value")
+	}
 }
 
-func TestConfigMapExists(t *testing.T) {
-	type args struct {
-		kubeClientset kubernetes.Interface
-		name          string
-		namespace     string
-		key           string
-		value         string
+func TestResourceQuotaUsageShouldBe(t *testing.T) {
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "namespace1"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{
+				corev1.ResourceRequestsCPU:    resource.MustParse("2"),
+				corev1.ResourceRequestsMemory: resource.MustParse("4Gi"),
+				corev1.ResourcePods:           resource.MustParse("10"),
+			},
+			Used: corev1.ResourceList{
+				corev1.ResourceRequestsCPU:    resource.MustParse("1500m"),
+				corev1.ResourceRequestsMemory: resource.MustParse("1Gi"),
+			},
+		},
 	}
-	configMapName := "configmap1"
-	namespace := "namespace1"
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name          string
+		resourceName  string
+		operator      string
+		expectedValue string
+		wantErr       bool
 	}{
 		{
-			name: "Positive Test",
-			args: args{
-				kubeClientset: fake.NewSimpleClientset(getResourceWithNamespace(t, configMapType, configMapName, namespace)),
-				name:          configMapName,
-				namespace:     namespace,
-				key:           "key1",
-				value:         "value1",
-			},
-			wantErr: false,
+			name:          "Positive Test: percentage of hard",
+			resourceName:  "requests.memory",
+			operator:      "<",
+			expectedValue: "80% of hard",
 		},
 		{
-			name: "Negative Test",
-			args: args{
-				kubeClientset: fake.NewSimpleClientset(getResourceWithNamespace(t, configMapType, configMapName, namespace)),
-				name:          configMapName,
-				namespace:     namespace,
-				key:           "key1",
-				value:         "value2",
-			},
-			wantErr: true,
+			name:          "Positive Test: quantity",
+			resourceName:  "requests.cpu",
+			operator:      "==",
+			expectedValue: "1.5",
+		},
+		{
+			name:          "Positive Test: nothing used",
+			resourceName:  "pods",
+			operator:      "==",
+			expectedValue: "0",
+		},
+		{
+			name:          "Negative Test: percentage of hard exceeded",
+			resourceName:  "requests.cpu",
+			operator:      "<=",
+			expectedValue: "50.5% of hard",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: no hard limit",
+			resourceName:  "limits.cpu",
+			operator:      "<",
+			expectedValue: "1",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid quantity",
+			resourceName:  "requests.cpu",
+			operator:      "<",
+			expectedValue: "lots",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid operator",
+			resourceName:  "requests.cpu",
+			operator:      "~",
+			expectedValue: "1",
+			wantErr:       true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := ConfigMapDataHasKeyAndValue(tt.args.kubeClientset, tt.args.name, tt.args.namespace, tt.args.key, tt.args.value); (err != nil) != tt.wantErr {
-				t.Errorf("ConfigMapDataHasKeyAndValue() error = %v, wantErr %v", err, tt.wantErr)
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := ResourceQuotaUsageShouldBe(context.Background(), fake.NewSimpleClientset(quota), w, tt.resourceName, "compute", "namespace1", tt.operator, tt.expectedValue); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceQuotaUsageShouldBe() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func TestPersistentVolExists(t *testing.T) {
-	type args struct {
-		kubeClientset kubernetes.Interface
-		name          string
-		expectedPhase string
+func TestLimitRangeDefaultsShouldBeApplied(t *testing.T) {
+	newLimitRange := func(name string, defaults, defaultRequests corev1.ResourceList) *corev1.LimitRange {
+		return &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "namespace1"},
+			Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{
+				{Type: corev1.LimitTypePod, Max: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}},
+				{Type: corev1.LimitTypeContainer, Default: defaults, DefaultRequest: defaultRequests},
+			}},
+		}
+	}
+	limitRange := newLimitRange("limits",
+		corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+		corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+	)
+	// defaults the pods like the LimitRanger admission plugin would
+	newDefaultingClient := func(resources corev1.ResourceRequirements, objects ...runtime.Object) *fake.Clientset {
+		client := fake.NewSimpleClientset(objects...)
+		client.PrependReactor("create", "pods", func(action kTesting.Action) (bool, runtime.Object, error) {
+			p := action.(kTesting.CreateAction).GetObject().(*corev1.Pod)
+			p.Spec.Containers[0].Resources = resources
+			return true, p, nil
+		})
+		return client
 	}
-	// expectedPhase: Available|Bound|Released|Failed|Pending
-	persistentvolumeName := "persistentvolume1"
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name          string
+		kubeClientset kubernetes.Interface
+		wantErr       bool
 	}{
-		// TODO: add negative tests and other positive test cases
 		{
 			name: "Positive Test",
-			args: args{
-				kubeClientset: fake.NewSimpleClientset(getResource(t, persistentVolumeType, persistentvolumeName)),
-				name:          persistentvolumeName,
-				expectedPhase: "",
-			},
+			kubeClientset: newDefaultingClient(corev1.ResourceRequirements{
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0.5"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+			}, limitRange),
+		},
+		{
+			name: "Negative Test: wrong request",
+			kubeClientset: newDefaultingClient(corev1.ResourceRequirements{
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+			}, limitRange),
+			wantErr: true,
+		},
+		{
+			name:          "Negative Test: defaults not applied",
+			kubeClientset: fake.NewSimpleClientset(limitRange),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: no limitrange",
+			kubeClientset: fake.NewSimpleClientset(),
+			wantErr:       true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := PersistentVolExists(tt.args.kubeClientset, tt.args.name, tt.args.expectedPhase); (err != nil) != tt.wantErr {
-				t.Errorf("PersistentVolExists() error = %v, wantErr %v", err, tt.wantErr)
+			if err := LimitRangeDefaultsShouldBeApplied(tt.kubeClientset, "namespace1"); (err != nil) != tt.wantErr {
+				t.Errorf("LimitRangeDefaultsShouldBeApplied() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func TestPersistentVolClaimExists(t *testing.T) {
-	type args struct {
-		kubeClientset kubernetes.Interface
-		name          string
-		namespace     string
-		expectedPhase string
+func TestImagePullShouldBe(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-creds", Namespace: "namespace1"},
+		Type:       corev1.SecretTypeDockerConfigJson,
 	}
-	// expectedPhase: Available|Bound|Released|Failed|Pending
-	persistentvolumeClaimName := "persistentvolumeclaim1"
+	opaqueSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "opaque", Namespace: "namespace1"},
+		Type:       corev1.SecretTypeOpaque,
+	}
+	// sets the state of the container of the created pods like the kubelet would
+	newClient := func(state corev1.ContainerState) *fake.Clientset {
+		client := fake.NewSimpleClientset(secret, opaqueSecret)
+		client.PrependReactor("create", "pods", func(action kTesting.Action) (bool, runtime.Object, error) {
+			p := action.(kTesting.CreateAction).GetObject().(*corev1.Pod)
+			p.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: p.Spec.Containers[0].Name, State: state}}
+			return false, nil, nil
+		})
+		return client
+	}
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	backOff := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "unauthorized"}}
+	creating := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name          string
+		kubeClientset kubernetes.Interface
+		secretName    string
+		pulled        bool
+		wantErr       bool
 	}{
 		{
-			name: "pvc found",
-			args: args{
-				kubeClientset: fake.NewSimpleClientset(getResource(t, persistentVolumeClaimType, persistentvolumeClaimName)),
-				name:          persistentvolumeClaimName,
-				namespace:     "",
-				expectedPhase: "Bound",
-			},
-			wantErr: false,
+			name:          "Positive Test: image pulled",
+			kubeClientset: newClient(running),
+			secretName:    "registry-creds",
+			pulled:        true,
 		},
 		{
-			name: "pvc found with wrong phase",
-			args: args{
-				kubeClientset: fake.NewSimpleClientset(getResource(t, persistentVolumeClaimType, persistentvolumeClaimName)),
-				name:          persistentvolumeClaimName,
-				namespace:     "",
-				expectedPhase: "Released",
-			},
-			wantErr: true,
+			name:          "Positive Test: image pull failed",
+			kubeClientset: newClient(backOff),
+			secretName:    "registry-creds",
 		},
 		{
-			name: "pvc not found Test",
-			args: args{
-				kubeClientset: fake.NewSimpleClientset(getResource(t, persistentVolumeClaimType, "testabc")),
-				name:          persistentvolumeClaimName,
-				namespace:     "",
-				expectedPhase: "",
-			},
-			wantErr: true,
+			name:          "Negative Test: image pull failed",
+			kubeClientset: newClient(backOff),
+			secretName:    "registry-creds",
+			pulled:        true,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: image pulled",
+			kubeClientset: newClient(running),
+			secretName:    "registry-creds",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: waiter timed out",
+			kubeClientset: newClient(creating),
+			secretName:    "registry-creds",
+			pulled:        true,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: not an image pull secret",
+			kubeClientset: newClient(running),
+			secretName:    "opaque",
+			pulled:        true,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: secret not found",
+			kubeClientset: newClient(running),
+			secretName:    "missing",
+			pulled:        true,
+			wantErr:       true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := PersistentVolClaimExists(tt.args.kubeClientset, tt.args.name, tt.args.expectedPhase, tt.args.namespace); (err != nil) != tt.wantErr {
-				t.Errorf("PersistentVolClaimExists() error = %v, wantErr %v", err, tt.wantErr)
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := ImagePullShouldBe(context.Background(), tt.kubeClientset, w, "registry.example.com/app:v1", tt.secretName, "namespace1", tt.pulled); (err != nil) != tt.wantErr {
+				t.Errorf("ImagePullShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			pods, _ := tt.kubeClientset.CoreV1().Pods("namespace1").List(context.Background(), metav1.ListOptions{})
+			if len(pods.Items) != 0 {
+				t.Errorf("ImagePullShouldBe() did not delete the test pod")
 			}
 		})
 	}
 }
 
-func TestValidatePrometheusVolumeClaimTemplatesName(t *testing.T) {
+func TestListNodes(t *testing.T) {
 	type args struct {
-		kubeClientset            kubernetes.Interface
-		statefulsetName          string
-		namespace                string
-		volumeClaimTemplatesName string
+		kubeClientset kubernetes.Interface
 	}
-	statefulSetName := "statefulset1"
-	namespace := "namespace1"
-	volumeClaimTemplatesName := "volumeclaimtemplates1"
 	tests := []struct {
 		name    string
 		args    args
@@ -554,179 +1002,2238 @@ func TestValidatePrometheusVolumeClaimTemplatesName(t *testing.T) {
 		{
 			name: "Positive Test",
 			args: args{
-				kubeClientset:            fake.NewSimpleClientset(getStatefulSetWithVolumeClaimTemplate(t, statefulSetName, namespace, volumeClaimTemplatesName)),
-				statefulsetName:          statefulSetName,
-				namespace:                namespace,
-				volumeClaimTemplatesName: volumeClaimTemplatesName,
+				kubeClientset: fake.NewSimpleClientset(getResource(t, nodeType, "node1")),
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := ValidatePrometheusVolumeClaimTemplatesName(tt.args.kubeClientset, tt.args.statefulsetName, tt.args.namespace, tt.args.volumeClaimTemplatesName); (err != nil) != tt.wantErr {
-				t.Errorf("ValidatePrometheusVolumeClaimTemplatesName() error = %v, wantErr %v", err, tt.wantErr)
+			if err := ListNodes(tt.args.kubeClientset); (err != nil) != tt.wantErr {
+				t.Errorf("ListNodes() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func TestSecretDelete(t *testing.T) {
+func TestDaemonSetRolloutComplete(t *testing.T) {
 	type args struct {
 		kubeClientset kubernetes.Interface
+		w             common.WaiterConfig
 		name          string
 		namespace     string
 	}
+	daemonsetName := "daemonset1"
+	namespace := "namespace1"
+	newDaemonSet := func(generation int64, status appsv1.DaemonSetStatus) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: daemonsetName, Namespace: namespace, Generation: generation},
+			Spec:       appsv1.DaemonSetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": daemonsetName}}},
+			Status:     status,
+		}
+	}
+	oldPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "daemonset1-abcde", Namespace: namespace, Labels: map[string]string{"app": daemonsetName}},
+		Spec:       corev1.PodSpec{NodeName: "node1"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
 	tests := []struct {
 		name    string
 		args    args
 		wantErr bool
 	}{
-		// TODO: add negative tests and other positive test cases
 		{
 			name: "Positive Test",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(getResourceWithNamespace(t, daemonSetType, daemonsetName, namespace)),
+				name:          daemonsetName,
+				namespace:     namespace,
+			},
+		},
+		{
+			name: "Positive Test: rolled out",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newDaemonSet(2, appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 2, CurrentNumberScheduled: 2, UpdatedNumberScheduled: 2})),
+				name:          daemonsetName,
+				namespace:     namespace,
+			},
+		},
+		{
+			name: "Negative Test: old pods still scheduled",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newDaemonSet(2, appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 2, CurrentNumberScheduled: 2, UpdatedNumberScheduled: 1}), oldPod),
+				name:          daemonsetName,
+				namespace:     namespace,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: pods unavailable",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newDaemonSet(2, appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 2, CurrentNumberScheduled: 2, UpdatedNumberScheduled: 2, NumberUnavailable: 1})),
+				name:          daemonsetName,
+				namespace:     namespace,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: generation not observed",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newDaemonSet(2, appsv1.DaemonSetStatus{ObservedGeneration: 1})),
+				name:          daemonsetName,
+				namespace:     namespace,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: not found",
 			args: args{
 				kubeClientset: fake.NewSimpleClientset(),
-				name:          "secret1",
-				namespace:     "namespace1",
+				name:          daemonsetName,
+				namespace:     namespace,
 			},
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := SecretDelete(tt.args.kubeClientset, tt.args.name, tt.args.namespace); (err != nil) != tt.wantErr {
-				t.Errorf("SecretDelete() error = %v, wantErr %v", err, tt.wantErr)
+			tt.args.w = common.NewWaiterConfig(1, time.Millisecond)
+			if err := DaemonSetRolloutComplete(tt.args.kubeClientset, tt.args.w, tt.args.name, tt.args.namespace); (err != nil) != tt.wantErr {
+				t.Errorf("DaemonSetRolloutComplete() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func TestSecretOperationFromEnvironmentVariable(t *testing.T) {
+func TestDaemonSetIsRunning(t *testing.T) {
 	type args struct {
-		kubeClientset       kubernetes.Interface
-		operation           string
-		name                string
-		namespace           string
-		environmentVariable string
+		kubeClientset kubernetes.Interface
+		expBackoff    wait.Backoff
+		name          string
+		namespace     string
 	}
-	secretName := "secret1"
+	daemonsetName := "daemonset1"
 	namespace := "namespace1"
 	tests := []struct {
 		name    string
 		args    args
 		wantErr bool
 	}{
-		// TODO: add negative tests
+		// TODO: add negative tests and other positive test cases
 		{
-			name: "Positive Test: create/submit",
+			name: "Positive Test",
 			args: args{
-				kubeClientset:       fake.NewSimpleClientset(),
-				operation:           common.OperationCreate,
-				name:                secretName,
-				namespace:           namespace,
-				environmentVariable: "MY_TEST_SECRET",
+				kubeClientset: fake.NewSimpleClientset(getResourceWithNamespace(t, daemonSetType, daemonsetName, namespace)),
+				expBackoff:    util.DefaultRetry,
+				name:          daemonsetName,
+				namespace:     namespace,
 			},
 		},
-		{
-			name: "Positive Test: update",
-			args: args{
-				kubeClientset:       fake.NewSimpleClientset(getResourceWithNamespace(t, secretType, secretName, namespace)),
-				operation:           common.OperationUpdate,
-				name:                secretName,
-				namespace:           namespace,
-				environmentVariable: "MY_TEST_SECRET",
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := DaemonSetIsRunning(tt.args.kubeClientset, tt.args.expBackoff, tt.args.name, tt.args.namespace); (err != nil) != tt.wantErr {
+				t.Errorf("DaemonSetIsRunning() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStatefulSetPartition(t *testing.T) {
+	const (
+		name      = "db"
+		namespace = "namespace1"
+	)
+	newStatefulSet := func(partition int32) *appsv1.StatefulSet {
+		var replicas int32 = 3
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Generation: 2},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+					Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+				},
 			},
+			Status: appsv1.StatefulSetStatus{ObservedGeneration: 2, CurrentRevision: "db-1", UpdateRevision: "db-2"},
+		}
+	}
+	newPods := func(revisions ...string) []runtime.Object {
+		pods := make([]runtime.Object, 0, len(revisions))
+		for i, revision := range revisions {
+			pods = append(pods, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name + "-" + strconv.Itoa(i),
+					Namespace: namespace,
+					Labels:    map[string]string{"app": name, appsv1.StatefulSetRevisionLabel: revision},
+				},
+				Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+			})
+		}
+		return pods
+	}
+
+	client := fake.NewSimpleClientset(newStatefulSet(0))
+	if err := SetStatefulSetPartition(client, name, namespace, 2); err != nil {
+		t.Fatalf("SetStatefulSetPartition() error = %v", err)
+	}
+	sts, err := client.AppsV1().StatefulSets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partition := getStatefulSetPartition(sts); partition != 2 {
+		t.Errorf("expected partition 2, got %d", partition)
+	}
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		wantErr bool
+	}{
+		{
+			name:    "Positive Test: pods from partition updated",
+			objects: append(newPods("db-1", "db-1", "db-2"), newStatefulSet(2)),
+		},
+		{
+			name:    "Negative Test: pod from partition not updated",
+			objects: append(newPods("db-1", "db-1", "db-1"), newStatefulSet(2)),
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: pod below partition updated",
+			objects: append(newPods("db-1", "db-2", "db-2"), newStatefulSet(2)),
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: pod missing",
+			objects: append(newPods("db-1", "db-1"), newStatefulSet(2)),
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			os.Setenv(tt.args.environmentVariable, "some-test-secret-value")
-			if err := SecretOperationFromEnvironmentVariable(tt.args.kubeClientset, tt.args.operation, tt.args.name, tt.args.namespace, tt.args.environmentVariable); (err != nil) != tt.wantErr {
-				t.Errorf("SecretOperationFromEnvironmentVariable() error = %v, wantErr %v", err, tt.wantErr)
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := StatefulSetPartitionShouldBeUpdated(context.Background(), fake.NewSimpleClientset(tt.objects...), w, name, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("StatefulSetPartitionShouldBeUpdated() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func TestIngressAvailable(t *testing.T) {
+func TestDeploymentIsRunning(t *testing.T) {
 	type args struct {
 		kubeClientset kubernetes.Interface
-		w             common.WaiterConfig
 		name          string
 		namespace     string
-		port          int
-		path          string
 	}
-	ingressName := "ingress1"
+	deploymentName := "deployment1"
 	namespace := "namespace1"
 	tests := []struct {
 		name    string
 		args    args
 		wantErr bool
 	}{
-		// TODO: add positive tests, will likely need to refactor IngressAvailable to fake http.Client
+		// TODO: add negative tests and other positive test cases
 		{
-			name: "Negative Test: endpoint not available",
+			name: "Positive Test",
 			args: args{
-				kubeClientset: fake.NewSimpleClientset(getIngressWithHostname(t, ingressName, namespace, "localhost")),
-				w:             common.NewWaiterConfig(1, time.Millisecond),
-				name:          ingressName,
+				kubeClientset: fake.NewSimpleClientset(getResourceWithNamespace(t, deploymentType, deploymentName, namespace)),
+				name:          deploymentName,
 				namespace:     namespace,
-				port:          6060,
-				path:          "/",
 			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := DeploymentIsRunning(tt.args.kubeClientset, tt.args.name, tt.args.namespace); (err != nil) != tt.wantErr {
+				t.Errorf("DeploymentIsRunning() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStatefulSetIsRunning(t *testing.T) {
+	statefulSetName := "statefulset1"
+	namespace := "namespace1"
+	newStatefulSet := func(strategy appsv1.StatefulSetUpdateStrategy, status appsv1.StatefulSetStatus) *appsv1.StatefulSet {
+		replicas := int32(3)
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: statefulSetName, Namespace: namespace, Generation: 2},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas, UpdateStrategy: strategy},
+			Status:     status,
+		}
+	}
+	partition := int32(2)
+	rollingUpdate := appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType}
+	tests := []struct {
+		name    string
+		sts     *appsv1.StatefulSet
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: rolled out",
+			sts:  newStatefulSet(rollingUpdate, appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 3, CurrentRevision: "rev2", UpdateRevision: "rev2"}),
+		},
+		{
+			name:    "Negative Test: generation not observed",
+			sts:     newStatefulSet(rollingUpdate, appsv1.StatefulSetStatus{ObservedGeneration: 1, ReadyReplicas: 3, UpdatedReplicas: 3, CurrentRevision: "rev2", UpdateRevision: "rev2"}),
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: pods not ready",
+			sts:     newStatefulSet(rollingUpdate, appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 2, UpdatedReplicas: 3, CurrentRevision: "rev2", UpdateRevision: "rev2"}),
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: revision not updated",
+			sts:     newStatefulSet(rollingUpdate, appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 3, CurrentRevision: "rev1", UpdateRevision: "rev2"}),
 			wantErr: true,
 		},
+		{
+			name: "Positive Test: pods from the partition updated",
+			sts: newStatefulSet(
+				appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType, RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition}},
+				appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 1, CurrentRevision: "rev1", UpdateRevision: "rev2"},
+			),
+		},
+		{
+			name: "Positive Test: OnDelete with ready pods",
+			sts: newStatefulSet(
+				appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+				appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, CurrentRevision: "rev1", UpdateRevision: "rev2"},
+			),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := IngressAvailable(tt.args.kubeClientset, tt.args.w, tt.args.name, tt.args.namespace, tt.args.port, tt.args.path); (err != nil) != tt.wantErr {
-				t.Errorf("IngressAvailable() error = %v, wantErr %v", err, tt.wantErr)
+			kubeClientset := fake.NewSimpleClientset(tt.sts)
+			if err := StatefulSetIsRunning(kubeClientset, statefulSetName, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("StatefulSetIsRunning() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := StatefulSetRolloutComplete(context.Background(), kubeClientset, w, statefulSetName, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("StatefulSetRolloutComplete() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func TestSendTrafficToIngress(t *testing.T) {
-	type args struct {
-		kubeClientset  kubernetes.Interface
-		w              common.WaiterConfig
-		tps            int
-		name           string
-		namespace      string
-		port           int
-		path           string
-		duration       int
-		durationUnits  string
-		expectedErrors int
+func TestWorkloadHasContainerImage(t *testing.T) {
+	const (
+		namespace = "namespace1"
+		image     = "nginx:1.25"
+	)
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	template := func(image string) corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: image}, {Name: "sidecar", Image: "busybox:1.36"}}},
+		}
+	}
+	newDeployment := func(image string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace},
+			Spec:       appsv1.DeploymentSpec{Selector: selector, Template: template(image)},
+		}
+	}
+	newPod := func(name, image string, deleted bool) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": "web"}},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "web", Image: image}},
+			},
+		}
+		if deleted {
+			p.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			p.Finalizers = []string{"kubernetes"}
+		}
+		return p
+	}
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	tests := []struct {
+		name          string
+		kubeClientset kubernetes.Interface
+		fn            func(context.Context, kubernetes.Interface, common.WaiterConfig, string, string, string, string) error
+		container     string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: deployment pods run the image with the registry added by the runtime",
+			kubeClientset: fake.NewSimpleClientset(newDeployment(image), newPod("web-1", "docker.io/library/nginx:1.25", false), newPod("web-2", image, false)),
+			fn:            DeploymentHasContainerImage,
+			container:     "web",
+		},
+		{
+			name:          "Positive Test: pod of the previous revision being deleted",
+			kubeClientset: fake.NewSimpleClientset(newDeployment(image), newPod("web-1", image, false), newPod("web-0", "nginx:1.24", true)),
+			fn:            DeploymentHasContainerImage,
+			container:     "web",
+		},
+		{
+			name: "Positive Test: daemonset",
+			kubeClientset: fake.NewSimpleClientset(&appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace},
+				Spec:       appsv1.DaemonSetSpec{Selector: selector, Template: template(image)},
+			}, newPod("web-1", image, false)),
+			fn:        DaemonSetHasContainerImage,
+			container: "web",
+		},
+		{
+			name: "Negative Test: statefulset pod of the previous revision",
+			kubeClientset: fake.NewSimpleClientset(&appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace},
+				Spec:       appsv1.StatefulSetSpec{Selector: selector, Template: template(image)},
+			}, newPod("web-0", image, false), newPod("web-1", "nginx:1.24", false)),
+			fn:        StatefulSetHasContainerImage,
+			container: "web",
+			wantErr:   true,
+		},
+		{
+			name:          "Negative Test: template has another image",
+			kubeClientset: fake.NewSimpleClientset(newDeployment("nginx:1.24"), newPod("web-1", image, false)),
+			fn:            DeploymentHasContainerImage,
+			container:     "web",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: container not started",
+			kubeClientset: fake.NewSimpleClientset(newDeployment(image), newPod("web-1", image, false)),
+			fn:            DeploymentHasContainerImage,
+			container:     "sidecar",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: no pods",
+			kubeClientset: fake.NewSimpleClientset(newDeployment(image)),
+			fn:            DeploymentHasContainerImage,
+			container:     "web",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: container not found",
+			kubeClientset: fake.NewSimpleClientset(newDeployment(image), newPod("web-1", image, false)),
+			fn:            DeploymentHasContainerImage,
+			container:     "app",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: deployment not found",
+			kubeClientset: fake.NewSimpleClientset(),
+			fn:            DeploymentHasContainerImage,
+			container:     "web",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(context.Background(), tt.kubeClientset, w, "web", namespace, tt.container, image); (err != nil) != tt.wantErr {
+				t.Errorf("HasContainerImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImagesMatch(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected string
+		want     bool
+	}{
+		{image: "docker.io/library/nginx:1.25", expected: "nginx:1.25", want: true},
+		{image: "docker.io/library/nginx:latest", expected: "nginx", want: true},
+		{image: "localhost:5000/my-app", expected: "localhost:5000/my-app:latest", want: true},
+		{image: "registry.example.com/my-team/my-app@sha256:0123", expected: "registry.example.com/my-team/my-app@sha256:0123", want: true},
+		{image: "registry.example.com/my-team/my-app:v1", expected: "my-team/my-app:v1", want: false},
+		{image: "nginx:1.24", expected: "nginx:1.25", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if got := imagesMatch(tt.image, tt.expected); got != tt.want {
+				t.Errorf("imagesMatch(%v, %v) = %v, want %v", tt.image, tt.expected, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeploymentShouldRunImageDigest(t *testing.T) {
+	const (
+		namespace  = "namespace1"
+		repository = "my-team/my-app"
+		digest     = "sha256:0123456789abcdef"
+	)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "deployment1", Namespace: namespace},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app"}},
+		},
+	}
+	newPod := func(name, imageID string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": "my-app"}},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "app", Image: "123456789012.dkr.ecr.us-west-2.amazonaws.com/" + repository + ":v1.2.3", ImageID: imageID},
+					{Name: "istio-proxy", Image: "docker.io/istio/proxyv2:1.20.0", ImageID: "docker.io/istio/proxyv2@sha256:fedcba9876543210"},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name          string
+		kubeClientset kubernetes.Interface
+		repository    string
+		wantErr       bool
+	}{
+		{
+			name:          "Pods run the digest",
+			kubeClientset: fake.NewSimpleClientset(deployment, newPod("pod1", "docker-pullable://123456789012.dkr.ecr.us-west-2.amazonaws.com/"+repository+"@"+digest)),
+			repository:    repository,
+		},
+		{
+			name:          "Pod runs another digest",
+			kubeClientset: fake.NewSimpleClientset(deployment, newPod("pod1", "123456789012.dkr.ecr.us-west-2.amazonaws.com/"+repository+"@"+digest), newPod("pod2", "123456789012.dkr.ecr.us-west-2.amazonaws.com/"+repository+"@sha256:0000000000000000")),
+			repository:    repository,
+			wantErr:       true,
+		},
+		{
+			name:          "No container of the repository",
+			kubeClientset: fake.NewSimpleClientset(deployment, newPod("pod1", "123456789012.dkr.ecr.us-west-2.amazonaws.com/"+repository+"@"+digest)),
+			repository:    "other-team/my-app",
+			wantErr:       true,
+		},
+		{
+			name:          "Deployment not found",
+			kubeClientset: fake.NewSimpleClientset(),
+			repository:    repository,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := DeploymentShouldRunImageDigest(tt.kubeClientset, "deployment1", namespace, tt.repository, digest); (err != nil) != tt.wantErr {
+				t.Errorf("DeploymentShouldRunImageDigest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigMapExists(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		name          string
+		namespace     string
+		key           string
+		value         string
+	}
+	configMapName := "configmap1"
+	namespace := "namespace1"
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(getResourceWithNamespace(t, configMapType, configMapName, namespace)),
+				name:          configMapName,
+				namespace:     namespace,
+				key:           "key1",
+				value:         "value1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Negative Test",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(getResourceWithNamespace(t, configMapType, configMapName, namespace)),
+				name:          configMapName,
+				namespace:     namespace,
+				key:           "key1",
+				value:         "value2",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ConfigMapDataHasKeyAndValue(tt.args.kubeClientset, tt.args.name, tt.args.namespace, tt.args.key, tt.args.value); (err != nil) != tt.wantErr {
+				t.Errorf("ConfigMapDataHasKeyAndValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigMapOperation(t *testing.T) {
+	const (
+		configMapName = "configmap1"
+		namespace     = "namespace1"
+	)
+	directory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directory, "application.properties"), []byte("log.level=debug"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(directory, "keystore.p12"), []byte{0x30, 0x82, 0x00, 0xff}, 0600); err != nil {
+		t.Fatal(err)
+	}
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: configMapName, Namespace: namespace},
+		Data:       map[string]string{"existing": "value", "keystore.p12": "text"},
+	}
+	tests := []struct {
+		name               string
+		kubeClientset      kubernetes.Interface
+		operation          func(kubeClientset kubernetes.Interface) error
+		expectedData       map[string]string
+		expectedBinaryData map[string][]byte
+		wantErr            bool
+		wantDeleted        bool
+	}{
+		{
+			name:          "Positive Test: create from literals",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation: func(kubeClientset kubernetes.Interface) error {
+				return ConfigMapOperationFromLiterals(kubeClientset, common.OperationCreate, configMapName, namespace, "log-level=debug,replicas=3")
+			},
+			expectedData: map[string]string{"log-level": "debug", "replicas": "3"},
+		},
+		{
+			name:          "Positive Test: create from a directory",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation: func(kubeClientset kubernetes.Interface) error {
+				return ConfigMapOperationFromFiles(kubeClientset, common.OperationCreate, configMapName, namespace, []string{directory})
+			},
+			expectedData:       map[string]string{"application.properties": "log.level=debug"},
+			expectedBinaryData: map[string][]byte{"keystore.p12": {0x30, 0x82, 0x00, 0xff}},
+		},
+		{
+			name:          "Positive Test: update moves a key to binary data",
+			kubeClientset: fake.NewSimpleClientset(existing),
+			operation: func(kubeClientset kubernetes.Interface) error {
+				return ConfigMapOperationFromFiles(kubeClientset, common.OperationUpdate, configMapName, namespace, []string{filepath.Join(directory, "keystore.p12")})
+			},
+			expectedData:       map[string]string{"existing": "value"},
+			expectedBinaryData: map[string][]byte{"keystore.p12": {0x30, 0x82, 0x00, 0xff}},
+		},
+		{
+			name:          "Positive Test: delete",
+			kubeClientset: fake.NewSimpleClientset(existing),
+			operation: func(kubeClientset kubernetes.Interface) error {
+				return ConfigMapDelete(kubeClientset, configMapName, namespace)
+			},
+			wantDeleted: true,
+		},
+		{
+			name:          "Positive Test: delete not found",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation: func(kubeClientset kubernetes.Interface) error {
+				return ConfigMapDelete(kubeClientset, configMapName, namespace)
+			},
+			wantDeleted: true,
+		},
+		{
+			name:          "Negative Test: already created",
+			kubeClientset: fake.NewSimpleClientset(existing),
+			operation: func(kubeClientset kubernetes.Interface) error {
+				return ConfigMapOperationFromLiterals(kubeClientset, common.OperationCreate, configMapName, namespace, "log-level=debug")
+			},
+			wantErr: true,
+		},
+		{
+			name:          "Negative Test: update not found",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation: func(kubeClientset kubernetes.Interface) error {
+				return ConfigMapOperationFromLiterals(kubeClientset, common.OperationUpdate, configMapName, namespace, "log-level=debug")
+			},
+			wantErr: true,
+		},
+		{
+			name:          "Negative Test: unsupported operation",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation: func(kubeClientset kubernetes.Interface) error {
+				return ConfigMapOperationFromLiterals(kubeClientset, "patch", configMapName, namespace, "log-level=debug")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.operation(tt.kubeClientset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("operation error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			configMap, err := tt.kubeClientset.CoreV1().ConfigMaps(namespace).Get(context.Background(), configMapName, metav1.GetOptions{})
+			if tt.wantDeleted {
+				if !kerrors.IsNotFound(err) {
+					t.Errorf("expected configmap to be deleted, got error %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(configMap.Data, tt.expectedData) {
+				t.Errorf("expected data %v, got %v", tt.expectedData, configMap.Data)
+			}
+			if len(configMap.BinaryData) != 0 || len(tt.expectedBinaryData) != 0 {
+				if !reflect.DeepEqual(configMap.BinaryData, tt.expectedBinaryData) {
+					t.Errorf("expected binary data %v, got %v", tt.expectedBinaryData, configMap.BinaryData)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigMapKeyConvergesTo(t *testing.T) {
+	const (
+		configMapName = "cluster-autoscaler-status"
+		namespace     = "kube-system"
+	)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: configMapName, Namespace: namespace},
+		Data:       map[string]string{"health": "Healthy"},
+	}
+	tests := []struct {
+		name          string
+		kubeClientset kubernetes.Interface
+		key           string
+		value         string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: value",
+			kubeClientset: fake.NewSimpleClientset(configMap),
+			key:           "health",
+			value:         "Healthy",
+		},
+		{
+			name:          "Negative Test: value differs",
+			kubeClientset: fake.NewSimpleClientset(configMap),
+			key:           "health",
+			value:         "Unhealthy",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: key not found",
+			kubeClientset: fake.NewSimpleClientset(configMap),
+			key:           "status",
+			value:         "Healthy",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: configmap not found",
+			kubeClientset: fake.NewSimpleClientset(),
+			key:           "health",
+			value:         "Healthy",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ConfigMapKeyConvergesTo(context.Background(), tt.kubeClientset, common.NewWaiterConfig(1, time.Millisecond), configMapName, namespace, tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConfigMapKeyConvergesTo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, common.ErrWaiterTimeout) {
+				t.Errorf("expected a waiter timeout, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPersistentVolExists(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		name          string
+		expectedPhase string
+	}
+	// expectedPhase: Available|Bound|Released|Failed|Pending
+	persistentvolumeName := "persistentvolume1"
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		// TODO: add negative tests and other positive test cases
+		{
+			name: "Positive Test",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(getResource(t, persistentVolumeType, persistentvolumeName)),
+				name:          persistentvolumeName,
+				expectedPhase: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := PersistentVolExists(tt.args.kubeClientset, tt.args.name, tt.args.expectedPhase); (err != nil) != tt.wantErr {
+				t.Errorf("PersistentVolExists() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPersistentVolClaimExists(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		name          string
+		namespace     string
+		expectedPhase string
+	}
+	// expectedPhase: Available|Bound|Released|Failed|Pending
+	persistentvolumeClaimName := "persistentvolumeclaim1"
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "pvc found",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(getResource(t, persistentVolumeClaimType, persistentvolumeClaimName)),
+				name:          persistentvolumeClaimName,
+				namespace:     "",
+				expectedPhase: "Bound",
+			},
+			wantErr: false,
+		},
+		{
+			name: "pvc found with wrong phase",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(getResource(t, persistentVolumeClaimType, persistentvolumeClaimName)),
+				name:          persistentvolumeClaimName,
+				namespace:     "",
+				expectedPhase: "Released",
+			},
+			wantErr: true,
+		},
+		{
+			name: "pvc not found Test",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(getResource(t, persistentVolumeClaimType, "testabc")),
+				name:          persistentvolumeClaimName,
+				namespace:     "",
+				expectedPhase: "",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := PersistentVolClaimExists(tt.args.kubeClientset, tt.args.name, tt.args.expectedPhase, tt.args.namespace); (err != nil) != tt.wantErr {
+				t.Errorf("PersistentVolClaimExists() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePrometheusVolumeClaimTemplatesName(t *testing.T) {
+	type args struct {
+		kubeClientset            kubernetes.Interface
+		statefulsetName          string
+		namespace                string
+		volumeClaimTemplatesName string
+	}
+	statefulSetName := "statefulset1"
+	namespace := "namespace1"
+	volumeClaimTemplatesName := "volumeclaimtemplates1"
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		// TODO: add negative tests and other positive test cases
+		{
+			name: "Positive Test",
+			args: args{
+				kubeClientset:            fake.NewSimpleClientset(getStatefulSetWithVolumeClaimTemplate(t, statefulSetName, namespace, volumeClaimTemplatesName)),
+				statefulsetName:          statefulSetName,
+				namespace:                namespace,
+				volumeClaimTemplatesName: volumeClaimTemplatesName,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidatePrometheusVolumeClaimTemplatesName(tt.args.kubeClientset, tt.args.statefulsetName, tt.args.namespace, tt.args.volumeClaimTemplatesName); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePrometheusVolumeClaimTemplatesName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSecretDelete(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		name          string
+		namespace     string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		// TODO: add negative tests and other positive test cases
+		{
+			name: "Positive Test",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				name:          "secret1",
+				namespace:     "namespace1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SecretDelete(tt.args.kubeClientset, tt.args.name, tt.args.namespace); (err != nil) != tt.wantErr {
+				t.Errorf("SecretDelete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSecretOperationFromEnvironmentVariable(t *testing.T) {
+	type args struct {
+		kubeClientset       kubernetes.Interface
+		operation           string
+		name                string
+		namespace           string
+		environmentVariable string
+	}
+	secretName := "secret1"
+	namespace := "namespace1"
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		// TODO: add negative tests
+		{
+			name: "Positive Test: create/submit",
+			args: args{
+				kubeClientset:       fake.NewSimpleClientset(),
+				operation:           common.OperationCreate,
+				name:                secretName,
+				namespace:           namespace,
+				environmentVariable: "MY_TEST_SECRET",
+			},
+		},
+		{
+			name: "Positive Test: update",
+			args: args{
+				kubeClientset:       fake.NewSimpleClientset(getResourceWithNamespace(t, secretType, secretName, namespace)),
+				operation:           common.OperationUpdate,
+				name:                secretName,
+				namespace:           namespace,
+				environmentVariable: "MY_TEST_SECRET",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(tt.args.environmentVariable, "some-test-secret-value")
+			if err := SecretOperationFromEnvironmentVariable(tt.args.kubeClientset, tt.args.operation, tt.args.name, tt.args.namespace, tt.args.environmentVariable); (err != nil) != tt.wantErr {
+				t.Errorf("SecretOperationFromEnvironmentVariable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLookupEnvironmentVariables(t *testing.T) {
+	t.Setenv("KUBEDOG_TEST_USERNAME", "admin")
+	t.Setenv("KUBEDOG_TEST_PASSWORD", "password")
+	t.Setenv("KUBEDOG_OTHER", "other")
+	tests := []struct {
+		name                 string
+		environmentVariables string
+		want                 map[string][]byte
+		wantErr              bool
+	}{
+		{
+			name:                 "Positive Test: single variable",
+			environmentVariables: "KUBEDOG_OTHER",
+			want:                 map[string][]byte{"KUBEDOG_OTHER": []byte("other")},
+		},
+		{
+			name:                 "Positive Test: list of variables",
+			environmentVariables: "KUBEDOG_TEST_USERNAME,KUBEDOG_OTHER",
+			want:                 map[string][]byte{"KUBEDOG_TEST_USERNAME": []byte("admin"), "KUBEDOG_OTHER": []byte("other")},
+		},
+		{
+			name:                 "Positive Test: glob",
+			environmentVariables: "KUBEDOG_TEST_*",
+			want:                 map[string][]byte{"KUBEDOG_TEST_USERNAME": []byte("admin"), "KUBEDOG_TEST_PASSWORD": []byte("password")},
+		},
+		{
+			name:                 "Negative Test: variable not set",
+			environmentVariables: "KUBEDOG_OTHER,KUBEDOG_NOT_SET",
+			wantErr:              true,
+		},
+		{
+			name:                 "Negative Test: glob does not match",
+			environmentVariables: "KUBEDOG_NOT_SET_*",
+			wantErr:              true,
+		},
+		{
+			name:                 "Negative Test: empty list",
+			environmentVariables: ",",
+			wantErr:              true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lookupEnvironmentVariables(tt.environmentVariables)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lookupEnvironmentVariables() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lookupEnvironmentVariables() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretOperationFromFile(t *testing.T) {
+	const (
+		secretName = "secret1"
+		namespace  = "namespace1"
+		key        = "keystore.p12"
+	)
+	content := []byte{0x30, 0x82, 0x00, 0xff, 0x0a}
+	filePath := filepath.Join(t.TempDir(), key)
+	if err := os.WriteFile(filePath, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name          string
+		kubeClientset kubernetes.Interface
+		operation     string
+		filePath      string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: create",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation:     common.OperationCreate,
+			filePath:      filePath,
+		},
+		{
+			name:          "Positive Test: update",
+			kubeClientset: fake.NewSimpleClientset(getResourceWithNamespace(t, secretType, secretName, namespace)),
+			operation:     common.OperationUpdate,
+			filePath:      filePath,
+		},
+		{
+			name:          "Negative Test: file not found",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation:     common.OperationCreate,
+			filePath:      filepath.Join(t.TempDir(), "not-found"),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: already created",
+			kubeClientset: fake.NewSimpleClientset(getResourceWithNamespace(t, secretType, secretName, namespace)),
+			operation:     common.OperationCreate,
+			filePath:      filePath,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SecretOperationFromFile(tt.kubeClientset, tt.operation, secretName, namespace, key, tt.filePath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SecretOperationFromFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			secret, err := tt.kubeClientset.CoreV1().Secrets(namespace).Get(context.Background(), secretName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(secret.Data[key], content) {
+				t.Errorf("expected key %v to be %v, got %v", key, content, secret.Data[key])
+			}
+		})
+	}
+}
+
+func TestSecretOperationFromLiterals(t *testing.T) {
+	const (
+		secretName = "secret1"
+		namespace  = "namespace1"
+	)
+	tests := []struct {
+		name          string
+		kubeClientset kubernetes.Interface
+		operation     string
+		literals      string
+		expectedData  map[string][]byte
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: create with several keys",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation:     common.OperationCreate,
+			literals:      "username=admin,password=a=b",
+			expectedData:  map[string][]byte{"username": []byte("admin"), "password": []byte("a=b")},
+		},
+		{
+			name: "Positive Test: update adds the keys",
+			kubeClientset: fake.NewSimpleClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: namespace},
+				Data:       map[string][]byte{"existing": []byte("value")},
+			}),
+			operation:    common.OperationUpdate,
+			literals:     "username=admin",
+			expectedData: map[string][]byte{"existing": []byte("value"), "username": []byte("admin")},
+		},
+		{
+			name:          "Negative Test: literal without value",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation:     common.OperationCreate,
+			literals:      "username",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: literal without key",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation:     common.OperationCreate,
+			literals:      "=admin",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SecretOperationFromLiterals(tt.kubeClientset, tt.operation, secretName, namespace, tt.literals)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SecretOperationFromLiterals() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			secret, err := tt.kubeClientset.CoreV1().Secrets(namespace).Get(context.Background(), secretName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(secret.Data, tt.expectedData) {
+				t.Errorf("expected data %v, got %v", tt.expectedData, secret.Data)
+			}
+		})
+	}
+}
+
+func TestSecretOperationFromFiles(t *testing.T) {
+	const (
+		secretName = "secret1"
+		namespace  = "namespace1"
+	)
+	directory := t.TempDir()
+	files := map[string][]byte{
+		"tls.crt": []byte("certificate"),
+		"tls.key": {0x30, 0x82, 0x00, 0xff},
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(directory, name), content, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(directory, "nested"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	otherDirectory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(otherDirectory, "tls.crt"), []byte("other"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name          string
+		kubeClientset kubernetes.Interface
+		operation     string
+		paths         []string
+		expectedData  map[string][]byte
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: create from files",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation:     common.OperationCreate,
+			paths:         []string{filepath.Join(directory, "tls.crt"), filepath.Join(directory, "tls.key")},
+			expectedData:  files,
+		},
+		{
+			name:          "Positive Test: create from a directory skips subdirectories",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation:     common.OperationCreate,
+			paths:         []string{directory},
+			expectedData:  files,
+		},
+		{
+			name:          "Negative Test: duplicate file names",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation:     common.OperationCreate,
+			paths:         []string{directory, otherDirectory},
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: empty directory",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation:     common.OperationCreate,
+			paths:         []string{t.TempDir()},
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: file not found",
+			kubeClientset: fake.NewSimpleClientset(),
+			operation:     common.OperationCreate,
+			paths:         []string{filepath.Join(directory, "not-found")},
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SecretOperationFromFiles(tt.kubeClientset, tt.operation, secretName, namespace, tt.paths)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SecretOperationFromFiles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			secret, err := tt.kubeClientset.CoreV1().Secrets(namespace).Get(context.Background(), secretName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(secret.Data, tt.expectedData) {
+				t.Errorf("expected data %v, got %v", tt.expectedData, secret.Data)
+			}
+		})
+	}
+}
+
+func TestSecretKeyShouldHaveValue(t *testing.T) {
+	const (
+		secretName = "secret1"
+		namespace  = "namespace1"
+	)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: namespace},
+		Data:       map[string][]byte{"password": []byte("s3cr3t")},
+	}
+	tests := []struct {
+		name          string
+		kubeClientset kubernetes.Interface
+		key           string
+		value         string
+		base64Encoded bool
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: value",
+			kubeClientset: fake.NewSimpleClientset(secret),
+			key:           "password",
+			value:         "s3cr3t",
+		},
+		{
+			name:          "Positive Test: base64 value",
+			kubeClientset: fake.NewSimpleClientset(secret),
+			key:           "password",
+			value:         "czNjcjN0",
+			base64Encoded: true,
+		},
+		{
+			name:          "Negative Test: value differs",
+			kubeClientset: fake.NewSimpleClientset(secret),
+			key:           "password",
+			value:         "other",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid base64 value",
+			kubeClientset: fake.NewSimpleClientset(secret),
+			key:           "password",
+			value:         "not base64",
+			base64Encoded: true,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: key not found",
+			kubeClientset: fake.NewSimpleClientset(secret),
+			key:           "username",
+			value:         "s3cr3t",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: secret not found",
+			kubeClientset: fake.NewSimpleClientset(),
+			key:           "password",
+			value:         "s3cr3t",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SecretKeyShouldHaveValue(context.Background(), tt.kubeClientset, common.NewWaiterConfig(1, time.Millisecond), secretName, namespace, tt.key, tt.value, tt.base64Encoded)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SecretKeyShouldHaveValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), "s3cr3t") {
+				t.Errorf("expected the error not to contain the secret value, got %v", err)
+			}
+		})
+	}
+}
+
+func TestIngressAvailable(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		w             common.WaiterConfig
+		name          string
+		namespace     string
+		port          int
+		path          string
+	}
+	ingressName := "ingress1"
+	namespace := "namespace1"
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		// TODO: add positive tests, will likely need to refactor IngressAvailable to fake http.Client
+		{
+			name: "Negative Test: endpoint not available",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(getIngressWithHostname(t, ingressName, namespace, "localhost")),
+				w:             common.NewWaiterConfig(1, time.Millisecond),
+				name:          ingressName,
+				namespace:     namespace,
+				port:          6060,
+				path:          "/",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := IngressAvailable(tt.args.kubeClientset, tt.args.w, tt.args.name, tt.args.namespace, tt.args.port, tt.args.path); (err != nil) != tt.wantErr {
+				t.Errorf("IngressAvailable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIngressAvailableWithRequest(t *testing.T) {
+	type args struct {
+		kubeClientset      kubernetes.Interface
+		w                  common.WaiterConfig
+		name               string
+		namespace          string
+		port               int
+		path               string
+		method             string
+		headers            string
+		body               string
+		expectedStatusCode int
+	}
+	ingressName := "ingress1"
+	namespace := "namespace1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Header.Get("Authorization") != "Bearer some-token":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.Host != "some.host.com":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPost && string(body) != `{"key":"value"}`:
+			w.WriteHeader(http.StatusBadRequest)
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := "Authorization: Bearer some-token; Host: some.host.com"
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: GET with headers",
+			args: args{
+				method:             http.MethodGet,
+				headers:            headers,
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			name: "Positive Test: POST with headers and body",
+			args: args{
+				method:             "post",
+				headers:            headers,
+				body:               `{"key":"value"}`,
+				expectedStatusCode: http.StatusCreated,
+			},
+		},
+		{
+			name: "Positive Test: expected status is not 200",
+			args: args{
+				method:             http.MethodGet,
+				expectedStatusCode: http.StatusUnauthorized,
+			},
+		},
+		{
+			name: "Negative Test: unexpected status",
+			args: args{
+				method:             http.MethodPost,
+				headers:            headers,
+				expectedStatusCode: http.StatusCreated,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid headers",
+			args: args{
+				method:             http.MethodGet,
+				headers:            "Authorization",
+				expectedStatusCode: http.StatusOK,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.kubeClientset = fake.NewSimpleClientset(getIngressWithHostname(t, ingressName, namespace, serverURL.Hostname()))
+			tt.args.w = common.NewWaiterConfig(1, time.Millisecond)
+			tt.args.name = ingressName
+			tt.args.namespace = namespace
+			tt.args.port = port
+			tt.args.path = "/"
+			if err := IngressAvailableWithRequest(tt.args.kubeClientset, tt.args.w, tt.args.name, tt.args.namespace, tt.args.port, tt.args.path, tt.args.method, tt.args.headers, tt.args.body, tt.args.expectedStatusCode); (err != nil) != tt.wantErr {
+				t.Errorf("IngressAvailableWithRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIngressResponseBody(t *testing.T) {
+	ingressName := "ingress1"
+	namespace := "namespace1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			_, _ = w.Write([]byte(`{"status":"ok","checks":[{"name":"db","healthy":true}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":"error","message":"database unavailable"}`))
+		}
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubeClientset := fake.NewSimpleClientset(getIngressWithHostname(t, ingressName, namespace, serverURL.Hostname()))
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	tests := []struct {
+		name    string
+		fn      func() error
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: body contains",
+			fn: func() error {
+				return IngressResponseBodyShould(context.Background(), kubeClientset, w, ingressName, namespace, port, "/health", "contains", `"status":"ok"`)
+			},
+		},
+		{
+			name: "Negative Test: body does not contain",
+			fn: func() error {
+				return IngressResponseBodyShould(context.Background(), kubeClientset, w, ingressName, namespace, port, "/", "contains", `"status":"ok"`)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Positive Test: body matches",
+			fn: func() error {
+				return IngressResponseBodyShould(context.Background(), kubeClientset, w, ingressName, namespace, port, "/", "matches", `"message":"database \w+"`)
+			},
+		},
+		{
+			name: "Negative Test: invalid regex",
+			fn: func() error {
+				return IngressResponseBodyShould(context.Background(), kubeClientset, w, ingressName, namespace, port, "/", "matches", `(`)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: unsupported operator",
+			fn: func() error {
+				return IngressResponseBodyShould(context.Background(), kubeClientset, w, ingressName, namespace, port, "/", "equals", "")
+			},
+			wantErr: true,
+		},
+		{
+			name: "Positive Test: json path",
+			fn: func() error {
+				return IngressResponseBodyJSONPathShouldBe(context.Background(), kubeClientset, w, ingressName, namespace, port, "/health", ".status", "ok")
+			},
+		},
+		{
+			name: "Positive Test: json path with braces and index",
+			fn: func() error {
+				return IngressResponseBodyJSONPathShouldBe(context.Background(), kubeClientset, w, ingressName, namespace, port, "/health", "{.checks[0].healthy}", "true")
+			},
+		},
+		{
+			name: "Negative Test: status 200 with error payload",
+			fn: func() error {
+				return IngressResponseBodyJSONPathShouldBe(context.Background(), kubeClientset, w, ingressName, namespace, port, "/", ".status", "ok")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewHTTPStatusCodeCheck(t *testing.T) {
+	tests := []struct {
+		statusCodes string
+		passing     []int
+		failing     []int
+		wantErr     bool
+	}{
+		{statusCodes: "200", passing: []int{200}, failing: []int{201, 404}},
+		{statusCodes: "2xx", passing: []int{200, 204, 299}, failing: []int{199, 300}},
+		{statusCodes: "200-204", passing: []int{200, 204}, failing: []int{205, 500}},
+		{statusCodes: "204-200", wantErr: true},
+		{statusCodes: "6xx", wantErr: true},
+		{statusCodes: "ok", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.statusCodes, func(t *testing.T) {
+			check, err := NewHTTPStatusCodeCheck(tt.statusCodes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewHTTPStatusCodeCheck() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, statusCode := range tt.passing {
+				if err := check.Validate(&HTTPResponse{StatusCode: statusCode}); err != nil {
+					t.Errorf("expected status %d to pass, got %v", statusCode, err)
+				}
+			}
+			for _, statusCode := range tt.failing {
+				if err := check.Validate(&HTTPResponse{StatusCode: statusCode}); err == nil {
+					t.Errorf("expected status %d to fail", statusCode)
+				}
+			}
+		})
+	}
+}
+
+func TestEndpointResponseShould(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Header.Get("Authorization") != "Bearer some-token":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"received":%q}`, body)
+		default:
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+	header := http.Header{"Authorization": []string{"Bearer some-token"}}
+	statusCheck := func(statusCodes string) HTTPResponseCheck {
+		check, err := NewHTTPStatusCodeCheck(statusCodes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return check
+	}
+	bodyCheck, err := NewResponseBodyJSONPathCheck(".received", "some-body")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name               string
+		request            HTTPRequest
+		check              HTTPResponseCheck
+		expectedStatusCode int
+		wantErr            bool
+	}{
+		{
+			name:               "Positive Test: POST with body",
+			request:            HTTPRequest{Method: http.MethodPost, Header: header, Body: "some-body", InsecureSkipVerify: true},
+			check:              NewHTTPResponseBodyCheck(bodyCheck),
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "Negative Test: unexpected status returns the response",
+			request:            HTTPRequest{Method: http.MethodGet, InsecureSkipVerify: true},
+			check:              statusCheck("2xx"),
+			expectedStatusCode: http.StatusUnauthorized,
+			wantErr:            true,
+		},
+		{
+			name:    "Negative Test: certificate not verified",
+			request: HTTPRequest{Method: http.MethodGet, Header: header},
+			check:   statusCheck("2xx"),
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: timeout",
+			request: HTTPRequest{Method: http.MethodGet, Header: header, Timeout: 10 * time.Millisecond, InsecureSkipVerify: true},
+			check:   statusCheck("2xx"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := EndpointResponseShouldCtx(context.Background(), common.NewWaiterConfig(1, time.Millisecond), server.URL, tt.request, tt.check)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EndpointResponseShouldCtx() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.expectedStatusCode == 0 {
+				if response != nil {
+					t.Errorf("expected no response, got status %d", response.StatusCode)
+				}
+				return
+			}
+			if response == nil || response.StatusCode != tt.expectedStatusCode {
+				t.Errorf("expected a response with status %d, got %+v", tt.expectedStatusCode, response)
+			}
+		})
+	}
+}
+
+func TestSendTrafficToIngress(t *testing.T) {
+	type args struct {
+		kubeClientset  kubernetes.Interface
+		w              common.WaiterConfig
+		tps            int
+		name           string
+		namespace      string
+		port           int
+		path           string
+		duration       int
+		durationUnits  string
+		expectedErrors int
+	}
+	ingressName := "ingress1"
+	namespace := "namespace1"
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		// TODO: add positive test expectedErrors = 0, will likely need to fake the endpoint
+		{
+			name: "Positive Test: expectedErrors = 1",
+			args: args{
+				kubeClientset:  fake.NewSimpleClientset(getIngressWithHostname(t, ingressName, namespace, "localhost")),
+				w:              common.NewWaiterConfig(1, time.Millisecond),
+				tps:            1,
+				name:           ingressName,
+				namespace:      namespace,
+				port:           6060,
+				path:           "/",
+				duration:       1,
+				durationUnits:  util.DurationSeconds,
+				expectedErrors: 1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SendTrafficToIngress(tt.args.kubeClientset, tt.args.w, tt.args.tps, tt.args.name, tt.args.namespace, tt.args.port, tt.args.path, tt.args.duration, tt.args.durationUnits, tt.args.expectedErrors); (err != nil) != tt.wantErr {
+				t.Errorf("SendTrafficToIngress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetServiceTargetPod(t *testing.T) {
+	namespace := "namespace1"
+	selector := map[string]string{"app": "app1"}
+	newService := func(targetPort intstr.IntOrString) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service1", Namespace: namespace},
+			Spec: corev1.ServiceSpec{
+				Selector: selector,
+				Ports:    []corev1.ServicePort{{Port: 80, TargetPort: targetPort}},
+			},
+		}
+	}
+	newPod := func(name string, phase corev1.PodPhase, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: selector},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}},
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:      phase,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	tests := []struct {
+		name           string
+		objects        []runtime.Object
+		service        *corev1.Service
+		port           int
+		wantPod        string
+		wantTargetPort int
+		wantErr        bool
+	}{
+		{
+			name:           "Positive Test: numeric target port",
+			objects:        []runtime.Object{newPod("pod1", corev1.PodRunning, corev1.ConditionTrue)},
+			service:        newService(intstr.FromInt(9090)),
+			port:           80,
+			wantPod:        "pod1",
+			wantTargetPort: 9090,
+		},
+		{
+			name:           "Positive Test: named target port skips pods not ready",
+			objects:        []runtime.Object{newPod("pod1", corev1.PodRunning, corev1.ConditionFalse), newPod("pod2", corev1.PodRunning, corev1.ConditionTrue)},
+			service:        newService(intstr.FromString("http")),
+			port:           80,
+			wantPod:        "pod2",
+			wantTargetPort: 8080,
+		},
+		{
+			name:           "Positive Test: target port defaults to port",
+			objects:        []runtime.Object{newPod("pod1", corev1.PodRunning, corev1.ConditionTrue)},
+			service:        newService(intstr.IntOrString{}),
+			port:           80,
+			wantPod:        "pod1",
+			wantTargetPort: 80,
+		},
+		{
+			name:    "Negative Test: port not exposed",
+			objects: []runtime.Object{newPod("pod1", corev1.PodRunning, corev1.ConditionTrue)},
+			service: newService(intstr.FromInt(9090)),
+			port:    443,
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: no ready pods",
+			objects: []runtime.Object{newPod("pod1", corev1.PodPending, corev1.ConditionFalse)},
+			service: newService(intstr.FromInt(9090)),
+			port:    80,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPod, gotTargetPort, err := getServiceTargetPod(fake.NewSimpleClientset(tt.objects...), tt.service, tt.port)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getServiceTargetPod() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotPod.Name != tt.wantPod || gotTargetPort != tt.wantTargetPort {
+				t.Errorf("getServiceTargetPod() = %v:%v, want %v:%v", gotPod.Name, gotTargetPort, tt.wantPod, tt.wantTargetPort)
+			}
+		})
+	}
+}
+
+func TestServiceAvailableWithoutRestConfig(t *testing.T) {
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	if err := ServiceAvailable(context.Background(), fake.NewSimpleClientset(), nil, w, "service1", "namespace1", 80, "/"); err == nil {
+		t.Errorf("ServiceAvailable() expected error with nil rest config")
+	}
+}
+
+func TestSendTrafficToEndpoints(t *testing.T) {
+	stable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer stable.Close()
+	canary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer canary.Close()
+
+	tests := []struct {
+		name           string
+		targets        []TrafficTarget
+		expectedErrors int
+		wantTargets    []string
+		wantErr        bool
+	}{
+		{
+			name: "Positive Test: metrics per target",
+			targets: []TrafficTarget{
+				{Name: "stable", Endpoint: stable.URL, TPS: 5},
+				{Name: "canary", Endpoint: canary.URL, TPS: 2},
+			},
+			expectedErrors: 10,
+			wantTargets:    []string{"stable", "canary"},
+		},
+		{
+			name: "Negative Test: target exceeds expected errors",
+			targets: []TrafficTarget{
+				{Name: "stable", Endpoint: stable.URL, TPS: 2},
+				{Name: "canary", Endpoint: canary.URL, TPS: 2},
+			},
+			wantTargets: []string{"stable", "canary"},
+			wantErr:     true,
+		},
+		{
+			name: "Negative Test: duplicate target names",
+			targets: []TrafficTarget{
+				{Name: "stable", Endpoint: stable.URL, TPS: 2},
+				{Name: "stable", Endpoint: canary.URL, TPS: 2},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: no targets",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, err := SendTrafficToEndpoints(tt.targets, 1, util.DurationSeconds, tt.expectedErrors)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SendTrafficToEndpoints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, target := range tt.wantTargets {
+				if metrics[target] == nil || metrics[target].Requests == 0 {
+					t.Errorf("SendTrafficToEndpoints() did not return metrics for target '%s'", target)
+				}
+			}
+			if len(tt.wantTargets) > 0 && metrics["canary"].StatusCodes["503"] == 0 {
+				t.Errorf("SendTrafficToEndpoints() canary metrics = %+v, want 503 responses", metrics["canary"])
+			}
+		})
+	}
+}
+func TestParseWeightedPaths(t *testing.T) {
+	tests := []struct {
+		paths   string
+		want    []WeightedPath
+		wantErr bool
+	}{
+		{paths: "/api/orders:3,/healthz", want: []WeightedPath{{"/api/orders", 3}, {"/healthz", 1}}},
+		{paths: "/search?q=a:b", want: []WeightedPath{{"/search?q=a:b", 1}}},
+		{paths: "/api:0", wantErr: true},
+		{paths: "api", wantErr: true},
+		{paths: ",", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.paths, func(t *testing.T) {
+			got, err := ParseWeightedPaths(tt.paths)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWeightedPaths() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseWeightedPaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrafficRequests(t *testing.T) {
+	header := http.Header{"Authorization": []string{"Bearer some-token"}}
+	requests := TrafficRequests{
+		Method: "post",
+		Body:   []byte(`{"key":"value"}`),
+		Paths:  []WeightedPath{{"/api/orders", 3}, {"/healthz", 1}},
+	}
+	targeter, err := requests.Targeter("https://some.host.com/", header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var urls []string
+	for i := 0; i < 8; i++ {
+		var target vegeta.Target
+		if err := targeter(&target); err != nil {
+			t.Fatal(err)
+		}
+		if target.Method != http.MethodPost || string(target.Body) != `{"key":"value"}` || target.Header.Get("Authorization") != "Bearer some-token" {
+			t.Errorf("unexpected target %+v", target)
+		}
+		urls = append(urls, target.URL)
+	}
+	orders := "https://some.host.com/api/orders"
+	healthz := "https://some.host.com/healthz"
+	if want := []string{orders, orders, healthz, orders, orders, orders, healthz, orders}; !reflect.DeepEqual(urls, want) {
+		t.Errorf("targeted %v, want %v", urls, want)
+	}
+
+	targeter, err = TrafficRequests{}.Targeter("http://some.host.com/healthz", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var target vegeta.Target
+	if err := targeter(&target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Method != http.MethodGet || target.URL != "http://some.host.com/healthz" {
+		t.Errorf("unexpected target %+v without paths", target)
+	}
+
+	if _, err := (TrafficRequests{Paths: []WeightedPath{{"/healthz", 0}}}).Targeter("http://some.host.com", nil); err == nil {
+		t.Error("Targeter() expected an error for a path without weight")
+	}
+}
+
+func TestSendTrafficToTarget(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPut || string(body) != "some-body" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	injected := TargeterBuilderFunc(func(endpoint string, header http.Header) (vegeta.Targeter, error) {
+		return vegeta.NewStaticTargeter(vegeta.Target{Method: http.MethodPut, URL: endpoint + "/injected", Body: []byte("some-body")}), nil
+	})
+	tests := []struct {
+		name           string
+		target         TrafficTarget
+		wantStatusCode string
+		wantErr        bool
+	}{
+		{
+			name:           "Positive Test: PUT with body over https",
+			target:         TrafficTarget{Endpoint: server.URL, TPS: 5, InsecureSkipVerify: true, Targeter: TrafficRequests{Method: http.MethodPut, Body: []byte("some-body")}},
+			wantStatusCode: "200",
+		},
+		{
+			name:           "Positive Test: injected targeter",
+			target:         TrafficTarget{Endpoint: server.URL, TPS: 5, InsecureSkipVerify: true, Targeter: injected},
+			wantStatusCode: "200",
+		},
+		{
+			name:           "Negative Test: GET by default",
+			target:         TrafficTarget{Endpoint: server.URL, TPS: 5, InsecureSkipVerify: true},
+			wantStatusCode: "400",
+			wantErr:        true,
+		},
+		{
+			name:    "Negative Test: certificate not verified",
+			target:  TrafficTarget{Endpoint: server.URL, TPS: 5, Targeter: injected},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, err := SendTrafficToTarget(tt.target, 1, util.DurationSeconds, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendTrafficToTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if metrics == nil || metrics.Requests == 0 {
+				t.Fatalf("SendTrafficToTarget() metrics = %+v, want requests", metrics)
+			}
+			if tt.wantStatusCode != "" && metrics.StatusCodes[tt.wantStatusCode] != int(metrics.Requests) {
+				t.Errorf("SendTrafficToTarget() status codes = %v, want only %v", metrics.StatusCodes, tt.wantStatusCode)
+			}
+		})
+	}
+}
+
+func TestExportTrafficMetrics(t *testing.T) {
+	metrics := &vegeta.Metrics{
+		Histogram: &vegeta.Histogram{Buckets: trafficHistogramBuckets},
+	}
+	metrics.Add(&vegeta.Result{Code: 200, Latency: 20 * time.Millisecond, Timestamp: time.Now()})
+	metrics.Add(&vegeta.Result{Code: 503, Latency: 300 * time.Millisecond, Error: "503 Service Unavailable", Timestamp: time.Now()})
+	metrics.Close()
+
+	artifactsPath := filepath.Join(t.TempDir(), "artifacts")
+	filePath, err := ExportTrafficMetrics(metrics, artifactsPath, "ingress-namespace1-ingress1")
+	if err != nil {
+		t.Fatalf("ExportTrafficMetrics() error = %v", err)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exported vegeta.Metrics
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("exported traffic metrics are not valid json: %v", err)
+	}
+	if exported.Requests != 2 || exported.StatusCodes["503"] != 1 || len(exported.Errors) != 1 {
+		t.Errorf("exported traffic metrics = %+v, want 2 requests with one 503 error", exported)
+	}
+	if !strings.Contains(string(data), `"buckets"`) {
+		t.Errorf("exported traffic metrics do not include the latency histogram: %s", data)
+	}
+
+	if _, err := ExportTrafficMetrics(nil, artifactsPath, "ingress-namespace1-ingress1"); err == nil {
+		t.Errorf("ExportTrafficMetrics() expected error with nil metrics")
+	}
+}
+
+func TestExportTrafficReport(t *testing.T) {
+	metrics := &vegeta.Metrics{
+		Histogram: &vegeta.Histogram{Buckets: trafficHistogramBuckets},
+	}
+	metrics.Add(&vegeta.Result{Code: 200, Latency: 20 * time.Millisecond, Timestamp: time.Now()})
+	metrics.Add(&vegeta.Result{Code: 503, Latency: 300 * time.Millisecond, Error: "503 <Service Unavailable>", Timestamp: time.Now()})
+	metrics.Close()
+
+	artifactsPath := filepath.Join(t.TempDir(), "artifacts")
+	filePath, err := ExportTrafficReport(metrics, artifactsPath, "ingress-namespace1-ingress1")
+	if err != nil {
+		t.Fatalf("ExportTrafficReport() error = %v", err)
+	}
+	if filepath.Ext(filePath) != ".html" {
+		t.Errorf("ExportTrafficReport() file path = %v, want an html file", filePath)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"Traffic test ingress-namespace1-ingress1",
+		"<td>503</td><td>1</td><td>50.00%</td>",
+		"Latency histogram",
+		"503 &lt;Service Unavailable&gt;",
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("exported traffic report does not contain '%v': %s", expected, data)
+		}
+	}
+
+	if _, err := ExportTrafficReport(nil, artifactsPath, "ingress-namespace1-ingress1"); err == nil {
+		t.Errorf("ExportTrafficReport() expected error with nil metrics")
+	}
+}
+
+func TestGetServiceLoadBalancerHostname(t *testing.T) {
+	namespace := "namespace1"
+	newService := func(serviceType corev1.ServiceType, ingress ...corev1.LoadBalancerIngress) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service1", Namespace: namespace},
+			Spec:       corev1.ServiceSpec{Type: serviceType},
+			Status:     corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: ingress}},
+		}
+	}
+	hostname := "k8s-namespa-service1-0123456789.elb.us-west-2.amazonaws.com"
+	tests := []struct {
+		name    string
+		service *corev1.Service
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Positive Test",
+			service: newService(corev1.ServiceTypeLoadBalancer, corev1.LoadBalancerIngress{Hostname: hostname}),
+			want:    hostname,
+		},
+		{
+			name:    "Negative Test: no load balancer hostname",
+			service: newService(corev1.ServiceTypeLoadBalancer, corev1.LoadBalancerIngress{IP: "10.0.0.1"}),
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: not a load balancer",
+			service: newService(corev1.ServiceTypeClusterIP),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetServiceLoadBalancerHostname(context.Background(), fake.NewSimpleClientset(tt.service), common.NewWaiterConfig(1, time.Millisecond), "service1", namespace)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetServiceLoadBalancerHostname() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetServiceLoadBalancerHostname() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetExternalDNSHostnames(t *testing.T) {
+	namespace := "namespace1"
+	annotations := map[string]string{externalDNSHostnameAnnotation: "app.example.com, www.example.com"}
+	tests := []struct {
+		name         string
+		object       runtime.Object
+		resourceType string
+		want         []string
+		wantErr      bool
+	}{
+		{
+			name: "Positive Test: ingress annotation",
+			object: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "resource1", Namespace: namespace, Annotations: annotations},
+				Spec:       networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{Host: "ignored.example.com"}}},
+			},
+			resourceType: ingressType,
+			want:         []string{"app.example.com", "www.example.com"},
+		},
+		{
+			name: "Positive Test: ingress rule hosts",
+			object: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "resource1", Namespace: namespace},
+				Spec:       networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{Host: "app.example.com"}, {}}},
+			},
+			resourceType: ingressType,
+			want:         []string{"app.example.com"},
+		},
+		{
+			name: "Positive Test: service annotation",
+			object: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "resource1", Namespace: namespace, Annotations: annotations},
+			},
+			resourceType: serviceType,
+			want:         []string{"app.example.com", "www.example.com"},
+		},
+		{
+			name: "Negative Test: service without annotation",
+			object: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "resource1", Namespace: namespace},
+			},
+			resourceType: serviceType,
+			wantErr:      true,
+		},
+		{
+			name: "Negative Test: unsupported resource type",
+			object: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "resource1", Namespace: namespace, Annotations: annotations},
+			},
+			resourceType: deploymentType,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetExternalDNSHostnames(fake.NewSimpleClientset(tt.object), tt.resourceType, "resource1", namespace)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetExternalDNSHostnames() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetExternalDNSHostnames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrafficMetricsAssertions(t *testing.T) {
+	metrics := &vegeta.Metrics{
+		Latencies: vegeta.LatencyMetrics{
+			P50: 10 * time.Millisecond,
+			P95: 100 * time.Millisecond,
+			P99: 300 * time.Millisecond,
+		},
+		Requests:   100,
+		Success:    0.95,
+		Throughput: 9.5,
+		StatusCodes: map[string]int{
+			"200": 95,
+			"503": 5,
+		},
 	}
-	ingressName := "ingress1"
-	namespace := "namespace1"
 	tests := []struct {
 		name    string
-		args    args
+		fn      func(*vegeta.Metrics) error
+		metrics *vegeta.Metrics
 		wantErr bool
 	}{
-		// TODO: add positive test expectedErrors = 0, will likely need to fake the endpoint
 		{
-			name: "Positive Test: expectedErrors = 1",
-			args: args{
-				kubeClientset:  fake.NewSimpleClientset(getIngressWithHostname(t, ingressName, namespace, "localhost")),
-				w:              common.NewWaiterConfig(1, time.Millisecond),
-				tps:            1,
-				name:           ingressName,
-				namespace:      namespace,
-				port:           6060,
-				path:           "/",
-				duration:       1,
-				durationUnits:  util.DurationSeconds,
-				expectedErrors: 1,
-			},
+			name:    "Positive Test: p99 latency less than threshold",
+			fn:      func(m *vegeta.Metrics) error { return TrafficLatencyShouldBeLessThan(m, "p99", "500ms") },
+			metrics: metrics,
+		},
+		{
+			name:    "Negative Test: p95 latency above threshold",
+			fn:      func(m *vegeta.Metrics) error { return TrafficLatencyShouldBeLessThan(m, "p95", "50ms") },
+			metrics: metrics,
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: invalid percentile",
+			fn:      func(m *vegeta.Metrics) error { return TrafficLatencyShouldBeLessThan(m, "p42", "50ms") },
+			metrics: metrics,
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: invalid duration",
+			fn:      func(m *vegeta.Metrics) error { return TrafficLatencyShouldBeLessThan(m, "p50", "50") },
+			metrics: metrics,
+			wantErr: true,
+		},
+		{
+			name:    "Positive Test: success ratio at least",
+			fn:      func(m *vegeta.Metrics) error { return TrafficSuccessRatioShouldBeAtLeast(m, "0.9") },
+			metrics: metrics,
+		},
+		{
+			name:    "Negative Test: success ratio below",
+			fn:      func(m *vegeta.Metrics) error { return TrafficSuccessRatioShouldBeAtLeast(m, "0.99") },
+			metrics: metrics,
+			wantErr: true,
+		},
+		{
+			name:    "Positive Test: status code percentage at least",
+			fn:      func(m *vegeta.Metrics) error { return TrafficStatusCodePercentageShouldBeAtLeast(m, 95, "200") },
+			metrics: metrics,
+		},
+		{
+			name:    "Negative Test: status code percentage below",
+			fn:      func(m *vegeta.Metrics) error { return TrafficStatusCodePercentageShouldBeAtLeast(m, 10, "503") },
+			metrics: metrics,
+			wantErr: true,
+		},
+		{
+			name:    "Positive Test: throughput at least",
+			fn:      func(m *vegeta.Metrics) error { return TrafficThroughputShouldBeAtLeast(m, "9.5") },
+			metrics: metrics,
+		},
+		{
+			name:    "Negative Test: throughput below",
+			fn:      func(m *vegeta.Metrics) error { return TrafficThroughputShouldBeAtLeast(m, "10") },
+			metrics: metrics,
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: no metrics",
+			fn:      func(m *vegeta.Metrics) error { return TrafficSuccessRatioShouldBeAtLeast(m, "0.9") },
+			metrics: nil,
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := SendTrafficToIngress(tt.args.kubeClientset, tt.args.w, tt.args.tps, tt.args.name, tt.args.namespace, tt.args.port, tt.args.path, tt.args.duration, tt.args.durationUnits, tt.args.expectedErrors); (err != nil) != tt.wantErr {
-				t.Errorf("SendTrafficToIngress() error = %v, wantErr %v", err, tt.wantErr)
+			if err := tt.fn(tt.metrics); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
@@ -942,3 +3449,574 @@ func errorIfNamespaceNotEmpty(t *testing.T, ns string) {
 		t.Errorf("Namespace should be empty, but is: %s", ns)
 	}
 }
+
+func TestStorageClassShouldProvisionVolume(t *testing.T) {
+	const (
+		storageClassName = "gp3"
+		namespace        = "storage-test"
+		volumeName       = "pvc-1234"
+	)
+	newClientset := func(podPhase corev1.PodPhase, provisionedBy string) *fake.Clientset {
+		volume := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: volumeName}}
+		if provisionedBy != "" {
+			volume.Annotations = map[string]string{provisionedByAnnotation: provisionedBy}
+		}
+		client := fake.NewSimpleClientset(volume)
+		// simulate the provisioner binding the claim and the pod running to completion
+		client.PrependReactor("create", "persistentvolumeclaims", func(action kTesting.Action) (bool, runtime.Object, error) {
+			claim := action.(kTesting.CreateAction).GetObject().(*corev1.PersistentVolumeClaim)
+			claim.Spec.VolumeName = volumeName
+			claim.Status.Phase = corev1.ClaimBound
+			return false, nil, nil
+		})
+		client.PrependReactor("create", "pods", func(action kTesting.Action) (bool, runtime.Object, error) {
+			action.(kTesting.CreateAction).GetObject().(*corev1.Pod).Status.Phase = podPhase
+			return false, nil, nil
+		})
+		return client
+	}
+	tests := []struct {
+		name          string
+		kubeClientset *fake.Clientset
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test",
+			kubeClientset: newClientset(corev1.PodSucceeded, "ebs.csi.aws.com"),
+		},
+		{
+			name:          "Negative Test: pod failed to read data",
+			kubeClientset: newClientset(corev1.PodFailed, "ebs.csi.aws.com"),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: pod did not complete",
+			kubeClientset: newClientset(corev1.PodPending, "ebs.csi.aws.com"),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: volume not dynamically provisioned",
+			kubeClientset: newClientset(corev1.PodSucceeded, ""),
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := StorageClassShouldProvisionVolume(context.Background(), tt.kubeClientset, w, storageClassName, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("StorageClassShouldProvisionVolume() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			pods, _ := tt.kubeClientset.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
+			claims, _ := tt.kubeClientset.CoreV1().PersistentVolumeClaims(namespace).List(context.Background(), metav1.ListOptions{})
+			if len(pods.Items) != 0 || len(claims.Items) != 0 {
+				t.Errorf("StorageClassShouldProvisionVolume() did not clean up, %d pods, %d claims", len(pods.Items), len(claims.Items))
+			}
+		})
+	}
+}
+
+func TestServiceHasEndpoints(t *testing.T) {
+	const (
+		serviceName = "service1"
+		namespace   = "namespace1"
+	)
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: namespace}}
+	newEndpoint := func(pod, address string, ready bool) discoveryv1.Endpoint {
+		return discoveryv1.Endpoint{
+			Addresses:  []string{address},
+			Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+			TargetRef:  &corev1.ObjectReference{Kind: "Pod", Namespace: namespace, Name: pod},
+		}
+	}
+	newSlice := func(name string, addressType discoveryv1.AddressType, endpoints ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: serviceName},
+			},
+			AddressType: addressType,
+			Endpoints:   endpoints,
+		}
+	}
+	tests := []struct {
+		name          string
+		kubeClientset kubernetes.Interface
+		minEndpoints  int
+		wantErr       bool
+	}{
+		{
+			name: "Positive Test: dual stack slices count each pod once",
+			kubeClientset: fake.NewSimpleClientset(service,
+				newSlice("service1-ipv4", discoveryv1.AddressTypeIPv4, newEndpoint("pod1", "10.0.0.1", true), newEndpoint("pod2", "10.0.0.2", true)),
+				newSlice("service1-ipv6", discoveryv1.AddressTypeIPv6, newEndpoint("pod1", "fd00::1", true), newEndpoint("pod2", "fd00::2", true)),
+			),
+			minEndpoints: 2,
+		},
+		{
+			name: "Positive Test: endpoints without slices",
+			kubeClientset: fake.NewSimpleClientset(service, &corev1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: namespace},
+				Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}},
+			}),
+			minEndpoints: 1,
+		},
+		{
+			name: "Negative Test: not enough ready endpoints",
+			kubeClientset: fake.NewSimpleClientset(service,
+				newSlice("service1-ipv4", discoveryv1.AddressTypeIPv4, newEndpoint("pod1", "10.0.0.1", true), newEndpoint("pod2", "10.0.0.2", false)),
+				newSlice("service1-ipv6", discoveryv1.AddressTypeIPv6, newEndpoint("pod1", "fd00::1", true), newEndpoint("pod2", "fd00::2", false)),
+			),
+			minEndpoints: 2,
+			wantErr:      true,
+		},
+		{
+			name:          "Negative Test: no endpoints",
+			kubeClientset: fake.NewSimpleClientset(service),
+			minEndpoints:  1,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: service not found",
+			kubeClientset: fake.NewSimpleClientset(),
+			minEndpoints:  1,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ServiceHasEndpoints(context.Background(), tt.kubeClientset, common.NewWaiterConfig(1, time.Millisecond), serviceName, namespace, tt.minEndpoints)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ServiceHasEndpoints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, common.ErrWaiterTimeout) {
+				t.Errorf("expected a waiter timeout, got %v", err)
+			}
+		})
+	}
+}
+
+func TestServiceShouldResolveInCluster(t *testing.T) {
+	const (
+		serviceName = "service1"
+		namespace   = "namespace1"
+	)
+	newClientset := func(clusterIP string, podPhase corev1.PodPhase) *fake.Clientset {
+		client := fake.NewSimpleClientset(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: namespace},
+			Spec:       corev1.ServiceSpec{ClusterIP: clusterIP},
+		})
+		client.PrependReactor("create", "pods", func(action kTesting.Action) (bool, runtime.Object, error) {
+			p := action.(kTesting.CreateAction).GetObject().(*corev1.Pod)
+			if clusterIP != corev1.ClusterIPNone && !strings.Contains(p.Spec.Containers[0].Command[2], clusterIP) {
+				t.Errorf("expected dns test pod to validate address %v", clusterIP)
+			}
+			p.Status.Phase = podPhase
+			return false, nil, nil
+		})
+		return client
+	}
+	tests := []struct {
+		name          string
+		kubeClientset *fake.Clientset
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test",
+			kubeClientset: newClientset("10.100.0.10", corev1.PodSucceeded),
+		},
+		{
+			name:          "Positive Test: headless service",
+			kubeClientset: newClientset(corev1.ClusterIPNone, corev1.PodSucceeded),
+		},
+		{
+			name:          "Negative Test: resolution failed",
+			kubeClientset: newClientset("10.100.0.10", corev1.PodFailed),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: service not found",
+			kubeClientset: fake.NewSimpleClientset(),
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := ServiceShouldResolveInCluster(context.Background(), tt.kubeClientset, w, serviceName, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("ServiceShouldResolveInCluster() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunJobShouldExitWithCode(t *testing.T) {
+	const (
+		image     = "busybox:1.36"
+		command   = "exit 3"
+		namespace = "namespace1"
+	)
+	newClientset := func(terminated bool, exitCode int32) *fake.Clientset {
+		client := fake.NewSimpleClientset()
+		client.PrependReactor("create", "jobs", func(action kTesting.Action) (bool, runtime.Object, error) {
+			job := action.(kTesting.CreateAction).GetObject().(*batchv1.Job)
+			container := job.Spec.Template.Spec.Containers[0]
+			if container.Image != image || container.Command[2] != command {
+				t.Errorf("expected job to run command %q with image %v", command, image)
+			}
+			status := corev1.ContainerStatus{Name: container.Name}
+			if terminated {
+				status.State.Terminated = &corev1.ContainerStateTerminated{ExitCode: exitCode}
+			}
+			p := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      job.Name + "-abcde",
+					Namespace: job.Namespace,
+					Labels:    map[string]string{jobNameLabel: job.Name},
+				},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{status}},
+			}
+			if err := client.Tracker().Add(p); err != nil {
+				t.Fatal(err)
+			}
+			return false, nil, nil
+		})
+		return client
+	}
+	tests := []struct {
+		name          string
+		kubeClientset *fake.Clientset
+		exitCode      int
+		wantLogs      bool
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test",
+			kubeClientset: newClientset(true, 3),
+			exitCode:      3,
+			wantLogs:      true,
+		},
+		{
+			name:          "Negative Test: unexpected exit code",
+			kubeClientset: newClientset(true, 1),
+			exitCode:      3,
+			wantLogs:      true,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: job does not complete",
+			kubeClientset: newClientset(false, 0),
+			exitCode:      3,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			artifactsPath := t.TempDir()
+			err := RunJobShouldExitWithCode(context.Background(), tt.kubeClientset, w, image, command, namespace, tt.exitCode, artifactsPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RunJobShouldExitWithCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			jobs, err := tt.kubeClientset.BatchV1().Jobs(namespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(jobs.Items) != 0 {
+				t.Errorf("expected job to be deleted")
+			}
+			logs, err := filepath.Glob(filepath.Join(artifactsPath, "*.log"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (len(logs) == 1) != tt.wantLogs {
+				t.Errorf("expected logs to be exported: %v, got %v", tt.wantLogs, logs)
+			}
+		})
+	}
+}
+
+func TestJobIsCompleted(t *testing.T) {
+	const namespace = "namespace1"
+	start := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	completion := metav1.NewTime(start.Add(90 * time.Second))
+	newJob := func(conditionType batchv1.JobConditionType) *batchv1.Job {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+			Status:     batchv1.JobStatus{StartTime: &start},
+		}
+		if conditionType != "" {
+			job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"}}
+		}
+		if conditionType == batchv1.JobComplete {
+			job.Status.CompletionTime = &completion
+		}
+		return job
+	}
+	jobPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1-abcde",
+			Namespace: namespace,
+			Labels:    map[string]string{jobNameLabel: "job1"},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}, {Name: "sidecar"}}},
+	}
+	tests := []struct {
+		name     string
+		job      *batchv1.Job
+		within   time.Duration
+		wantLogs int
+		wantErr  bool
+	}{
+		{
+			name:   "Positive Test: job completed",
+			job:    newJob(batchv1.JobComplete),
+			within: 2 * time.Minute,
+		},
+		{
+			name:    "Negative Test: job completed too slowly",
+			job:     newJob(batchv1.JobComplete),
+			within:  time.Minute,
+			wantErr: true,
+		},
+		{
+			name:     "Negative Test: job failed",
+			job:      newJob(batchv1.JobFailed),
+			within:   2 * time.Minute,
+			wantLogs: 2,
+			wantErr:  true,
+		},
+		{
+			name:     "Negative Test: job does not complete",
+			job:      newJob(""),
+			within:   2 * time.Minute,
+			wantLogs: 2,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			artifactsPath := t.TempDir()
+			kubeClientset := fake.NewSimpleClientset(tt.job, jobPod)
+			err := JobSucceededWithinDuration(context.Background(), kubeClientset, w, tt.job.Name, namespace, tt.within, artifactsPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JobSucceededWithinDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := JobIsCompleted(context.Background(), kubeClientset, w, tt.job.Name, namespace, t.TempDir()); (err != nil) != (getJobCondition(tt.job, batchv1.JobComplete) == nil) {
+				t.Errorf("JobIsCompleted() error = %v", err)
+			}
+			logs, err := filepath.Glob(filepath.Join(artifactsPath, "*.log"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(logs) != tt.wantLogs {
+				t.Errorf("expected %d logs to be exported, got %v", tt.wantLogs, logs)
+			}
+		})
+	}
+}
+
+func TestCronJobHasLastScheduleTime(t *testing.T) {
+	const namespace = "namespace1"
+	lastSchedule := metav1.Now()
+	scheduled := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "scheduled", Namespace: namespace},
+		Status:     batchv1.CronJobStatus{LastScheduleTime: &lastSchedule},
+	}
+	notScheduled := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "not-scheduled", Namespace: namespace},
+		Spec:       batchv1.CronJobSpec{Schedule: "0 0 * * *"},
+	}
+	kubeClientset := fake.NewSimpleClientset(scheduled, notScheduled)
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	if err := CronJobHasLastScheduleTime(context.Background(), kubeClientset, w, scheduled.Name, namespace); err != nil {
+		t.Errorf("CronJobHasLastScheduleTime() error = %v", err)
+	}
+	if err := CronJobHasLastScheduleTime(context.Background(), kubeClientset, w, notScheduled.Name, namespace); err == nil {
+		t.Errorf("CronJobHasLastScheduleTime() expected an error for a cronjob without a last schedule time")
+	}
+	if err := CronJobHasLastScheduleTime(context.Background(), kubeClientset, w, "missing", namespace); err == nil {
+		t.Errorf("CronJobHasLastScheduleTime() expected an error for a missing cronjob")
+	}
+}
+
+func TestServiceShouldBeReachable(t *testing.T) {
+	const (
+		serviceName     = "service1"
+		namespace       = "namespace1"
+		sourceNamespace = "namespace2"
+		port            = 8080
+	)
+	newClientset := func(podPhase corev1.PodPhase) *fake.Clientset {
+		client := fake.NewSimpleClientset()
+		client.PrependReactor("create", "pods", func(action kTesting.Action) (bool, runtime.Object, error) {
+			p := action.(kTesting.CreateAction).GetObject().(*corev1.Pod)
+			if p.Namespace != sourceNamespace {
+				t.Errorf("expected reachability test pod in namespace %v, got %v", sourceNamespace, p.Namespace)
+			}
+			p.Status.Phase = podPhase
+			return false, nil, nil
+		})
+		return client
+	}
+	tests := []struct {
+		name          string
+		kubeClientset *fake.Clientset
+		reachable     bool
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: reachable",
+			kubeClientset: newClientset(corev1.PodSucceeded),
+			reachable:     true,
+		},
+		{
+			name:          "Positive Test: not reachable",
+			kubeClientset: newClientset(corev1.PodFailed),
+		},
+		{
+			name:          "Negative Test: expected reachable",
+			kubeClientset: newClientset(corev1.PodFailed),
+			reachable:     true,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: expected not reachable",
+			kubeClientset: newClientset(corev1.PodSucceeded),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: pod does not complete",
+			kubeClientset: newClientset(corev1.PodPending),
+			reachable:     true,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := ServiceShouldBeReachable(context.Background(), tt.kubeClientset, w, serviceName, namespace, port, sourceNamespace, tt.reachable); (err != nil) != tt.wantErr {
+				t.Errorf("ServiceShouldBeReachable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookConfigurationShouldBeReady(t *testing.T) {
+	const (
+		configurationName = "webhook-configuration"
+		serviceName       = "webhook-service"
+		namespace         = "webhook-system"
+	)
+	newClientConfig := func(caBundle string) admissionregistrationv1.WebhookClientConfig {
+		return admissionregistrationv1.WebhookClientConfig{
+			CABundle: []byte(caBundle),
+			Service:  &admissionregistrationv1.ServiceReference{Name: serviceName, Namespace: namespace},
+		}
+	}
+	newEndpoints := func(addresses ...string) *corev1.Endpoints {
+		subset := corev1.EndpointSubset{}
+		for _, address := range addresses {
+			subset.Addresses = append(subset.Addresses, corev1.EndpointAddress{IP: address})
+		}
+		return &corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: namespace},
+			Subsets:    []corev1.EndpointSubset{subset},
+		}
+	}
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: configurationName},
+		Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "validate.example.com", ClientConfig: newClientConfig("ca")}},
+	}
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: configurationName},
+		Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "mutate.example.com", ClientConfig: newClientConfig("")}},
+	}
+	tests := []struct {
+		name          string
+		kubeClientset kubernetes.Interface
+		webhookType   string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: validating webhook ready",
+			kubeClientset: fake.NewSimpleClientset(validating, newEndpoints("10.0.0.1")),
+			webhookType:   "validating",
+		},
+		{
+			name:          "Negative Test: no ready endpoints",
+			kubeClientset: fake.NewSimpleClientset(validating, newEndpoints()),
+			webhookType:   "validating",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: no caBundle",
+			kubeClientset: fake.NewSimpleClientset(mutating, newEndpoints("10.0.0.1")),
+			webhookType:   "mutating",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: configuration not found",
+			kubeClientset: fake.NewSimpleClientset(validating),
+			webhookType:   "mutating",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: unsupported webhook type",
+			kubeClientset: fake.NewSimpleClientset(validating),
+			webhookType:   "conversion",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := WebhookConfigurationShouldBeReady(tt.kubeClientset, tt.webhookType, configurationName); (err != nil) != tt.wantErr {
+				t.Errorf("WebhookConfigurationShouldBeReady() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateAndDeleteNamespace(t *testing.T) {
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	kubeClientset := fake.NewSimpleClientset()
+	if err := CreateNamespace(kubeClientset, "namespace1", map[string]string{"team": "my-team"}); err != nil {
+		t.Fatalf("CreateNamespace() error = %v", err)
+	}
+	if err := CreateNamespace(kubeClientset, "namespace1", nil); err == nil {
+		t.Error("CreateNamespace() expected an error for an existing namespace")
+	}
+	if err := DeleteNamespace(context.Background(), kubeClientset, w, "namespace1"); err != nil {
+		t.Fatalf("DeleteNamespace() error = %v", err)
+	}
+	if _, err := kubeClientset.CoreV1().Namespaces().Get(context.Background(), "namespace1", metav1.GetOptions{}); !kerrors.IsNotFound(err) {
+		t.Errorf("expected namespace to be deleted, got %v", err)
+	}
+	if err := DeleteNamespace(context.Background(), kubeClientset, w, "namespace1"); err != nil {
+		t.Errorf("DeleteNamespace() error = %v, expected a missing namespace to be deleted", err)
+	}
+}
+
+func TestDeleteNamespaceStuckTerminating(t *testing.T) {
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	kubeClientset := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace1", Finalizers: []string{"example.com/cleanup"}},
+		Spec:       corev1.NamespaceSpec{Finalizers: []corev1.FinalizerName{corev1.FinalizerKubernetes}},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+	})
+	// the namespace controller never finishes deleting it
+	kubeClientset.PrependReactor("delete", "namespaces", func(action kTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+	if err := DeleteNamespace(context.Background(), kubeClientset, w, "namespace1"); err != nil {
+		t.Fatalf("DeleteNamespace() error = %v", err)
+	}
+	namespace, err := kubeClientset.CoreV1().Namespaces().Get(context.Background(), "namespace1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(namespace.Finalizers) != 0 || len(namespace.Spec.Finalizers) != 0 {
+		t.Errorf("expected the finalizers to be removed, got %v and %v", namespace.Finalizers, namespace.Spec.Finalizers)
+	}
+}
@@ -20,9 +20,9 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/keikoproj/kubedog/internal/util"
 	"github.com/keikoproj/kubedog/pkg/kube/common"
@@ -36,13 +36,15 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	kstatus "sigs.k8s.io/cli-utils/pkg/kstatus/status"
 )
 
-func ResourceOperation(dynamicClient dynamic.Interface, resource unstructuredResource, operation string) error {
+func ResourceOperation(dynamicClient dynamic.Interface, resource UnstructuredResource, operation string) error {
 	return ResourceOperationInNamespace(dynamicClient, resource, operation, "")
 }
 
-func ResourcesOperation(dynamicClient dynamic.Interface, resources []unstructuredResource, operation string) error {
+func ResourcesOperation(dynamicClient dynamic.Interface, resources []UnstructuredResource, operation string) error {
 	for _, resource := range resources {
 		err := ResourceOperationInNamespace(dynamicClient, resource, operation, "")
 		if err != nil {
@@ -52,7 +54,7 @@ func ResourcesOperation(dynamicClient dynamic.Interface, resources []unstructure
 	return nil
 }
 
-func ResourcesOperationInNamespace(dynamicClient dynamic.Interface, resources []unstructuredResource, operation, namespace string) error {
+func ResourcesOperationInNamespace(dynamicClient dynamic.Interface, resources []UnstructuredResource, operation, namespace string) error {
 	for _, resource := range resources {
 		err := ResourceOperationInNamespace(dynamicClient, resource, operation, namespace)
 		if err != nil {
@@ -62,8 +64,20 @@ func ResourcesOperationInNamespace(dynamicClient dynamic.Interface, resources []
 	return nil
 }
 
-func ResourceOperationInNamespace(dynamicClient dynamic.Interface, resource unstructuredResource, operation, namespace string) error {
-	if err := validateDynamicClient(dynamicClient); err != nil {
+func ResourcesOperationAndWaitReady(dynamicClient dynamic.Interface, resources []UnstructuredResource, w common.WaiterConfig, operation string) error {
+	if err := ResourcesOperation(dynamicClient, resources, operation); err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		if err := ResourceShouldBeCurrent(dynamicClient, resource, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ResourceOperationInNamespace(dynamicClient dynamic.Interface, resource UnstructuredResource, operation, namespace string) error {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
 		return err
 	}
 
@@ -87,7 +101,7 @@ func ResourceOperationInNamespace(dynamicClient dynamic.Interface, resource unst
 	case common.OperationUpdate:
 		currentResourceVersion, err := dynamicClient.Resource(gvr.Resource).Namespace(namespace).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
 		if err != nil {
-			return err
+			return common.WrapNotFound(err)
 		}
 
 		unstruct.SetResourceVersion(currentResourceVersion.DeepCopy().GetResourceVersion())
@@ -132,16 +146,16 @@ func ResourceOperationInNamespace(dynamicClient dynamic.Interface, resource unst
 		}
 		log.Infof("%s %s has been deleted from namespace %s", unstruct.GetKind(), unstruct.GetName(), namespace)
 	default:
-		return fmt.Errorf("unsupported operation: %s", operation)
+		return common.Errorf(common.ErrUnsupportedOperation, "unsupported operation: %s", operation)
 	}
 	return nil
 }
 
-func ResourceOperationWithResult(dynamicClient dynamic.Interface, resource unstructuredResource, operation, expectedResult string) error {
+func ResourceOperationWithResult(dynamicClient dynamic.Interface, resource UnstructuredResource, operation, expectedResult string) error {
 	return ResourceOperationWithResultInNamespace(dynamicClient, resource, operation, "", expectedResult)
 }
 
-func ResourceOperationWithResultInNamespace(dynamicClient dynamic.Interface, resource unstructuredResource, operation, namespace, expectedResult string) error {
+func ResourceOperationWithResultInNamespace(dynamicClient dynamic.Interface, resource UnstructuredResource, operation, namespace, expectedResult string) error {
 	var expectError = strings.EqualFold(expectedResult, "fail")
 	err := ResourceOperationInNamespace(dynamicClient, resource, operation, namespace)
 	if !expectError && err != nil {
@@ -152,13 +166,79 @@ func ResourceOperationWithResultInNamespace(dynamicClient dynamic.Interface, res
 	return nil
 }
 
-func ResourceShouldBe(dynamicClient dynamic.Interface, resource unstructuredResource, w common.WaiterConfig, state string) error {
+// ResourceOperationShouldBeDenied expects the operation to be rejected, e.g. by an admission policy, with an error containing expectedMessage
+func ResourceOperationShouldBeDenied(dynamicClient dynamic.Interface, resource UnstructuredResource, operation, namespace, expectedMessage string) error {
+	err := ResourceOperationInNamespace(dynamicClient, resource, operation, namespace)
+	if err == nil {
+		return fmt.Errorf("expected '%s' '%s' to be denied, but it succeeded", operation, resource.Resource.GetName())
+	}
+	if !strings.Contains(err.Error(), expectedMessage) {
+		return fmt.Errorf("expected '%s' '%s' to be denied with message '%s', but received: '%s'", operation, resource.Resource.GetName(), expectedMessage, err.Error())
+	}
+	log.Infof("'%s' '%s' was denied (%s): %s", operation, resource.Resource.GetName(), kerrors.ReasonForError(err), err.Error())
+	return nil
+}
+
+// ResourceDryRunShouldHaveField creates the resource with a server-side dry run, which calls admission webhooks without persisting it,
+// and validates the returned resource has the field selector '<key>=<value>' set, e.g. by a mutating webhook.
+func ResourceDryRunShouldHaveField(dynamicClient dynamic.Interface, resource UnstructuredResource, selector string) error {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	split := util.DeleteEmpty(strings.Split(selector, "="))
+	if len(split) != 2 {
+		return errors.Errorf("Selector '%s' should meet format '<key>=<value>'", selector)
+	}
+	keySlice := util.DeleteEmpty(strings.Split(split[0], "."))
+	if len(keySlice) < 1 {
+		return errors.Errorf("Found empty 'key' in selector '%s' of form '<key>=<value>'", selector)
+	}
+
+	dryRunResource, err := createDryRun(dynamicClient, resource)
+	if err != nil {
+		return errors.Wrapf(err, "dry run create of %v %v failed", resource.Resource.GetKind(), resource.Resource.GetName())
+	}
+	val, err := util.ExtractField(dryRunResource.UnstructuredContent(), keySlice)
+	if err != nil {
+		return err
+	}
+	if fmt.Sprint(val) != split[1] {
+		return errors.Errorf("expected dry run of %v %v to have %v=%v, but found '%v'", resource.Resource.GetKind(), resource.Resource.GetName(), split[0], split[1], val)
+	}
+	log.Infof("dry run of %v %v has %v", resource.Resource.GetKind(), resource.Resource.GetName(), selector)
+	return nil
+}
+
+// ResourceDryRunShouldBeDenied creates the resource with a server-side dry run and expects it to be denied with an error containing expectedMessage.
+func ResourceDryRunShouldBeDenied(dynamicClient dynamic.Interface, resource UnstructuredResource, expectedMessage string) error {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	_, err := createDryRun(dynamicClient, resource)
+	if err == nil {
+		return errors.Errorf("expected dry run of %v %v to be denied, but it succeeded", resource.Resource.GetKind(), resource.Resource.GetName())
+	}
+	if !strings.Contains(err.Error(), expectedMessage) {
+		return errors.Errorf("expected dry run of %v %v to be denied with message '%v', but received: '%v'", resource.Resource.GetKind(), resource.Resource.GetName(), expectedMessage, err.Error())
+	}
+	log.Infof("dry run of %v %v was denied (%s): %s", resource.Resource.GetKind(), resource.Resource.GetName(), kerrors.ReasonForError(err), err.Error())
+	return nil
+}
+
+func ResourceShouldBe(dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig, state string) error {
+	return ResourceShouldBeCtx(context.Background(), dynamicClient, resource, w, state)
+}
+
+// ResourceShouldBeCtx is ResourceShouldBe, returning early when the context is done
+func ResourceShouldBeCtx(ctx context.Context, dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig, state string) error {
 	var (
 		exists  bool
 		counter int
 	)
 
-	if err := validateDynamicClient(dynamicClient); err != nil {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
 		return err
 	}
 
@@ -166,11 +246,11 @@ func ResourceShouldBe(dynamicClient dynamic.Interface, resource unstructuredReso
 	for {
 		exists = true
 		if counter >= w.GetTries() {
-			return errors.New("waiter timed out waiting for resource state")
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for resource state")
 		}
 		log.Infof("waiting for resource %v/%v to become %v", unstruct.GetNamespace(), unstruct.GetName(), state)
 
-		_, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
+		_, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(ctx, unstruct.GetName(), metav1.GetOptions{})
 		if err != nil {
 			if !kerrors.IsNotFound(err) {
 				return err
@@ -192,55 +272,163 @@ func ResourceShouldBe(dynamicClient dynamic.Interface, resource unstructuredReso
 			}
 		}
 		counter++
-		time.Sleep(w.GetInterval())
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
 	}
 }
 
-func ResourceShouldConvergeToField(dynamicClient dynamic.Interface, resource unstructuredResource, w common.WaiterConfig, selector string) error {
+func ResourcesShouldBe(dynamicClient dynamic.Interface, resources []UnstructuredResource, w common.WaiterConfig, state string) error {
+	return ResourcesShouldBeCtx(context.Background(), dynamicClient, resources, w, state)
+}
+
+// ResourcesShouldBeCtx waits for every resource to be created or deleted concurrently and reports all that were not
+func ResourcesShouldBeCtx(ctx context.Context, dynamicClient dynamic.Interface, resources []UnstructuredResource, w common.WaiterConfig, state string) error {
+	return waitForResources(resources, func(resource UnstructuredResource) error {
+		return ResourceShouldBeCtx(ctx, dynamicClient, resource, w, state)
+	})
+}
+
+func ResourceShouldBeCurrent(dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig) error {
+	return ResourceShouldBeCurrentCtx(context.Background(), dynamicClient, resource, w)
+}
+
+// ResourceShouldBeCurrentCtx is ResourceShouldBeCurrent, returning early when the context is done
+func ResourceShouldBeCurrentCtx(ctx context.Context, dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig) error {
 	var counter int
 
-	if err := validateDynamicClient(dynamicClient); err != nil {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
 		return err
 	}
 
-	split := util.DeleteEmpty(strings.Split(selector, "="))
-	if len(split) != 2 {
-		return errors.Errorf("Selector '%s' should meet format '<key>=<value>'", selector)
+	gvr, unstruct := resource.GVR, resource.Resource
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for %v %v/%v to be current", unstruct.GetKind(), unstruct.GetNamespace(), unstruct.GetName())
+		}
+		cr, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(ctx, unstruct.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return common.WrapNotFound(err)
+		}
+		status, message := computeResourceStatus(cr)
+		switch status {
+		case kstatus.CurrentStatus:
+			log.Infof("%v %v/%v is current", cr.GetKind(), cr.GetNamespace(), cr.GetName())
+			return nil
+		case kstatus.FailedStatus:
+			return errors.Errorf("%v %v/%v failed: %v", cr.GetKind(), cr.GetNamespace(), cr.GetName(), message)
+		}
+		log.Infof("waiting for %v %v/%v to be current, it is %v: %v", cr.GetKind(), cr.GetNamespace(), cr.GetName(), status, message)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
 	}
+}
 
-	key := split[0]
-	value := split[1]
+// ResourceShouldBeCurrentInClusters waits until the resource exists and is current in every cluster, the dynamic clients are keyed by cluster name
+func ResourceShouldBeCurrentInClusters(ctx context.Context, clusters map[string]dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig) error {
+	var counter int
 
-	keySlice := util.DeleteEmpty(strings.Split(key, "."))
-	if len(keySlice) < 1 {
-		return errors.Errorf("Found empty 'key' in selector '%s' of form '<key>=<value>'", selector)
+	if len(clusters) == 0 {
+		return errors.Errorf("no clusters to look for the resource in")
+	}
+	names := make([]string, 0, len(clusters))
+	for name, dynamicClient := range clusters {
+		if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+			return errors.Wrapf(err, "cluster %v", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	gvr, unstruct := resource.GVR, resource.Resource
+	for {
+		var pending []string
+		for _, name := range names {
+			cr, err := clusters[name].Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(ctx, unstruct.GetName(), metav1.GetOptions{})
+			if err != nil {
+				pending = append(pending, fmt.Sprintf("%v: %v", name, err))
+				continue
+			}
+			status, message := computeResourceStatus(cr)
+			if status == kstatus.CurrentStatus {
+				log.Infof("%v %v/%v is current in cluster %v", cr.GetKind(), cr.GetNamespace(), cr.GetName(), name)
+				continue
+			}
+			pending = append(pending, fmt.Sprintf("%v: %v, %v", name, status, message))
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for %v %v/%v to be current in clusters %v:\n%v", unstruct.GetKind(), unstruct.GetNamespace(), unstruct.GetName(), names, strings.Join(pending, "\n"))
+		}
+		log.Infof("waiting for %v %v/%v to be current in clusters:\n%v", unstruct.GetKind(), unstruct.GetNamespace(), unstruct.GetName(), strings.Join(pending, "\n"))
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func ResourceShouldConvergeToField(dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig, selector string) error {
+	return ResourceShouldConvergeToFieldCtx(context.Background(), dynamicClient, resource, w, selector)
+}
+
+// ResourceShouldConvergeToFieldCtx is ResourceShouldConvergeToField, returning early when the context is done
+func ResourceShouldConvergeToFieldCtx(ctx context.Context, dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig, selector string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	s, err := parseResourceSelector(selector)
+	if err != nil {
+		return err
 	}
 
 	gvr, unstruct := resource.GVR, resource.Resource
 
 	for {
 		if counter >= w.GetTries() {
-			return errors.New("waiter timed out waiting for resource")
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for resource")
 		}
-		log.Infof("waiting for resource %v/%v to converge to %v=%v", unstruct.GetNamespace(), unstruct.GetName(), key, value)
-		retResource, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
+		log.Infof("waiting for resource %v/%v to converge to %v", unstruct.GetNamespace(), unstruct.GetName(), s)
+		retResource, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(ctx, unstruct.GetName(), metav1.GetOptions{})
 		if err != nil {
-			return err
+			return common.WrapNotFound(err)
 		}
 
-		val, err := util.ExtractField(retResource.UnstructuredContent(), keySlice)
+		if s.operator != selectorOperatorEquals {
+			ok, err := s.matchesPresence(retResource.UnstructuredContent())
+			if err != nil {
+				return err
+			}
+			if ok {
+				break
+			}
+			counter++
+			if err := w.Sleep(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		val, err := util.ExtractField(retResource.UnstructuredContent(), s.keySlice)
 		if err != nil {
 			return err
 		}
 		var convertedValue any
 		switch val.(type) {
 		case int, int64:
-			convertedValue, err = strconv.ParseInt(value, 10, 64)
+			convertedValue, err = strconv.ParseInt(s.value, 10, 64)
 			if err != nil {
 				return err
 			}
 		case string:
-			convertedValue = value
+			convertedValue = s.value
 		default:
 			return errors.New("unknown type")
 		}
@@ -248,66 +436,156 @@ func ResourceShouldConvergeToField(dynamicClient dynamic.Interface, resource uns
 			break
 		}
 		counter++
-		time.Sleep(w.GetInterval())
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func ResourceShouldConvergeToSelector(dynamicClient dynamic.Interface, resource unstructuredResource, w common.WaiterConfig, selector string) error {
+// ResourceFieldShouldBeAtLeast waits until the field of the resource is greater than or equal to the quantity or duration
+func ResourceFieldShouldBeAtLeast(ctx context.Context, dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig, key, value string) error {
+	return waitForFieldComparison(ctx, dynamicClient, resource, w, key, value, fieldComparisonAtLeast)
+}
+
+// ResourceFieldShouldBeAtMost waits until the field of the resource is less than or equal to the quantity or duration
+func ResourceFieldShouldBeAtMost(ctx context.Context, dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig, key, value string) error {
+	return waitForFieldComparison(ctx, dynamicClient, resource, w, key, value, fieldComparisonAtMost)
+}
+
+// ResourceFieldShouldMatchJSONPath waits until the values at the JSONPath of the resource satisfy the operator, one of
+// ==, !=, >, >=, <, <=, contains or matches. The path must have a value and any value can satisfy the operator
+// except for !=, which no value may equal.
+// Values are ordered as quantities or durations and matched by a regexp with matches.
+func ResourceFieldShouldMatchJSONPath(ctx context.Context, dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig, jsonPath, operator, value string) error {
 	var counter int
 
-	if err := validateDynamicClient(dynamicClient); err != nil {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
 		return err
 	}
 
-	split := util.DeleteEmpty(strings.Split(selector, "="))
-	if len(split) != 2 {
-		return errors.Errorf("Selector '%s' should meet format '<key>=<value>'", selector)
+	parser, err := newJSONPathParser(jsonPath)
+	if err != nil {
+		return err
+	}
+	if _, err := matchJSONPathValues(nil, operator, value); err != nil {
+		return err
 	}
 
-	key := split[0]
-	value := split[1]
+	gvr, unstruct := resource.GVR, resource.Resource
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for %v of %v %v/%v to be %v %v", jsonPath, unstruct.GetKind(), unstruct.GetNamespace(), unstruct.GetName(), operator, value)
+		}
+		cr, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(ctx, unstruct.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return common.WrapNotFound(err)
+		}
+		values, err := getJSONPathValues(parser, cr.UnstructuredContent())
+		if err != nil {
+			return errors.Wrapf(err, "failed to get %v of %v %v/%v", jsonPath, cr.GetKind(), cr.GetNamespace(), cr.GetName())
+		}
+		ok, err := matchJSONPathValues(values, operator, value)
+		if err != nil {
+			return err
+		}
+		if ok {
+			log.Infof("%v of %v %v/%v is %v %v", jsonPath, cr.GetKind(), cr.GetNamespace(), cr.GetName(), operator, value)
+			return nil
+		}
+		log.Infof("waiting for %v of %v %v/%v to be %v %v, it is %v", jsonPath, cr.GetKind(), cr.GetNamespace(), cr.GetName(), operator, value, values)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
 
-	keySlice := util.DeleteEmpty(strings.Split(key, "."))
-	if len(keySlice) < 1 {
-		return errors.Errorf("Found empty 'key' in selector '%s' of form '<key>=<value>'", selector)
+func ResourceShouldConvergeToSelector(dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig, selector string) error {
+	return ResourceShouldConvergeToSelectorCtx(context.Background(), dynamicClient, resource, w, selector)
+}
+
+// ResourceShouldConvergeToSelectorCtx is ResourceShouldConvergeToSelector, returning early when the context is done
+func ResourceShouldConvergeToSelectorCtx(ctx context.Context, dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig, selector string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	s, err := parseResourceSelector(selector)
+	if err != nil {
+		return err
 	}
 
 	gvr, unstruct := resource.GVR, resource.Resource
 
 	for {
 		if counter >= w.GetTries() {
-			return errors.New("waiter timed out waiting for resource")
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for resource")
 		}
-		log.Infof("waiting for resource %v/%v to converge to %v=%v", unstruct.GetNamespace(), unstruct.GetName(), key, value)
-		retResource, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
+		log.Infof("waiting for resource %v/%v to converge to %v", unstruct.GetNamespace(), unstruct.GetName(), s)
+		retResource, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(ctx, unstruct.GetName(), metav1.GetOptions{})
 		if err != nil {
-			return err
+			return common.WrapNotFound(err)
 		}
 
-		if val, ok, err := unstructured.NestedString(retResource.UnstructuredContent(), keySlice...); ok {
+		if s.operator != selectorOperatorEquals {
+			ok, err := s.matchesPresence(retResource.UnstructuredContent())
 			if err != nil {
 				return err
 			}
-			if strings.EqualFold(val, value) {
+			if ok {
+				break
+			}
+			counter++
+			if err := w.Sleep(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if val, ok, err := unstructured.NestedString(retResource.UnstructuredContent(), s.keySlice...); ok {
+			if err != nil {
+				return err
+			}
+			if strings.EqualFold(val, s.value) {
 				break
 			}
 		}
 		counter++
-		time.Sleep(w.GetInterval())
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func ResourceConditionShouldBe(dynamicClient dynamic.Interface, resource unstructuredResource, w common.WaiterConfig, conditionType, conditionValue string) error {
+func ResourcesShouldConvergeToSelector(dynamicClient dynamic.Interface, resources []UnstructuredResource, w common.WaiterConfig, selector string) error {
+	return ResourcesShouldConvergeToSelectorCtx(context.Background(), dynamicClient, resources, w, selector)
+}
+
+// ResourcesShouldConvergeToSelectorCtx waits for every resource to converge to the selector concurrently and reports all that did not
+func ResourcesShouldConvergeToSelectorCtx(ctx context.Context, dynamicClient dynamic.Interface, resources []UnstructuredResource, w common.WaiterConfig, selector string) error {
+	return waitForResources(resources, func(resource UnstructuredResource) error {
+		return ResourceShouldConvergeToSelectorCtx(ctx, dynamicClient, resource, w, selector)
+	})
+}
+
+func ResourceConditionShouldBe(dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig, conditionType, conditionValue string) error {
+	return ResourceConditionShouldBeCtx(context.Background(), dynamicClient, resource, w, conditionType, conditionValue)
+}
+
+// ResourceConditionShouldBeCtx is ResourceConditionShouldBe, returning early when the context is done
+func ResourceConditionShouldBeCtx(ctx context.Context, dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig, conditionType, conditionValue string) error {
 	var (
 		counter        int
 		expectedStatus = cases.Title(language.English).String(conditionValue)
 	)
 
-	if err := validateDynamicClient(dynamicClient); err != nil {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
 		return err
 	}
 
@@ -315,12 +593,12 @@ func ResourceConditionShouldBe(dynamicClient dynamic.Interface, resource unstruc
 
 	for {
 		if counter >= w.GetTries() {
-			return errors.New("waiter timed out waiting for resource state")
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for resource state")
 		}
 		log.Infof("waiting for resource %v/%v to meet condition %v=%v", unstruct.GetNamespace(), unstruct.GetName(), conditionType, expectedStatus)
-		cr, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
+		cr, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(ctx, unstruct.GetName(), metav1.GetOptions{})
 		if err != nil {
-			return err
+			return common.WrapNotFound(err)
 		}
 
 		if conditions, ok, err := unstructured.NestedSlice(cr.UnstructuredContent(), "status", "conditions"); ok {
@@ -350,56 +628,84 @@ func ResourceConditionShouldBe(dynamicClient dynamic.Interface, resource unstruc
 			}
 		}
 		counter++
-		time.Sleep(w.GetInterval())
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
 	}
 }
 
-func UpdateResourceWithField(dynamicClient dynamic.Interface, resource unstructuredResource, key string, value string) error {
-	var (
-		keySlice     = util.DeleteEmpty(strings.Split(key, "."))
-		overrideType bool
-		intValue     int64
-		//err          error
-	)
-
-	if err := validateDynamicClient(dynamicClient); err != nil {
+func UpdateResourceWithField(dynamicClient dynamic.Interface, resource UnstructuredResource, key string, value string) error {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
 		return err
 	}
 
-	gvr, unstruct := resource.GVR, resource.Resource
-
-	n, err := strconv.ParseInt(value, 10, 64)
-	if err == nil {
-		overrideType = true
-		intValue = n
+	updateTarget, err := getResourceWithField(dynamicClient, resource, key, value)
+	if err != nil {
+		return err
 	}
 
-	updateTarget, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
+	_, err = dynamicClient.Resource(resource.GVR.Resource).Namespace(updateTarget.GetNamespace()).Update(context.Background(), updateTarget, metav1.UpdateOptions{})
 	if err != nil {
 		return err
 	}
+	return nil
+}
 
-	switch overrideType {
-	case true:
-		if err := unstructured.SetNestedField(updateTarget.UnstructuredContent(), intValue, keySlice...); err != nil {
-			return err
-		}
-	case false:
-		if err := unstructured.SetNestedField(updateTarget.UnstructuredContent(), value, keySlice...); err != nil {
-			return err
-		}
+// UpdateResourceStatusWithField writes the field through the status subresource, the way a controller reports status
+func UpdateResourceStatusWithField(dynamicClient dynamic.Interface, resource UnstructuredResource, key string, value string) error {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
 	}
 
-	_, err = dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Update(context.Background(), updateTarget, metav1.UpdateOptions{})
+	updateTarget, err := getResourceWithField(dynamicClient, resource, key, value)
 	if err != nil {
 		return err
 	}
+
+	_, err = dynamicClient.Resource(resource.GVR.Resource).Namespace(updateTarget.GetNamespace()).UpdateStatus(context.Background(), updateTarget, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the status of %s %s/%s", updateTarget.GetKind(), updateTarget.GetNamespace(), updateTarget.GetName())
+	}
+	log.Infof("%s %s/%s status updated with %s set to %s", updateTarget.GetKind(), updateTarget.GetNamespace(), updateTarget.GetName(), key, value)
 	return nil
 }
 
+// ResourceFieldShouldBeOwnedBy validates the field manager owns the field of the live resource according to its managedFields
+func ResourceFieldShouldBeOwnedBy(dynamicClient dynamic.Interface, resource UnstructuredResource, field, manager string) error {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	gvr, unstruct := resource.GVR, resource.Resource
+	cr, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return common.WrapNotFound(err)
+	}
+
+	managers, err := getFieldManagers(cr, splitFieldPath(field))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the managedFields of %v %v/%v", cr.GetKind(), cr.GetNamespace(), cr.GetName())
+	}
+	for _, m := range managers {
+		if m == manager {
+			log.Infof("field %v of %v %v/%v is owned by %v", field, cr.GetKind(), cr.GetNamespace(), cr.GetName(), managers)
+			return nil
+		}
+	}
+	if len(managers) == 0 {
+		return errors.Errorf("field %v of %v %v/%v is not owned by any field manager, expected '%v'", field, cr.GetKind(), cr.GetNamespace(), cr.GetName(), manager)
+	}
+	return errors.Errorf("field %v of %v %v/%v is owned by %v, expected '%v'", field, cr.GetKind(), cr.GetNamespace(), cr.GetName(), managers, manager)
+}
+
 // TODO: refactor so it doesnt need the dynamic and discovery clients
 func DeleteResourcesAtPath(dynamicClient dynamic.Interface, dc discovery.DiscoveryInterface, TemplateArguments interface{}, w common.WaiterConfig, resourcesPath string) error {
-	if err := validateDynamicClient(dynamicClient); err != nil {
+	return DeleteResourcesAtPathCtx(context.Background(), dynamicClient, dc, TemplateArguments, w, resourcesPath)
+}
+
+// DeleteResourcesAtPathCtx is DeleteResourcesAtPath, returning early when the context is done
+func DeleteResourcesAtPathCtx(ctx context.Context, dynamicClient dynamic.Interface, dc discovery.DiscoveryInterface, TemplateArguments interface{}, w common.WaiterConfig, resourcesPath string) error {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
 		return err
 	}
 
@@ -412,13 +718,13 @@ func DeleteResourcesAtPath(dynamicClient dynamic.Interface, dc discovery.Discove
 			return nil
 		}
 
-		resources, err := GetResources(dc, TemplateArguments, path)
+		resources, err := GetResourcesCtx(ctx, dc, TemplateArguments, path)
 		if err != nil {
 			return err
 		}
 		for _, resource := range resources {
 			gvr, unstruct := resource.GVR, resource.Resource
-			err = dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Delete(context.Background(), unstruct.GetName(), metav1.DeleteOptions{})
+			err = dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Delete(ctx, unstruct.GetName(), metav1.DeleteOptions{})
 			if err != nil {
 				if kerrors.IsNotFound(err) {
 					log.Infof("resource %v/%v already deleted", unstruct.GetNamespace(), unstruct.GetName())
@@ -444,7 +750,7 @@ func DeleteResourcesAtPath(dynamicClient dynamic.Interface, dc discovery.Discove
 			return nil
 		}
 
-		resources, err := GetResources(dc, TemplateArguments, path)
+		resources, err := GetResourcesCtx(ctx, dc, TemplateArguments, path)
 		if err != nil {
 			return err
 		}
@@ -452,10 +758,10 @@ func DeleteResourcesAtPath(dynamicClient dynamic.Interface, dc discovery.Discove
 			gvr, unstruct := resource.GVR, resource.Resource
 			for {
 				if counter >= w.GetTries() {
-					return errors.New("waiter timed out waiting for deletion")
+					return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for deletion")
 				}
 				log.Infof("waiting for resource deletion of %v/%v", unstruct.GetNamespace(), unstruct.GetName())
-				_, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
+				_, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(ctx, unstruct.GetName(), metav1.GetOptions{})
 				if err != nil {
 					if kerrors.IsNotFound(err) {
 						log.Infof("resource %v/%v already deleted", unstruct.GetNamespace(), unstruct.GetName())
@@ -463,7 +769,9 @@ func DeleteResourcesAtPath(dynamicClient dynamic.Interface, dc discovery.Discove
 					}
 				}
 				counter++
-				time.Sleep(w.GetInterval())
+				if err := w.Sleep(ctx); err != nil {
+					return err
+				}
 			}
 		}
 		return nil
@@ -478,6 +786,214 @@ func DeleteResourcesAtPath(dynamicClient dynamic.Interface, dc discovery.Discove
 	return nil
 }
 
+// ResourceFieldShouldMatch waits until the field of a resource matches the same field of the source resource of the same
+// type, ignoring the comma separated fields under it, e.g. for a resource copied by a replication controller
+func ResourceFieldShouldMatch(ctx context.Context, dynamicClient dynamic.Interface, dc discovery.DiscoveryInterface, w common.WaiterConfig, resourceType, name, namespace, sourceName, sourceNamespace, field, ignoredFields string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	gvr, err := getResourceType(dc, resourceType)
+	if err != nil {
+		return err
+	}
+	fieldPath := splitFieldPath(field)
+	if len(fieldPath) < 1 {
+		return errors.Errorf("Found empty field '%s'", field)
+	}
+
+	for {
+		source, err := dynamicClient.Resource(gvr).Namespace(sourceNamespace).Get(ctx, sourceName, metav1.GetOptions{})
+		if err != nil {
+			return common.WrapNotFound(err)
+		}
+		expected, found, err := getComparableField(source, fieldPath, ignoredFields)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return errors.Errorf("field '%s' not found in %v %v/%v", field, resourceType, sourceNamespace, sourceName)
+		}
+
+		var diffs []string
+		resource, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case kerrors.IsNotFound(err):
+			diffs = []string{fmt.Sprintf("%v %v/%v not found", resourceType, namespace, name)}
+		case err != nil:
+			return err
+		default:
+			value, _, err := getComparableField(resource, fieldPath, ignoredFields)
+			if err != nil {
+				return err
+			}
+			diffs = diffFields(field, value, expected)
+		}
+
+		if len(diffs) == 0 {
+			log.Infof("field '%s' of %v %v/%v matches %v/%v", field, resourceType, namespace, name, sourceNamespace, sourceName)
+			return nil
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "field '%s' of %v %v/%v does not match %v/%v, differences:\n%v", field, resourceType, namespace, name, sourceNamespace, sourceName, strings.Join(diffs, "\n"))
+		}
+		log.Infof("waiting for field '%s' of %v %v/%v to match %v/%v, differences: %v", field, resourceType, namespace, name, sourceNamespace, sourceName, diffs)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// DefaultIgnoredFields are the fields populated by the API server that ResourceShouldMatchExpected ignores unless others are given
+var DefaultIgnoredFields = []string{
+	"status",
+	"metadata.managedFields",
+	"metadata.uid",
+	"metadata.resourceVersion",
+	"metadata.creationTimestamp",
+	"metadata.generation",
+}
+
+// ResourceShouldMatchExpected waits until the resource has every field of the expected resource, like the assert files
+// of KUTTL, the fields only the resource has are not compared and lists must have the same items in order.
+// The ignored fields are removed from both before comparing them
+func ResourceShouldMatchExpected(ctx context.Context, dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig, expected *unstructured.Unstructured, ignoredFields []string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	expectedContent := withoutFields(expected, ignoredFields)
+	gvr, unstruct := resource.GVR, resource.Resource
+	for {
+		var diffs []string
+		cr, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(ctx, unstruct.GetName(), metav1.GetOptions{})
+		switch {
+		case kerrors.IsNotFound(err):
+			diffs = []string{fmt.Sprintf("%v %v/%v not found", unstruct.GetKind(), unstruct.GetNamespace(), unstruct.GetName())}
+		case err != nil:
+			return err
+		default:
+			diffs = diffExpectedFields("", withoutFields(cr, ignoredFields), expectedContent)
+		}
+
+		if len(diffs) == 0 {
+			log.Infof("%v %v/%v matches the expected state", unstruct.GetKind(), unstruct.GetNamespace(), unstruct.GetName())
+			return nil
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for %v %v/%v to match the expected state, differences:\n%v", unstruct.GetKind(), unstruct.GetNamespace(), unstruct.GetName(), strings.Join(diffs, "\n"))
+		}
+		log.Infof("waiting for %v %v/%v to match the expected state, differences: %v", unstruct.GetKind(), unstruct.GetNamespace(), unstruct.GetName(), diffs)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// DeleteResourceChildrenShouldBeGarbageCollected deletes the resource and validates the garbage collector deletes the
+// resources of the kinds with an ownerReference to it
+func DeleteResourceChildrenShouldBeGarbageCollected(ctx context.Context, dynamicClient dynamic.Interface, dc discovery.DiscoveryInterface, resource UnstructuredResource, w common.WaiterConfig, childKinds string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	mappings, err := getChildMappings(ctx, dc, childKinds)
+	if err != nil {
+		return err
+	}
+	gvr, unstruct := resource.GVR, resource.Resource
+	parent, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(ctx, unstruct.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return common.WrapNotFound(err)
+	}
+	children, err := getOwnedResources(dynamicClient, mappings, parent)
+	if err != nil {
+		return err
+	}
+	log.Infof("%v %v/%v owns %d resources of kinds %v", parent.GetKind(), parent.GetNamespace(), parent.GetName(), len(children), childKinds)
+
+	propagationPolicy := metav1.DeletePropagationBackground
+	if err := dynamicClient.Resource(gvr.Resource).Namespace(parent.GetNamespace()).Delete(ctx, parent.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}); err != nil {
+		return err
+	}
+	log.Infof("submitted deletion for %v/%v", parent.GetNamespace(), parent.GetName())
+
+	for {
+		children, err := getOwnedResources(dynamicClient, mappings, parent)
+		if err != nil {
+			return err
+		}
+		if len(children) == 0 {
+			log.Infof("children of %v %v/%v are garbage collected", parent.GetKind(), parent.GetNamespace(), parent.GetName())
+			return nil
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for children of %v %v/%v to be garbage collected, remaining:\n%v", parent.GetKind(), parent.GetNamespace(), parent.GetName(), strings.Join(children, "\n"))
+		}
+		log.Infof("waiting for children of %v %v/%v to be garbage collected, remaining: %v", parent.GetKind(), parent.GetNamespace(), parent.GetName(), children)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// ResourcesAtPathShouldBeUninstalled validates the CustomResourceDefinitions, webhook configurations, ClusterRoles and
+// Namespaces in the manifests at the path are deleted, waiting for finalizers to complete their cleanup
+func ResourcesAtPathShouldBeUninstalled(ctx context.Context, dynamicClient dynamic.Interface, TemplateArguments interface{}, w common.WaiterConfig, resourcesPath string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	manifests := make([]*unstructured.Unstructured, 0)
+	var getFn = func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if info.IsDir() || filepath.Ext(path) != ".yaml" {
+			return nil
+		}
+		resources, err := GetManifests(TemplateArguments, path)
+		if err != nil {
+			return err
+		}
+		manifests = append(manifests, resources...)
+		return nil
+	}
+	if err := filepath.Walk(resourcesPath, getFn); err != nil {
+		return err
+	}
+
+	for {
+		remaining, err := getRemainingResources(dynamicClient, manifests)
+		if err != nil {
+			return err
+		}
+		if len(remaining) == 0 {
+			log.Infof("resources in '%s' are uninstalled", resourcesPath)
+			return nil
+		}
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for resources in '%s' to be uninstalled, remaining:\n%v", resourcesPath, strings.Join(remaining, "\n"))
+		}
+		log.Infof("waiting for resources in '%s' to be uninstalled, remaining: %v", resourcesPath, remaining)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
 func VerifyInstanceGroups(dynamicClient dynamic.Interface) error {
 	igs, err := GetInstanceGroupList(dynamicClient)
 	if err != nil {
@@ -498,3 +1014,101 @@ func VerifyInstanceGroups(dynamicClient dynamic.Interface) error {
 
 	return nil
 }
+
+func ScaleInstanceGroup(dynamicClient dynamic.Interface, name, namespace string, minSize, maxSize int64) error {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	if minSize > maxSize {
+		return errors.Errorf("instancegroup minSize %d cannot be greater than maxSize %d", minSize, maxSize)
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"eks":{"minSize":%d,"maxSize":%d}}}`, minSize, maxSize)
+	if err := patchInstanceGroup(dynamicClient, name, namespace, patch); err != nil {
+		return errors.Wrapf(err, "failed to scale instancegroup '%v/%v'", namespace, name)
+	}
+	log.Infof("instancegroup %v/%v scaled to minSize %d, maxSize %d", namespace, name, minSize, maxSize)
+	return nil
+}
+
+func InstanceGroupShouldBe(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, name, namespace, state string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for instancegroup '%v/%v' to be '%v'", namespace, name, state)
+		}
+
+		ig, err := GetInstanceGroup(dynamicClient, name, namespace)
+		if err != nil {
+			return err
+		}
+
+		currentState := getInstanceGroupState(ig)
+		if strings.EqualFold(currentState, state) {
+			log.Infof("instancegroup %v/%v is %v", namespace, name, currentState)
+			return nil
+		}
+		if strings.EqualFold(currentState, instanceGroupStateError) {
+			return errors.Errorf("instancegroup '%v/%v' is in '%v' state, expected '%v'", namespace, name, currentState, state)
+		}
+
+		log.Infof("instancegroup %v/%v is %v, waiting for it to be %v", namespace, name, currentState, state)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func InstanceGroupNodesShouldMatchMinSize(ctx context.Context, kubeClientset kubernetes.Interface, dynamicClient dynamic.Interface, w common.WaiterConfig, name, namespace string) error {
+	var counter int
+
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	ig, err := GetInstanceGroup(dynamicClient, name, namespace)
+	if err != nil {
+		return err
+	}
+
+	minSize, err := getInstanceGroupMinSize(ig)
+	if err != nil {
+		return err
+	}
+
+	opts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%v=%v", instanceGroupNodeLabel, name),
+	}
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for instancegroup '%v/%v' to have %d nodes", namespace, name, minSize)
+		}
+
+		nodes, err := kubeClientset.CoreV1().Nodes().List(ctx, opts)
+		if err != nil {
+			return err
+		}
+
+		if int64(len(nodes.Items)) == minSize {
+			log.Infof("instancegroup %v/%v has %d nodes", namespace, name, minSize)
+			return nil
+		}
+
+		log.Infof("instancegroup %v/%v has %d nodes, waiting for %d", namespace, name, len(nodes.Items), minSize)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
@@ -17,72 +17,369 @@ package unstructured
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	serializer "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/openapi"
 	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	kstatus "sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
 )
 
 const (
 	yamlSeparator = "\n---"
 	trimTokens    = "\n "
+
+	instanceGroupNamespace  = "instance-manager"
+	instanceGroupNodeLabel  = "node.kubernetes.io/instancegroup"
+	instanceGroupStateError = "Error"
+
+	schemaRefPrefix           = "#/components/schemas/"
+	extensionGroupVersionKind = "x-kubernetes-group-version-kind"
+	extensionPreserveUnknown  = "x-kubernetes-preserve-unknown-fields"
+	extensionIntOrString      = "x-kubernetes-int-or-string"
+	extensionEmbeddedResource = "x-kubernetes-embedded-resource"
+	schemaTypeObject          = "object"
+	schemaTypeArray           = "array"
+	schemaTypeString          = "string"
+	schemaTypeInteger         = "integer"
+	schemaTypeNumber          = "number"
+	schemaTypeBoolean         = "boolean"
+)
+
+// selectorOperator is the operator of a resource selector
+type selectorOperator string
+
+const (
+	selectorOperatorEquals    selectorOperator = "="
+	selectorOperatorExists    selectorOperator = "exists"
+	selectorOperatorNotExists selectorOperator = "!"
 )
 
-type unstructuredResource struct {
+// resourceSelector selects a resource by the value of a field, or by the presence or absence of the field
+type resourceSelector struct {
+	key      string
+	keySlice []string
+	value    string
+	operator selectorOperator
+}
+
+var instanceGroupResource = schema.GroupVersionResource{
+	Group:    "instancemgr.keikoproj.io",
+	Version:  "v1alpha1",
+	Resource: "instancegroups",
+}
+
+// uninstallResources are the cluster scoped kinds of an operator that must be deleted when it is uninstalled
+var uninstallResources = map[schema.GroupKind]schema.GroupVersionResource{
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:               {Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}: {Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingwebhookconfigurations"},
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:   {Group: "admissionregistration.k8s.io", Version: "v1", Resource: "mutatingwebhookconfigurations"},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:                       {Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"},
+	{Group: "", Kind: "Namespace"}:                                                  {Group: "", Version: "v1", Resource: "namespaces"},
+}
+
+// UnstructuredResource is a resource rendered from a file with the REST mapping of its kind
+type UnstructuredResource struct {
 	GVR      *meta.RESTMapping
 	Resource *unstructured.Unstructured
 }
 
-func GetResource(dc discovery.DiscoveryInterface, TemplateArguments interface{}, resourceFilePath string) (unstructuredResource, error) {
+func GetResource(dc discovery.DiscoveryInterface, TemplateArguments interface{}, resourceFilePath string) (UnstructuredResource, error) {
+	return GetResourceCtx(context.Background(), dc, TemplateArguments, resourceFilePath)
+}
+
+// GetResourceCtx is GetResource, returning early when the context is done while waiting for the kind to be served
+func GetResourceCtx(ctx context.Context, dc discovery.DiscoveryInterface, TemplateArguments interface{}, resourceFilePath string) (UnstructuredResource, error) {
 	data, err := os.ReadFile(resourceFilePath)
 	if err != nil {
-		return unstructuredResource{nil, nil}, err
+		return UnstructuredResource{nil, nil}, err
 	}
-	return getResourceFromString(string(data), dc, TemplateArguments)
+	return getResourceFromString(ctx, string(data), dc, TemplateArguments)
+}
+
+func GetResources(dc discovery.DiscoveryInterface, TemplateArguments interface{}, resourcesFilePath string) ([]UnstructuredResource, error) {
+	return GetResourcesCtx(context.Background(), dc, TemplateArguments, resourcesFilePath)
 }
 
-func GetResources(dc discovery.DiscoveryInterface, TemplateArguments interface{}, resourcesFilePath string) ([]unstructuredResource, error) {
+// GetResourcesCtx is GetResources, returning early when the context is done while waiting for a kind to be served
+func GetResourcesCtx(ctx context.Context, dc discovery.DiscoveryInterface, TemplateArguments interface{}, resourcesFilePath string) ([]UnstructuredResource, error) {
 	data, err := os.ReadFile(resourcesFilePath)
 	if err != nil {
 		return nil, err
 	}
+	return getResourcesFromBytes(ctx, data, dc, TemplateArguments)
+}
+
+// GetKustomizeResources returns the resources of the kustomization directory as rendered by 'kustomize build'
+func GetKustomizeResources(ctx context.Context, dc discovery.DiscoveryInterface, TemplateArguments interface{}, kustomizationDir string) ([]UnstructuredResource, error) {
+	data, err := buildKustomization(kustomizationDir)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := getResourcesFromBytes(ctx, data, dc, TemplateArguments)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the resources of kustomization '%s'", kustomizationDir)
+	}
+	return resources, nil
+}
+
+func getResourcesFromBytes(ctx context.Context, data []byte, dc discovery.DiscoveryInterface, TemplateArguments interface{}) ([]UnstructuredResource, error) {
 	manifests := bytes.Split(data, []byte(yamlSeparator))
-	resourceList := make([]unstructuredResource, 0)
+	resourceList := make([]UnstructuredResource, 0)
 	for _, manifest := range manifests {
 		if len(bytes.Trim(manifest, trimTokens)) == 0 {
 			continue
 		}
-		resource, err := getResourceFromString(string(manifest), dc, TemplateArguments)
+		resource, err := getResourceFromString(ctx, string(manifest), dc, TemplateArguments)
 		if err != nil {
 			return nil, err
 		}
 		resourceList = append(resourceList, resource)
 	}
-	return resourceList, err
+	return resourceList, nil
 }
 
-func GetInstanceGroupList(dynamicClient dynamic.Interface) (*unstructured.UnstructuredList, error) {
-	const (
-		instanceGroupNamespace   = "instance-manager"
-		customResourceGroup      = "instancemgr"
-		customResourceAPIVersion = "v1alpha1"
-		customeResourceDomain    = "keikoproj.io"
-		customResourceKind       = "instancegroups"
-	)
+// buildKustomization renders the kustomization directory in-process with the kustomize API
+func buildKustomization(kustomizationDir string) ([]byte, error) {
+	log.Infof("rendering kustomization '%s'", kustomizationDir)
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(filesys.MakeFsOnDisk(), kustomizationDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render kustomization '%s'", kustomizationDir)
+	}
+	data, err := resMap.AsYaml()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to encode the resources of kustomization '%s'", kustomizationDir)
+	}
+	return data, nil
+}
+
+// ValidateResources validates the resources rendered from the file against the OpenAPI v3 schemas published by the cluster, including
+// the structural schemas of custom resources, and reports the file, the index of the document ignoring empty ones, and the field path
+// of every violation. Resources whose schema can't be found are not validated.
+func ValidateResources(openAPIClient openapi.Client, resourcesFilePath string, resources ...UnstructuredResource) error {
+	if openAPIClient == nil {
+		return common.Errorf(common.ErrClientNotInitialized, "'k8s.io/client-go/openapi.Client' is nil.")
+	}
+	paths, err := openAPIClient.Paths()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the OpenAPI v3 paths")
+	}
+	documents := map[string]*spec3.OpenAPI{}
+	var violations []string
+	for i, resource := range resources {
+		gvk := resource.Resource.GroupVersionKind()
+		root, s, err := getResourceSchema(paths, documents, gvk)
+		if err != nil {
+			log.Warnf("skipping schema validation of %v/%v in '%v': %v", gvk.Kind, resource.Resource.GetName(), resourcesFilePath, err)
+			continue
+		}
+		validator := schemaValidator{schemas: root.Components.Schemas}
+		for _, violation := range validator.validate("", resource.Resource.Object, s) {
+			violations = append(violations, fmt.Sprintf("%v: document %d: %v/%v: %v", resourcesFilePath, i, gvk.Kind, resource.Resource.GetName(), violation))
+		}
+	}
+	if len(violations) > 0 {
+		return errors.Errorf("schema validation failed:\n%v", strings.Join(violations, "\n"))
+	}
+	return nil
+}
+
+// getResourceSchema returns the OpenAPI v3 document of the group version and the schema of the kind
+func getResourceSchema(paths map[string]openapi.GroupVersion, documents map[string]*spec3.OpenAPI, gvk schema.GroupVersionKind) (*spec3.OpenAPI, *spec.Schema, error) {
+	path := "apis/" + gvk.GroupVersion().String()
+	if gvk.Group == "" {
+		path = "api/" + gvk.Version
+	}
+	document, ok := documents[path]
+	if !ok {
+		gv, ok := paths[path]
+		if !ok {
+			return nil, nil, errors.Errorf("no OpenAPI v3 schema published for '%v'", path)
+		}
+		data, err := gv.Schema(runtime.ContentTypeJSON)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to get the OpenAPI v3 schema of '%v'", path)
+		}
+		document = &spec3.OpenAPI{}
+		if err := json.Unmarshal(data, document); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse the OpenAPI v3 schema of '%v'", path)
+		}
+		documents[path] = document
+	}
+	if document.Components == nil {
+		return nil, nil, errors.Errorf("no schema found for '%v'", gvk)
+	}
+	for _, s := range document.Components.Schemas {
+		gvks, _ := s.Extensions[extensionGroupVersionKind].([]interface{})
+		for _, item := range gvks {
+			if m, ok := item.(map[string]interface{}); ok && m["group"] == gvk.Group && m["version"] == gvk.Version && m["kind"] == gvk.Kind {
+				return document, s, nil
+			}
+		}
+	}
+	return nil, nil, errors.Errorf("no schema found for '%v'", gvk)
+}
+
+type schemaValidator struct {
+	schemas map[string]*spec.Schema
+}
+
+// validate returns '<field path>: <message>' for every value of the object not allowed by the schema
+func (v schemaValidator) validate(path string, value interface{}, s *spec.Schema) []string {
+	s = v.resolve(s)
+	if s == nil || value == nil {
+		return nil
+	}
+	if alternatives := append(append([]spec.Schema{}, s.OneOf...), s.AnyOf...); len(alternatives) > 0 {
+		for i := range alternatives {
+			if len(v.validate(path, value, &alternatives[i])) == 0 {
+				return nil
+			}
+		}
+		return []string{fmt.Sprintf("%v: value %v is not allowed by any of the schemas", fieldPath(path), value)}
+	}
+	if isExtensionSet(s, extensionIntOrString) {
+		switch value.(type) {
+		case string, int64, float64:
+			return nil
+		}
+		return []string{fmt.Sprintf("%v: expected an integer or a string, got %v", fieldPath(path), value)}
+	}
+
+	schemaType := ""
+	if len(s.Type) > 0 {
+		schemaType = s.Type[0]
+	} else if len(s.Properties) > 0 {
+		schemaType = schemaTypeObject
+	}
+	switch schemaType {
+	case schemaTypeObject:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%v: expected an object, got %v", fieldPath(path), value)}
+		}
+		return v.validateObject(path, object, s)
+	case schemaTypeArray:
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%v: expected an array, got %v", fieldPath(path), value)}
+		}
+		var violations []string
+		if s.Items != nil && s.Items.Schema != nil {
+			for i, item := range items {
+				violations = append(violations, v.validate(fmt.Sprintf("%v[%d]", path, i), item, s.Items.Schema)...)
+			}
+		}
+		return violations
+	case schemaTypeString:
+		if _, ok := value.(string); !ok {
+			return []string{fmt.Sprintf("%v: expected a string, got %v", fieldPath(path), value)}
+		}
+	case schemaTypeInteger:
+		if _, ok := value.(int64); !ok {
+			return []string{fmt.Sprintf("%v: expected an integer, got %v", fieldPath(path), value)}
+		}
+	case schemaTypeNumber:
+		switch value.(type) {
+		case int64, float64:
+		default:
+			return []string{fmt.Sprintf("%v: expected a number, got %v", fieldPath(path), value)}
+		}
+	case schemaTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%v: expected a boolean, got %v", fieldPath(path), value)}
+		}
+	}
+	return nil
+}
+
+func (v schemaValidator) validateObject(path string, object map[string]interface{}, s *spec.Schema) []string {
 	var (
-		customResourceName    = fmt.Sprintf("%v.%v", customResourceGroup, customeResourceDomain)
-		instanceGroupResource = schema.GroupVersionResource{Group: customResourceName, Version: customResourceAPIVersion, Resource: customResourceKind}
+		violations []string
+		keys       = make([]string, 0, len(object))
 	)
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fieldValue := object[key]
+		if property, ok := s.Properties[key]; ok {
+			violations = append(violations, v.validate(path+"."+key, fieldValue, &property)...)
+			continue
+		}
+		if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+			violations = append(violations, v.validate(path+"."+key, fieldValue, s.AdditionalProperties.Schema)...)
+			continue
+		}
+		if len(s.Properties) == 0 || isExtensionSet(s, extensionPreserveUnknown) || isExtensionSet(s, extensionEmbeddedResource) ||
+			(s.AdditionalProperties != nil && s.AdditionalProperties.Allows) {
+			continue
+		}
+		violations = append(violations, fmt.Sprintf("%v: unknown field", fieldPath(path+"."+key)))
+	}
+	return violations
+}
+
+// resolve follows the reference of the schema, or of its single allOf schema as used by fields with defaults
+func (v schemaValidator) resolve(s *spec.Schema) *spec.Schema {
+	for s != nil {
+		if ref := s.Ref.String(); ref != "" {
+			s = v.schemas[strings.TrimPrefix(ref, schemaRefPrefix)]
+			continue
+		}
+		if len(s.AllOf) == 1 && len(s.Type) == 0 && len(s.Properties) == 0 {
+			s = &s.AllOf[0]
+			continue
+		}
+		return s
+	}
+	return nil
+}
+
+func isExtensionSet(s *spec.Schema, extension string) bool {
+	set, _ := s.Extensions.GetBool(extension)
+	return set
+}
+
+func fieldPath(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
+
+func GetInstanceGroupList(dynamicClient dynamic.Interface) (*unstructured.UnstructuredList, error) {
 	igs, err := dynamicClient.Resource(instanceGroupResource).Namespace(instanceGroupNamespace).List(context.Background(), metav1.ListOptions{})
 	if err != nil {
 		return nil, err
@@ -90,26 +387,324 @@ func GetInstanceGroupList(dynamicClient dynamic.Interface) (*unstructured.Unstru
 	return igs, nil
 }
 
-func validateDynamicClient(dynamicClient dynamic.Interface) error {
-	if dynamicClient == nil {
-		return errors.Errorf("'k8s.io/client-go/dynamic.Interface' is nil.")
+func GetInstanceGroup(dynamicClient dynamic.Interface, name, namespace string) (*unstructured.Unstructured, error) {
+	ig, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return dynamicClient.Resource(instanceGroupResource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get instancegroup '%v/%v'", namespace, name)
 	}
-	return nil
+	return ig.(*unstructured.Unstructured), nil
+}
+
+func patchInstanceGroup(dynamicClient dynamic.Interface, name, namespace, patch string) error {
+	_, err := dynamicClient.Resource(instanceGroupResource).Namespace(namespace).Patch(context.Background(), name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+func getInstanceGroupState(ig *unstructured.Unstructured) string {
+	state, _, _ := unstructured.NestedString(ig.UnstructuredContent(), "status", "currentState")
+	return state
+}
+
+func getInstanceGroupMinSize(ig *unstructured.Unstructured) (int64, error) {
+	minSize, found, err := unstructured.NestedInt64(ig.UnstructuredContent(), "spec", "eks", "minSize")
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, errors.Errorf("instancegroup '%v/%v' does not have spec.eks.minSize", ig.GetNamespace(), ig.GetName())
+	}
+	return minSize, nil
+}
+
+// computeResourceStatus computes the kstatus status of the resource with sigs.k8s.io/cli-utils, together with the
+// message explaining it
+func computeResourceStatus(resource *unstructured.Unstructured) (kstatus.Status, string) {
+	result, err := kstatus.Compute(resource)
+	if err != nil {
+		return kstatus.UnknownStatus, err.Error()
+	}
+	return result.Status, result.Message
 }
 
-func getResourceFromString(resourceString string, dc discovery.DiscoveryInterface, args interface{}) (unstructuredResource, error) {
+// getRemainingResources returns the resources in the manifests, of the kinds in uninstallResources, that still exist
+func getRemainingResources(dynamicClient dynamic.Interface, manifests []*unstructured.Unstructured) ([]string, error) {
+	remaining := make([]string, 0)
+	for _, manifest := range manifests {
+		gvr, ok := uninstallResources[manifest.GroupVersionKind().GroupKind()]
+		if !ok {
+			continue
+		}
+		resource, err := dynamicClient.Resource(gvr).Get(context.Background(), manifest.GetName(), metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		description := fmt.Sprintf("%v %v", resource.GetKind(), resource.GetName())
+		if resource.GetDeletionTimestamp() != nil {
+			description = fmt.Sprintf("%v (terminating, finalizers: %v)", description, resource.GetFinalizers())
+		}
+		remaining = append(remaining, description)
+	}
+	return remaining, nil
+}
+
+// getChildMappings returns the REST mappings of a comma separated list of kinds in the apiVersion/Kind format, e.g. apps/v1/ReplicaSet,v1/Pod
+func getChildMappings(ctx context.Context, dc discovery.DiscoveryInterface, childKinds string) ([]*meta.RESTMapping, error) {
+	mappings := make([]*meta.RESTMapping, 0)
+	for _, childKind := range strings.Split(childKinds, ",") {
+		i := strings.LastIndex(childKind, "/")
+		if i <= 0 || i == len(childKind)-1 {
+			return nil, errors.Errorf("kind '%s' must be in the apiVersion/Kind format, e.g. apps/v1/ReplicaSet", childKind)
+		}
+		gv, err := schema.ParseGroupVersion(childKind[:i])
+		if err != nil {
+			return nil, err
+		}
+		gvk := gv.WithKind(childKind[i+1:])
+		mapping, err := getGVR(ctx, &gvk, dc)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings, nil
+}
+
+// getOwnedResources returns the resources of the mappings with an ownerReference to the owner
+func getOwnedResources(dynamicClient dynamic.Interface, mappings []*meta.RESTMapping, owner *unstructured.Unstructured) ([]string, error) {
+	owned := make([]string, 0)
+	for _, mapping := range mappings {
+		namespace := ""
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			namespace = owner.GetNamespace()
+		}
+		list, err := dynamicClient.Resource(mapping.Resource).Namespace(namespace).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			for _, ref := range item.GetOwnerReferences() {
+				if ref.UID == owner.GetUID() {
+					owned = append(owned, fmt.Sprintf("%v %v/%v", item.GetKind(), item.GetNamespace(), item.GetName()))
+					break
+				}
+			}
+		}
+	}
+	return owned, nil
+}
+
+// parseResourceSelector parses a selector of the form '<key>=<value>', '<key>' (exists) or '!<key>' (absent), where key
+// is a path of fields separated by dots; dots in a field name, e.g. of a label or annotation, are escaped as '\.'
+func parseResourceSelector(selector string) (resourceSelector, error) {
+	s := resourceSelector{key: selector, operator: selectorOperatorExists}
+	if strings.Contains(selector, "=") {
+		split := util.DeleteEmpty(strings.Split(selector, "="))
+		if len(split) != 2 {
+			return s, errors.Errorf("Selector '%s' should meet format '<key>=<value>', '<key>' or '!<key>'", selector)
+		}
+		s.key, s.value, s.operator = split[0], split[1], selectorOperatorEquals
+	} else if strings.HasPrefix(selector, "!") {
+		s.key, s.operator = strings.TrimPrefix(selector, "!"), selectorOperatorNotExists
+	}
+
+	s.keySlice = splitFieldPath(s.key)
+	if len(s.keySlice) < 1 {
+		return s, errors.Errorf("Found empty 'key' in selector '%s' of form '<key>=<value>', '<key>' or '!<key>'", selector)
+	}
+	return s, nil
+}
+
+// matchesPresence returns true if the field of an exists selector is present, or the field of an absent selector is not
+func (s resourceSelector) matchesPresence(content map[string]interface{}) (bool, error) {
+	_, found, err := unstructured.NestedFieldNoCopy(content, s.keySlice...)
+	if err != nil {
+		return false, err
+	}
+	return found == (s.operator == selectorOperatorExists), nil
+}
+
+func (s resourceSelector) String() string {
+	switch s.operator {
+	case selectorOperatorEquals:
+		return fmt.Sprintf("%v=%v", s.key, s.value)
+	case selectorOperatorNotExists:
+		return fmt.Sprintf("!%v", s.key)
+	default:
+		return s.key
+	}
+}
+
+// splitFieldPath splits a path of fields separated by dots, except for escaped dots
+func splitFieldPath(path string) []string {
+	const escapedDot = "\x00"
+	split := strings.Split(strings.ReplaceAll(path, "\\.", escapedDot), ".")
+	for i := range split {
+		split[i] = strings.ReplaceAll(split[i], escapedDot, ".")
+	}
+	return util.DeleteEmpty(split)
+}
+
+// getResourceType returns the resource of a type like secret, deployments or widgets.example.com
+func getResourceType(dc discovery.DiscoveryInterface, resourceType string) (schema.GroupVersionResource, error) {
+	if dc == nil {
+		return schema.GroupVersionResource{}, common.Errorf(common.ErrClientNotInitialized, "'k8s.io/client-go/discovery.DiscoveryInterface' is nil.")
+	}
+	return getRESTMapper(dc).ResourceFor(schema.ParseGroupResource(strings.ToLower(resourceType)).WithVersion(""))
+}
+
+// getComparableField returns a copy of the field of the resource without the comma separated fields under it
+func getComparableField(resource *unstructured.Unstructured, field []string, ignoredFields string) (interface{}, bool, error) {
+	value, found, err := unstructured.NestedFieldCopy(resource.UnstructuredContent(), field...)
+	if err != nil || !found {
+		return nil, found, err
+	}
+	if object, ok := value.(map[string]interface{}); ok {
+		for _, ignoredField := range util.DeleteEmpty(strings.Split(ignoredFields, ",")) {
+			unstructured.RemoveNestedField(object, splitFieldPath(ignoredField)...)
+		}
+	}
+	return value, true, nil
+}
+
+// diffFields returns the paths under the path where the values differ
+func diffFields(path string, value, expected interface{}) []string {
+	valueObject, valueIsObject := value.(map[string]interface{})
+	expectedObject, expectedIsObject := expected.(map[string]interface{})
+	if !valueIsObject || !expectedIsObject {
+		if reflect.DeepEqual(value, expected) {
+			return nil
+		}
+		return []string{path}
+	}
+	keys := make(map[string]bool)
+	for key := range valueObject {
+		keys[key] = true
+	}
+	for key := range expectedObject {
+		keys[key] = true
+	}
+	diffs := make([]string, 0)
+	for key := range keys {
+		diffs = append(diffs, diffFields(path+"."+key, valueObject[key], expectedObject[key])...)
+	}
+	sort.Strings(diffs)
+	return diffs
+}
+
+// withoutFields returns a copy of the content of the resource without the fields
+func withoutFields(resource *unstructured.Unstructured, fields []string) map[string]interface{} {
+	content := resource.DeepCopy().UnstructuredContent()
+	for _, field := range fields {
+		unstructured.RemoveNestedField(content, splitFieldPath(field)...)
+	}
+	return content
+}
+
+// diffExpectedFields returns the differences under the path where the value lacks or differs from a field of the
+// expected value, fields only the value has are not compared and lists must have the same length
+func diffExpectedFields(path string, value, expected interface{}) []string {
+	switch expected := expected.(type) {
+	case map[string]interface{}:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%v: expected an object, found %v", pathOrRoot(path), value)}
+		}
+		diffs := make([]string, 0)
+		for key, expectedField := range expected {
+			field, found := object[key]
+			if !found {
+				diffs = append(diffs, fmt.Sprintf("%v.%v: missing, expected %v", path, key, expectedField))
+				continue
+			}
+			diffs = append(diffs, diffExpectedFields(path+"."+key, field, expectedField)...)
+		}
+		sort.Strings(diffs)
+		return diffs
+	case []interface{}:
+		list, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%v: expected a list, found %v", pathOrRoot(path), value)}
+		}
+		if len(list) != len(expected) {
+			return []string{fmt.Sprintf("%v: expected %d items, found %d", pathOrRoot(path), len(expected), len(list))}
+		}
+		diffs := make([]string, 0)
+		for i := range expected {
+			diffs = append(diffs, diffExpectedFields(fmt.Sprintf("%v[%d]", path, i), list[i], expected[i])...)
+		}
+		return diffs
+	default:
+		if reflect.DeepEqual(value, expected) {
+			return nil
+		}
+		return []string{fmt.Sprintf("%v: expected %v, found %v", pathOrRoot(path), expected, value)}
+	}
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
+
+func createDryRun(dynamicClient dynamic.Interface, resource UnstructuredResource) (*unstructured.Unstructured, error) {
+	gvr, unstruct := resource.GVR, resource.Resource
+	return dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Create(context.Background(), unstruct, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+}
+
+// GetManifests decodes the documents of the file without mapping them to the resources of a cluster, so
+// they can be inspected even when the cluster does not serve their API
+func GetManifests(TemplateArguments interface{}, resourcesFilePath string) ([]*unstructured.Unstructured, error) {
+	data, err := os.ReadFile(resourcesFilePath)
+	if err != nil {
+		return nil, err
+	}
+	manifests := bytes.Split(data, []byte(yamlSeparator))
+	resourceList := make([]*unstructured.Unstructured, 0)
+	for _, manifest := range manifests {
+		if len(bytes.Trim(manifest, trimTokens)) == 0 {
+			continue
+		}
+		resource, _, err := decodeResource(string(manifest), TemplateArguments)
+		if err != nil {
+			return nil, err
+		}
+		resourceList = append(resourceList, resource)
+	}
+	return resourceList, nil
+}
+
+func getResourceFromString(ctx context.Context, resourceString string, dc discovery.DiscoveryInterface, args interface{}) (UnstructuredResource, error) {
+	resource, gvk, err := decodeResource(resourceString, args)
+	if err != nil {
+		return UnstructuredResource{GVR: nil, Resource: resource}, err
+	}
+	gvr, err := getGVR(ctx, gvk, dc)
+	if err != nil {
+		return UnstructuredResource{GVR: nil, Resource: resource}, err
+	}
+	return UnstructuredResource{GVR: gvr, Resource: resource}, err
+}
+
+func decodeResource(resourceString string, args interface{}) (*unstructured.Unstructured, *schema.GroupVersionKind, error) {
 	resource := &unstructured.Unstructured{}
 	var renderBuffer bytes.Buffer
 
 	if args != nil {
 		template, err := template.New("Resource").Parse(resourceString)
 		if err != nil {
-			return unstructuredResource{GVR: nil, Resource: resource}, err
+			return resource, nil, err
 		}
 
 		err = template.Execute(&renderBuffer, &args)
 		if err != nil {
-			return unstructuredResource{GVR: nil, Resource: resource}, err
+			return resource, nil, err
 		}
 	} else {
 		renderBuffer.WriteString(resourceString)
@@ -117,26 +712,331 @@ func getResourceFromString(resourceString string, dc discovery.DiscoveryInterfac
 
 	dec := serializer.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 	_, gvk, err := dec.Decode(renderBuffer.Bytes(), nil, resource)
+	return resource, gvk, err
+}
+
+// getGVR returns the REST mapping of the kind. A kind the discovery does not know yet invalidates the discovery, which is
+// retried for a few seconds since the API server takes a moment to serve the kind of a CRD that was just applied. The
+// discovery is cached when dc is a discovery.CachedDiscoveryInterface, as the one of the ClientSet
+func getGVR(ctx context.Context, gvk *schema.GroupVersionKind, dc discovery.DiscoveryInterface) (*meta.RESTMapping, error) {
+	var counter int
+
+	if dc == nil {
+		return nil, common.Errorf(common.ErrClientNotInitialized, "'k8s.io/client-go/discovery.DiscoveryInterface' is nil.")
+	}
+
+	mapper := getRESTMapper(dc)
+	for {
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if !meta.IsNoMatchError(err) || counter >= noKindMatchWaiter.GetTries() {
+			return mapping, err
+		}
+		mapper.Reset()
+		if counter > 0 {
+			log.Infof("waiting for kind %s to be served", gvk.String())
+			if err := noKindMatchWaiter.Sleep(ctx); err != nil {
+				return nil, err
+			}
+		}
+		counter++
+	}
+}
+
+// noKindMatchWaiter waits a few seconds for a new kind to be served after the discovery is invalidated
+var noKindMatchWaiter = common.NewWaiterConfig(5, time.Second)
+
+// getRESTMapper returns a REST mapper over the discovery, wrapping it in a memory cache unless it is cached already
+func getRESTMapper(dc discovery.DiscoveryInterface) *restmapper.DeferredDiscoveryRESTMapper {
+	cachedDiscovery, ok := dc.(discovery.CachedDiscoveryInterface)
+	if !ok {
+		cachedDiscovery = memory.NewMemCacheClient(dc)
+	}
+	return restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery)
+}
+
+// getResourceWithField gets the live resource and sets the field to the value, as an integer when the value parses as one
+func getResourceWithField(dynamicClient dynamic.Interface, resource UnstructuredResource, key, value string) (*unstructured.Unstructured, error) {
+	keySlice := util.DeleteEmpty(strings.Split(key, "."))
+
+	gvr, unstruct := resource.GVR, resource.Resource
+	updateTarget, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
 	if err != nil {
-		return unstructuredResource{GVR: nil, Resource: resource}, err
+		return nil, common.WrapNotFound(err)
 	}
-	gvr, err := getGVR(gvk, dc)
+
+	var fieldValue interface{} = value
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		fieldValue = n
+	}
+	if err := unstructured.SetNestedField(updateTarget.UnstructuredContent(), fieldValue, keySlice...); err != nil {
+		return nil, err
+	}
+	return updateTarget, nil
+}
+
+// getFieldManagers returns the sorted field managers whose managedFields entry includes the field or a field under it
+func getFieldManagers(resource *unstructured.Unstructured, field []string) ([]string, error) {
+	var (
+		managers []string
+		seen     = map[string]bool{}
+	)
+	for _, entry := range resource.GetManagedFields() {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal the fields of manager %v", entry.Manager)
+		}
+		if !fieldSetContains(fields, field) || seen[entry.Manager] {
+			continue
+		}
+		seen[entry.Manager] = true
+		managers = append(managers, entry.Manager)
+	}
+	sort.Strings(managers)
+	return managers, nil
+}
+
+// fieldSetContains walks the 'f:' keys of a FieldsV1 set, list items keyed by 'k:' or 'v:' are not addressable by a field path
+func fieldSetContains(fields map[string]interface{}, field []string) bool {
+	current := fields
+	for _, name := range field {
+		next, ok := current["f:"+name]
+		if !ok {
+			return false
+		}
+		if current, ok = next.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+type fieldComparison string
+
+const (
+	fieldComparisonAtLeast fieldComparison = "at least"
+	fieldComparisonAtMost  fieldComparison = "at most"
+)
+
+func waitForFieldComparison(ctx context.Context, dynamicClient dynamic.Interface, resource UnstructuredResource, w common.WaiterConfig, key, value string, comparison fieldComparison) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	var (
+		keySlice        = splitFieldPath(key)
+		gvr, unstruct   = resource.GVR, resource.Resource
+		lastObservation string
+	)
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for field %v of %v %v/%v to be %v %v, %v", key, unstruct.GetKind(), unstruct.GetNamespace(), unstruct.GetName(), comparison, value, lastObservation)
+		}
+		cr, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(ctx, unstruct.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return common.WrapNotFound(err)
+		}
+		actual, found, err := unstructured.NestedFieldNoCopy(cr.UnstructuredContent(), keySlice...)
+		if err != nil {
+			return err
+		}
+		if found {
+			result, err := compareFieldValue(actual, value)
+			if err != nil {
+				return errors.Wrapf(err, "failed to compare field %v of %v %v/%v", key, cr.GetKind(), cr.GetNamespace(), cr.GetName())
+			}
+			if (comparison == fieldComparisonAtLeast && result >= 0) || (comparison == fieldComparisonAtMost && result <= 0) {
+				log.Infof("field %v of %v %v/%v is %v, %v %v", key, cr.GetKind(), cr.GetNamespace(), cr.GetName(), actual, comparison, value)
+				return nil
+			}
+			lastObservation = fmt.Sprintf("it is %v", actual)
+		} else {
+			lastObservation = "it is not set"
+		}
+		log.Infof("waiting for field %v of %v %v/%v to be %v %v, %v", key, cr.GetKind(), cr.GetNamespace(), cr.GetName(), comparison, value, lastObservation)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// compareFieldValue returns -1, 0 or 1 as the field value is less than, equal to or greater than the expected value.
+// Both are compared as quantities when they parse as one and as durations otherwise, so 500m is half a unit but 1m30s is a duration
+func compareFieldValue(actual interface{}, expected string) (int, error) {
+	var actualString string
+	switch v := actual.(type) {
+	case int64:
+		actualString = strconv.FormatInt(v, 10)
+	case float64:
+		actualString = strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		actualString = v
+	default:
+		return 0, errors.Errorf("field value %v of type %T is not a number, quantity or duration", actual, actual)
+	}
+
+	actualQuantity, actualErr := resource.ParseQuantity(actualString)
+	expectedQuantity, expectedErr := resource.ParseQuantity(expected)
+	if actualErr == nil && expectedErr == nil {
+		return actualQuantity.Cmp(expectedQuantity), nil
+	}
+
+	actualDuration, err := time.ParseDuration(actualString)
+	if err != nil {
+		return 0, errors.Errorf("field value %v is not a number, quantity or duration", actual)
+	}
+	expectedDuration, err := time.ParseDuration(expected)
 	if err != nil {
-		return unstructuredResource{GVR: nil, Resource: resource}, err
+		return 0, errors.Errorf("%v is not a quantity or duration comparable to %v", expected, actual)
 	}
-	return unstructuredResource{GVR: gvr, Resource: resource}, err
+	switch {
+	case actualDuration < expectedDuration:
+		return -1, nil
+	case actualDuration > expectedDuration:
+		return 1, nil
+	}
+	return 0, nil
 }
 
-func getGVR(gvk *schema.GroupVersionKind, dc discovery.DiscoveryInterface) (*meta.RESTMapping, error) {
-	if dc == nil {
-		return nil, errors.Errorf("'k8s.io/client-go/discovery.DiscoveryInterface' is nil.")
+const (
+	jsonPathEquals         = "=="
+	jsonPathNotEquals      = "!="
+	jsonPathGreater        = ">"
+	jsonPathGreaterOrEqual = ">="
+	jsonPathLess           = "<"
+	jsonPathLessOrEqual    = "<="
+	jsonPathContains       = "contains"
+	jsonPathMatches        = "matches"
+)
+
+func newJSONPathParser(jsonPath string) (*jsonpath.JSONPath, error) {
+	expression := jsonPath
+	if !strings.HasPrefix(expression, "{") {
+		expression = fmt.Sprintf("{%s}", expression)
 	}
+	parser := jsonpath.New("resource").AllowMissingKeys(true)
+	if err := parser.Parse(expression); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse json path '%s'", jsonPath)
+	}
+	return parser, nil
+}
 
-	CachedDiscoveryInterface := memory.NewMemCacheClient(dc)
-	DeferredDiscoveryRESTMapper := restmapper.NewDeferredDiscoveryRESTMapper(CachedDiscoveryInterface)
-	RESTMapping, err := DeferredDiscoveryRESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+// getJSONPathValues returns every value the parser finds in the object, a missing field has no values
+func getJSONPathValues(parser *jsonpath.JSONPath, object map[string]interface{}) ([]interface{}, error) {
+	results, err := parser.FindResults(object)
 	if err != nil {
 		return nil, err
 	}
-	return RESTMapping, nil
+	var values []interface{}
+	for _, result := range results {
+		for _, value := range result {
+			values = append(values, value.Interface())
+		}
+	}
+	return values, nil
+}
+
+// matchJSONPathValues returns true if any of the values satisfies the operator or, for !=, if none equals the expected value
+func matchJSONPathValues(values []interface{}, operator, expected string) (bool, error) {
+	var match func(value interface{}) (bool, error)
+	switch operator {
+	case jsonPathEquals, jsonPathNotEquals:
+		match = func(value interface{}) (bool, error) {
+			if result, err := compareFieldValue(value, expected); err == nil {
+				return result == 0, nil
+			}
+			return jsonPathValueString(value) == expected, nil
+		}
+	case jsonPathGreater, jsonPathGreaterOrEqual, jsonPathLess, jsonPathLessOrEqual:
+		match = func(value interface{}) (bool, error) {
+			result, err := compareFieldValue(value, expected)
+			if err != nil {
+				return false, err
+			}
+			switch operator {
+			case jsonPathGreater:
+				return result > 0, nil
+			case jsonPathGreaterOrEqual:
+				return result >= 0, nil
+			case jsonPathLess:
+				return result < 0, nil
+			}
+			return result <= 0, nil
+		}
+	case jsonPathContains:
+		match = func(value interface{}) (bool, error) {
+			return strings.Contains(jsonPathValueString(value), expected), nil
+		}
+	case jsonPathMatches:
+		regex, err := regexp.Compile(expected)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to compile regexp '%s'", expected)
+		}
+		match = func(value interface{}) (bool, error) {
+			return regex.MatchString(jsonPathValueString(value)), nil
+		}
+	default:
+		return false, common.Errorf(common.ErrUnsupportedOperation, "unsupported operator '%s', expected one of ==, !=, >, >=, <, <=, contains or matches", operator)
+	}
+
+	if len(values) == 0 {
+		return false, nil
+	}
+	for _, value := range values {
+		ok, err := match(value)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return operator != jsonPathNotEquals, nil
+		}
+	}
+	return operator == jsonPathNotEquals, nil
+}
+
+// jsonPathValueString returns scalars as they are printed and objects and lists as json
+func jsonPathValueString(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(value)
+		if err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// waitForResources runs the waiter for every resource concurrently and aggregates the failures in document order
+func waitForResources(resources []UnstructuredResource, waiter func(resource UnstructuredResource) error) error {
+	if len(resources) == 0 {
+		return errors.Errorf("no resources to wait for")
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(resources))
+	for i, resource := range resources {
+		wg.Add(1)
+		go func(i int, resource UnstructuredResource) {
+			defer wg.Done()
+			errs[i] = waiter(resource)
+		}(i, resource)
+	}
+	wg.Wait()
+
+	var failures []string
+	for i, err := range errs {
+		if err != nil {
+			unstruct := resources[i].Resource
+			failures = append(failures, fmt.Sprintf("%v %v/%v: %v", unstruct.GetKind(), unstruct.GetNamespace(), unstruct.GetName(), err))
+		}
+	}
+	if len(failures) > 0 {
+		return errors.Errorf("%v of %v resources failed:\n%v", len(failures), len(resources), strings.Join(failures, "\n"))
+	}
+	return nil
 }
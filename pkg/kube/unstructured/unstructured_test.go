@@ -16,7 +16,9 @@ package unstructured
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -27,23 +29,31 @@ import (
 	"github.com/keikoproj/kubedog/internal/util"
 	"github.com/keikoproj/kubedog/pkg/generic"
 	"github.com/keikoproj/kubedog/pkg/kube/common"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	serializer "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
 	fakeDiscovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/dynamic"
 	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/openapi"
+	"k8s.io/client-go/openapi/openapitest"
 	kTesting "k8s.io/client-go/testing"
+	kstatus "sigs.k8s.io/cli-utils/pkg/kstatus/status"
 )
 
 func TestResourceOperationInNamespace(t *testing.T) {
 	type args struct {
 		dynamicClient dynamic.Interface
-		resource      unstructuredResource
+		resource      UnstructuredResource
 		operation     string
 		namespace     string
 	}
@@ -228,7 +238,7 @@ func TestResourceOperationInNamespace(t *testing.T) {
 func TestResourcesOperation(t *testing.T) {
 	type args struct {
 		dynamicClient dynamic.Interface
-		resources     []unstructuredResource
+		resources     []UnstructuredResource
 		operation     string
 	}
 	tests := []struct {
@@ -263,10 +273,69 @@ func TestResourcesOperation(t *testing.T) {
 	}
 }
 
+func TestResourcesOperationAndWaitReady(t *testing.T) {
+	type args struct {
+		dynamicClient dynamic.Interface
+		resources     []UnstructuredResource
+		operation     string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: resources with conditions",
+			args: args{
+				dynamicClient: newFakeDynamicClient(),
+				resources:     getResourcesFromYaml(t, getFilePath("multi-resource.yaml")),
+				operation:     common.OperationCreate,
+			},
+		},
+		{
+			name: "Negative Test: operation fails",
+			args: args{
+				dynamicClient: nil,
+				resources:     getResourcesFromYaml(t, getFilePath("multi-resource.yaml")),
+				operation:     common.OperationCreate,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: waiter timed out, resource is not ready",
+			args: args{
+				dynamicClient: newFakeDynamicClient(),
+				resources: []UnstructuredResource{getResourceFromBytes(t, []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deployment1
+  namespace: namespace1
+spec:
+  replicas: 2
+status:
+  observedGeneration: 1
+  updatedReplicas: 2
+  availableReplicas: 1
+`))},
+				operation: common.OperationCreate,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := ResourcesOperationAndWaitReady(tt.args.dynamicClient, tt.args.resources, w, tt.args.operation); (err != nil) != tt.wantErr {
+				t.Errorf("ResourcesOperationAndWaitReady() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestResourcesOperationInNamespace(t *testing.T) {
 	type args struct {
 		dynamicClient dynamic.Interface
-		resources     []unstructuredResource
+		resources     []UnstructuredResource
 		operation     string
 		namespace     string
 	}
@@ -316,7 +385,7 @@ func TestResourcesOperationInNamespace(t *testing.T) {
 func TestResourceOperationWithResultInNamespace(t *testing.T) {
 	type args struct {
 		dynamicClient  dynamic.Interface
-		resource       unstructuredResource
+		resource       UnstructuredResource
 		operation      string
 		namespace      string
 		expectedResult string
@@ -372,10 +441,113 @@ func TestResourceOperationWithResultInNamespace(t *testing.T) {
 	}
 }
 
+func TestResourceOperationShouldBeDenied(t *testing.T) {
+	type args struct {
+		dynamicClient   dynamic.Interface
+		expectedMessage string
+	}
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	deniedError := kerrors.NewForbidden(resource.GVR.Resource.GroupResource(), resource.Resource.GetName(),
+		errors.New(`admission webhook "validate.kyverno.svc" denied the request: label 'team' is required`))
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: denied with expected message",
+			args: args{
+				dynamicClient:   newFakeDynamicClientWithReaction("create", "*", newReactionFuncWithError(deniedError)),
+				expectedMessage: "label 'team' is required",
+			},
+		},
+		{
+			name: "Negative Test: denied with different message",
+			args: args{
+				dynamicClient:   newFakeDynamicClientWithReaction("create", "*", newReactionFuncWithError(deniedError)),
+				expectedMessage: "label 'owner' is required",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: not denied",
+			args: args{
+				dynamicClient:   newFakeDynamicClient(),
+				expectedMessage: "label 'team' is required",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ResourceOperationShouldBeDenied(tt.args.dynamicClient, resource, common.OperationCreate, "", tt.args.expectedMessage); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceOperationShouldBeDenied() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceDryRun(t *testing.T) {
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	deniedError := kerrors.NewBadRequest(`admission webhook "validate.example.com" denied the request: image tag is required`)
+	tests := []struct {
+		name    string
+		fn      func() error
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: dry run has field",
+			fn: func() error {
+				return ResourceDryRunShouldHaveField(newFakeDynamicClient(), resource, "metadata.labels.someTestKey=someTestValue")
+			},
+		},
+		{
+			name: "Positive Test: dry run has numeric field",
+			fn: func() error {
+				return ResourceDryRunShouldHaveField(newFakeDynamicClient(), resource, "status.replicaCount=2")
+			},
+		},
+		{
+			name: "Negative Test: dry run has different field value",
+			fn: func() error {
+				return ResourceDryRunShouldHaveField(newFakeDynamicClient(), resource, "metadata.labels.someTestKey=mutated")
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid selector",
+			fn: func() error {
+				return ResourceDryRunShouldHaveField(newFakeDynamicClient(), resource, "metadata.labels.someTestKey")
+			},
+			wantErr: true,
+		},
+		{
+			name: "Positive Test: dry run denied",
+			fn: func() error {
+				return ResourceDryRunShouldBeDenied(newFakeDynamicClientWithReaction("create", "*", newReactionFuncWithError(deniedError)), resource, "image tag is required")
+			},
+		},
+		{
+			name: "Negative Test: dry run not denied",
+			fn: func() error {
+				return ResourceDryRunShouldBeDenied(newFakeDynamicClient(), resource, "image tag is required")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceDryRun() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestResourceShouldBe(t *testing.T) {
 	type args struct {
 		dynamicClient dynamic.Interface
-		resource      unstructuredResource
+		resource      UnstructuredResource
 		w             common.WaiterConfig
 		state         string
 	}
@@ -434,15 +606,142 @@ func TestResourceShouldBe(t *testing.T) {
 	}
 }
 
+func TestResourcesShouldBe(t *testing.T) {
+	resources := getResourcesFromYaml(t, getFilePath("multi-resource.yaml"))
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		resources     []UnstructuredResource
+		state         string
+		wantFailed    []string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: all resources created",
+			dynamicClient: newFakeDynamicClientWithResourcesAndResourcesLists(resources...),
+			resources:     resources,
+			state:         common.StateCreated,
+		},
+		{
+			name:          "Positive Test: all resources deleted",
+			dynamicClient: newFakeDynamicClient(),
+			resources:     resources,
+			state:         common.StateDeleted,
+		},
+		{
+			name:          "Negative Test: one resource missing",
+			dynamicClient: newFakeDynamicClientWithResource(resources[0]),
+			resources:     resources,
+			state:         common.StateCreated,
+			wantFailed:    []string{"OtherKind someTestNamespace/otherResource"},
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: every failure is reported",
+			dynamicClient: newFakeDynamicClient(),
+			resources:     resources,
+			state:         common.StateCreated,
+			wantFailed:    []string{"SomeKind someTestNamespace/someResource", "OtherKind someTestNamespace/otherResource"},
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: no resources",
+			dynamicClient: newFakeDynamicClient(),
+			state:         common.StateCreated,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ResourcesShouldBe(tt.dynamicClient, tt.resources, w, tt.state)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResourcesShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, failed := range tt.wantFailed {
+				if !strings.Contains(err.Error(), failed) {
+					t.Errorf("ResourcesShouldBe() error = %v, want it to report %v", err, failed)
+				}
+			}
+		})
+	}
+}
+
+func TestResourcesShouldConvergeToSelector(t *testing.T) {
+	resources := getResourcesFromYaml(t, getFilePath("multi-resource.yaml"))
+	dynamicClient := newFakeDynamicClientWithResourcesAndResourcesLists(resources...)
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	if err := ResourcesShouldConvergeToSelector(dynamicClient, resources, w, ".metadata.labels.someTestKey=someTestValue"); err != nil {
+		t.Errorf("ResourcesShouldConvergeToSelector() error = %v", err)
+	}
+	if err := ResourcesShouldConvergeToSelector(dynamicClient, resources, w, ".metadata.labels.someTestKey=otherValue"); err == nil || !strings.Contains(err.Error(), "2 of 2 resources failed") {
+		t.Errorf("ResourcesShouldConvergeToSelector() error = %v, want both resources to fail", err)
+	}
+}
+
+func TestResourceShouldBeCurrent(t *testing.T) {
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	stalled := getResourceFromBytes(t, []byte(`apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget1
+  namespace: namespace1
+status:
+  conditions:
+  - type: Stalled
+    status: "True"
+`))
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		resource      UnstructuredResource
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			resource:      resource,
+		},
+		{
+			name:          "Negative Test: invalid client",
+			dynamicClient: nil,
+			resource:      resource,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: 'Get' call fails",
+			dynamicClient: newFakeDynamicClient(),
+			resource:      resource,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: resource failed",
+			dynamicClient: newFakeDynamicClientWithResource(stalled),
+			resource:      stalled,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := ResourceShouldBeCurrent(tt.dynamicClient, tt.resource, w); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceShouldBeCurrent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestResourceShouldConvergeToSelector(t *testing.T) {
 	type args struct {
 		dynamicClient dynamic.Interface
-		resource      unstructuredResource
+		resource      UnstructuredResource
 		w             common.WaiterConfig
 		selector      string
 	}
 	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
 	labelKey, labelValue := getOneLabel(t, *resource.Resource)
+	annotatedResource := UnstructuredResource{GVR: resource.GVR, Resource: resource.Resource.DeepCopy()}
+	annotatedResource.Resource.SetAnnotations(map[string]string{"example.com/ready": "true"})
 	tests := []struct {
 		name    string
 		args    args
@@ -456,6 +755,30 @@ func TestResourceShouldConvergeToSelector(t *testing.T) {
 				selector:      ".metadata.labels." + labelKey + "=" + labelValue,
 			},
 		},
+		{
+			name: "Positive Test: key exists",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resource),
+				resource:      resource,
+				selector:      ".metadata.labels." + labelKey,
+			},
+		},
+		{
+			name: "Positive Test: key absent",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resource),
+				resource:      resource,
+				selector:      "!.metadata.labels.someNotFoundKey",
+			},
+		},
+		{
+			name: "Positive Test: escaped dots in key",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(annotatedResource),
+				resource:      annotatedResource,
+				selector:      ".metadata.annotations.example\\.com/ready=true",
+			},
+		},
 		{
 			name: "Negative Test: invalid client",
 			args: args{
@@ -499,6 +822,15 @@ func TestResourceShouldConvergeToSelector(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Negative Test: waiter timed out, key not absent",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resource),
+				resource:      resource,
+				selector:      "!.metadata.labels." + labelKey,
+			},
+			wantErr: true,
+		},
 		{
 			name: "Negative Test: waiter timed out, value does not match",
 			args: args{
@@ -522,7 +854,7 @@ func TestResourceShouldConvergeToSelector(t *testing.T) {
 func TestResourceShouldConvergeToField(t *testing.T) {
 	type args struct {
 		dynamicClient dynamic.Interface
-		resource      unstructuredResource
+		resource      UnstructuredResource
 		w             common.WaiterConfig
 		selector      string
 	}
@@ -605,7 +937,7 @@ func TestResourceShouldConvergeToField(t *testing.T) {
 func TestResourceConditionShouldBe(t *testing.T) {
 	type args struct {
 		dynamicClient  dynamic.Interface
-		resource       unstructuredResource
+		resource       UnstructuredResource
 		w              common.WaiterConfig
 		conditionType  string
 		conditionValue string
@@ -667,7 +999,7 @@ func TestResourceConditionShouldBe(t *testing.T) {
 func TestUpdateResourceWithField(t *testing.T) {
 	type args struct {
 		dynamicClient dynamic.Interface
-		resource      unstructuredResource
+		resource      UnstructuredResource
 		key           string
 		value         string
 	}
@@ -743,98 +1075,825 @@ func TestUpdateResourceWithField(t *testing.T) {
 	}
 }
 
-func TestDeleteResourcesAtPath(t *testing.T) {
-	type args struct {
-		dynamicClient     dynamic.Interface
-		dc                discovery.DiscoveryInterface
-		TemplateArguments interface{}
-		w                 common.WaiterConfig
-		resourcesPath     string
-	}
-
-	resourcePath := getFilePath("resource.yaml")
-	resource := getResourceFromYaml(t, resourcePath)
-	client := newFakeDynamicClientWithResourceList(resource)
-	clientWithResource := newFakeDynamicClientWithResourcesAndResourcesLists(resource)
-
-	resourcesPath := getFilePath("multi-resource.yaml")
-	resources := getResourcesFromYaml(t, resourcesPath)
-	clientWithResources := newFakeDynamicClientWithResourcesAndResourcesLists(resources...)
-	clientWithResourcesInTestDir := newFakeDynamicClientWithResourcesInDir(t, getTestFilesDirPath())
-
+func TestUpdateResourceStatusWithField(t *testing.T) {
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name          string
+		dynamicClient dynamic.Interface
+		key           string
+		value         string
+		wantErr       bool
 	}{
 		{
-			name: "Positive Test: delete resource in directory",
-			args: args{
-				dynamicClient: clientWithResourcesInTestDir,
-				dc:            newFakeDiscoveryClient(&clientWithResourcesInTestDir.Fake),
-				resourcesPath: getTestFilesDirPath(),
-			},
-		},
-		{
-			name: "Positive Test: delete resource",
-			args: args{
-				dynamicClient: clientWithResource,
-				dc:            newFakeDiscoveryClient(&clientWithResource.Fake),
-				resourcesPath: resourcePath,
-			},
+			name:          "Positive Test: string value",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			key:           ".status.phase",
+			value:         "Failed",
 		},
 		{
-			name: "Positive Test: already deleted",
-			args: args{
-				dynamicClient: client,
-				dc:            newFakeDiscoveryClient(&client.Fake),
-				resourcesPath: resourcePath,
-			},
+			name:          "Positive Test: integer value",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			key:           ".status.observedGeneration",
+			value:         "2",
 		},
 		{
-			name: "Positive Test: delete multiple resources",
-			args: args{
-				dynamicClient: clientWithResources,
-				dc:            newFakeDiscoveryClient(&clientWithResources.Fake),
-				resourcesPath: resourcesPath,
-			},
+			name:    "Negative Test: invalid client",
+			wantErr: true,
 		},
 		{
-			name: "Negative Test: invalid client",
-			args: args{
-				dynamicClient: nil,
-			},
-			wantErr: true,
+			name:          "Negative Test: 'Get' call fails",
+			dynamicClient: newFakeDynamicClient(),
+			key:           ".status.phase",
+			value:         "Failed",
+			wantErr:       true,
 		},
 		{
-			name: "Negative Test: 'GetResources' fails",
-			args: args{
-				dynamicClient: client,
-				dc:            nil,
-				resourcesPath: resourcePath,
-			},
+			name: "Negative Test: 'Update' call fails",
+			dynamicClient: newFakeDynamicClientWithReactors(
+				&kTesting.SimpleReactor{
+					Verb:     "get",
+					Resource: resource.Resource.GetName(),
+					Reaction: newReactionFunc(),
+				},
+				&kTesting.SimpleReactor{
+					Verb:     "update",
+					Resource: resource.Resource.GetName(),
+					Reaction: newReactionFuncWithError(errors.New("an error")),
+				},
+			),
+			key:     ".status.phase",
+			value:   "Failed",
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.args.w = common.NewWaiterConfig(1, time.Second)
-			if err := DeleteResourcesAtPath(tt.args.dynamicClient, tt.args.dc, tt.args.TemplateArguments, tt.args.w, tt.args.resourcesPath); (err != nil) != tt.wantErr {
-				t.Errorf("DeleteResourcesAtPath() error = %v, wantErr %v", err, tt.wantErr)
+			err := UpdateResourceStatusWithField(tt.dynamicClient, resource, tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateResourceStatusWithField() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			updated, err := tt.dynamicClient.Resource(resource.GVR.Resource).Namespace(resource.Resource.GetNamespace()).Get(context.Background(), resource.Resource.GetName(), metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _, _ := unstructured.NestedFieldNoCopy(updated.Object, util.DeleteEmpty(strings.Split(tt.key, "."))...)
+			if fmt.Sprint(got) != tt.value {
+				t.Errorf("UpdateResourceStatusWithField() %s = %v, want %v", tt.key, got, tt.value)
 			}
 		})
 	}
 }
 
-func TestVerifyInstanceGroups(t *testing.T) {
-	type args struct {
-		dynamicClient dynamic.Interface
-	}
-	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
-	}{
+func TestResourceFieldShouldBeOwnedBy(t *testing.T) {
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	resource.Resource = resource.Resource.DeepCopy()
+	resource.Resource.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:   "my-operator",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:template":{"f:containers":{}}}}`)},
+		},
+		{
+			Manager:   "kubectl-edit",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:someTestKey":{}}}}`)},
+		},
+		{
+			Manager:     "my-operator",
+			Operation:   metav1.ManagedFieldsOperationUpdate,
+			Subresource: "status",
+			FieldsV1:    &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:replicaCount":{}}}`)},
+		},
+	})
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		field         string
+		manager       string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: owned field",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			field:         "spec.template.containers",
+			manager:       "my-operator",
+		},
+		{
+			name:          "Positive Test: field under an owned parent",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			field:         ".spec.template",
+			manager:       "my-operator",
+		},
+		{
+			name:          "Positive Test: status field",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			field:         "status.replicaCount",
+			manager:       "my-operator",
+		},
+		{
+			name:          "Negative Test: field owned by another manager",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			field:         "metadata.labels.someTestKey",
+			manager:       "my-operator",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: field not owned",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			field:         "spec.replicas",
+			manager:       "my-operator",
+			wantErr:       true,
+		},
+		{
+			name:    "Negative Test: invalid client",
+			wantErr: true,
+		},
+		{
+			name:          "Negative Test: 'Get' call fails",
+			dynamicClient: newFakeDynamicClient(),
+			field:         "spec.template",
+			manager:       "my-operator",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ResourceFieldShouldBeOwnedBy(tt.dynamicClient, resource, tt.field, tt.manager); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceFieldShouldBeOwnedBy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceFieldShouldBeAtLeastAtMost(t *testing.T) {
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	tests := []struct {
+		name       string
+		comparison fieldComparison
+		key        string
+		value      string
+		wantErr    bool
+	}{
+		{
+			name:       "Positive Test: at least an equal value",
+			comparison: fieldComparisonAtLeast,
+			key:        ".status.replicaCount",
+			value:      "2",
+		},
+		{
+			name:       "Positive Test: at most a greater value",
+			comparison: fieldComparisonAtMost,
+			key:        ".status.replicaCount",
+			value:      "3",
+		},
+		{
+			name:       "Negative Test: at least a greater value",
+			comparison: fieldComparisonAtLeast,
+			key:        ".status.replicaCount",
+			value:      "3",
+			wantErr:    true,
+		},
+		{
+			name:       "Negative Test: field not set",
+			comparison: fieldComparisonAtLeast,
+			key:        ".status.readyReplicas",
+			value:      "1",
+			wantErr:    true,
+		},
+		{
+			name:       "Negative Test: field is not comparable",
+			comparison: fieldComparisonAtMost,
+			key:        ".metadata.labels.someTestKey",
+			value:      "1",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compare := ResourceFieldShouldBeAtLeast
+			if tt.comparison == fieldComparisonAtMost {
+				compare = ResourceFieldShouldBeAtMost
+			}
+			if err := compare(context.Background(), newFakeDynamicClientWithResource(resource), resource, w, tt.key, tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceFieldShouldBe %v error = %v, wantErr %v", tt.comparison, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompareFieldValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		actual   interface{}
+		expected string
+		want     int
+		wantErr  bool
+	}{
+		{name: "integer less than", actual: int64(2), expected: "3", want: -1},
+		{name: "integer equal", actual: int64(3), expected: "3", want: 0},
+		{name: "float greater than", actual: 1.5, expected: "1", want: 1},
+		{name: "quantity with suffixes", actual: "1Gi", expected: "512Mi", want: 1},
+		{name: "milli quantity", actual: "500m", expected: "1", want: -1},
+		{name: "duration less than", actual: "25s", expected: "30s", want: -1},
+		{name: "duration with minutes", actual: "1m30s", expected: "90s", want: 0},
+		{name: "not a quantity or duration", actual: "Ready", expected: "1", wantErr: true},
+		{name: "duration against a quantity", actual: "30s", expected: "1", wantErr: true},
+		{name: "unsupported type", actual: true, expected: "1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := compareFieldValue(tt.actual, tt.expected)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("compareFieldValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("compareFieldValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceFieldShouldMatchJSONPath(t *testing.T) {
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	tests := []struct {
+		name     string
+		jsonPath string
+		operator string
+		value    string
+		wantErr  bool
+	}{
+		{name: "Positive Test: equals a condition status", jsonPath: `.status.conditions[?(@.type=="someConditionType")].status`, operator: "==", value: "True"},
+		{name: "Positive Test: equals a number", jsonPath: "{.status.replicaCount}", operator: "==", value: "2"},
+		{name: "Positive Test: not equals", jsonPath: ".metadata.labels.someTestKey", operator: "!=", value: "otherValue"},
+		{name: "Positive Test: greater than", jsonPath: ".status.replicaCount", operator: ">", value: "1"},
+		{name: "Positive Test: at most", jsonPath: ".status.replicaCount", operator: "<=", value: "2"},
+		{name: "Positive Test: any container port", jsonPath: ".spec.template.containers[*].ports[*].containerPort", operator: ">=", value: "8940"},
+		{name: "Positive Test: contains", jsonPath: ".metadata.labels", operator: "contains", value: "someTestValue"},
+		{name: "Positive Test: matches", jsonPath: ".spec.template.containers[*].image", operator: "matches", value: "^some.*$"},
+		{name: "Negative Test: not equals a value", jsonPath: ".status.replicaCount", operator: "!=", value: "2", wantErr: true},
+		{name: "Negative Test: less than", jsonPath: ".status.replicaCount", operator: "<", value: "2", wantErr: true},
+		{name: "Negative Test: field not set", jsonPath: ".status.readyReplicas", operator: "==", value: "1", wantErr: true},
+		{name: "Negative Test: field is not comparable", jsonPath: ".metadata.name", operator: ">", value: "1", wantErr: true},
+		{name: "Negative Test: invalid json path", jsonPath: ".status[", operator: "==", value: "1", wantErr: true},
+		{name: "Negative Test: invalid regexp", jsonPath: ".metadata.name", operator: "matches", value: "(", wantErr: true},
+		{name: "Negative Test: unsupported operator", jsonPath: ".metadata.name", operator: "~=", value: "1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ResourceFieldShouldMatchJSONPath(context.Background(), newFakeDynamicClientWithResource(resource), resource, w, tt.jsonPath, tt.operator, tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceFieldShouldMatchJSONPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceShouldBeCurrentInClusters(t *testing.T) {
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	failed := resource.Resource.DeepCopy()
+	_ = unstructured.SetNestedSlice(failed.Object, []interface{}{
+		map[string]interface{}{"type": "Ready", "status": "False"},
+	}, "status", "conditions")
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	tests := []struct {
+		name     string
+		clusters map[string]dynamic.Interface
+		wantErr  string
+	}{
+		{
+			name: "Positive Test: current in every cluster",
+			clusters: map[string]dynamic.Interface{
+				"east": newFakeDynamicClientWithResource(resource),
+				"west": newFakeDynamicClientWithResource(resource),
+			},
+		},
+		{
+			name: "Negative Test: missing in a cluster",
+			clusters: map[string]dynamic.Interface{
+				"east": newFakeDynamicClientWithResource(resource),
+				"west": newFakeDynamicClient(),
+			},
+			wantErr: "west: ",
+		},
+		{
+			name: "Negative Test: not ready in a cluster",
+			clusters: map[string]dynamic.Interface{
+				"east": newFakeDynamicClientWithResource(UnstructuredResource{GVR: resource.GVR, Resource: failed}),
+				"west": newFakeDynamicClientWithResource(resource),
+			},
+			wantErr: "east: InProgress",
+		},
+		{
+			name:    "Negative Test: no clusters",
+			wantErr: "no clusters",
+		},
+		{
+			name:     "Negative Test: invalid client",
+			clusters: map[string]dynamic.Interface{"east": nil},
+			wantErr:  "cluster east",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ResourceShouldBeCurrentInClusters(context.Background(), tt.clusters, resource, w)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ResourceShouldBeCurrentInClusters() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ResourceShouldBeCurrentInClusters() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceShouldBeCtx(t *testing.T) {
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := ResourceShouldBeCtx(ctx, newFakeDynamicClient(), resource, common.NewWaiterConfig(5, time.Minute), common.StateCreated)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ResourceShouldBeCtx() error = %v, want %v", err, context.Canceled)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("ResourceShouldBeCtx() waited %v after the context was canceled", time.Since(start))
+	}
+	if err := ResourceShouldBeCtx(ctx, newFakeDynamicClientWithResource(resource), resource, common.NewWaiterConfig(5, time.Minute), common.StateCreated); err != nil {
+		t.Errorf("ResourceShouldBeCtx() error = %v, expected no wait for a created resource", err)
+	}
+}
+
+func TestDeleteResourcesAtPath(t *testing.T) {
+	type args struct {
+		dynamicClient     dynamic.Interface
+		dc                discovery.DiscoveryInterface
+		TemplateArguments interface{}
+		w                 common.WaiterConfig
+		resourcesPath     string
+	}
+
+	resourcePath := getFilePath("resource.yaml")
+	resource := getResourceFromYaml(t, resourcePath)
+	client := newFakeDynamicClientWithResourceList(resource)
+	clientWithResource := newFakeDynamicClientWithResourcesAndResourcesLists(resource)
+
+	resourcesPath := getFilePath("multi-resource.yaml")
+	resources := getResourcesFromYaml(t, resourcesPath)
+	clientWithResources := newFakeDynamicClientWithResourcesAndResourcesLists(resources...)
+	clientWithResourcesInTestDir := newFakeDynamicClientWithResourcesInDir(t, getTestFilesDirPath())
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: delete resource in directory",
+			args: args{
+				dynamicClient: clientWithResourcesInTestDir,
+				dc:            newFakeDiscoveryClient(&clientWithResourcesInTestDir.Fake),
+				resourcesPath: getTestFilesDirPath(),
+			},
+		},
+		{
+			name: "Positive Test: delete resource",
+			args: args{
+				dynamicClient: clientWithResource,
+				dc:            newFakeDiscoveryClient(&clientWithResource.Fake),
+				resourcesPath: resourcePath,
+			},
+		},
+		{
+			name: "Positive Test: already deleted",
+			args: args{
+				dynamicClient: client,
+				dc:            newFakeDiscoveryClient(&client.Fake),
+				resourcesPath: resourcePath,
+			},
+		},
+		{
+			name: "Positive Test: delete multiple resources",
+			args: args{
+				dynamicClient: clientWithResources,
+				dc:            newFakeDiscoveryClient(&clientWithResources.Fake),
+				resourcesPath: resourcesPath,
+			},
+		},
+		{
+			name: "Negative Test: invalid client",
+			args: args{
+				dynamicClient: nil,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: 'GetResources' fails",
+			args: args{
+				dynamicClient: client,
+				dc:            nil,
+				resourcesPath: resourcePath,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Second)
+			if err := DeleteResourcesAtPath(tt.args.dynamicClient, tt.args.dc, tt.args.TemplateArguments, tt.args.w, tt.args.resourcesPath); (err != nil) != tt.wantErr {
+				t.Errorf("DeleteResourcesAtPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceFieldShouldMatch(t *testing.T) {
+	secretGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	newSecret := func(namespace string, data map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("Secret")
+		u.SetName("secret1")
+		u.SetNamespace(namespace)
+		if data != nil {
+			u.Object["data"] = data
+		}
+		return u
+	}
+	newClient := func(objects ...runtime.Object) *fakeDynamic.FakeDynamicClient {
+		client := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			secretGVR: "SecretList",
+		}, objects...)
+		client.Resources = append(client.Resources, newAPIResourceList(secretGVR.GroupVersion(), secretGVR.Resource, "Secret", true))
+		return client
+	}
+	source := newSecret("namespace1", map[string]interface{}{"username": "YWRtaW4=", "password": "cGFzc3dvcmQ="})
+	tests := []struct {
+		name          string
+		dynamicClient *fakeDynamic.FakeDynamicClient
+		resourceType  string
+		field         string
+		ignoredFields string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: field matches",
+			dynamicClient: newClient(source, newSecret("namespace2", map[string]interface{}{"username": "YWRtaW4=", "password": "cGFzc3dvcmQ="})),
+			resourceType:  "secret",
+			field:         "data",
+		},
+		{
+			name:          "Positive Test: ignored field differs",
+			dynamicClient: newClient(source, newSecret("namespace2", map[string]interface{}{"username": "YWRtaW4=", "password": "b3RoZXI="})),
+			resourceType:  "secrets",
+			field:         "data",
+			ignoredFields: "password",
+		},
+		{
+			name:          "Negative Test: field differs",
+			dynamicClient: newClient(source, newSecret("namespace2", map[string]interface{}{"username": "YWRtaW4=", "password": "b3RoZXI="})),
+			resourceType:  "secret",
+			field:         "data",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: resource not found",
+			dynamicClient: newClient(source),
+			resourceType:  "secret",
+			field:         "data",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: field not found in source",
+			dynamicClient: newClient(newSecret("namespace1", nil), newSecret("namespace2", nil)),
+			resourceType:  "secret",
+			field:         "data",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: unknown resource type",
+			dynamicClient: newClient(source),
+			resourceType:  "configmap",
+			field:         "data",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			dc := newFakeDiscoveryClient(&tt.dynamicClient.Fake)
+			if err := ResourceFieldShouldMatch(context.Background(), tt.dynamicClient, dc, w, tt.resourceType, "secret1", "namespace2", "secret1", "namespace1", tt.field, tt.ignoredFields); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceFieldShouldMatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceShouldMatchExpected(t *testing.T) {
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	expected := getResourceFromYaml(t, getFilePath("resource-expected.yaml")).Resource
+	withField := func(value interface{}, field ...string) *unstructured.Unstructured {
+		u := expected.DeepCopy()
+		if err := unstructured.SetNestedField(u.Object, value, field...); err != nil {
+			t.Fatal(err)
+		}
+		return u
+	}
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		expected      *unstructured.Unstructured
+		ignoredFields []string
+		wantErr       bool
+		wantDiff      string
+	}{
+		{
+			name:          "Positive Test: partial expected state",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			expected:      expected,
+			ignoredFields: DefaultIgnoredFields,
+		},
+		{
+			name:          "Positive Test: the resource itself",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			expected:      resource.Resource,
+		},
+		{
+			name:          "Positive Test: ignoring a different field",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			expected:      withField("otherValue", "metadata", "labels", "someTestKey"),
+			ignoredFields: append([]string{"metadata.labels"}, DefaultIgnoredFields...),
+		},
+		{
+			name:          "Negative Test: different value",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			expected:      withField("otherValue", "metadata", "labels", "someTestKey"),
+			ignoredFields: DefaultIgnoredFields,
+			wantErr:       true,
+			wantDiff:      ".metadata.labels.someTestKey: expected otherValue, found someTestValue",
+		},
+		{
+			name:          "Negative Test: missing field",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			expected:      withField("otherValue", "metadata", "labels", "otherKey"),
+			ignoredFields: DefaultIgnoredFields,
+			wantErr:       true,
+			wantDiff:      ".metadata.labels.otherKey: missing",
+		},
+		{
+			name:          "Negative Test: fewer list items",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			expected: withField([]interface{}{
+				map[string]interface{}{"name": "someContainer", "ports": []interface{}{map[string]interface{}{"containerPort": int64(8080)}}},
+			}, "spec", "template", "containers"),
+			ignoredFields: DefaultIgnoredFields,
+			wantErr:       true,
+			wantDiff:      ".spec.template.containers[0].ports: expected 1 items, found 2",
+		},
+		{
+			name:          "Negative Test: status is compared when not ignored",
+			dynamicClient: newFakeDynamicClientWithResource(resource),
+			expected:      expected,
+			ignoredFields: []string{"metadata.uid", "metadata.resourceVersion"},
+			wantErr:       true,
+			wantDiff:      ".status.replicaCount: expected 5, found 2",
+		},
+		{
+			name:          "Negative Test: resource not found",
+			dynamicClient: newFakeDynamicClient(),
+			expected:      expected,
+			ignoredFields: DefaultIgnoredFields,
+			wantErr:       true,
+			wantDiff:      "not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ResourceShouldMatchExpected(context.Background(), tt.dynamicClient, resource, w, tt.expected, tt.ignoredFields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResourceShouldMatchExpected() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.wantDiff) {
+				t.Errorf("ResourceShouldMatchExpected() error = %v, want a difference %v", err, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func TestDeleteResourceChildrenShouldBeGarbageCollected(t *testing.T) {
+	var (
+		deploymentGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+		replicaSetGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+	)
+	newObject := func(kind, name string, uid types.UID, owner *unstructured.Unstructured) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("apps/v1")
+		u.SetKind(kind)
+		u.SetName(name)
+		u.SetNamespace("namespace1")
+		u.SetUID(uid)
+		if owner != nil {
+			u.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: owner.GetAPIVersion(), Kind: owner.GetKind(), Name: owner.GetName(), UID: owner.GetUID()}})
+		}
+		return u
+	}
+	parent := newObject("Deployment", "deployment1", "uid1", nil)
+	otherParent := newObject("Deployment", "deployment2", "uid2", nil)
+	child := newObject("ReplicaSet", "replicaset1", "uid3", parent)
+	otherChild := newObject("ReplicaSet", "replicaset2", "uid4", otherParent)
+	resource := UnstructuredResource{
+		GVR:      &meta.RESTMapping{Resource: deploymentGVR, GroupVersionKind: parent.GroupVersionKind(), Scope: meta.RESTScopeNamespace},
+		Resource: parent,
+	}
+	newClient := func(collect bool, objects ...runtime.Object) *fakeDynamic.FakeDynamicClient {
+		client := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			deploymentGVR: "DeploymentList",
+			replicaSetGVR: "ReplicaSetList",
+		}, objects...)
+		client.Resources = append(client.Resources, &metav1.APIResourceList{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true},
+				{Name: "replicasets", Kind: "ReplicaSet", Namespaced: true},
+			},
+		})
+		if collect {
+			client.PrependReactor("delete", "deployments", func(action kTesting.Action) (bool, runtime.Object, error) {
+				if err := client.Tracker().Delete(replicaSetGVR, child.GetNamespace(), child.GetName()); err != nil && !kerrors.IsNotFound(err) {
+					return true, nil, err
+				}
+				return false, nil, nil
+			})
+		}
+		return client
+	}
+	tests := []struct {
+		name          string
+		dynamicClient *fakeDynamic.FakeDynamicClient
+		childKinds    string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: children garbage collected",
+			dynamicClient: newClient(true, parent, child, otherParent, otherChild),
+			childKinds:    "apps/v1/ReplicaSet",
+		},
+		{
+			name:          "Positive Test: no children",
+			dynamicClient: newClient(false, parent, otherParent, otherChild),
+			childKinds:    "apps/v1/ReplicaSet",
+		},
+		{
+			name:          "Negative Test: child not garbage collected",
+			dynamicClient: newClient(false, parent, child),
+			childKinds:    "apps/v1/ReplicaSet",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: parent not found",
+			dynamicClient: newClient(true, child),
+			childKinds:    "apps/v1/ReplicaSet",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid kind",
+			dynamicClient: newClient(true, parent, child),
+			childKinds:    "ReplicaSet",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: unknown kind",
+			dynamicClient: newClient(true, parent, child),
+			childKinds:    "apps/v1/ReplicaSet,v1/Pod",
+			wantErr:       true,
+		},
+	}
+	defaultWaiter := noKindMatchWaiter
+	defer func() { noKindMatchWaiter = defaultWaiter }()
+	noKindMatchWaiter = common.NewWaiterConfig(2, time.Millisecond)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			dc := newFakeDiscoveryClient(&tt.dynamicClient.Fake)
+			if err := DeleteResourceChildrenShouldBeGarbageCollected(context.Background(), tt.dynamicClient, dc, resource, w, tt.childKinds); (err != nil) != tt.wantErr {
+				t.Errorf("DeleteResourceChildrenShouldBeGarbageCollected() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourcesAtPathShouldBeUninstalled(t *testing.T) {
+	const bundle = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: operator-system
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: operator-role
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: operator
+  namespace: operator-system
+`
+	bundlePath := filepath.Join(t.TempDir(), "bundle.yaml")
+	if err := os.WriteFile(bundlePath, []byte(bundle), 0644); err != nil {
+		t.Fatal(err)
+	}
+	newObject := func(apiVersion, kind, name string, finalizers ...string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetName(name)
+		if len(finalizers) > 0 {
+			now := metav1.Now()
+			u.SetDeletionTimestamp(&now)
+			u.SetFinalizers(finalizers)
+		}
+		return u
+	}
+	newClient := func(objects ...runtime.Object) dynamic.Interface {
+		return fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			uninstallResources[schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}]: "CustomResourceDefinitionList",
+			uninstallResources[schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}]:         "ClusterRoleList",
+			uninstallResources[schema.GroupKind{Kind: "Namespace"}]:                                               "NamespaceList",
+		}, objects...)
+	}
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		resourcesPath string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: uninstalled",
+			dynamicClient: newClient(newObject("apps/v1", "Deployment", "unrelated")),
+			resourcesPath: bundlePath,
+		},
+		{
+			name:          "Positive Test: directory",
+			dynamicClient: newClient(),
+			resourcesPath: filepath.Dir(bundlePath),
+		},
+		{
+			name:          "Negative Test: CustomResourceDefinition remains",
+			dynamicClient: newClient(newObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "widgets.example.com")),
+			resourcesPath: bundlePath,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: Namespace is terminating",
+			dynamicClient: newClient(newObject("v1", "Namespace", "operator-system", "kubernetes")),
+			resourcesPath: bundlePath,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid client",
+			resourcesPath: bundlePath,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: path not found",
+			dynamicClient: newClient(),
+			resourcesPath: "not-found",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := ResourcesAtPathShouldBeUninstalled(context.Background(), tt.dynamicClient, nil, w, tt.resourcesPath); (err != nil) != tt.wantErr {
+				t.Errorf("ResourcesAtPathShouldBeUninstalled() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyInstanceGroups(t *testing.T) {
+	type args struct {
+		dynamicClient dynamic.Interface
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
 		{
 			name: "Positive Test: .status.currentState=Ready",
 			args: args{
@@ -866,6 +1925,171 @@ func TestVerifyInstanceGroups(t *testing.T) {
 	}
 }
 
+func TestScaleInstanceGroup(t *testing.T) {
+	type args struct {
+		name      string
+		namespace string
+		minSize   int64
+		maxSize   int64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{
+				name:      "hello-world",
+				namespace: "instance-manager",
+				minSize:   3,
+				maxSize:   5,
+			},
+		},
+		{
+			name: "Negative Test: minSize greater than maxSize",
+			args: args{
+				name:      "hello-world",
+				namespace: "instance-manager",
+				minSize:   5,
+				maxSize:   3,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: instancegroup not found",
+			args: args{
+				name:      "not-found",
+				namespace: "instance-manager",
+				minSize:   3,
+				maxSize:   5,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := newFakeDynamicClientWithCustomListKinds(
+				getInstanceGroupFromYaml(t, getFilePath("instance-group.yaml")),
+			)
+			err := ScaleInstanceGroup(dynamicClient, tt.args.name, tt.args.namespace, tt.args.minSize, tt.args.maxSize)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ScaleInstanceGroup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			ig, err := GetInstanceGroup(dynamicClient, tt.args.name, tt.args.namespace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := getInstanceGroupMinSize(ig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.args.minSize {
+				t.Errorf("ScaleInstanceGroup() minSize = %d, want %d", got, tt.args.minSize)
+			}
+		})
+	}
+}
+
+func TestInstanceGroupShouldBe(t *testing.T) {
+	type args struct {
+		resource UnstructuredResource
+		state    string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: .status.currentState=Ready",
+			args: args{
+				resource: getInstanceGroupFromYaml(t, getFilePath("instance-group.yaml")),
+				state:    "ready",
+			},
+		},
+		{
+			name: "Negative Test: .status.currentState=NotReady",
+			args: args{
+				resource: getInstanceGroupFromYaml(t, getFilePath("instance-group-not-ready.yaml")),
+				state:    "ready",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := newFakeDynamicClientWithCustomListKinds(tt.args.resource)
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := InstanceGroupShouldBe(context.Background(), dynamicClient, w, "hello-world", "instance-manager", tt.args.state); (err != nil) != tt.wantErr {
+				t.Errorf("InstanceGroupShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInstanceGroupNodesShouldMatchMinSize(t *testing.T) {
+	newNode := func(name, instanceGroup string) runtime.Object {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{instanceGroupNodeLabel: instanceGroup},
+			},
+		}
+	}
+	type args struct {
+		resource UnstructuredResource
+		nodes    []runtime.Object
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{
+				resource: getInstanceGroupFromYaml(t, getFilePath("instance-group.yaml")),
+				nodes: []runtime.Object{
+					newNode("node-1", "hello-world"),
+					newNode("node-2", "hello-world"),
+					newNode("node-3", "other"),
+				},
+			},
+		},
+		{
+			name: "Negative Test: node count does not match",
+			args: args{
+				resource: getInstanceGroupFromYaml(t, getFilePath("instance-group.yaml")),
+				nodes: []runtime.Object{
+					newNode("node-1", "hello-world"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: spec.eks.minSize not set",
+			args: args{
+				resource: getInstanceGroupFromYaml(t, getFilePath("instance-group-not-ready.yaml")),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := newFakeDynamicClientWithCustomListKinds(tt.args.resource)
+			kubeClient := fakeKube.NewSimpleClientset(tt.args.nodes...)
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := InstanceGroupNodesShouldMatchMinSize(context.Background(), kubeClient, dynamicClient, w, "hello-world", "instance-manager"); (err != nil) != tt.wantErr {
+				t.Errorf("InstanceGroupNodesShouldMatchMinSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestGetResource(t *testing.T) {
 	type args struct {
 		dc                discovery.DiscoveryInterface
@@ -897,7 +2121,7 @@ func TestGetResource(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    args
-		want    unstructuredResource
+		want    UnstructuredResource
 		wantErr bool
 	}{
 		// TODO: add negative tests
@@ -944,6 +2168,115 @@ func TestGetResource(t *testing.T) {
 	}
 }
 
+func TestGetKustomizeResources(t *testing.T) {
+	resourcesPath := getFilePath("multi-resource.yaml")
+	resources := getResourcesFromYaml(t, resourcesPath)
+	rendered, err := os.ReadFile(resourcesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name          string
+		kustomization string
+		want          []UnstructuredResource
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test",
+			kustomization: "resources:\n- rendered.yaml\n",
+			want:          resources,
+		},
+		{
+			name:          "Negative Test: missing resource",
+			kustomization: "resources:\n- missing.yaml\n",
+			wantErr:       true,
+		},
+		{
+			name:    "Negative Test: missing kustomization",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kustomizationDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(kustomizationDir, "rendered.yaml"), rendered, 0644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.kustomization != "" {
+				if err := os.WriteFile(filepath.Join(kustomizationDir, "kustomization.yaml"), []byte(tt.kustomization), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			dc := newFakeDiscoveryClient(&newFakeDynamicClientWithResourcesLists(resources...).Fake)
+			got, err := GetKustomizeResources(context.Background(), dc, nil, kustomizationDir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetKustomizeResources() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetKustomizeResources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetResourceOfNewKind(t *testing.T) {
+	defaultWaiter := noKindMatchWaiter
+	defer func() { noKindMatchWaiter = defaultWaiter }()
+	noKindMatchWaiter = common.NewWaiterConfig(3, time.Millisecond)
+
+	resourcePath := getFilePath("resource.yaml")
+	resource := getResourceFromYaml(t, resourcePath)
+	served := newFakeDynamicClientWithResourceList(resource).Resources
+
+	// servedAfter 0 never serves the kind
+	newDiscoveryClient := func(servedAfter int) (discovery.CachedDiscoveryInterface, *int) {
+		dc := newFakeDiscoveryClient(&kTesting.Fake{})
+		// an API server always serves the core group
+		dc.Resources = []*metav1.APIResourceList{newAPIResourceList(corev1.SchemeGroupVersion, "pods", "Pod", true)}
+		discoveries := 0
+		// the kind of a CRD that was just installed is served after a few discoveries
+		dc.PrependReactor("get", "group", func(action kTesting.Action) (bool, runtime.Object, error) {
+			discoveries++
+			if discoveries == servedAfter {
+				dc.Resources = append(dc.Resources, served...)
+			}
+			return false, nil, nil
+		})
+		// the ClientSet caches the discovery
+		return memory.NewMemCacheClient(dc), &discoveries
+	}
+
+	dc, discoveries := newDiscoveryClient(3)
+	got, err := GetResource(dc, nil, resourcePath)
+	if err != nil {
+		t.Fatalf("GetResource() error = %v, expected the kind to be found once served", err)
+	}
+	if !reflect.DeepEqual(got, resource) {
+		t.Errorf("GetResource() = %s, want %s", util.StructToPrettyString(got), util.StructToPrettyString(resource))
+	}
+	if _, err := GetResource(dc, nil, resourcePath); err != nil {
+		t.Fatalf("GetResource() error = %v", err)
+	}
+	if *discoveries != 3 {
+		t.Errorf("expected the API groups to be discovered 3 times, got %d", *discoveries)
+	}
+
+	dc, discoveries = newDiscoveryClient(0)
+	if _, err := GetResource(dc, nil, resourcePath); !meta.IsNoMatchError(err) {
+		t.Errorf("GetResource() error = %v, expected a no match error for a kind that is never served", err)
+	}
+	if *discoveries != 4 {
+		t.Errorf("expected the API groups to be discovered 4 times, got %d", *discoveries)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	dc, _ = newDiscoveryClient(0)
+	if _, err := GetResourceCtx(ctx, dc, nil, resourcePath); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetResourceCtx() error = %v, expected the canceled context to stop the retries", err)
+	}
+}
+
 func TestGetResources(t *testing.T) {
 	type args struct {
 		dc                discovery.DiscoveryInterface
@@ -955,7 +2288,7 @@ func TestGetResources(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    args
-		want    []unstructuredResource
+		want    []UnstructuredResource
 		wantErr bool
 	}{
 		// TODO: add negative tests
@@ -984,6 +2317,439 @@ func TestGetResources(t *testing.T) {
 	}
 }
 
+func TestValidateResources(t *testing.T) {
+	newResource := func(yaml string) UnstructuredResource {
+		resource := &unstructured.Unstructured{}
+		dec := serializer.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
+		if _, _, err := dec.Decode([]byte(yaml), nil, resource); err != nil {
+			t.Fatal(err)
+		}
+		return UnstructuredResource{Resource: resource}
+	}
+	deployment := func(spec string) UnstructuredResource {
+		return newResource(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deployment1
+  labels:
+    app: app1
+spec:
+` + spec)
+	}
+	validSpec := `  replicas: 2
+  selector:
+    matchLabels:
+      app: app1
+  strategy:
+    rollingUpdate:
+      maxSurge: 25%
+      maxUnavailable: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1
+        resources:
+          limits:
+            cpu: 1
+            memory: 128Mi
+`
+	instanceGroup := func(spec string) UnstructuredResource {
+		return newResource(`apiVersion: instancemgr.keikoproj.io/v1alpha1
+kind: InstanceGroup
+metadata:
+  name: instancegroup1
+spec:
+` + spec)
+	}
+	openAPIClient := openapitest.NewEmbeddedFileClient()
+	paths, err := openAPIClient.Paths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths["apis/instancemgr.keikoproj.io/v1alpha1"] = openapitest.FakeGroupVersion{GVSpec: []byte(`{
+  "openapi": "3.0.0",
+  "info": {"title": "Kubernetes CRD Swagger", "version": "v0.1.0"},
+  "paths": {},
+  "components": {"schemas": {"io.keikoproj.instancemgr.v1alpha1.InstanceGroup": {
+    "type": "object",
+    "x-kubernetes-group-version-kind": [{"group": "instancemgr.keikoproj.io", "kind": "InstanceGroup", "version": "v1alpha1"}],
+    "properties": {
+      "apiVersion": {"type": "string"},
+      "kind": {"type": "string"},
+      "metadata": {"type": "object"},
+      "spec": {"type": "object", "properties": {
+        "provisioner": {"type": "string"},
+        "eks": {"type": "object", "x-kubernetes-preserve-unknown-fields": true, "properties": {"minSize": {"type": "integer"}}}
+      }}
+    }
+  }}}
+}`)}
+	tests := []struct {
+		name       string
+		resources  []UnstructuredResource
+		violations []string
+	}{
+		{
+			name:      "Positive Test: valid resources",
+			resources: []UnstructuredResource{deployment(validSpec), instanceGroup("  provisioner: eks\n  eks:\n    minSize: 1\n    maxSize: 3\n")},
+		},
+		{
+			name:      "Positive Test: no schema",
+			resources: []UnstructuredResource{newResource("apiVersion: example.com/v1\nkind: Unknown\nmetadata:\n  name: unknown1\nspec:\n  field: value\n")},
+		},
+		{
+			name: "Negative Test: invalid fields",
+			resources: []UnstructuredResource{
+				deployment(validSpec),
+				deployment(strings.Replace(strings.Replace(validSpec, "replicas: 2", "replica: 2", 1), "image: app:v1", "image: 1", 1)),
+				instanceGroup("  provisioner: [eks]\n  eks:\n    minSize: one\n"),
+			},
+			violations: []string{
+				"file.yaml: document 1: Deployment/deployment1: .spec.replica: unknown field",
+				"file.yaml: document 1: Deployment/deployment1: .spec.template.spec.containers[0].image: expected a string, got 1",
+				"file.yaml: document 2: InstanceGroup/instancegroup1: .spec.eks.minSize: expected an integer, got one",
+				"file.yaml: document 2: InstanceGroup/instancegroup1: .spec.provisioner: expected a string, got [eks]",
+			},
+		},
+		{
+			name:       "Negative Test: invalid int or string",
+			resources:  []UnstructuredResource{deployment(strings.Replace(validSpec, "maxUnavailable: 1", "maxUnavailable: 1.5", 1))},
+			violations: []string{"file.yaml: document 0: Deployment/deployment1: .spec.strategy.rollingUpdate.maxUnavailable: value 1.5 is not allowed by any of the schemas"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateResources(openapitest.FakeClient{PathsMap: paths}, "file.yaml", tt.resources...)
+			if len(tt.violations) == 0 {
+				if err != nil {
+					t.Errorf("ValidateResources() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("ValidateResources() expected violations %v", tt.violations)
+			}
+			if got := strings.Split(err.Error(), "\n")[1:]; !reflect.DeepEqual(got, tt.violations) {
+				t.Errorf("ValidateResources() violations = %q, want %q", got, tt.violations)
+			}
+		})
+	}
+
+	// nil client
+	var nilClient openapi.Client
+	if err := ValidateResources(nilClient, "file.yaml", deployment(validSpec)); err == nil {
+		t.Errorf("ValidateResources() expected error with a nil client")
+	}
+}
+
+func TestParseResourceSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		want     resourceSelector
+		wantErr  bool
+	}{
+		{
+			name:     "Positive Test: equals",
+			selector: ".metadata.labels.app=web",
+			want:     resourceSelector{key: ".metadata.labels.app", keySlice: []string{"metadata", "labels", "app"}, value: "web", operator: selectorOperatorEquals},
+		},
+		{
+			name:     "Positive Test: exists",
+			selector: "metadata.annotations.example\\.com/ready",
+			want:     resourceSelector{key: "metadata.annotations.example\\.com/ready", keySlice: []string{"metadata", "annotations", "example.com/ready"}, operator: selectorOperatorExists},
+		},
+		{
+			name:     "Positive Test: absent",
+			selector: "!metadata.finalizers",
+			want:     resourceSelector{key: "metadata.finalizers", keySlice: []string{"metadata", "finalizers"}, operator: selectorOperatorNotExists},
+		},
+		{
+			name:     "Negative Test: empty value",
+			selector: "metadata.name=",
+			wantErr:  true,
+		},
+		{
+			name:     "Negative Test: empty key",
+			selector: "!.",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseResourceSelector(tt.selector)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseResourceSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseResourceSelector() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeResourceStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		resource   string
+		wantStatus kstatus.Status
+	}{
+		{
+			name: "Positive Test: deployment rolled out",
+			resource: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deployment1
+  generation: 2
+spec:
+  replicas: 1
+status:
+  observedGeneration: 2
+  replicas: 1
+  updatedReplicas: 1
+  availableReplicas: 1
+  readyReplicas: 1
+  conditions:
+  - type: Available
+    status: "True"
+`,
+			wantStatus: kstatus.CurrentStatus,
+		},
+		{
+			name: "Positive Test: deployment rollout not observed",
+			resource: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deployment1
+  generation: 2
+spec:
+  replicas: 1
+status:
+  observedGeneration: 1
+  updatedReplicas: 1
+  availableReplicas: 1
+`,
+			wantStatus: kstatus.InProgressStatus,
+		},
+		{
+			name: "Positive Test: daemonset status not observed",
+			resource: `apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: daemonset1
+`,
+			wantStatus: kstatus.InProgressStatus,
+		},
+		{
+			name: "Positive Test: statefulset rolled out",
+			resource: `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: statefulset1
+  generation: 1
+spec:
+  replicas: 3
+status:
+  observedGeneration: 1
+  replicas: 3
+  readyReplicas: 3
+  currentReplicas: 3
+  updatedReplicas: 3
+  currentRevision: statefulset1-1
+  updateRevision: statefulset1-1
+`,
+			wantStatus: kstatus.CurrentStatus,
+		},
+		{
+			name: "Negative Test: deployment exceeded progress deadline",
+			resource: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deployment1
+  generation: 1
+status:
+  observedGeneration: 1
+  conditions:
+  - type: Progressing
+    status: "False"
+    reason: ProgressDeadlineExceeded
+`,
+			wantStatus: kstatus.FailedStatus,
+		},
+		{
+			name: "Positive Test: job complete",
+			resource: `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job1
+status:
+  conditions:
+  - type: Complete
+    status: "True"
+`,
+			wantStatus: kstatus.CurrentStatus,
+		},
+		{
+			name: "Positive Test: job running",
+			resource: `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job1
+status:
+  active: 1
+`,
+			wantStatus: kstatus.InProgressStatus,
+		},
+		{
+			name: "Negative Test: job failed",
+			resource: `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job1
+status:
+  conditions:
+  - type: Failed
+    status: "True"
+`,
+			wantStatus: kstatus.FailedStatus,
+		},
+		{
+			name: "Positive Test: pod succeeded",
+			resource: `apiVersion: v1
+kind: Pod
+metadata:
+  name: pod1
+status:
+  phase: Succeeded
+`,
+			wantStatus: kstatus.CurrentStatus,
+		},
+		{
+			name: "Positive Test: pod failed is completed",
+			resource: `apiVersion: v1
+kind: Pod
+metadata:
+  name: pod1
+status:
+  phase: Failed
+`,
+			wantStatus: kstatus.CurrentStatus,
+		},
+		{
+			name: "Positive Test: custom resource not ready",
+			resource: `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget1
+status:
+  conditions:
+  - type: Ready
+    status: "False"
+    reason: Reconciling
+`,
+			wantStatus: kstatus.InProgressStatus,
+		},
+		{
+			name: "Positive Test: custom resource without ready condition",
+			resource: `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget1
+status:
+  conditions:
+  - type: Available
+    status: "True"
+`,
+			wantStatus: kstatus.CurrentStatus,
+		},
+		{
+			name: "Positive Test: resource without status",
+			resource: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: configmap1
+`,
+			wantStatus: kstatus.CurrentStatus,
+		},
+		{
+			name: "Negative Test: custom resource stalled",
+			resource: `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget1
+  generation: 3
+status:
+  observedGeneration: 3
+  conditions:
+  - type: Stalled
+    status: "True"
+    reason: InvalidSpec
+`,
+			wantStatus: kstatus.FailedStatus,
+		},
+		{
+			name: "Positive Test: custom resource reconciling",
+			resource: `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget1
+status:
+  conditions:
+  - type: Reconciling
+    status: "True"
+`,
+			wantStatus: kstatus.InProgressStatus,
+		},
+		{
+			name: "Positive Test: load balancer service not provisioned",
+			resource: `apiVersion: v1
+kind: Service
+metadata:
+  name: service1
+spec:
+  type: LoadBalancer
+`,
+			wantStatus: kstatus.InProgressStatus,
+		},
+		{
+			name: "Positive Test: statefulset revision not rolled out",
+			resource: `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: statefulset1
+spec:
+  replicas: 1
+status:
+  observedGeneration: 0
+  readyReplicas: 1
+  currentReplicas: 1
+  currentRevision: statefulset1-1
+  updateRevision: statefulset1-2
+`,
+			wantStatus: kstatus.InProgressStatus,
+		},
+		{
+			name: "Positive Test: resource being deleted",
+			resource: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: configmap1
+  deletionTimestamp: "2024-01-01T00:00:00Z"
+`,
+			wantStatus: kstatus.TerminatingStatus,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := getResourceFromBytes(t, []byte(tt.resource))
+			if status, message := computeResourceStatus(resource.Resource); status != tt.wantStatus {
+				t.Errorf("computeResourceStatus() status = %v (%v), want %v", status, message, tt.wantStatus)
+			}
+		})
+	}
+}
+
 func TestGetInstanceGroupList(t *testing.T) {
 	type args struct {
 		dynamicClient dynamic.Interface
@@ -1034,7 +2800,7 @@ func generateFileFromTemplate(t *testing.T, templatedFilePath string, templateAr
 	return generatedPath
 }
 
-func resourceToList(t *testing.T, resource unstructuredResource) *unstructured.UnstructuredList {
+func resourceToList(t *testing.T, resource UnstructuredResource) *unstructured.UnstructuredList {
 	unstructList := unstructured.Unstructured{}
 	unstructList.SetUnstructuredContent(map[string]interface{}{
 		"apiVersion": resource.Resource.GetAPIVersion(),
@@ -1067,7 +2833,7 @@ func getTemplatedFilePath(testFileName string) string {
 	return filepath.Join(getTestDirPath(), "templates", testFileName)
 }
 
-func getInstanceGroupFromYaml(t *testing.T, resourceFilePath string) unstructuredResource {
+func getInstanceGroupFromYaml(t *testing.T, resourceFilePath string) UnstructuredResource {
 	rawResource, err := os.ReadFile(resourceFilePath)
 	if err != nil {
 		t.Error(err)
@@ -1087,10 +2853,10 @@ func getInstanceGroupFromYaml(t *testing.T, resourceFilePath string) unstructure
 		},
 		GroupVersionKind: *gvk,
 	}
-	return unstructuredResource{GVR: gvr, Resource: resource}
+	return UnstructuredResource{GVR: gvr, Resource: resource}
 }
 
-func getResourceFromBytes(t *testing.T, rawResource []byte) unstructuredResource {
+func getResourceFromBytes(t *testing.T, rawResource []byte) UnstructuredResource {
 	resource := &unstructured.Unstructured{}
 	decoder := serializer.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 	_, gvk, err := decoder.Decode(rawResource, nil, resource)
@@ -1111,10 +2877,10 @@ func getResourceFromBytes(t *testing.T, rawResource []byte) unstructuredResource
 		GroupVersionKind: *gvk,
 		Scope:            scope,
 	}
-	return unstructuredResource{GVR: gvr, Resource: resource}
+	return UnstructuredResource{GVR: gvr, Resource: resource}
 }
 
-func getResourceFromYaml(t *testing.T, resourceFilePath string) unstructuredResource {
+func getResourceFromYaml(t *testing.T, resourceFilePath string) UnstructuredResource {
 	rawResource, err := os.ReadFile(resourceFilePath)
 	if err != nil {
 		t.Error(err)
@@ -1122,13 +2888,13 @@ func getResourceFromYaml(t *testing.T, resourceFilePath string) unstructuredReso
 	return getResourceFromBytes(t, rawResource)
 }
 
-func getResourcesFromYaml(t *testing.T, resourcesFilePath string) []unstructuredResource {
+func getResourcesFromYaml(t *testing.T, resourcesFilePath string) []UnstructuredResource {
 	rawResources, err := os.ReadFile(resourcesFilePath)
 	if err != nil {
 		t.Error(err)
 	}
 	rawResourcesSplit := bytes.Split(rawResources, []byte(yamlSeparator))
-	resources := make([]unstructuredResource, 0)
+	resources := make([]UnstructuredResource, 0)
 	for _, rawResource := range rawResourcesSplit {
 		if len(bytes.Trim(rawResource, trimTokens)) == 0 {
 			continue
@@ -1147,7 +2913,7 @@ func newFakeDynamicClient() *fakeDynamic.FakeDynamicClient {
 	return fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme())
 }
 
-func newFakeDynamicClientWithCustomListKinds(resource unstructuredResource) *fakeDynamic.FakeDynamicClient {
+func newFakeDynamicClientWithCustomListKinds(resource UnstructuredResource) *fakeDynamic.FakeDynamicClient {
 	return fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(
 		runtime.NewScheme(),
 		map[schema.GroupVersionResource]string{
@@ -1158,7 +2924,7 @@ func newFakeDynamicClientWithCustomListKinds(resource unstructuredResource) *fak
 	)
 }
 
-func newFakeDynamicClientWithResourceList(resource unstructuredResource) *fakeDynamic.FakeDynamicClient {
+func newFakeDynamicClientWithResourceList(resource UnstructuredResource) *fakeDynamic.FakeDynamicClient {
 	client := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme())
 
 	namespaced := true
@@ -1174,7 +2940,7 @@ func newFakeDynamicClientWithResourceList(resource unstructuredResource) *fakeDy
 	return client
 }
 
-func newFakeDynamicClientWithResourcesLists(resources ...unstructuredResource) *fakeDynamic.FakeDynamicClient {
+func newFakeDynamicClientWithResourcesLists(resources ...UnstructuredResource) *fakeDynamic.FakeDynamicClient {
 	client := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme())
 	for _, resource := range resources {
 		namespaced := true
@@ -1196,7 +2962,7 @@ func newFakeDynamicClientWithResourcesInDir(t *testing.T, dirPath string) *fakeD
 	if err != nil {
 		t.Error(err)
 	}
-	allResources := []unstructuredResource{}
+	allResources := []UnstructuredResource{}
 	for _, file := range files {
 		if strings.HasSuffix(file.Name(), ".yaml") {
 			resources := getResourcesFromYaml(t, filepath.Join(dirPath, file.Name()))
@@ -1209,8 +2975,8 @@ func newFakeDynamicClientWithResourcesInDir(t *testing.T, dirPath string) *fakeD
 	return newFakeDynamicClientWithResourcesAndResourcesLists(allResources...)
 }
 
-func newFakeDynamicClientWithResourcesAndResourcesLists(resources ...unstructuredResource) *fakeDynamic.FakeDynamicClient {
-	uniqueResources := map[string]unstructuredResource{}
+func newFakeDynamicClientWithResourcesAndResourcesLists(resources ...UnstructuredResource) *fakeDynamic.FakeDynamicClient {
+	uniqueResources := map[string]UnstructuredResource{}
 	for _, resource := range resources {
 		key := /*resource.Resource.GetNamespace() + "/" +*/ resource.Resource.GetKind() + "/" + resource.Resource.GetName()
 		if _, ok := uniqueResources[key]; !ok {
@@ -1246,7 +3012,7 @@ func newFakeDynamicClientWithReactors(reactors ...kTesting.Reactor) *fakeDynamic
 	return client
 }
 
-func newFakeDynamicClientWithResource(resource unstructuredResource) *fakeDynamic.FakeDynamicClient {
+func newFakeDynamicClientWithResource(resource UnstructuredResource) *fakeDynamic.FakeDynamicClient {
 	client := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme())
 	_ = client.Tracker().Create(resource.GVR.Resource, resource.Resource, resource.Resource.GetNamespace())
 	return client
@@ -0,0 +1,76 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrademanager
+
+import (
+	"context"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+)
+
+func CreateRollingUpgrade(dynamicClient dynamic.Interface, name, namespace, asgName string) error {
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+	if asgName == "" {
+		return errors.Errorf("cannot create rollingupgrade %v/%v without an Auto Scaling Group name", namespace, name)
+	}
+
+	rollingUpgrade := newRollingUpgrade(name, namespace, asgName)
+	if _, err := dynamicClient.Resource(RollingUpgradeResource).Namespace(namespace).Create(context.Background(), rollingUpgrade, metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to create rollingupgrade %v/%v", namespace, name)
+	}
+	log.Infof("rollingupgrade %v/%v created for Auto Scaling Group %v", namespace, name, asgName)
+	return nil
+}
+
+func RollingUpgradeShouldBe(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, name, namespace, status string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+	if !isSupportedStatus(status) {
+		return common.Errorf(common.ErrUnsupportedOperation, "unsupported rollingupgrade status: '%s'", status)
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for rollingupgrade %v/%v to be %v", namespace, name, status)
+		}
+		log.Infof("waiting for rollingupgrade %v/%v to be %v", namespace, name, status)
+		rollingUpgrade, err := getRollingUpgrade(dynamicClient, name, namespace)
+		if err != nil {
+			return err
+		}
+		currentStatus := getCurrentStatus(rollingUpgrade)
+		if currentStatus == status {
+			log.Infof("rollingupgrade %v/%v is %v", namespace, name, status)
+			return nil
+		}
+		if currentStatus == StatusError {
+			return errors.Errorf("rollingupgrade %v/%v failed: %v", namespace, name, getFailureReasons(rollingUpgrade))
+		}
+		log.Infof("rollingupgrade %v/%v is %v, %v", namespace, name, currentStatus, getProgress(rollingUpgrade))
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrademanager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	StatusInit      = "init"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusError     = "error"
+)
+
+var RollingUpgradeResource = schema.GroupVersionResource{Group: "upgrademgr.keikoproj.io", Version: "v1alpha1", Resource: "rollingupgrades"}
+
+func newRollingUpgrade(name, namespace, asgName string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": RollingUpgradeResource.GroupVersion().String(),
+		"kind":       "RollingUpgrade",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"asgName": asgName,
+		},
+	}}
+}
+
+func getRollingUpgrade(dynamicClient dynamic.Interface, name, namespace string) (*unstructured.Unstructured, error) {
+	rollingUpgrade, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return dynamicClient.Resource(RollingUpgradeResource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get rollingupgrade '%v'", name)
+	}
+	return rollingUpgrade.(*unstructured.Unstructured), nil
+}
+
+func getCurrentStatus(rollingUpgrade *unstructured.Unstructured) string {
+	status, _, _ := unstructured.NestedString(rollingUpgrade.Object, "status", "currentStatus")
+	return status
+}
+
+func getProgress(rollingUpgrade *unstructured.Unstructured) string {
+	processed, _, _ := unstructured.NestedInt64(rollingUpgrade.Object, "status", "nodesProcessed")
+	total, _, _ := unstructured.NestedInt64(rollingUpgrade.Object, "status", "totalNodes")
+	return fmt.Sprintf("%d/%d nodes processed", processed, total)
+}
+
+// getFailureReasons joins the conditions of the rollingupgrade status, upgrade-manager does not record a single failure message
+func getFailureReasons(rollingUpgrade *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(rollingUpgrade.Object, "status", "conditions")
+	var reasons []string
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		reason := fmt.Sprintf("%v=%v", condition["type"], condition["status"])
+		if message, ok := condition["message"]; ok {
+			reason = fmt.Sprintf("%v: %v", reason, message)
+		}
+		reasons = append(reasons, reason)
+	}
+	if len(reasons) == 0 {
+		return "no conditions reported"
+	}
+	return strings.Join(reasons, ", ")
+}
+
+func isSupportedStatus(status string) bool {
+	switch status {
+	case StatusInit, StatusRunning, StatusCompleted, StatusError:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrademanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/internal/testutil"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	rollingUpgradeName = "rollingupgrade1"
+	namespace          = "namespace1"
+	asgName            = "asg1"
+)
+
+func TestCreateRollingUpgrade(t *testing.T) {
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		asgName       string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources),
+			asgName:       asgName,
+		},
+		{
+			name:          "Negative Test: already exists",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newRollingUpgradeWithStatus(StatusRunning, nil)),
+			asgName:       asgName,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: no asg name",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid client",
+			dynamicClient: nil,
+			asgName:       asgName,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CreateRollingUpgrade(tt.dynamicClient, rollingUpgradeName, namespace, tt.asgName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateRollingUpgrade() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			rollingUpgrade, err := getRollingUpgrade(tt.dynamicClient, rollingUpgradeName, namespace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _, _ := unstructured.NestedString(rollingUpgrade.Object, "spec", "asgName"); got != tt.asgName {
+				t.Errorf("CreateRollingUpgrade() spec.asgName = %v, want %v", got, tt.asgName)
+			}
+		})
+	}
+}
+
+func TestRollingUpgradeShouldBe(t *testing.T) {
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		status        string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: rollingupgrade completed",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newRollingUpgradeWithStatus(StatusCompleted, nil)),
+			status:        StatusCompleted,
+		},
+		{
+			name:          "Negative Test: rollingupgrade running",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newRollingUpgradeWithStatus(StatusRunning, nil)),
+			status:        StatusCompleted,
+			wantErr:       true,
+		},
+		{
+			name: "Negative Test: rollingupgrade failed",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newRollingUpgradeWithStatus(StatusError, []interface{}{
+				map[string]interface{}{"type": "Complete", "status": "False"},
+			})),
+			status:  StatusCompleted,
+			wantErr: true,
+		},
+		{
+			name:          "Negative Test: unsupported status",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newRollingUpgradeWithStatus(StatusCompleted, nil)),
+			status:        "done",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: rollingupgrade not found",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources),
+			status:        StatusCompleted,
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid client",
+			dynamicClient: nil,
+			status:        StatusCompleted,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RollingUpgradeShouldBe(context.Background(), tt.dynamicClient, common.NewWaiterConfig(1, time.Millisecond), rollingUpgradeName, namespace, tt.status); (err != nil) != tt.wantErr {
+				t.Errorf("RollingUpgradeShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetFailureReasons(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []interface{}
+		want       string
+	}{
+		{
+			name: "conditions with message",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Complete", "status": "False", "message": "drain failed"},
+			},
+			want: "Complete=False: drain failed",
+		},
+		{
+			name: "no conditions",
+			want: "no conditions reported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getFailureReasons(newRollingUpgradeWithStatus(StatusError, tt.conditions)); got != tt.want {
+				t.Errorf("getFailureReasons() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeResources are the resources of the kinds tracked by the fake dynamic client
+var fakeResources = map[string]schema.GroupVersionResource{
+	"RollingUpgrade": RollingUpgradeResource,
+}
+
+func newRollingUpgradeWithStatus(status string, conditions []interface{}) *unstructured.Unstructured {
+	rollingUpgrade := newRollingUpgrade(rollingUpgradeName, namespace, asgName)
+	_ = unstructured.SetNestedField(rollingUpgrade.Object, status, "status", "currentStatus")
+	if conditions != nil {
+		_ = unstructured.SetNestedSlice(rollingUpgrade.Object, conditions, "status", "conditions")
+	}
+	return rollingUpgrade
+}
@@ -0,0 +1,95 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vpa
+
+import (
+	"context"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/dynamic"
+)
+
+// RecommendationShouldBeProvided waits for the verticalpodautoscaler to recommend resources for the container, or for any container if none is given.
+func RecommendationShouldBeProvided(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, name, namespace, containerName string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for verticalpodautoscaler %v/%v to provide a recommendation", namespace, name)
+		}
+		vpa, err := getVerticalPodAutoscaler(dynamicClient, name, namespace)
+		if err != nil {
+			return err
+		}
+		if recommendation, ok := getContainerRecommendation(vpa, containerName); ok {
+			log.Infof("verticalpodautoscaler %v/%v recommends %v for container %v", namespace, name, recommendation.target, recommendation.containerName)
+			return nil
+		}
+		log.Infof("waiting for verticalpodautoscaler %v/%v to provide a recommendation", namespace, name)
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// RecommendationShouldBeBetween waits for the target the verticalpodautoscaler recommends for the resource of the container to be between min and max.
+func RecommendationShouldBeBetween(ctx context.Context, dynamicClient dynamic.Interface, w common.WaiterConfig, resourceName, containerName, name, namespace, min, max string) error {
+	var counter int
+
+	if err := common.ValidateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+	minQuantity, err := resource.ParseQuantity(min)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse minimum value '%v'", min)
+	}
+	maxQuantity, err := resource.ParseQuantity(max)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse maximum value '%v'", max)
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return common.Errorf(common.ErrWaiterTimeout, "waiter timed out waiting for the %v recommendation of container %v in verticalpodautoscaler %v/%v to be between %v and %v", resourceName, containerName, namespace, name, min, max)
+		}
+		vpa, err := getVerticalPodAutoscaler(dynamicClient, name, namespace)
+		if err != nil {
+			return err
+		}
+		recommendation, ok := getContainerRecommendation(vpa, containerName)
+		if !ok {
+			log.Infof("verticalpodautoscaler %v/%v has no recommendation for container %v yet", namespace, name, containerName)
+		} else if target, err := recommendation.getTarget(resourceName); err != nil {
+			return err
+		} else if target.Cmp(minQuantity) >= 0 && target.Cmp(maxQuantity) <= 0 {
+			log.Infof("%v recommendation %v of container %v in verticalpodautoscaler %v/%v is between %v and %v", resourceName, target.String(), containerName, namespace, name, min, max)
+			return nil
+		} else {
+			log.Infof("%v recommendation %v of container %v in verticalpodautoscaler %v/%v is not between %v and %v", resourceName, target.String(), containerName, namespace, name, min, max)
+		}
+		counter++
+		if err := w.Sleep(ctx); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vpa
+
+import (
+	"context"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var VerticalPodAutoscalerResource = schema.GroupVersionResource{Group: "autoscaling.k8s.io", Version: "v1", Resource: "verticalpodautoscalers"}
+
+type containerRecommendation struct {
+	containerName string
+	target        map[string]interface{}
+}
+
+func (r containerRecommendation) getTarget(resourceName string) (resource.Quantity, error) {
+	value, ok := r.target[resourceName]
+	if !ok {
+		return resource.Quantity{}, errors.Errorf("no %v recommendation for container %v", resourceName, r.containerName)
+	}
+	quantity, err := resource.ParseQuantity(quantityString(value))
+	if err != nil {
+		return resource.Quantity{}, errors.Wrapf(err, "failed to parse %v recommendation of container %v", resourceName, r.containerName)
+	}
+	return quantity, nil
+}
+
+func getVerticalPodAutoscaler(dynamicClient dynamic.Interface, name, namespace string) (*unstructured.Unstructured, error) {
+	vpa, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return dynamicClient.Resource(VerticalPodAutoscalerResource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(common.WrapNotFound(err), "failed to get verticalpodautoscaler '%v'", name)
+	}
+	return vpa.(*unstructured.Unstructured), nil
+}
+
+// getContainerRecommendation returns the recommendation of the container, or the first one if containerName is empty
+func getContainerRecommendation(vpa *unstructured.Unstructured, containerName string) (containerRecommendation, bool) {
+	recommendations, _, _ := unstructured.NestedSlice(vpa.Object, "status", "recommendation", "containerRecommendations")
+	for _, r := range recommendations {
+		recommendation, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := recommendation["containerName"].(string)
+		target, _ := recommendation["target"].(map[string]interface{})
+		if (containerName == "" || name == containerName) && len(target) > 0 {
+			return containerRecommendation{containerName: name, target: target}, true
+		}
+	}
+	return containerRecommendation{}, false
+}
+
+// quantityString returns the quantity of a value decoded from json as a string, numbers have no units
+func quantityString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int64:
+		return resource.NewQuantity(v, resource.DecimalSI).String()
+	case float64:
+		return resource.NewMilliQuantity(int64(v*1000), resource.DecimalSI).String()
+	default:
+		return ""
+	}
+}
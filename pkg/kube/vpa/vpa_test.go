@@ -0,0 +1,131 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vpa
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/internal/testutil"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	vpaName   = "vpa1"
+	namespace = "namespace1"
+)
+
+func TestRecommendationShouldBeProvided(t *testing.T) {
+	tests := []struct {
+		name          string
+		dynamicClient dynamic.Interface
+		containerName string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: any container",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newVerticalPodAutoscaler(map[string]interface{}{"cpu": "25m", "memory": "262144k"})),
+		},
+		{
+			name:          "Positive Test: container",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newVerticalPodAutoscaler(map[string]interface{}{"cpu": "25m"})),
+			containerName: "app",
+		},
+		{
+			name:          "Negative Test: other container",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newVerticalPodAutoscaler(map[string]interface{}{"cpu": "25m"})),
+			containerName: "istio-proxy",
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: no recommendation",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources, newVerticalPodAutoscaler(nil)),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: verticalpodautoscaler not found",
+			dynamicClient: testutil.NewFakeDynamicClient(t, fakeResources),
+			wantErr:       true,
+		},
+		{
+			name:          "Negative Test: invalid client",
+			dynamicClient: nil,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RecommendationShouldBeProvided(context.Background(), tt.dynamicClient, common.NewWaiterConfig(1, time.Millisecond), vpaName, namespace, tt.containerName); (err != nil) != tt.wantErr {
+				t.Errorf("RecommendationShouldBeProvided() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecommendationShouldBeBetween(t *testing.T) {
+	dynamicClient := testutil.NewFakeDynamicClient(t, fakeResources, newVerticalPodAutoscaler(map[string]interface{}{"cpu": "25m", "memory": int64(262144000)}))
+	tests := []struct {
+		name         string
+		resourceName string
+		min          string
+		max          string
+		wantErr      bool
+	}{
+		{name: "Positive Test: cpu", resourceName: "cpu", min: "10m", max: "100m"},
+		{name: "Positive Test: memory without units", resourceName: "memory", min: "128Mi", max: "512Mi"},
+		{name: "Positive Test: inclusive bounds", resourceName: "cpu", min: "25m", max: "25m"},
+		{name: "Negative Test: above max", resourceName: "cpu", min: "1m", max: "20m", wantErr: true},
+		{name: "Negative Test: below min", resourceName: "memory", min: "512Mi", max: "1Gi", wantErr: true},
+		{name: "Negative Test: no recommendation for resource", resourceName: "ephemeral-storage", min: "1Gi", max: "2Gi", wantErr: true},
+		{name: "Negative Test: invalid min", resourceName: "cpu", min: "a", max: "100m", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RecommendationShouldBeBetween(context.Background(), dynamicClient, common.NewWaiterConfig(1, time.Millisecond), tt.resourceName, "app", vpaName, namespace, tt.min, tt.max); (err != nil) != tt.wantErr {
+				t.Errorf("RecommendationShouldBeBetween() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fakeResources are the resources of the kinds tracked by the fake dynamic client
+var fakeResources = map[string]schema.GroupVersionResource{
+	"VerticalPodAutoscaler": VerticalPodAutoscalerResource,
+}
+
+func newVerticalPodAutoscaler(target map[string]interface{}) *unstructured.Unstructured {
+	vpa := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": VerticalPodAutoscalerResource.GroupVersion().String(),
+		"kind":       "VerticalPodAutoscaler",
+		"metadata": map[string]interface{}{
+			"name":      vpaName,
+			"namespace": namespace,
+		},
+	}}
+	if target != nil {
+		vpa.Object["status"] = map[string]interface{}{
+			"recommendation": map[string]interface{}{
+				"containerRecommendations": []interface{}{
+					map[string]interface{}{"containerName": "app", "target": target},
+				},
+			},
+		}
+	}
+	return vpa
+}
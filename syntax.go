@@ -0,0 +1,104 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubedog
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"regexp"
+	"sync"
+
+	"github.com/cucumber/godog"
+	"github.com/keikoproj/kubedog/generate/syntax/catalog"
+	log "github.com/sirupsen/logrus"
+)
+
+//go:embed docs/syntax.json
+var syntaxCatalog []byte
+
+var (
+	compiledSyntax     []compiledStep
+	compiledSyntaxOnce sync.Once
+)
+
+type compiledStep struct {
+	regex      *regexp.Regexp
+	deprecated string
+	// index is the position of the step in the syntax catalog
+	index int
+}
+
+/*
+Syntax returns the steps defined by SetScenario as generated in docs/syntax.json, including their titles, categories, descriptions and examples.
+*/
+func Syntax() ([]catalog.Step, error) {
+	steps := []catalog.Step{}
+	if err := json.Unmarshal(syntaxCatalog, &steps); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+// getCompiledSyntax returns the steps of the syntax catalog with their regexps compiled once
+func getCompiledSyntax() []compiledStep {
+	compiledSyntaxOnce.Do(func() {
+		steps, err := Syntax()
+		if err != nil {
+			log.Warnf("failed loading the syntax catalog, deprecated and used steps will not be reported: %v", err)
+			return
+		}
+		compiledSyntax = compileSteps(steps)
+	})
+	return compiledSyntax
+}
+
+// warnDeprecatedStep is a godog before step hook logging a warning when the step is deprecated
+func warnDeprecatedStep(ctx context.Context, st *godog.Step) (context.Context, error) {
+	if hint := getDeprecation(getCompiledSyntax(), st.Text); hint != "" {
+		log.Warnf("step '%s' is deprecated: %s", st.Text, hint)
+	}
+	return ctx, nil
+}
+
+func compileSteps(steps []catalog.Step) []compiledStep {
+	compiled := []compiledStep{}
+	for i, step := range steps {
+		regex, err := regexp.Compile(step.Regex)
+		if err != nil {
+			continue
+		}
+		compiled = append(compiled, compiledStep{regex: regex, deprecated: step.Deprecated, index: i})
+	}
+	return compiled
+}
+
+// findStep returns the first step matching text, as godog does, or nil if no step matches
+func findStep(steps []compiledStep, text string) *compiledStep {
+	for i := range steps {
+		if steps[i].regex.MatchString(text) {
+			return &steps[i]
+		}
+	}
+	return nil
+}
+
+// getDeprecation returns the deprecation hint of the first step matching text, or empty if it is not deprecated
+func getDeprecation(steps []compiledStep, text string) string {
+	if step := findStep(steps, text); step != nil {
+		return step.deprecated
+	}
+	return ""
+}
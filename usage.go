@@ -0,0 +1,154 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubedog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cucumber/godog"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	stepUsageJSONFile     = "step-usage.json"
+	stepUsageMarkdownFile = "step-usage.md"
+)
+
+// StepUsage is how many times a step of the syntax catalog was run
+type StepUsage struct {
+	Method   string `json:"method"`
+	Regex    string `json:"regex"`
+	Category string `json:"category,omitempty"`
+	Count    int    `json:"count"`
+}
+
+// stepUsage counts the steps run by index in the syntax catalog, scenarios may run concurrently
+type stepUsage struct {
+	sync.Mutex
+	counts map[int]int
+}
+
+func (u *stepUsage) record(index int) {
+	u.Lock()
+	defer u.Unlock()
+	if u.counts == nil {
+		u.counts = map[int]int{}
+	}
+	u.counts[index]++
+}
+
+func (u *stepUsage) count(index int) int {
+	u.Lock()
+	defer u.Unlock()
+	return u.counts[index]
+}
+
+/*
+SetStepUsageReport sets the directory the step usage report is written to after the test suite, as step-usage.json and step-usage.md.
+The report lists how many times each step was run, including the steps never used. It requires SetTestSuite.
+*/
+func (kdt *Test) SetStepUsageReport(dir string) {
+	kdt.stepUsageDir = dir
+}
+
+/*
+StepUsage returns every step of the syntax catalog with the number of times it was run, steps not defined by kubedog are not counted.
+*/
+func (kdt *Test) StepUsage() ([]StepUsage, error) {
+	steps, err := Syntax()
+	if err != nil {
+		return nil, err
+	}
+	usage := make([]StepUsage, 0, len(steps))
+	for i, step := range steps {
+		usage = append(usage, StepUsage{
+			Method:   step.Method,
+			Regex:    step.Regex,
+			Category: step.Category,
+			Count:    kdt.stepUsage.count(i),
+		})
+	}
+	return usage, nil
+}
+
+/*
+ExportStepUsage writes the step usage report to the directory as step-usage.json and step-usage.md.
+*/
+func (kdt *Test) ExportStepUsage(dir string) error {
+	usage, err := kdt.StepUsage()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return err
+	}
+	jsonPath := filepath.Join(dir, stepUsageJSONFile)
+	if err := os.WriteFile(jsonPath, data, 0644); err != nil {
+		return errors.Wrapf(err, "failed writing the step usage to '%s'", jsonPath)
+	}
+	markdownPath := filepath.Join(dir, stepUsageMarkdownFile)
+	if err := os.WriteFile(markdownPath, []byte(renderStepUsage(usage)), 0644); err != nil {
+		return errors.Wrapf(err, "failed writing the step usage to '%s'", markdownPath)
+	}
+	log.Infof("exported the step usage to '%s' and '%s'", jsonPath, markdownPath)
+	return nil
+}
+
+// recordStepUsage is a godog before step hook counting the step of the syntax catalog matching the step text
+func (kdt *Test) recordStepUsage(ctx context.Context, st *godog.Step) (context.Context, error) {
+	if step := findStep(getCompiledSyntax(), st.Text); step != nil {
+		kdt.stepUsage.record(step.index)
+	}
+	return ctx, nil
+}
+
+// renderStepUsage renders the used steps by descending count and the steps never used as markdown
+func renderStepUsage(usage []StepUsage) string {
+	var used, unused []StepUsage
+	for _, step := range usage {
+		if step.Count > 0 {
+			used = append(used, step)
+		} else {
+			unused = append(unused, step)
+		}
+	}
+	sort.SliceStable(used, func(i, j int) bool {
+		return used[i].Count > used[j].Count
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Step usage\n\n%d of %d steps used\n\n", len(used), len(usage))
+	b.WriteString("## Used\n\n| Method | Count |\n| --- | --- |\n")
+	for _, step := range used {
+		fmt.Fprintf(&b, "| %s | %d |\n", step.Method, step.Count)
+	}
+	b.WriteString("\n## Never used\n\n")
+	for _, step := range unused {
+		fmt.Fprintf(&b, "- %s `%s`\n", step.Method, step.Regex)
+	}
+	return b.String()
+}